@@ -1,22 +1,45 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/cli-ai-org/cli/internal/builtins"
+	"github.com/cli-ai-org/cli/internal/categories"
+	"github.com/cli-ai-org/cli/internal/collector"
+	"github.com/cli-ai-org/cli/internal/duplicates"
 	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/outdated"
 	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/pathenv"
 	"github.com/cli-ai-org/cli/internal/scanner"
+	"github.com/cli-ai-org/cli/internal/semver"
+	"github.com/cli-ai-org/cli/internal/shims"
+	"github.com/cli-ai-org/cli/internal/variants"
 	"github.com/spf13/cobra"
 )
 
 var (
-	auditOutput string
+	auditOutput   string
+	auditExplain  bool
+	auditWithHash bool
+	auditRegistry bool
+	auditFormat   string
+	auditJSON     bool
+	auditFixPath  string
+	auditDryRun   bool
+	auditFailOn   string
 )
 
+// formatMarkdown is the audit's default, human- and LLM-readable report
+const formatMarkdown = "markdown"
+
 // auditCmd represents the audit command
 var auditCmd = &cobra.Command{
 	Use:   "audit",
@@ -26,103 +49,306 @@ var auditCmd = &cobra.Command{
 This command analyzes:
   - Installation clashes (tools from multiple package managers)
   - Shadowed installations (tools not being used)
+  - Upgrades not taking effect (a shadowed installation is newer than the
+    one that runs)
   - Package manager coverage
+  - pip packages installed at different versions under different Python
+    interpreters (each interpreter's packages are kept in the package
+    cache; see --no-cache and --refresh)
+  - Tools provided both by a version manager shim and by another package
+    manager, with the plugin and version the shim runs
   - System health recommendations
 
-The audit generates a markdown report suitable for AI agents to analyze.`,
+The audit generates a markdown report suitable for AI agents to analyze.
+
+With --with-hash, tools are also compared by content to find the same binary
+installed as separate copies under different names. Only files that share a
+size are read, but this is still slower than the default audit.
+
+With --check-registry, global npm packages are looked up on the npm registry
+to flag deprecated packages and installed versions that have been
+unpublished. This needs network access; offline, the rest of the audit still
+runs.
+
+With --explain, each recommendation is followed by the evidence behind it:
+the concrete tools, paths, and versions that triggered it.
+
+With --format json (or --json), the audit is written as one JSON object
+instead: summary counts, clashes, shadowed installations, package manager
+statistics, every finding, and the recommendations with their severities and
+references, for scripts and agents to post-process. Empty lists are omitted.
+
+With --fix-script FILE, a shell script is also written that resolves the
+shadowed installations found: the brew uninstall, npm uninstall -g, pipx
+uninstall (and so on) commands that remove the copies that never run, or the
+older copy that runs when a shadowed one is newer. Each command is preceded by
+comments explaining it. Copies owned by the operating system are left alone,
+as are version manager shims: when a shim runs an older version, the script
+notes the pyenv global/local (or asdf, mise, nvm, volta) command that selects
+another one. Commands that delete files no package manager owns, or uninstall a
+package that still provides tools in use, are commented out for review.
+--dry-run prints that plan instead of the report and writes nothing.
+
+With --fail-on SEVERITY, the audit exits with status 1 when any
+recommendation is at least that severe (low, medium, or high), after writing
+its report, so it can gate CI jobs. It exits with status 2 when it cannot
+run.`,
 	Example: `  # Run audit and display to console
   cli-ai audit
 
   # Save audit report to file
   cli-ai audit --output cli-audit.md
 
+  # Show the tools and paths behind each recommendation
+  cli-ai audit --explain
+
+  # Also flag deprecated or unpublished npm packages
+  cli-ai audit --check-registry
+
+  # Structured audit for scripts
+  cli-ai audit --format json | jq '.recommendations[] | select(.severity == "high")'
+
+  # Preview the commands that would resolve shadowed installations
+  cli-ai audit --dry-run
+
+  # Write them to a script to review and run
+  cli-ai audit --fix-script fix-cli.sh
+
+  # Also find duplicate binaries by content
+  cli-ai audit --with-hash
+
+  # Fail a CI job on high-severity recommendations
+  cli-ai audit --fail-on high
+
   # Save with custom name
   cli-ai audit -o my-system-audit.md`,
 	Run: func(cmd *cobra.Command, args []string) {
-		s := scanner.New()
+		format := auditFormat
+		if auditJSON {
+			format = formatJSON
+		}
+		if format != formatMarkdown && format != formatJSON {
+			cmd.PrintErrf("Error: unknown format %q (valid: %s, %s)\n", format, formatMarkdown, formatJSON)
+			os.Exit(exitError)
+		}
+		switch auditFailOn {
+		case "", "low", "medium", "high":
+		default:
+			cmd.PrintErrf("Error: unknown --fail-on severity %q (valid: low, medium, high)\n", auditFailOn)
+			os.Exit(exitError)
+		}
 
-		// Scan all tools
-		tools, err := s.ScanAllDetailed()
-		if err != nil {
+		s := newScanner(cmd)
+
+		// Scan every installation, including shadowed ones; if stopped
+		// early, audit what was found
+		tools, err := s.ScanAllOccurrences()
+		if err != nil && !isCancelled(err) {
 			cmd.PrintErrf("Error scanning tools: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitError)
 		}
+		scanner.CheckInterpreters(tools)
 
 		// Detect packages
-		detector := packages.NewDetector()
+		detector := newDetector(cmd)
 		pkgs, err := detector.DetectAll()
-		if err != nil {
+		if err != nil && !isCancelled(err) {
 			cmd.PrintErrf("Error detecting packages: %v\n", err)
-			os.Exit(1)
+			os.Exit(exitError)
 		}
 
 		// Link tools to packages
-		linker := packages.NewLinker(pkgs)
+		linker := newLinker(cmd, pkgs)
 		tools = linker.LinkTools(tools)
 
 		// Perform audit
-		report := performAudit(tools, pkgs)
+		result := performAudit(cmd.Context(), tools, pkgs, detector, auditWithHash, auditRegistry)
+		warnIfPartial(cmd)
+
+		if auditDryRun || auditFixPath != "" {
+			script := generateFixScript(result, tools)
+			if auditDryRun {
+				fmt.Fprint(os.Stdout, script)
+				exitOnAuditFindings(result)
+				return
+			}
+			if err := os.WriteFile(auditFixPath, []byte(script), 0755); err != nil {
+				cmd.PrintErrf("Error writing fix script: %v\n", err)
+				os.Exit(exitError)
+			}
+			fmt.Fprintf(os.Stderr, "✓ Fix script saved to: %s\n", auditFixPath)
+		}
+
+		var report string
+		if format == formatJSON {
+			data, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(exitError)
+			}
+			report = string(data) + "\n"
+		} else {
+			report = generateMarkdownReport(result, auditExplain)
+		}
 
 		// Output report
 		if auditOutput != "" {
 			err := os.WriteFile(auditOutput, []byte(report), 0644)
 			if err != nil {
 				cmd.PrintErrf("Error writing audit report: %v\n", err)
-				os.Exit(1)
+				os.Exit(exitError)
 			}
 			fmt.Fprintf(os.Stdout, "✓ Audit report saved to: %s\n", auditOutput)
 		} else {
 			fmt.Fprint(os.Stdout, report)
 		}
+		exitOnAuditFindings(result)
 	},
 }
 
+// auditSeverityRanks orders recommendation severities for --fail-on
+var auditSeverityRanks = map[string]int{"info": 0, "low": 1, "medium": 2, "high": 3}
+
+// exitOnAuditFindings exits with exitFindings when a recommendation is at
+// least as severe as --fail-on
+func exitOnAuditFindings(result AuditResult) {
+	if auditFailOn == "" {
+		return
+	}
+	for _, rec := range result.Recommendations {
+		if auditSeverityRanks[rec.Severity] >= auditSeverityRanks[auditFailOn] {
+			os.Exit(exitFindings)
+		}
+	}
+}
+
 type AuditResult struct {
-	TotalTools        int
-	PackageManagedTools int
-	UnmanagedTools    int
-	Clashes           []ToolClash
-	ShadowedTools     []ShadowedTool
-	PackageManagers   []PackageManagerInfo
-	Recommendations   []Recommendation
+	// GeneratedAt is when the audit ran, in RFC 3339 format
+	GeneratedAt string `json:"generated_at"`
+	// Partial is set when the audit was stopped by --timeout or Ctrl-C
+	Partial             bool           `json:"partial,omitempty"`
+	TotalTools          int            `json:"total_tools"`
+	PackageManagedTools int            `json:"package_managed_tools"`
+	UnmanagedTools      int            `json:"unmanaged_tools"`
+	Clashes             []ToolClash    `json:"clashes,omitempty"`
+	ShadowedTools       []ShadowedTool `json:"shadowed_tools,omitempty"`
+	// NewerShadowed are the shadowed installations newer than the one that
+	// runs, typically an upgrade that is not taking effect
+	NewerShadowed     []ShadowedTool          `json:"newer_shadowed,omitempty"`
+	StaleShims        []shims.StaleShim       `json:"stale_shims,omitempty"`
+	ShimConflicts     []ShimConflict          `json:"shim_conflicts,omitempty"`
+	RuntimeVersions   []shims.RuntimeVersions `json:"runtime_versions,omitempty"`
+	BrokenTools       []BrokenTool            `json:"broken_tools,omitempty"`
+	DuplicateBinaries []duplicates.Group      `json:"duplicate_binaries,omitempty"`
+	AppExecAliases    []models.Tool           `json:"app_exec_aliases,omitempty"`
+	Shortcuts         []models.Tool           `json:"shortcuts,omitempty"`
+	VersionedVariants []variants.Group        `json:"versioned_variants,omitempty"`
+	Categories        []categories.Count      `json:"categories,omitempty"`
+	BuiltinCollisions []BuiltinCollision      `json:"builtin_collisions,omitempty"`
+	UnlinkedKegs      []packages.UnlinkedKeg  `json:"unlinked_kegs,omitempty"`
+	OrphanedFormulae  []string                `json:"orphaned_formulae,omitempty"`
+	PipVersionSkew    []packages.PipSkew      `json:"pip_version_skew,omitempty"`
+	NPMStatus         []outdated.NPMStatus    `json:"npm_status,omitempty"`
+	// RegistryError is why registry checks could not complete, e.g. offline
+	RegistryError   string               `json:"registry_error,omitempty"`
+	PackageManagers []PackageManagerInfo `json:"package_managers,omitempty"`
+	Recommendations []Recommendation     `json:"recommendations"`
 }
 
 type ToolClash struct {
-	ToolName      string
-	Installations []InstallationInfo
+	ToolName      string                    `json:"tool_name"`
+	Installations []models.InstallationInfo `json:"installations"`
+}
+
+type ShadowedTool struct {
+	ToolName        string `json:"tool_name"`
+	ActivePath      string `json:"active_path"`
+	ShadowedPath    string `json:"shadowed_path"`
+	ActivePackage   string `json:"active_package,omitempty"`
+	ShadowedPackage string `json:"shadowed_package,omitempty"`
+	// Versions are the package versions, or the version reported by the
+	// binary when it is not package-managed or its package is not the
+	// tool itself (see comparableVersion); empty when unknown
+	ActiveVersion   string `json:"active_version,omitempty"`
+	ShadowedVersion string `json:"shadowed_version,omitempty"`
 }
 
-type InstallationInfo struct {
-	Path           string
-	PackageName    string
-	PackageManager string
-	Version        string
-	IsActive       bool
+// ShimConflict is a tool provided both by a version manager's shim and by
+// a package manager such as brew, so only one of them runs
+type ShimConflict struct {
+	ToolName    string `json:"tool_name"`
+	ShimPath    string `json:"shim_path"`
+	ShimManager string `json:"shim_manager"`
+	Plugin      string `json:"plugin,omitempty"`
+	ShimVersion string `json:"shim_version,omitempty"`
+	// ShimTarget is the binary the shim runs
+	ShimTarget   string `json:"shim_target,omitempty"`
+	OtherPath    string `json:"other_path"`
+	OtherManager string `json:"other_manager"`
+	OtherPackage string `json:"other_package"`
+	OtherVersion string `json:"other_version,omitempty"`
+	// ShimWins is set when the shim is earlier in PATH, so the package
+	// manager's copy never runs
+	ShimWins bool `json:"shim_wins"`
 }
 
-type ShadowedTool struct {
-	ToolName       string
-	ActivePath     string
-	ShadowedPath   string
-	ActivePackage  string
-	ShadowedPackage string
+type BrokenTool struct {
+	ToolName string `json:"tool_name"`
+	Path     string `json:"path"`
+	Reason   string `json:"reason"`
+}
+
+// BuiltinCollision is a PATH executable that shares its name with a shell
+// builtin, so typing the name runs the builtin instead
+type BuiltinCollision struct {
+	ToolName string   `json:"tool_name"`
+	Path     string   `json:"path"`
+	Shells   []string `json:"shells"`
 }
 
 type PackageManagerInfo struct {
-	Name         string
-	PackageCount int
-	ToolCount    int
+	Name         string `json:"name"`
+	PackageCount int    `json:"package_count"`
+	ToolCount    int    `json:"tool_count"`
 }
 
 type Recommendation struct {
-	Severity string // "high", "medium", "low"
-	Category string
-	Issue    string
-	Action   string
+	Severity string `json:"severity"` // "high", "medium", "low"
+	Category string `json:"category"`
+	Issue    string `json:"issue"`
+	Action   string `json:"action"`
+	// References are the concrete tools, paths, or versions behind the
+	// recommendation, one per entry
+	References []string `json:"references,omitempty"`
 }
 
-func performAudit(tools []models.Tool, pkgs []packages.Package) string {
-	result := AuditResult{}
+// shimDescription names the manager, plugin, and version behind a shim,
+// such as "asdf nodejs 20.11.0"
+func shimDescription(conflict ShimConflict) string {
+	return strings.TrimSpace(strings.Join([]string{conflict.ShimManager, conflict.Plugin, conflict.ShimVersion}, " "))
+}
+
+// maxExplainedReferences caps how many references --explain prints per
+// recommendation in the markdown report
+const maxExplainedReferences = 50
+
+// performAudit analyzes the tools and packages.
+// tools holds every installation in PATH order, as ScanAllOccurrences
+// returns them; clashes, shadowing, and broken installations are found
+// across all of them, and everything else considers only the installations
+// that run. Probes that run tools or package managers stop when ctx is
+// cancelled, and the report is then marked partial. The pip environments
+// of the Python interpreters are found with detector, through its package
+// cache.
+func performAudit(ctx context.Context, occurrences []models.Tool, pkgs []packages.Package, detector *packages.Detector, withHash, checkRegistry bool) AuditResult {
+	result := AuditResult{GeneratedAt: time.Now().Format(time.RFC3339)}
+
+	var tools []models.Tool
+	for _, tool := range occurrences {
+		if !tool.Shadowed {
+			tools = append(tools, tool)
+		}
+	}
 
 	// Count tools
 	result.TotalTools = len(tools)
@@ -135,10 +361,83 @@ func performAudit(tools []models.Tool, pkgs []packages.Package) string {
 	}
 
 	// Find clashes
-	result.Clashes = findClashes(tools)
+	result.Clashes = findClashes(occurrences)
 
 	// Find shadowed tools
-	result.ShadowedTools = findShadowedTools(tools)
+	result.ShadowedTools = findShadowedTools(occurrences)
+	result.NewerShadowed = findNewerShadowed(ctx, result.ShadowedTools)
+
+	// Find broken installations flagged by the scanner
+	for _, tool := range occurrences {
+		if tool.Broken {
+			result.BrokenTools = append(result.BrokenTools, BrokenTool{
+				ToolName: tool.Name,
+				Path:     tool.Path,
+				Reason:   tool.BrokenReason,
+			})
+		}
+	}
+
+	// Find separate copies of one binary under different names
+	if withHash {
+		result.DuplicateBinaries = duplicates.FindByContent(tools)
+	}
+
+	// Find Windows App Execution Aliases and .lnk shortcuts posing as
+	// installed tools
+	for _, tool := range tools {
+		if tool.AppExecAlias {
+			result.AppExecAliases = append(result.AppExecAliases, tool)
+		}
+		if tool.Shortcut {
+			result.Shortcuts = append(result.Shortcuts, tool)
+		}
+	}
+
+	// Find brew formulae that are installed but not linked onto PATH
+	result.UnlinkedKegs = packages.UnlinkedKegs(pkgs)
+
+	// Find brew formulae left behind as dependencies of since-removed formulae
+	result.OrphanedFormulae = packages.BrewOrphans(ctx, pkgs)
+
+	// Find pip packages installed at different versions under different
+	// Python interpreters, from the package cache when it is on
+	result.PipVersionSkew = packages.PipVersionSkew(detector.DetectPipEnvironments(scanner.New().GetPaths()))
+
+	// Find global npm packages that are deprecated or no longer published
+	if checkRegistry {
+		checker := outdated.NewChecker()
+		checker.SetContext(ctx)
+		statuses, err := checker.CheckNPMStatus(pkgs)
+		result.NPMStatus = statuses
+		if err != nil {
+			result.RegistryError = err.Error()
+			fmt.Fprintf(os.Stderr, "Warning: %v; registry checks are incomplete\n", err)
+		}
+	}
+
+	// Find executables that shell builtins take precedence over
+	for _, tool := range tools {
+		if shells := builtins.Shells(tool.Name); len(shells) > 0 {
+			result.BuiltinCollisions = append(result.BuiltinCollisions, BuiltinCollision{
+				ToolName: tool.Name,
+				Path:     tool.Path,
+				Shells:   shells,
+			})
+		}
+	}
+
+	// Break tools down by category
+	result.Categories = categories.Summarize(tools)
+
+	// Group version-suffixed variants (python3.11, python3.12, ...)
+	result.VersionedVariants = variants.Find(tools)
+
+	// Find version-manager shims pointing at uninstalled versions
+	shimDirs := shims.DetectDirs()
+	result.StaleShims = shims.FindStale(shimDirs)
+	result.ShimConflicts = findShimConflicts(occurrences)
+	result.RuntimeVersions = shims.ListRuntimes(shimDirs)
 
 	// Analyze package managers
 	result.PackageManagers = analyzePackageManagers(pkgs, tools)
@@ -146,65 +445,206 @@ func performAudit(tools []models.Tool, pkgs []packages.Package) string {
 	// Generate recommendations
 	result.Recommendations = generateRecommendations(result, tools, pkgs)
 
-	// Generate markdown report
-	return generateMarkdownReport(result)
+	result.Partial = ctx.Err() != nil
+
+	return result
 }
 
 func findClashes(tools []models.Tool) []ToolClash {
 	toolGroups := make(map[string][]models.Tool)
 	for _, tool := range tools {
-		if tool.PackageName != "" {
-			toolGroups[tool.Name] = append(toolGroups[tool.Name], tool)
-		}
+		toolGroups[tool.Name] = append(toolGroups[tool.Name], tool)
 	}
 
 	var clashes []ToolClash
 	for name, instances := range toolGroups {
-		packageSeen := make(map[string]bool)
+		if !isClash(instances) {
+			continue
+		}
+
+		clash := ToolClash{ToolName: name}
 		for _, instance := range instances {
-			packageSeen[instance.PackageName] = true
-		}
-
-		if len(packageSeen) > 1 {
-			clash := ToolClash{ToolName: name}
-			for i, instance := range instances {
-				clash.Installations = append(clash.Installations, InstallationInfo{
-					Path:           instance.Path,
-					PackageName:    instance.PackageName,
-					PackageManager: instance.PackageManager,
-					Version:        instance.PackageVersion,
-					IsActive:       i == 0,
-				})
-			}
-			clashes = append(clashes, clash)
+			clash.Installations = append(clash.Installations, models.InstallationInfo{
+				Path:           instance.Path,
+				PackageName:    instance.PackageName,
+				PackageManager: packages.Provenance(instance),
+				Version:        instance.PackageVersion,
+				IsActive:       !instance.Shadowed,
+				Broken:         instance.Broken,
+				BrokenReason:   instance.BrokenReason,
+			})
 		}
+		clashes = append(clashes, clash)
 	}
 
+	sort.Slice(clashes, func(i, j int) bool {
+		return clashes[i].ToolName < clashes[j].ToolName
+	})
+
 	return clashes
 }
 
+// isClash reports whether installations of one tool come from more than one
+// source (e.g. Xcode's /usr/bin/git, Homebrew, and MacPorts) and at least
+// one of them is package-managed
+func isClash(instances []models.Tool) bool {
+	sources := make(map[string]bool)
+	managed := false
+	for _, instance := range instances {
+		sources[packages.Provenance(instance)+":"+instance.PackageName] = true
+		if instance.PackageName != "" {
+			managed = true
+		}
+	}
+	return managed && len(sources) > 1
+}
+
+// findShadowedTools pairs each shadowed installation with the one that
+// runs instead, in PATH order
 func findShadowedTools(tools []models.Tool) []ShadowedTool {
-	toolGroups := make(map[string][]models.Tool)
+	active := make(map[string]models.Tool)
 	for _, tool := range tools {
-		toolGroups[tool.Name] = append(toolGroups[tool.Name], tool)
+		if !tool.Shadowed {
+			active[tool.Name] = tool
+		}
 	}
 
 	var shadowed []ShadowedTool
-	for name, instances := range toolGroups {
-		if len(instances) > 1 {
-			for i := 1; i < len(instances); i++ {
-				shadowed = append(shadowed, ShadowedTool{
-					ToolName:        name,
-					ActivePath:      instances[0].Path,
-					ShadowedPath:    instances[i].Path,
-					ActivePackage:   instances[0].PackageName,
-					ShadowedPackage: instances[i].PackageName,
+	for _, tool := range tools {
+		winner, ok := active[tool.Name]
+		if !tool.Shadowed || !ok {
+			continue
+		}
+		shadowed = append(shadowed, ShadowedTool{
+			ToolName:        tool.Name,
+			ActivePath:      winner.Path,
+			ShadowedPath:    tool.Path,
+			ActivePackage:   winner.PackageName,
+			ShadowedPackage: tool.PackageName,
+			ActiveVersion:   comparableVersion(winner, tool),
+			ShadowedVersion: comparableVersion(tool, winner),
+		})
+	}
+
+	return shadowed
+}
+
+// findShimConflicts pairs each version manager shim with the installations
+// of the same tool that a package manager owns, in PATH order
+func findShimConflicts(tools []models.Tool) []ShimConflict {
+	byName := make(map[string][]models.Tool)
+	var names []string
+	for _, tool := range tools {
+		if byName[tool.Name] == nil {
+			names = append(names, tool.Name)
+		}
+		byName[tool.Name] = append(byName[tool.Name], tool)
+	}
+	sort.Strings(names)
+
+	var conflicts []ShimConflict
+	for _, name := range names {
+		for _, shim := range byName[name] {
+			if shim.Shim == nil {
+				continue
+			}
+			for _, other := range byName[name] {
+				if other.Shim != nil || other.PackageName == "" {
+					continue
+				}
+				conflicts = append(conflicts, ShimConflict{
+					ToolName:     name,
+					ShimPath:     shim.Path,
+					ShimManager:  shim.Shim.Manager,
+					Plugin:       shim.Shim.Plugin,
+					ShimVersion:  shim.Shim.Version,
+					ShimTarget:   shim.Shim.Target,
+					OtherPath:    other.Path,
+					OtherManager: other.PackageManager,
+					OtherPackage: other.PackageName,
+					OtherVersion: knownVersion(other),
+					ShimWins:     shim.PathRank < other.PathRank,
 				})
 			}
 		}
 	}
+	return conflicts
+}
 
-	return shadowed
+// knownVersion returns a tool's version without running it: its package
+// version, or the version in its probed --version output
+func knownVersion(tool models.Tool) string {
+	if tool.PackageVersion != "" {
+		return tool.PackageVersion
+	}
+	return semver.Extract(tool.Version)
+}
+
+// comparableVersion returns a tool's version, known without running it,
+// for comparing with another installation of the same name. Its package
+// version is only used when both belong to the same package, or when the
+// package is named after the tool: a pyenv shim for pip is owned by the
+// python package, whose version says nothing about the pip it runs. In
+// that case the version comes from the tool's own --version output, which
+// findNewerShadowed probes when it is missing.
+func comparableVersion(tool, other models.Tool) string {
+	if tool.PackageVersion != "" &&
+		(tool.PackageName == other.PackageName || strings.EqualFold(tool.PackageName, tool.Name)) {
+		return tool.PackageVersion
+	}
+	return semver.Extract(tool.Version)
+}
+
+// findNewerShadowed fills in missing versions of shadowed installations and
+// returns those newer than the active one. Binaries are only run with
+// --version when at least one side is package-managed, since that is where
+// an upgrade can silently lose to an older copy, and never when both paths
+// are the same file.
+func findNewerShadowed(ctx context.Context, shadowed []ShadowedTool) []ShadowedTool {
+	c := collector.NewWithOptions(collectorOptions(ctx))
+
+	var newer []ShadowedTool
+	for i := range shadowed {
+		shadow := &shadowed[i]
+		if ctx.Err() != nil {
+			break
+		}
+		if shadow.ActivePackage == "" && shadow.ShadowedPackage == "" {
+			continue
+		}
+		if sameFile(shadow.ActivePath, shadow.ShadowedPath) {
+			continue
+		}
+		if shadow.ActiveVersion == "" {
+			shadow.ActiveVersion = semver.Extract(c.ProbeVersion(shadow.ToolName, shadow.ActivePath))
+		}
+		if shadow.ShadowedVersion == "" {
+			shadow.ShadowedVersion = semver.Extract(c.ProbeVersion(shadow.ToolName, shadow.ShadowedPath))
+		}
+		if shadow.ActiveVersion != "" && shadow.ShadowedVersion != "" &&
+			semver.Newer(shadow.ShadowedVersion, shadow.ActiveVersion) {
+			newer = append(newer, *shadow)
+		}
+	}
+
+	sort.Slice(newer, func(i, j int) bool {
+		return newer[i].ToolName < newer[j].ToolName
+	})
+	return newer
+}
+
+// sameFile reports whether two paths resolve to the same file, as with
+// /bin and /usr/bin on merged-/usr systems
+func sameFile(a, b string) bool {
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(aInfo, bInfo)
 }
 
 func analyzePackageManagers(pkgs []packages.Package, tools []models.Tool) []PackageManagerInfo {
@@ -244,32 +684,300 @@ func generateRecommendations(result AuditResult, tools []models.Tool, pkgs []pac
 
 	// Check for clashes
 	if len(result.Clashes) > 0 {
+		var refs []string
+		for _, clash := range result.Clashes {
+			var sources []string
+			for _, inst := range clash.Installations {
+				sources = append(sources, fmt.Sprintf("%s (%s)", inst.Path, inst.PackageManager))
+			}
+			refs = append(refs, fmt.Sprintf("%s: %s", clash.ToolName, strings.Join(sources, ", ")))
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "high",
+			Category:   "Installation Conflicts",
+			Issue:      fmt.Sprintf("Found %d tools with multiple installations from different package managers", len(result.Clashes)),
+			Action:     "Review conflicting installations and uninstall duplicates to avoid version conflicts. Use `cli-ai debug --clashes` for details.",
+			References: refs,
+		})
+	}
+
+	// Suggest concrete PATH reordering for clashes where a managed install loses
+	recs = append(recs, pathOrderRecommendations(result.Clashes, pkgs)...)
+
+	// Check for upgrades that lose to an older copy earlier in PATH
+	if len(result.NewerShadowed) > 0 {
+		var refs []string
+		for _, shadow := range result.NewerShadowed {
+			refs = append(refs, fmt.Sprintf("%s: %s (%s) runs instead of %s (%s)",
+				shadow.ToolName, shadow.ActivePath, shadow.ActiveVersion, shadow.ShadowedPath, shadow.ShadowedVersion))
+		}
 		recs = append(recs, Recommendation{
-			Severity: "high",
-			Category: "Installation Conflicts",
-			Issue:    fmt.Sprintf("Found %d tools with multiple installations from different package managers", len(result.Clashes)),
-			Action:   "Review conflicting installations and uninstall duplicates to avoid version conflicts. Use `cli-ai debug --clashes` for details.",
+			Severity:   "high",
+			Category:   "Shadowed Installations",
+			Issue:      fmt.Sprintf("Your upgrade isn't taking effect: %d tools have a newer installation shadowed by an older one earlier in PATH", len(result.NewerShadowed)),
+			Action:     "Remove the older installations, or move the directories holding the newer ones earlier in PATH. Use `cli-ai which <tool>` to see which copy runs.",
+			References: refs,
 		})
 	}
 
 	// Check for shadowed tools
 	if len(result.ShadowedTools) > 0 {
+		var refs []string
+		for _, shadow := range result.ShadowedTools {
+			refs = append(refs, fmt.Sprintf("%s: %s shadowed by %s", shadow.ToolName, shadow.ShadowedPath, shadow.ActivePath))
+		}
 		recs = append(recs, Recommendation{
-			Severity: "medium",
-			Category: "Shadowed Installations",
-			Issue:    fmt.Sprintf("Found %d tools with shadowed installations that are not being used", len(result.ShadowedTools)),
-			Action:   "Remove unused installations to free up disk space and reduce confusion. The shadowed installations are not in use.",
+			Severity:   "medium",
+			Category:   "Shadowed Installations",
+			Issue:      fmt.Sprintf("Found %d tools with shadowed installations that are not being used", len(result.ShadowedTools)),
+			Action:     "Remove unused installations to free up disk space and reduce confusion. The shadowed installations are not in use.",
+			References: refs,
+		})
+	}
+
+	// Check for stale version-manager shims
+	for _, stale := range result.StaleShims {
+		issue := fmt.Sprintf("%s %s is selected via %s but not installed; %d shim(s) will fail",
+			stale.Runtime, stale.Version, stale.Manager, len(stale.Shims))
+		if stale.Source != "" {
+			issue = fmt.Sprintf("%s %s is selected in %s but not installed; %d %s shim(s) will fail",
+				stale.Runtime, stale.Version, stale.Source, len(stale.Shims), stale.Manager)
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "high",
+			Category:   "Version Managers",
+			Issue:      issue,
+			Action:     fmt.Sprintf("Install the missing version (`%s`) or select an installed one.", stale.InstallCommand()),
+			References: stale.Shims,
+		})
+	}
+
+	// Check for shims competing with package manager installs
+	var shimsLosing, shimsWinning []ShimConflict
+	for _, conflict := range result.ShimConflicts {
+		if conflict.ShimWins {
+			shimsWinning = append(shimsWinning, conflict)
+		} else {
+			shimsLosing = append(shimsLosing, conflict)
+		}
+	}
+	if len(shimsLosing) > 0 {
+		var names, refs []string
+		for _, conflict := range shimsLosing {
+			names = append(names, conflict.ToolName)
+			refs = append(refs, fmt.Sprintf("%s: %s (%s %s) runs instead of %s (%s)",
+				conflict.ToolName, conflict.OtherPath, conflict.OtherManager, conflict.OtherPackage, conflict.ShimPath, shimDescription(conflict)))
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "high",
+			Category:   "Version Managers",
+			Issue:      fmt.Sprintf("%d tools selected by a version manager run from another package manager instead, so the selected versions are ignored: %s", len(names), listNames(names)),
+			Action:     "Move the shims directory ahead of the other manager's directory in PATH, or uninstall the other copies if the version manager should own these tools.",
+			References: refs,
+		})
+	}
+	if len(shimsWinning) > 0 {
+		var names, refs []string
+		for _, conflict := range shimsWinning {
+			names = append(names, conflict.ToolName)
+			refs = append(refs, fmt.Sprintf("%s: %s (%s %s) is shadowed by %s (%s)",
+				conflict.ToolName, conflict.OtherPath, conflict.OtherManager, conflict.OtherPackage, conflict.ShimPath, shimDescription(conflict)))
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "low",
+			Category:   "Version Managers",
+			Issue:      fmt.Sprintf("%d tools are installed by a package manager but a version manager's shim runs instead: %s", len(names), listNames(names)),
+			Action:     "The package manager's copies never run from the shell. Uninstall them unless another package depends on them (`brew uses --installed <formula>` shows what does).",
+			References: refs,
+		})
+	}
+
+	// Check for broken installations
+	if len(result.BrokenTools) > 0 {
+		var refs []string
+		for _, broken := range result.BrokenTools {
+			refs = append(refs, fmt.Sprintf("%s: %s", broken.Path, broken.Reason))
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "medium",
+			Category:   "Broken Installations",
+			Issue:      fmt.Sprintf("Found %d executables that cannot run (e.g. zero-byte files from failed downloads, or scripts whose interpreter was removed)", len(result.BrokenTools)),
+			Action:     "Reinstall the affected tools with their package manager, reinstall the missing interpreter, or delete the broken files. See the Broken Installations section for paths.",
+			References: refs,
 		})
 	}
 
+	// Check for unlinked Homebrew kegs
+	if len(result.UnlinkedKegs) > 0 {
+		var names, commands, refs []string
+		for _, keg := range result.UnlinkedKegs {
+			names = append(names, keg.Name)
+			commands = append(commands, "`"+keg.LinkCommand()+"`")
+			refs = append(refs, fmt.Sprintf("%s %s: %s", keg.Name, keg.Version, strings.Join(keg.Binaries, ", ")))
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "medium",
+			Category:   "Homebrew",
+			Issue:      fmt.Sprintf("%d formulae are installed but unlinked, so their tools are not on PATH: %s", len(names), strings.Join(names, ", ")),
+			Action:     fmt.Sprintf("Link them with %s, or uninstall them if they were unlinked on purpose.", strings.Join(commands, ", ")),
+			References: refs,
+		})
+	}
+
+	// Check for orphaned Homebrew dependencies
+	if len(result.OrphanedFormulae) > 0 {
+		recs = append(recs, Recommendation{
+			Severity:   "low",
+			Category:   "Homebrew",
+			Issue:      fmt.Sprintf("%d formulae were installed as dependencies and are no longer needed by anything: %s", len(result.OrphanedFormulae), strings.Join(result.OrphanedFormulae, ", ")),
+			Action:     "Reclaim disk space with `brew autoremove` (preview with `brew autoremove --dry-run`).",
+			References: result.OrphanedFormulae,
+		})
+	}
+
+	// Check for pip packages skewed across interpreters
+	if len(result.PipVersionSkew) > 0 {
+		var names, refs []string
+		for _, skew := range result.PipVersionSkew {
+			names = append(names, skew.Name)
+			for _, install := range skew.Installs {
+				refs = append(refs, fmt.Sprintf("%s %s: %s", skew.Name, install.Version, install.Interpreter))
+			}
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "medium",
+			Category:   "Python Packages",
+			Issue:      fmt.Sprintf("%d pip packages are installed at different versions under different Python interpreters: %s", len(names), strings.Join(names, ", ")),
+			Action:     "Code can import one version under one interpreter and another elsewhere. Upgrade the packages to the same version (`python -m pip install -U <pkg>` for each interpreter), or uninstall them from interpreters that do not need them. See the Python Version Skew section.",
+			References: refs,
+		})
+	}
+
+	// Check for npm packages pulled from the registry or abandoned
+	var unpublished, deprecated, notFound []string
+	var unpublishedRefs, deprecatedRefs, notFoundRefs []string
+	for _, status := range result.NPMStatus {
+		switch {
+		case status.Unpublished:
+			unpublished = append(unpublished, status.Name)
+			unpublishedRefs = append(unpublishedRefs, fmt.Sprintf("%s@%s: %s", status.Name, status.Version, status.Reason))
+		case status.NotFound:
+			notFound = append(notFound, status.Name)
+			notFoundRefs = append(notFoundRefs, fmt.Sprintf("%s@%s: %s", status.Name, status.Version, status.Reason))
+		default:
+			deprecated = append(deprecated, status.Name)
+			deprecatedRefs = append(deprecatedRefs, fmt.Sprintf("%s@%s: %s", status.Name, status.Version, status.Deprecated))
+		}
+	}
+	if len(unpublished) > 0 {
+		recs = append(recs, Recommendation{
+			Severity:   "high",
+			Category:   "Supply Chain",
+			Issue:      fmt.Sprintf("%d global npm packages are installed at versions no longer published on the registry: %s", len(unpublished), strings.Join(unpublished, ", ")),
+			Action:     "Versions are usually unpublished for security or legal reasons. Check why, then upgrade (`npm install -g <pkg>@latest`) or uninstall them. See the npm Package Status section.",
+			References: unpublishedRefs,
+		})
+	}
+	if len(notFound) > 0 {
+		recs = append(recs, Recommendation{
+			Severity:   "low",
+			Category:   "Supply Chain",
+			Issue:      fmt.Sprintf("%d global npm packages were not found on their registry: %s", len(notFound), strings.Join(notFound, ", ")),
+			Action:     "Usually they come from a private registry this check cannot read, or were installed from a tarball. If not, the package may have been removed; check where it came from with `npm ls -g --long`. See the npm Package Status section.",
+			References: notFoundRefs,
+		})
+	}
+	if len(deprecated) > 0 {
+		recs = append(recs, Recommendation{
+			Severity:   "medium",
+			Category:   "Supply Chain",
+			Issue:      fmt.Sprintf("%d global npm packages are deprecated by their maintainers: %s", len(deprecated), strings.Join(deprecated, ", ")),
+			Action:     "Deprecated packages no longer receive fixes. Follow the deprecation message to a replacement, or upgrade if only the installed version is deprecated. See the npm Package Status section.",
+			References: deprecatedRefs,
+		})
+	}
+
+	// Check for App Execution Aliases
+	if len(result.AppExecAliases) > 0 {
+		var names, refs []string
+		for _, tool := range result.AppExecAliases {
+			names = append(names, tool.Name)
+			refs = append(refs, tool.Path)
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "low",
+			Category:   "Not Actually Installed",
+			Issue:      fmt.Sprintf("%d commands are Windows App Execution Aliases, not installed tools: %s", len(names), strings.Join(names, ", ")),
+			Action:     "Running these opens the Microsoft Store. Install the real tool, or turn the alias off in Settings > Apps > Advanced app settings > App execution aliases.",
+			References: refs,
+		})
+	}
+
+	// Check for .lnk shortcuts run through PATHEXT
+	if len(result.Shortcuts) > 0 {
+		var names, refs []string
+		for _, tool := range result.Shortcuts {
+			names = append(names, tool.Name)
+			refs = append(refs, tool.Path)
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "low",
+			Category:   "Not Actually Installed",
+			Issue:      fmt.Sprintf("%d commands are .lnk shortcuts, not installed tools: %s", len(names), strings.Join(names, ", ")),
+			Action:     "cmd.exe opens these through the shell, but scripts and other programs cannot run them. Put the directory of the program they point to on PATH instead.",
+			References: refs,
+		})
+	}
+
+	// Check for duplicate binaries by content
+	if len(result.DuplicateBinaries) > 0 {
+		var wasted int64
+		var refs []string
+		for _, group := range result.DuplicateBinaries {
+			wasted += group.Wasted()
+			var paths []string
+			for _, tool := range group.Tools {
+				paths = append(paths, tool.Path)
+			}
+			refs = append(refs, fmt.Sprintf("%s: %s", group.SHA256[:12], strings.Join(paths, ", ")))
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "low",
+			Category:   "Duplicate Binaries",
+			Issue:      fmt.Sprintf("Found %d binaries installed as separate copies under different names, wasting %s", len(result.DuplicateBinaries), formatBytes(wasted)),
+			Action:     "Replace the extra copies with symlinks to one file, or uninstall the redundant names. See the Duplicate Binaries section.",
+			References: refs,
+		})
+	}
+
+	// Check for runtime version sprawl
+	for _, rv := range result.RuntimeVersions {
+		if len(rv.Versions) > 3 {
+			recs = append(recs, Recommendation{
+				Severity:   "low",
+				Category:   "Runtime Versions",
+				Issue:      rv.Summary(),
+				Action:     fmt.Sprintf("Uninstall %s versions you no longer use to reclaim disk space (`%s uninstall ...`).", rv.Runtime, rv.Manager),
+				References: rv.Versions,
+			})
+		}
+	}
+
 	// Check for unmanaged tools
 	unmanagedPercent := float64(result.UnmanagedTools) / float64(result.TotalTools) * 100
 	if unmanagedPercent > 20 {
+		var refs []string
+		for _, tool := range tools {
+			if tool.PackageName == "" {
+				refs = append(refs, tool.Path)
+			}
+		}
 		recs = append(recs, Recommendation{
-			Severity: "low",
-			Category: "Package Management",
-			Issue:    fmt.Sprintf("%.1f%% of tools (%d/%d) are not managed by a package manager", unmanagedPercent, result.UnmanagedTools, result.TotalTools),
-			Action:   "Consider installing tools via package managers (brew, npm, pip) for easier updates and management.",
+			Severity:   "low",
+			Category:   "Package Management",
+			Issue:      fmt.Sprintf("%.1f%% of tools (%d/%d) are not managed by a package manager", unmanagedPercent, result.UnmanagedTools, result.TotalTools),
+			Action:     "Consider installing tools via package managers (brew, npm, pip) for easier updates and management.",
+			References: refs,
 		})
 	}
 
@@ -293,15 +1001,102 @@ func generateRecommendations(result AuditResult, tools []models.Tool, pkgs []pac
 		})
 	}
 
+	// Note executables hidden behind shell builtins. This is informational,
+	// so it does not count against "No issues detected" above.
+	if len(result.BuiltinCollisions) > 0 {
+		var names, refs []string
+		for _, collision := range result.BuiltinCollisions {
+			names = append(names, collision.ToolName)
+			refs = append(refs, fmt.Sprintf("%s: builtin in %s", collision.Path, strings.Join(collision.Shells, ", ")))
+		}
+		recs = append(recs, Recommendation{
+			Severity:   "info",
+			Category:   "Shell Builtins",
+			Issue:      fmt.Sprintf("%d executables share their name with a shell builtin, which runs instead when typed: %s", len(names), strings.Join(names, ", ")),
+			Action:     "Nothing to fix. To run the executable rather than the builtin, call it by full path or with `env <name>`.",
+			References: refs,
+		})
+	}
+
+	return recs
+}
+
+// pathOrderRecommendations turns clashes into PATH ordering advice, naming
+// the directories to swap (e.g. "move /opt/homebrew/bin (brew) before
+// /usr/bin (system)") and the tools affected
+func pathOrderRecommendations(clashes []ToolClash, pkgs []packages.Package) []Recommendation {
+	if len(clashes) == 0 {
+		return nil
+	}
+
+	s := scanner.New()
+	dirs := pathenv.Analyze(s.GetPaths())
+	pathenv.AssignOwners(dirs, packages.WithPackageDirs(packages.BinDirs(), pkgs), shims.DetectDirs())
+	byPath := make(map[string]pathenv.Dir)
+	for _, dir := range dirs {
+		if dir.DuplicateOf < 0 {
+			byPath[filepath.Clean(dir.Path)] = dir
+		}
+	}
+
+	type move struct{ winner, loser string }
+	affected := make(map[move][]string)
+	var order []move
+	for _, clash := range clashes {
+		var active models.InstallationInfo
+		for _, inst := range clash.Installations {
+			if inst.IsActive {
+				active = inst
+			}
+		}
+		activeDir, ok := byPath[filepath.Dir(active.Path)]
+		if !ok {
+			continue
+		}
+
+		for _, inst := range clash.Installations {
+			dir, ok := byPath[filepath.Dir(inst.Path)]
+			if inst.IsActive || inst.PackageName == "" || !ok || dir.Owner == activeDir.Owner {
+				continue
+			}
+			m := move{winner: activeDir.Path, loser: dir.Path}
+			if _, seen := affected[m]; !seen {
+				order = append(order, m)
+			}
+			affected[m] = append(affected[m], clash.ToolName)
+		}
+	}
+
+	var recs []Recommendation
+	for _, m := range order {
+		winner, loser := byPath[filepath.Clean(m.winner)], byPath[filepath.Clean(m.loser)]
+		var references []string
+		for _, name := range affected[m] {
+			references = append(references, fmt.Sprintf("%s: %s shadowed by %s",
+				name, filepath.Join(loser.Path, name), filepath.Join(winner.Path, name)))
+		}
+		recs = append(recs, Recommendation{
+			Severity: "medium",
+			Category: "PATH Order",
+			Issue: fmt.Sprintf("%s versions of %s are shadowed by %s (%s)",
+				loser.Owner, strings.Join(affected[m], ", "), winner.Path, winner.Owner),
+			Action: fmt.Sprintf("If you want the %s versions, move %s (%s) before %s (%s) in your PATH; otherwise uninstall them.",
+				loser.Owner, loser.Path, loser.Owner, winner.Path, winner.Owner),
+			References: references,
+		})
+	}
 	return recs
 }
 
-func generateMarkdownReport(result AuditResult) string {
+func generateMarkdownReport(result AuditResult, explain bool) string {
 	var sb strings.Builder
 
 	// Header
 	sb.WriteString("# CLI Environment Audit Report\n\n")
 	sb.WriteString(fmt.Sprintf("**Generated:** %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	if result.Partial {
+		sb.WriteString("> ⚠️ **Partial report:** the audit was stopped early by `--timeout` or an interrupt, so some tools, packages, or checks are missing.\n\n")
+	}
 
 	// Executive Summary
 	sb.WriteString("## Executive Summary\n\n")
@@ -313,7 +1108,20 @@ func generateMarkdownReport(result AuditResult) string {
 		result.UnmanagedTools,
 		float64(result.UnmanagedTools)/float64(result.TotalTools)*100))
 	sb.WriteString(fmt.Sprintf("- **Installation Conflicts:** %d\n", len(result.Clashes)))
-	sb.WriteString(fmt.Sprintf("- **Shadowed Installations:** %d\n\n", len(result.ShadowedTools)))
+	sb.WriteString(fmt.Sprintf("- **Shadowed Installations:** %d\n", len(result.ShadowedTools)))
+	sb.WriteString(fmt.Sprintf("- **Newer Versions Shadowed:** %d\n\n", len(result.NewerShadowed)))
+
+	// Tool Categories
+	if len(result.Categories) > 0 {
+		sb.WriteString("## Tool Categories\n\n")
+		sb.WriteString("| Category | Tools | Examples |\n")
+		sb.WriteString("|----------|-------|----------|\n")
+		for _, category := range result.Categories {
+			sb.WriteString(fmt.Sprintf("| %s | %d | %s |\n",
+				category.Category, category.Count, strings.Join(category.Examples, ", ")))
+		}
+		sb.WriteString("\n")
+	}
 
 	// Package Managers
 	sb.WriteString("## Package Managers\n\n")
@@ -341,13 +1149,25 @@ func generateMarkdownReport(result AuditResult) string {
 			sb.WriteString(fmt.Sprintf("### %d. %s %s - %s\n\n", i+1, icon, strings.ToUpper(rec.Severity), rec.Category))
 			sb.WriteString(fmt.Sprintf("**Issue:** %s\n\n", rec.Issue))
 			sb.WriteString(fmt.Sprintf("**Action:** %s\n\n", rec.Action))
+
+			if explain && len(rec.References) > 0 {
+				sb.WriteString("**Evidence:**\n\n")
+				for j, ref := range rec.References {
+					if j == maxExplainedReferences {
+						sb.WriteString(fmt.Sprintf("- ... and %d more\n", len(rec.References)-j))
+						break
+					}
+					sb.WriteString(fmt.Sprintf("- %s\n", ref))
+				}
+				sb.WriteString("\n")
+			}
 		}
 	}
 
 	// Installation Conflicts Details
 	if len(result.Clashes) > 0 {
 		sb.WriteString("## Installation Conflicts (Detailed)\n\n")
-		sb.WriteString("The following tools have multiple installations from different package managers:\n\n")
+		sb.WriteString("The following tools have multiple installations from different sources (package managers or the system):\n\n")
 
 		for _, clash := range result.Clashes {
 			sb.WriteString(fmt.Sprintf("### `%s`\n\n", clash.ToolName))
@@ -358,13 +1178,35 @@ func generateMarkdownReport(result AuditResult) string {
 				} else {
 					status = " (shadowed)"
 				}
-				sb.WriteString(fmt.Sprintf("- `%s` via **%s** (v%s)%s\n",
-					inst.Path, inst.PackageManager, inst.Version, status))
+				version := ""
+				if inst.Version != "" {
+					version = fmt.Sprintf(" (v%s)", inst.Version)
+				}
+				sb.WriteString(fmt.Sprintf("- `%s` via **%s**%s%s\n",
+					inst.Path, inst.PackageManager, version, status))
 			}
 			sb.WriteString("\n")
 		}
 	}
 
+	// Newer versions losing to older ones
+	if len(result.NewerShadowed) > 0 {
+		sb.WriteString("## Newer Versions Shadowed\n\n")
+		sb.WriteString("An older installation earlier in PATH runs instead of a newer one, so upgrading did not take effect:\n\n")
+		sb.WriteString("| Tool | Runs | Shadowed (newer) |\n")
+		sb.WriteString("|------|------|------------------|\n")
+
+		for _, shadow := range result.NewerShadowed {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s (%s) | %s (%s) |\n",
+				shadow.ToolName,
+				shadow.ActivePath,
+				shadow.ActiveVersion,
+				shadow.ShadowedPath,
+				shadow.ShadowedVersion))
+		}
+		sb.WriteString("\n")
+	}
+
 	// Shadowed Tools Details
 	if len(result.ShadowedTools) > 0 {
 		sb.WriteString("## Shadowed Installations (Detailed)\n\n")
@@ -383,6 +1225,174 @@ func generateMarkdownReport(result AuditResult) string {
 		sb.WriteString("\n")
 	}
 
+	// Broken Installations Details
+	if len(result.BrokenTools) > 0 {
+		sb.WriteString("## Broken Installations\n\n")
+		sb.WriteString("These executables are on PATH but cannot run:\n\n")
+		sb.WriteString("| Tool | Path | Problem |\n")
+		sb.WriteString("|------|------|---------|\n")
+
+		for _, broken := range result.BrokenTools {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s |\n", broken.ToolName, broken.Path, broken.Reason))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Duplicate Binaries Details
+	if len(result.DuplicateBinaries) > 0 {
+		sb.WriteString("## Duplicate Binaries (by content)\n\n")
+		sb.WriteString("These tools have different names but are separate copies of identical files (not symlinks or hardlinks):\n\n")
+
+		for _, group := range result.DuplicateBinaries {
+			var names []string
+			for _, tool := range group.Tools {
+				names = append(names, "`"+tool.Name+"`")
+			}
+			sb.WriteString(fmt.Sprintf("### %s (%s each, sha256 %s)\n\n", strings.Join(names, ", "), formatBytes(group.Size), group.SHA256[:12]))
+			for _, tool := range group.Tools {
+				sb.WriteString(fmt.Sprintf("- %s\n", tool.Path))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// Runtime Versions Details
+	if len(result.RuntimeVersions) > 0 {
+		sb.WriteString("## Runtime Versions\n\n")
+		sb.WriteString("Runtimes installed side by side by version managers:\n\n")
+		sb.WriteString("| Manager | Runtime | Installed | Active |\n")
+		sb.WriteString("|---------|---------|-----------|--------|\n")
+
+		for _, rv := range result.RuntimeVersions {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				rv.Manager, rv.Runtime, strings.Join(rv.Versions, ", "), rv.Active))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Unlinked Kegs Details
+	if len(result.UnlinkedKegs) > 0 {
+		sb.WriteString("## Unlinked Homebrew Formulae\n\n")
+		sb.WriteString("These formulae are installed but not linked, so their tools are not on PATH:\n\n")
+		sb.WriteString("| Formula | Version | Tools | Fix |\n")
+		sb.WriteString("|---------|---------|-------|-----|\n")
+
+		for _, keg := range result.UnlinkedKegs {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | `%s` |\n",
+				keg.Name, keg.Version, strings.Join(keg.Binaries, ", "), keg.LinkCommand()))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Orphaned Formulae Details
+	if len(result.OrphanedFormulae) > 0 {
+		sb.WriteString("## Orphaned Homebrew Dependencies\n\n")
+		sb.WriteString(fmt.Sprintf("%d formulae were installed only as dependencies and nothing installed on request needs them any more. `brew autoremove` removes them:\n\n", len(result.OrphanedFormulae)))
+		for _, name := range result.OrphanedFormulae {
+			sb.WriteString(fmt.Sprintf("- %s\n", name))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Pip Version Skew Details
+	if len(result.PipVersionSkew) > 0 {
+		sb.WriteString("## Python Version Skew\n\n")
+		sb.WriteString("These pip packages are installed at different versions under different interpreters:\n\n")
+		sb.WriteString("| Package | Version | Interpreter |\n")
+		sb.WriteString("|---------|---------|-------------|\n")
+
+		for _, skew := range result.PipVersionSkew {
+			for _, install := range skew.Installs {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", skew.Name, install.Version, install.Interpreter))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// npm Registry Status Details
+	if len(result.NPMStatus) > 0 || result.RegistryError != "" {
+		sb.WriteString("## npm Package Status\n\n")
+		if result.RegistryError != "" {
+			sb.WriteString(fmt.Sprintf("Registry checks are incomplete: %s\n\n", result.RegistryError))
+		}
+		if len(result.NPMStatus) > 0 {
+			sb.WriteString("These global npm packages are deprecated, no longer published, or not found on their registry:\n\n")
+			sb.WriteString("| Package | Installed | Latest | Status |\n")
+			sb.WriteString("|---------|-----------|--------|--------|\n")
+
+			for _, status := range result.NPMStatus {
+				state := "deprecated: " + status.Deprecated
+				switch {
+				case status.Unpublished:
+					state = "unpublished: " + status.Reason
+				case status.NotFound:
+					state = "not found: " + status.Reason
+				}
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", status.Name, status.Version, status.Latest, state))
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	// Shell Builtin Details
+	if len(result.BuiltinCollisions) > 0 {
+		sb.WriteString("## Shell Builtins\n\n")
+		sb.WriteString("These executables are shadowed by shell builtins when typed interactively:\n\n")
+		sb.WriteString("| Tool | Path | Builtin In |\n")
+		sb.WriteString("|------|------|------------|\n")
+
+		for _, collision := range result.BuiltinCollisions {
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s |\n",
+				collision.ToolName, collision.Path, strings.Join(collision.Shells, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Versioned Variants Details
+	if len(result.VersionedVariants) > 0 {
+		sb.WriteString("## Versioned Variants\n\n")
+		sb.WriteString("Tools present under several version-suffixed names:\n\n")
+
+		for _, group := range result.VersionedVariants {
+			sb.WriteString(fmt.Sprintf("- **%s**: %d versioned variants present (%s)\n",
+				group.Base, len(group.Variants), strings.Join(group.Variants, ", ")))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Shim conflict details
+	if len(result.ShimConflicts) > 0 {
+		sb.WriteString("## Version Manager Conflicts\n\n")
+		sb.WriteString("These tools are provided both by a version manager's shim and by a package manager:\n\n")
+		sb.WriteString("| Tool | Shim | Package Manager Copy | Runs |\n")
+		sb.WriteString("|------|------|----------------------|------|\n")
+
+		for _, conflict := range result.ShimConflicts {
+			runs := conflict.OtherManager
+			if conflict.ShimWins {
+				runs = conflict.ShimManager
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s (%s) | %s (%s %s) | %s |\n",
+				conflict.ToolName, conflict.ShimPath, shimDescription(conflict),
+				conflict.OtherPath, conflict.OtherManager, conflict.OtherPackage, runs))
+		}
+		sb.WriteString("\n")
+	}
+
+	// Stale Shims Details
+	if len(result.StaleShims) > 0 {
+		sb.WriteString("## Stale Version-Manager Shims\n\n")
+		sb.WriteString("These shims select a runtime version that is no longer installed:\n\n")
+		sb.WriteString("| Manager | Runtime | Missing Version | Selected By | Shims |\n")
+		sb.WriteString("|---------|---------|-----------------|-------------|-------|\n")
+
+		for _, stale := range result.StaleShims {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %d |\n",
+				stale.Manager, stale.Runtime, stale.Version, stale.Source, len(stale.Shims)))
+		}
+		sb.WriteString("\n")
+	}
+
 	// AI Agent Notes
 	sb.WriteString("## Notes for AI Agents\n\n")
 	sb.WriteString("This audit report can be used to:\n")
@@ -400,7 +1410,29 @@ func generateMarkdownReport(result AuditResult) string {
 	return sb.String()
 }
 
+// formatBytes renders a byte count for humans, e.g. "1.5 MB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
 func init() {
 	rootCmd.AddCommand(auditCmd)
 	auditCmd.Flags().StringVarP(&auditOutput, "output", "o", "", "save audit report to file (default: display to console)")
+	auditCmd.Flags().BoolVar(&auditRegistry, "check-registry", false, "query the npm registry for deprecated or unpublished global packages (needs network)")
+	auditCmd.Flags().BoolVar(&auditWithHash, "with-hash", false, "compare tools by content to find duplicate binaries under different names")
+	auditCmd.Flags().BoolVar(&auditExplain, "explain", false, "list the tools and paths behind each recommendation")
+	auditCmd.Flags().StringVarP(&auditFormat, "format", "f", formatMarkdown, "report format: markdown or json")
+	auditCmd.Flags().BoolVarP(&auditJSON, "json", "j", false, "write the report as JSON (same as --format json)")
+	auditCmd.Flags().StringVar(&auditFixPath, "fix-script", "", "also write a shell script of the commands that resolve shadowed installations")
+	auditCmd.Flags().BoolVar(&auditDryRun, "dry-run", false, "print the fix plan instead of the report, without writing anything")
+	auditCmd.Flags().StringVar(&auditFailOn, "fail-on", "", "exit with status 1 when a recommendation is at least this severe: low, medium, or high")
 }
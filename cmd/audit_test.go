@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// writeScript writes an executable shell script that prints output
+func writeScript(t *testing.T, path, output string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\necho '"+output+"'\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindNewerShadowedShimOfOtherPackage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake tools are shell scripts")
+	}
+	root := t.TempDir()
+	shim := filepath.Join(root, "pyenv", "shims", "pip")
+	conda := filepath.Join(root, "miniconda", "bin", "pip")
+	writeScript(t, shim, "pip 23.2.1 from /root/.pyenv/versions/3.11.7/lib/python3.11/site-packages/pip (python 3.11)")
+	writeScript(t, conda, "pip 23.2.1 from /root/miniconda/lib/python3.11/site-packages/pip (python 3.11)")
+
+	// The shim is owned by the python package, whose version is not pip's
+	tools := []models.Tool{
+		{Name: "pip", Path: shim, PackageName: "python", PackageVersion: "3.11.7", Shim: &models.ShimInfo{Manager: "pyenv"}},
+		{Name: "pip", Path: conda, PackageName: "pip", PackageVersion: "23.2.1", Shadowed: true, PathRank: 1},
+	}
+	shadowed := findShadowedTools(tools)
+	if len(shadowed) != 1 {
+		t.Fatalf("got %d shadowed tools, want 1", len(shadowed))
+	}
+	if shadowed[0].ActiveVersion == "3.11.7" {
+		t.Errorf("active version taken from the python package: %+v", shadowed[0])
+	}
+
+	if newer := findNewerShadowed(context.Background(), shadowed); len(newer) != 0 {
+		t.Errorf("flagged %+v as newer, want nothing: both run pip 23.2.1", newer)
+	}
+}
+
+func TestFindNewerShadowedSamePackage(t *testing.T) {
+	tools := []models.Tool{
+		{Name: "node", Path: "/usr/local/bin/node", PackageName: "node", PackageManager: "brew", PackageVersion: "18.19.0"},
+		{Name: "node", Path: "/opt/homebrew/bin/node", PackageName: "node", PackageManager: "brew", PackageVersion: "21.5.0", Shadowed: true, PathRank: 1},
+	}
+	newer := findNewerShadowed(context.Background(), findShadowedTools(tools))
+	if len(newer) != 1 || newer[0].ShadowedVersion != "21.5.0" {
+		t.Errorf("got %+v, want the shadowed node 21.5.0 flagged", newer)
+	}
+}
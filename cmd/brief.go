@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/brief"
+	"github.com/cli-ai-org/cli/internal/categories"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/shims"
+)
+
+// agentBrief summarizes the environment as compact prose to paste into an
+// LLM prompt: what is installed, how, and what is likely to surprise an
+// agent working on this machine. tools must already be linked to pkgs.
+func agentBrief(tools []models.Tool, pkgs []packages.Package, paths []string, maxTokens int) string {
+	managed := 0
+	for _, tool := range tools {
+		if tool.PackageName != "" {
+			managed++
+		}
+	}
+
+	sections := []brief.Section{{
+		Items: []string{fmt.Sprintf("%s/%s machine with %d CLI tools on PATH (%d directories), %d of them from package managers.",
+			runtime.GOOS, runtime.GOARCH, len(tools), len(paths), managed)},
+	}}
+
+	var managers []string
+	for _, pm := range analyzePackageManagers(pkgs, tools) {
+		managers = append(managers, fmt.Sprintf("%s (%d packages, %d tools)", pm.Name, pm.PackageCount, pm.ToolCount))
+	}
+	sections = append(sections, brief.Section{Label: "Package managers", Items: managers})
+
+	var runtimes []string
+	for _, rv := range shims.ListRuntimes(shims.DetectDirs()) {
+		runtimes = append(runtimes, rv.Summary())
+	}
+	sections = append(sections, brief.Section{Label: "Runtimes", Items: runtimes})
+
+	var cats []string
+	for _, count := range categories.Summarize(tools) {
+		if count.Category == categories.Other {
+			continue
+		}
+		examples := count.Examples
+		if len(examples) > 3 {
+			examples = examples[:3]
+		}
+		cats = append(cats, fmt.Sprintf("%s %d (%s)", count.Category, count.Count, strings.Join(examples, ", ")))
+	}
+	sections = append(sections, brief.Section{Label: "Tool categories", Items: cats})
+
+	var conflicts []string
+	for _, clash := range findClashes(tools) {
+		var sources []string
+		for _, inst := range clash.Installations {
+			source := inst.PackageManager
+			if inst.IsActive {
+				source += " active"
+			}
+			sources = append(sources, source)
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%s [%s]", clash.ToolName, strings.Join(sources, ", ")))
+	}
+	sections = append(sections, brief.Section{Label: "Conflicting installs", Items: conflicts})
+
+	var problems []string
+	for _, tool := range tools {
+		if tool.Broken {
+			problems = append(problems, fmt.Sprintf("%s is broken (%s)", tool.Name, tool.BrokenReason))
+		}
+	}
+	for _, stale := range shims.FindStale(shims.DetectDirs()) {
+		problems = append(problems, fmt.Sprintf("%s shims select %s %s, which is not installed", stale.Manager, stale.Runtime, stale.Version))
+	}
+	sections = append(sections, brief.Section{Label: "Problems", Items: problems})
+
+	return brief.Render(sections, maxTokens)
+}
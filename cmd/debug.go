@@ -10,12 +10,21 @@ import (
 	"github.com/cli-ai-org/cli/internal/models"
 	"github.com/cli-ai-org/cli/internal/packages"
 	"github.com/cli-ai-org/cli/internal/scanner"
+	"github.com/cli-ai-org/cli/internal/shims"
 	"github.com/spf13/cobra"
 )
 
 var (
 	debugAll     bool
 	debugClashes bool
+	debugSort    string
+)
+
+// Orderings for debug --clashes
+const (
+	sortByName          = "name"
+	sortByInstallations = "installations"
+	sortByDivergence    = "divergence"
 )
 
 // debugCmd represents the debug command
@@ -30,7 +39,11 @@ managers (brew, pip, npm, etc.) and shows which installation is active in your P
 Modes:
   - debug TOOL_NAME: Show all installations of a specific tool
   - debug --clashes: Show all tools with conflicting installations
-  - debug --all: Show debug info for all tools`,
+  - debug --all: Show debug info for all tools
+
+Clashes are listed alphabetically. Use --sort installations to put tools
+installed the most ways first, or --sort divergence to put tools whose
+installations report the most distinct versions first.`,
 	Example: `  # Debug a specific tool
   cli-ai debug python
   cli-ai debug docker
@@ -38,22 +51,34 @@ Modes:
   # Show all installation clashes
   cli-ai debug --clashes
 
+  # Worst offenders first
+  cli-ai debug --clashes --sort installations
+
   # Debug all tools
   cli-ai debug --all`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		s := scanner.New()
+		switch debugSort {
+		case sortByName, sortByInstallations, sortByDivergence:
+		default:
+			cmd.PrintErrf("Error: unknown sort %q (valid: %s, %s, %s)\n",
+				debugSort, sortByName, sortByInstallations, sortByDivergence)
+			os.Exit(1)
+		}
+
+		s := newScanner(cmd)
 		d := display.New(os.Stdout)
 
-		// Scan all tools
-		tools, err := s.ScanAllDetailed()
+		// Scan every installation, including shadowed ones
+		tools, err := s.ScanAllOccurrences()
 		if err != nil {
 			cmd.PrintErrf("Error scanning tools: %v\n", err)
 			os.Exit(1)
 		}
+		scanner.CheckInterpreters(tools)
 
 		// Detect packages
-		detector := packages.NewDetector()
+		detector := newDetector(cmd)
 		pkgs, err := detector.DetectAll()
 		if err != nil {
 			cmd.PrintErrf("Error detecting packages: %v\n", err)
@@ -61,7 +86,8 @@ Modes:
 		}
 
 		// Link tools to packages
-		linker := packages.NewLinker(pkgs)
+		linker := newLinker(cmd, pkgs)
+		linker.SetExplain(true)
 		tools = linker.LinkTools(tools)
 
 		if debugClashes {
@@ -73,7 +99,7 @@ Modes:
 			cmd.Usage()
 			os.Exit(1)
 		} else {
-			showToolDebug(args[0], tools, d)
+			showToolDebug(args[0], tools, len(s.GetPaths()), d)
 		}
 	},
 }
@@ -82,20 +108,13 @@ func showClashes(tools []models.Tool, d *display.Display) {
 	// Group tools by name
 	toolGroups := make(map[string][]models.Tool)
 	for _, tool := range tools {
-		if tool.PackageName != "" {
-			toolGroups[tool.Name] = append(toolGroups[tool.Name], tool)
-		}
+		toolGroups[tool.Name] = append(toolGroups[tool.Name], tool)
 	}
 
-	// Find clashes (tools installed by multiple packages)
+	// Find clashes (tools installed from multiple sources)
 	var clashes []string
 	for name, instances := range toolGroups {
-		// Check if multiple different packages provide this tool
-		packageSeen := make(map[string]bool)
-		for _, instance := range instances {
-			packageSeen[instance.PackageName] = true
-		}
-		if len(packageSeen) > 1 {
+		if isClash(instances) {
 			clashes = append(clashes, name)
 		}
 	}
@@ -105,7 +124,7 @@ func showClashes(tools []models.Tool, d *display.Display) {
 		return
 	}
 
-	sort.Strings(clashes)
+	sortClashes(clashes, toolGroups, debugSort)
 
 	fmt.Fprintf(os.Stdout, "Found %d tools with multiple installations:\n\n", len(clashes))
 
@@ -113,13 +132,13 @@ func showClashes(tools []models.Tool, d *display.Display) {
 		instances := toolGroups[name]
 		fmt.Fprintf(os.Stdout, "🔴 %s (%d installations)\n", name, len(instances))
 
-		// Sort by PATH order (first is active)
-		for i, instance := range instances {
+		// Instances are in PATH order; the first is active
+		for _, instance := range instances {
 			active := ""
-			if i == 0 {
-				active = " ✓ ACTIVE"
+			if !instance.Shadowed {
+				active = " " + display.Status("active", "✓ ACTIVE")
 			}
-			fmt.Fprintf(os.Stdout, "   %s via %s%s\n", instance.Path, instance.PackageManager, active)
+			fmt.Fprintf(os.Stdout, "   %s via %s%s\n", instance.Path, display.Manager(packages.Provenance(instance)), active)
 			if instance.PackageVersion != "" {
 				fmt.Fprintf(os.Stdout, "      Version: %s\n", instance.PackageVersion)
 			}
@@ -128,7 +147,40 @@ func showClashes(tools []models.Tool, d *display.Display) {
 	}
 }
 
-func showToolDebug(toolName string, tools []models.Tool, d *display.Display) {
+// sortClashes orders clashing tool names by name, by number of
+// installations, or by number of distinct versions, most first. Ties fall
+// back to name order.
+func sortClashes(names []string, groups map[string][]models.Tool, by string) {
+	key := func(name string) int {
+		switch by {
+		case sortByInstallations:
+			return len(groups[name])
+		case sortByDivergence:
+			return distinctVersions(groups[name])
+		}
+		return 0
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if ki, kj := key(names[i]), key(names[j]); ki != kj {
+			return ki > kj
+		}
+		return names[i] < names[j]
+	})
+}
+
+// distinctVersions counts the different package versions among a tool's
+// installations
+func distinctVersions(instances []models.Tool) int {
+	versions := make(map[string]bool)
+	for _, instance := range instances {
+		if instance.PackageVersion != "" {
+			versions[instance.PackageVersion] = true
+		}
+	}
+	return len(versions)
+}
+
+func showToolDebug(toolName string, tools []models.Tool, pathCount int, d *display.Display) {
 	var matches []models.Tool
 	for _, tool := range tools {
 		if tool.Name == toolName {
@@ -144,14 +196,15 @@ func showToolDebug(toolName string, tools []models.Tool, d *display.Display) {
 	fmt.Fprintf(os.Stdout, "Debug information for: %s\n", toolName)
 	fmt.Fprintf(os.Stdout, "Total installations: %d\n\n", len(matches))
 
-	for i, tool := range matches {
-		fmt.Fprintf(os.Stdout, "Installation #%d:\n", i+1)
-		if i == 0 {
-			fmt.Fprintln(os.Stdout, "  Status: ✓ ACTIVE (first in PATH)")
+	for _, tool := range matches {
+		fmt.Fprintf(os.Stdout, "Installation #%d:\n", tool.PathRank+1)
+		if !tool.Shadowed {
+			fmt.Fprintf(os.Stdout, "  Status: %s (first in PATH)\n", display.Status("active", "✓ ACTIVE"))
 		} else {
-			fmt.Fprintln(os.Stdout, "  Status: ⚠ SHADOWED (not used)")
+			fmt.Fprintf(os.Stdout, "  Status: %s (not used)\n", display.Status("shadowed", "⚠ SHADOWED"))
 		}
 		fmt.Fprintf(os.Stdout, "  Path: %s\n", tool.Path)
+		fmt.Fprintf(os.Stdout, "  Resolved from PATH position #%d of %d\n", tool.PathIndex+1, pathCount)
 
 		if tool.IsSymlink {
 			fmt.Fprintf(os.Stdout, "  Symlink to: %s\n", tool.SymlinkTo)
@@ -167,18 +220,46 @@ func showToolDebug(toolName string, tools []models.Tool, d *display.Display) {
 			fmt.Fprintln(os.Stdout, "  Package: (not detected)")
 		}
 
+		if tool.LinkStrategy == "none" {
+			fmt.Fprintf(os.Stdout, "  Not linked: %s\n", tool.LinkReason)
+		} else if tool.LinkStrategy != "" {
+			fmt.Fprintf(os.Stdout, "  Linked by: %s match (%s)\n", tool.LinkStrategy, tool.LinkReason)
+		}
+
 		if tool.Size > 0 {
 			fmt.Fprintf(os.Stdout, "  Size: %d bytes\n", tool.Size)
 		}
 
+		if tool.Broken {
+			fmt.Fprintf(os.Stdout, "  ⚠ Broken: %s\n", tool.BrokenReason)
+		}
+
+		if tool.AppExecAlias {
+			fmt.Fprintln(os.Stdout, "  ⚠ App Execution Alias: not actually installed (opens the Microsoft Store)")
+		}
+
+		if tool.Shortcut {
+			fmt.Fprintln(os.Stdout, "  ⚠ Shortcut: a .lnk file, not an installed tool (only cmd.exe can open it)")
+		}
+
 		fmt.Fprintln(os.Stdout)
 	}
 
+	// Show the versions behind a version-manager shim
+	for _, rv := range shims.ListRuntimes(shims.DetectDirs()) {
+		for _, shim := range rv.Shims {
+			if shim == toolName {
+				fmt.Fprintf(os.Stdout, "Runtime: %s\n", rv.Summary())
+				fmt.Fprintf(os.Stdout, "  Installed: %s\n\n", strings.Join(rv.Versions, ", "))
+			}
+		}
+	}
+
 	// Show recommendation if multiple installations
 	if len(matches) > 1 {
 		fmt.Fprintln(os.Stdout, "⚠️  RECOMMENDATION:")
 		fmt.Fprintln(os.Stdout, "Multiple installations detected. Consider:")
-		fmt.Fprintf(os.Stdout, "  - Using the active installation via %s\n", matches[0].PackageManager)
+		fmt.Fprintf(os.Stdout, "  - Using the active installation via %s\n", packages.Provenance(matches[0]))
 		fmt.Fprintln(os.Stdout, "  - Uninstalling unused versions to avoid conflicts")
 	}
 }
@@ -187,7 +268,7 @@ func showAllDebug(tools []models.Tool, d *display.Display) {
 	// Group by package
 	packageTools := make(map[string][]models.Tool)
 	for _, tool := range tools {
-		if tool.PackageName != "" {
+		if tool.PackageName != "" && !tool.Shadowed {
 			key := fmt.Sprintf("%s:%s", tool.PackageManager, tool.PackageName)
 			packageTools[key] = append(packageTools[key], tool)
 		}
@@ -227,4 +308,5 @@ func init() {
 	rootCmd.AddCommand(debugCmd)
 	debugCmd.Flags().BoolVarP(&debugAll, "all", "a", false, "show debug information for all packages")
 	debugCmd.Flags().BoolVarP(&debugClashes, "clashes", "c", false, "show only tools with conflicting installations")
+	debugCmd.Flags().StringVar(&debugSort, "sort", sortByName, "order clashes by name, installations, or divergence")
 }
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffFrom   string
+	diffTo     string
+	diffJSON   bool
+	diffFormat string
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff --from <catalog> [--to <catalog>]",
+	Short: "Compare an exported catalog with this machine or another catalog",
+	Long: `Compare a catalog written by "cli export" with a live scan of this machine,
+or with a second catalog, and report new tools, removed tools, version
+changes, and ownership changes.
+
+Tools are compared by name, using the installation that runs. A tool that
+changed is reported as upgraded or downgraded (by version), reowned (now
+provided by another package or manager), moved (runs from another path), or
+modified (the file changed size without a version change).
+
+Ownership and packages are only compared when both catalogs record them:
+export with --with-packages to include them. Versions come from the
+packages, or from the tools themselves in an export made with --with-meta.
+
+Output is a table for people, markdown for reports and agents, or JSON.`,
+	Example: `  # What changed since last month's export?
+  cli diff --from tools-september.json
+
+  # Compare two machines
+  cli diff --from web-01.json --to web-02.json
+
+  # Markdown for a ticket or an agent
+  cli diff --from baseline.json --format markdown`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := diffFormat
+		if format != formatMarkdown {
+			var err error
+			if format, err = resolveFormat(diffFormat, diffJSON); err != nil {
+				cmd.PrintErrf("Error: unknown format %q (valid: %s, %s, %s)\n", diffFormat, formatTable, formatMarkdown, formatJSON)
+				os.Exit(1)
+			}
+		}
+		if diffFrom == "" {
+			cmd.PrintErrf("Error: --from is required\n")
+			os.Exit(1)
+		}
+
+		from, err := snapshot.Load(diffFrom)
+		if err != nil {
+			cmd.PrintErrf("Error reading catalog: %v\n", err)
+			os.Exit(1)
+		}
+
+		var to *models.ToolCatalog
+		toName := "this machine"
+		if diffTo != "" {
+			to, err = snapshot.Load(diffTo)
+			toName = diffTo
+		} else {
+			to, _, err = currentCatalog(cmd)
+		}
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		diff := snapshot.Compare(from, to)
+		diff.From, diff.To = diffFrom, toName
+
+		switch format {
+		case formatJSON:
+			printSnapshotJSON(cmd, diff)
+		case formatMarkdown:
+			fmt.Fprint(os.Stdout, diffMarkdown(diff))
+		default:
+			printSnapshotDiff(diff)
+		}
+	},
+}
+
+// diffMarkdown renders a diff as a markdown report
+func diffMarkdown(diff snapshot.Diff) string {
+	var sb strings.Builder
+
+	sb.WriteString("# CLI Environment Changes\n\n")
+	sb.WriteString(fmt.Sprintf("**From:** %s  \n**To:** %s\n\n", diff.From, diff.To))
+	if !diff.PackagesCompared {
+		sb.WriteString("> **Note:** one of the catalogs has no package information, so ownership and packages were not compared. Export with `--with-packages` to include them.\n\n")
+	}
+	if diff.Empty() {
+		sb.WriteString("No changes.\n")
+		return sb.String()
+	}
+
+	sb.WriteString("## Summary\n\n")
+	sb.WriteString(fmt.Sprintf("- **Tools added:** %d\n", len(diff.AddedTools)))
+	sb.WriteString(fmt.Sprintf("- **Tools removed:** %d\n", len(diff.RemovedTools)))
+	sb.WriteString(fmt.Sprintf("- **Tools changed:** %d\n", len(diff.ChangedTools)))
+	sb.WriteString(fmt.Sprintf("- **Packages added:** %d\n", len(diff.AddedPackages)))
+	sb.WriteString(fmt.Sprintf("- **Packages removed:** %d\n", len(diff.RemovedPackages)))
+	sb.WriteString(fmt.Sprintf("- **Packages changed:** %d\n\n", len(diff.ChangedPackages)))
+
+	writeTools := func(title string, tools []snapshot.ToolState) {
+		if len(tools) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+		sb.WriteString("| Tool | Version | Owner | Path |\n")
+		sb.WriteString("|------|---------|-------|------|\n")
+		for _, tool := range tools {
+			owner := "unknown"
+			if diff.PackagesCompared {
+				owner = describeOwnerState(tool)
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | `%s` |\n", tool.Name, tool.Version, owner, tool.Path))
+		}
+		sb.WriteString("\n")
+	}
+	writeTools("Tools Added", diff.AddedTools)
+	writeTools("Tools Removed", diff.RemovedTools)
+
+	if len(diff.ChangedTools) > 0 {
+		sb.WriteString("## Tools Changed\n\n")
+		sb.WriteString("| Tool | Change | Before | After |\n")
+		sb.WriteString("|------|--------|--------|-------|\n")
+		for _, change := range diff.ChangedTools {
+			var before, after string
+			switch change.Kind {
+			case snapshot.Upgraded, snapshot.Downgraded:
+				before, after = change.Before.Version, change.After.Version
+			case snapshot.Reowned:
+				before, after = describeOwnerState(change.Before), describeOwnerState(change.After)
+			default:
+				before, after = "`"+change.Before.Path+"`", "`"+change.After.Path+"`"
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", change.Name, change.Kind, before, after))
+		}
+		sb.WriteString("\n")
+	}
+
+	writePackages := func(title string, pkgs []models.PackageInfo) {
+		if len(pkgs) == 0 {
+			return
+		}
+		sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+		sb.WriteString("| Package | Manager | Version | Tools |\n")
+		sb.WriteString("|---------|---------|---------|-------|\n")
+		for _, pkg := range pkgs {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n", pkg.Name, pkg.Manager, pkg.Version, listNames(pkg.Binaries)))
+		}
+		sb.WriteString("\n")
+	}
+	writePackages("Packages Added", diff.AddedPackages)
+	writePackages("Packages Removed", diff.RemovedPackages)
+
+	if len(diff.ChangedPackages) > 0 {
+		sb.WriteString("## Packages Changed\n\n")
+		sb.WriteString("| Package | Manager | Change | Before | After |\n")
+		sb.WriteString("|---------|---------|--------|--------|-------|\n")
+		for _, pkg := range diff.ChangedPackages {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", pkg.Name, pkg.Manager, pkg.Kind, pkg.Before, pkg.After))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVar(&diffFrom, "from", "", "catalog to compare from (JSON or NDJSON written by cli export)")
+	diffCmd.Flags().StringVar(&diffTo, "to", "", "catalog to compare to (default: scan this machine)")
+	diffCmd.Flags().BoolVarP(&diffJSON, "json", "j", false, "output in JSON format")
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "", "output format: table, markdown, or json (default: table in a terminal, json when piped)")
+}
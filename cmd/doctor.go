@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/doctor"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/pathenv"
+	"github.com/cli-ai-org/cli/internal/shims"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorJSON   bool
+	doctorFormat string
+	doctorFailOn string
+)
+
+// doctorCmd represents the doctor command
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check your environment for common setup problems",
+	Long: `Run a checklist of environment checks and report each as pass, warn, or fail,
+with a suggested fix for anything that is not passing.
+
+Checks:
+  - PATH size: warns when PATH is unusually large and fails when it is close
+    to the platform's environment size limit
+  - PATH entries exist: warns about PATH entries that do not exist
+  - PATH duplicates: warns about directories listed more than once
+  - PATH permissions: fails when a PATH directory is world-writable, since
+    anyone could plant a command there
+  - PATH symlinks: warns about broken symlinks in PATH directories
+  - Package manager bin directories: warns when a manager (brew, cargo, go,
+    npm, pip, gem, ...) has tools installed in a bin directory that is not on
+    PATH, so they cannot be run by name
+  - Package manager commands: warns when a manager has tools installed but
+    its own command (brew, npm, pip, ...) is not on PATH
+  - Version manager shims: fails when commands shimmed by pyenv, rbenv,
+    asdf, or mise are shadowed by earlier PATH entries (e.g. Homebrew's
+    python3 ahead of ~/.pyenv/shims), and warns when the shims directory is
+    not on PATH at all
+  - Version manager versions: fails when the version a version manager
+    selects for a runtime (e.g. in .python-version) is not installed, so its
+    shims fail
+
+Failures are high-severity problems; warnings are worth fixing but do not
+break anything. Exits with status 1 when any check fails, so doctor can gate
+CI jobs and dotfiles setup scripts; with --fail-on warn, warnings count too.
+Exits with status 2 when doctor cannot run.`,
+	Example: `  # Run all checks
+  cli doctor
+
+  # JSON output for scripts
+  cli doctor --json
+
+  # Stop a setup script when the environment has high-severity problems
+  cli doctor >/dev/null || exit 1
+
+  # Fail a CI job on any warning
+  cli doctor --fail-on warn`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveFormat(doctorFormat, doctorJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(exitError)
+		}
+		if doctorFailOn != string(doctor.Warn) && doctorFailOn != string(doctor.Fail) {
+			cmd.PrintErrf("Error: unknown --fail-on status %q (valid: %s, %s)\n", doctorFailOn, doctor.Warn, doctor.Fail)
+			os.Exit(exitError)
+		}
+
+		s := newScanner(cmd)
+		binDirs := packages.BinDirs()
+		shimDirs := shims.DetectDirs()
+		dirs := pathenv.Analyze(s.GetPaths())
+		pathenv.AssignOwners(dirs, binDirs, shimDirs)
+
+		checks := []doctor.Check{
+			doctor.PathSize(pathenv.Measure(os.Getenv("PATH"), dirs)),
+			doctor.MissingPathEntries(dirs),
+			doctor.DuplicatePathEntries(dirs),
+			doctor.WorldWritableDirs(dirs),
+			doctor.BrokenSymlinks(dirs),
+		}
+		checks = append(checks, doctor.ManagerBinDirs(binDirs, s.GetPaths())...)
+		checks = append(checks, doctor.ManagerCommands(binDirs)...)
+		checks = append(checks, doctor.ShadowedShims(dirs, shimDirs)...)
+		checks = append(checks, doctor.StaleShims(shimDirs)...)
+
+		failed := false
+		for _, check := range checks {
+			if check.Status == doctor.Fail || check.Status == doctor.Warn && doctorFailOn == string(doctor.Warn) {
+				failed = true
+			}
+		}
+
+		if format == formatJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(checks); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(exitError)
+			}
+		} else {
+			for _, check := range checks {
+				icon := "✓"
+				switch check.Status {
+				case doctor.Warn:
+					icon = "⚠"
+				case doctor.Fail:
+					icon = "✗"
+				}
+				fmt.Fprintf(os.Stdout, "%s %s: %s\n", display.Severity(string(check.Status), icon), check.Name, check.Message)
+				if check.Fix != "" {
+					fmt.Fprintf(os.Stdout, "    Fix: %s\n", check.Fix)
+				}
+			}
+		}
+
+		if failed {
+			os.Exit(exitFindings)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVarP(&doctorJSON, "json", "j", false, "output in JSON format")
+	doctorCmd.Flags().StringVarP(&doctorFormat, "format", "f", "", "output format: table or json (default: table in a terminal, json when piped)")
+	doctorCmd.Flags().StringVar(&doctorFailOn, "fail-on", string(doctor.Fail), "exit with status 1 when a check reaches this status: warn or fail")
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/pathenv"
+	"github.com/cli-ai-org/cli/internal/shims"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envJSON   bool
+	envFormat string
+)
+
+// envCmd represents the env command
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Show how your PATH is put together",
+	Long: `Show every PATH entry in resolution order, labelled with the package or
+version manager that owns it.
+
+Owners are determined from each manager's bin directory conventions
+(Homebrew, MacPorts, nix, snap, flatpak, cargo, go, deno, dotnet, composer,
+npm, pip, gem), version-manager shim directories (pyenv, rbenv, asdf, mise,
+volta), the bin directory of the node version nvm puts on PATH, and the
+directories the detected packages were installed into. Other directories
+are labelled "system" (/usr/bin, /bin, ...), "user" (under your home
+directory), or "unknown".
+
+For each entry the number of executables it contains and how many of them
+are actually reachable (not shadowed by an earlier entry) is shown.
+
+The total PATH length and entry count are reported along with the owners
+contributing the most to it, with a warning when PATH is unusually large or
+approaching the platform's environment size limit.`,
+	Example: `  # Show PATH entries and their owners
+  cli env
+
+  # JSON output for agents
+  cli env --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveFormat(envFormat, envJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		s := newScanner(cmd)
+		dirs := pathenv.Analyze(s.GetPaths())
+		// Without the detected packages, owners come from the managers'
+		// conventional directories alone
+		pkgs, _ := newDetector(cmd).DetectAll()
+		pathenv.AssignOwners(dirs, packages.WithPackageDirs(packages.BinDirs(), pkgs), shims.DetectDirs())
+
+		usage := pathenv.Measure(os.Getenv("PATH"), dirs)
+
+		if format == formatJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			output := struct {
+				Usage   pathenv.Usage `json:"usage"`
+				Entries []pathenv.Dir `json:"entries"`
+			}{usage, dirs}
+			if err := encoder.Encode(output); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		fmt.Fprintf(os.Stdout, "PATH has %d entries (in resolution order):\n\n", len(dirs))
+		table := display.NewTable(os.Stdout, "#", "OWNER", "TOOLS", "REACHABLE", "PATH")
+		table.Indent = "  "
+		table.AlignRight(0, 2, 3)
+		for _, dir := range dirs {
+			note := ""
+			switch {
+			case dir.DuplicateOf >= 0:
+				note = fmt.Sprintf("  (duplicate of #%d)", dir.DuplicateOf+1)
+			case !dir.Exists:
+				note = "  (missing)"
+			case len(dir.Claimants) > 1:
+				note = fmt.Sprintf("  (also used by %s)", strings.Join(dir.Claimants[1:], ", "))
+			}
+			table.Row(strconv.Itoa(dir.Index+1), display.Manager(dir.Owner), strconv.Itoa(dir.ExecutableCount),
+				strconv.Itoa(dir.ReachableCount), dir.Path+note)
+		}
+		table.Flush()
+
+		fmt.Fprintf(os.Stdout, "\nPATH length: %d characters in %d entries (limit %d)\n",
+			usage.Length, usage.Entries, usage.Limit)
+		fmt.Fprintln(os.Stdout, "Largest contributors:")
+		for i, c := range usage.Contributors {
+			if i == 5 {
+				break
+			}
+			fmt.Fprintf(os.Stdout, "  %-10s %5d characters in %d entries\n", c.Owner, c.Length, c.Entries)
+		}
+		if usage.Warning != "" {
+			fmt.Fprintf(os.Stdout, "\n⚠ %s\n", usage.Warning)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.Flags().BoolVarP(&envJSON, "json", "j", false, "output in JSON format")
+	envCmd.Flags().StringVarP(&envFormat, "format", "f", "", "output format: table or json (default: table in a terminal, json when piped)")
+}
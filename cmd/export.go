@@ -1,22 +1,51 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
 
+	"github.com/cli-ai-org/cli/internal/brief"
+	"github.com/cli-ai-org/cli/internal/budget"
+	"github.com/cli-ai-org/cli/internal/categories"
 	"github.com/cli-ai-org/cli/internal/collector"
 	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/duplicates"
+	"github.com/cli-ai-org/cli/internal/filelock"
+	"github.com/cli-ai-org/cli/internal/history"
+	"github.com/cli-ai-org/cli/internal/models"
 	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/plugins"
+	"github.com/cli-ai-org/cli/internal/safety"
+	"github.com/cli-ai-org/cli/internal/sbom"
 	"github.com/cli-ai-org/cli/internal/scanner"
+	"github.com/cli-ai-org/cli/internal/search"
 	"github.com/spf13/cobra"
 )
 
 var (
-	exportJSON        bool
-	exportPretty      bool
-	exportOutput      string
-	exportWithMeta    bool
+	exportJSON         bool
+	exportPretty       bool
+	exportOutput       string
+	exportFormat       string
+	exportWithMeta     bool
+	exportHelpDepth    int
 	exportWithPackages bool
+	exportExplainLinks bool
+	exportAppend       bool
+	exportMatch        string
+	exportRegex        string
+	exportAgentPrompt  bool
+	exportMaxTokens    int
+	exportFields       []string
+	exportNoMetaCache  bool
+	exportConcurrency  int
+	exportWithHash     bool
+	exportEmbeddings   bool
+	exportPrioritize   string
+	exportWithSafety   bool
+	exportWithAliases  bool
 )
 
 // exportCmd represents the export command
@@ -30,11 +59,129 @@ This command generates a machine-readable JSON catalog containing:
   - Full paths and locations
   - Tool metadata (size, symlinks, etc.)
   - Optional: Version information (slower, requires running tools)
-  - Optional: Help text extraction (slower, requires running tools)
+  - Optional: Help text extraction (slower, requires running tools), parsed
+    into usage, flags, and subcommands
   - Optional: Package information (which package each tool comes from)
 
 The exported catalog can be used by AI agents to discover and understand
-available CLI tools on the system.`,
+available CLI tools on the system.
+
+Version and help text are cached per binary by path, size, and modification
+time, so later --with-meta runs only run tools that were added or changed.
+Use --no-meta-cache to probe everything again.
+
+With --with-meta, each tool's "help" object holds what could be parsed from
+its --help output: the usage line, a description, its flags (name, short
+form, argument, description, and default), its subcommands, and examples.
+Cobra, clap, argparse, and GNU getopt layouts are understood; tools whose
+help text is free-form have no "help" object.
+
+--help-depth N also walks the subcommands of tools like git, kubectl, and gh,
+N levels deep, running "tool help <sub>" or "tool <sub> --help" for each, and
+nests each subcommand's usage, flags, and own subcommands under it. Walking
+one tool stops after 30 seconds or 500 runs, keeping what it found.
+
+Windows App Execution Aliases and .lnk shortcuts are not installed tools
+and are left out of the catalog; --include-aliases keeps them, marked with
+"app_exec_alias" or "shortcut".
+
+Plugins of kubectl, gh, cargo, and git (see cli plugins) are recorded as
+subcommands of their host: the host tool's "plugins" lists them, and each
+plugin executable's "plugin" names its host and the command that runs it.
+
+Output formats (--format):
+  json       Full JSON catalog (default)
+  env        Shell variable assignments (TOOL_GIT=/usr/bin/git) suitable for eval
+  ndjson     One JSON tool record per line
+  toml       TOML document with [[tools]] and [[packages]] tables
+  yaml       YAML document with the same fields as the JSON catalog
+  cyclonedx  CycloneDX 1.5 JSON bill of materials (implies --with-packages)
+  spdx       SPDX 2.3 JSON document (implies --with-packages)
+  openai-tools     OpenAI function tool definitions (implies --with-meta)
+  anthropic-tools  Anthropic tool definitions (implies --with-meta)
+
+In a bill of materials, each package is a component identified by its
+package URL (pkg:brew/jq@1.7.1, pkg:npm/%40vercel/cli@33.0.0, pkg:pypi/...),
+and each tool no package manager owns is a pkg:generic component with its
+path, its version if --with-meta found one, and its SHA-256 with --with-hash.
+
+The openai-tools and anthropic-tools formats describe each tool as a
+function an agent framework can register and call: its name (with
+characters function names may not hold replaced by "_"), its man page
+summary and usage line, and a parameter per flag parsed from its help,
+named without its dashes, a boolean for a switch and a string or integer
+for a flag taking a value. "subcommand" picks one of the tool's
+subcommands, and "args" holds the positional arguments. --help and
+--version are left out. With --help-depth, each walked subcommand is a
+function of its own ("git_commit"). Select the tools with --match or
+--regex, since APIs limit how many tools a request may define.
+
+With --agent-prompt, a compact natural-language brief of the environment
+(tool counts by category, package managers, runtime versions, conflicts, and
+problems) is printed instead of the catalog, sized to fit --max-tokens, for
+pasting into an LLM prompt.
+
+With --embeddings-input, one JSON record per tool is printed instead of the
+catalog, for building a local vector index of the tools: a stable "id"
+derived from the tool's path, its name, path, package, and version, and a
+"text" of its name, man page summary or description, the first 1500
+characters of its help with whitespace normalized, and the examples parsed
+from its help. "text_sha256" changes only when the text does, so unchanged
+tools need not be embedded again, and "tokens" estimates the text's size.
+It implies --with-meta; with --no-exec, only cached help is used.
+
+Without --agent-prompt, --max-tokens trims the json or ndjson catalog to
+fit an LLM context window instead. Broken and shadowed tools are dropped,
+help text is cut to its first lines and parsed help to its leading flags,
+subcommands, and examples, and tools are kept in --prioritize order for as
+long as the output fits:
+  usage    the tools run most often in your bash, zsh, fish, or PowerShell
+           history first (default)
+  recency  the tools run most recently first
+  package  tools installed by package managers first, the command each
+           package is named after before its other binaries
+The number of tools kept and the approximate token count are printed to
+stderr, and recorded in the json catalog as "omitted_tools" and
+"estimated_tokens".
+
+Each tool's "tags" list its categories (vcs, container, network,
+compression, language-runtime, cloud, editor, build, and others), from a
+built-in list of well-known tools and their packages and, with --with-meta,
+the words of their descriptions.
+
+With --with-safety, each tool that is risky to run has a "safety" object,
+so agent policies can ask for confirmation first. Its "risks" are any of
+destructive (deletes or overwrites data, or stops processes or the
+machine: rm, dd, mkfs, kill), network-write (changes remote state, uploads
+data, or downloads code to run: curl, scp, aws), and privileged (sudo,
+mount), with a "reason". Its "subcommands" list the subcommands with risks
+of their own, such as terraform destroy or git push. Risks come from a
+built-in list of tools and subcommands and, with --with-meta, from the
+names of the subcommands a tool's help lists (delete, destroy, push,
+deploy, ...); "source" says which. Tools with no known risk have no
+"safety" object.
+
+The safety file (~/.cli-safety.yaml, or safety_file in the config file)
+overrides the annotation of the tools it names, by name or shell glob:
+
+  tools:
+    deploy-prod:
+      risks: [network-write]
+      reason: deploys to production
+    terraform:
+      subcommands:
+        - {name: destroy, risks: [destructive]}
+    curl: {}    # no known risk
+
+With --fields, each tool object in json and ndjson output is reduced to the
+listed fields, in that order, to shrink the payload for token-constrained
+agents and large fleets.
+
+With --append, each tool is tagged with this machine's hostname and appended
+as NDJSON to the --output file instead of overwriting it. Run it on every
+machine against a shared file to build a fleet-wide inventory. The file is
+locked while writing, so concurrent writers on a shared mount do not
+interleave their records.`,
 	Example: `  # Export basic catalog to stdout
   cli export
 
@@ -47,23 +194,188 @@ available CLI tools on the system.`,
   # Export with metadata (version, help text) - slower
   cli export --with-meta --output tools-detailed.json
 
+  # Record a SHA-256 of each tool's content
+  cli export --with-hash --output tools.json
+
+  # Re-probe every tool, ignoring cached version and help text
+  cli export --with-meta --no-meta-cache
+
+  # Include the full command tree of tools with subcommands
+  cli export --match 'kubectl' --help-depth 2
+
   # Export with package information
   cli export --with-packages --pretty --output tools-with-packages.json
 
+  # Show which linker strategy attributed each tool to its package
+  cli export --explain-links | jq '.tools[] | {name, link_strategy, link_reason}'
+
   # Pipe to AI agent or other tool
-  cli export | jq '.tools[] | .name'`,
+  cli export | jq '.tools[] | .name'
+
+  # Load tool paths into a shell script
+  eval "$(cli export --format env)"
+
+  # Export only Kubernetes tooling
+  cli export --match 'kube*' --with-packages
+
+  # Short environment brief to paste into an LLM prompt
+  cli export --agent-prompt --max-tokens 300
+
+  # Register kubectl and helm as tools for an OpenAI agent
+  cli export --format openai-tools --regex '^(kubectl|helm)$' --output tools.json
+
+  # Records for a local embedding index
+  cli export --embeddings-input --with-packages --output tools.jsonl
+
+  # The most used tools, with trimmed help, in about 8000 tokens
+  cli export --with-meta --max-tokens 8000 --prioritize usage
+
+  # Flag destructive and network-writing tools for an agent policy
+  cli export --with-meta --with-safety | jq '.tools[] | select(.safety) | {name, safety}'
+
+  # Only names and paths
+  cli export --fields name,path
+
+  # Accumulate tools from several machines into one file
+  cli export --append --output /mnt/shared/fleet.ndjson`,
 	Run: func(cmd *cobra.Command, args []string) {
-		s := scanner.New()
+		if exportFormat != "json" && exportFormat != "env" && exportFormat != "ndjson" && exportFormat != "toml" && exportFormat != "yaml" && !isSBOMFormat(exportFormat) && !isToolSchemaFormat(exportFormat) {
+			cmd.PrintErrf("Error: unknown format %q (valid: json, env, ndjson, toml, yaml, cyclonedx, spdx, openai-tools, anthropic-tools)\n", exportFormat)
+			os.Exit(1)
+		}
+
+		if exportAppend {
+			if exportOutput == "" {
+				cmd.PrintErrln("Error: --append requires --output")
+				os.Exit(1)
+			}
+			// Only line-oriented records can be appended to an existing file
+			if cmd.Flags().Changed("format") && exportFormat != "ndjson" {
+				cmd.PrintErrf("Error: --append only supports --format ndjson, not %q\n", exportFormat)
+				os.Exit(1)
+			}
+			exportFormat = "ndjson"
+		}
+
+		if len(exportFields) > 0 {
+			if err := models.ValidateToolFields(exportFields); err != nil {
+				cmd.PrintErrf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			if exportFormat != "json" && exportFormat != "ndjson" {
+				cmd.PrintErrf("Error: --fields only supports json and ndjson output, not %q\n", exportFormat)
+				os.Exit(1)
+			}
+		}
+
+		if exportAgentPrompt && exportAppend {
+			cmd.PrintErrln("Error: --agent-prompt cannot be used with --append")
+			os.Exit(1)
+		}
+
+		// Without --agent-prompt, --max-tokens trims the catalog itself
+		trimCatalog := cmd.Flags().Changed("max-tokens") && !exportAgentPrompt
+		if cmd.Flags().Changed("prioritize") {
+			if !trimCatalog {
+				cmd.PrintErrln("Error: --prioritize requires --max-tokens and cannot be used with --agent-prompt")
+				os.Exit(1)
+			}
+			if err := budget.CheckPriority(exportPrioritize); err != nil {
+				cmd.PrintErrf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if trimCatalog {
+			if exportMaxTokens <= 0 {
+				cmd.PrintErrln("Error: --max-tokens must be positive")
+				os.Exit(1)
+			}
+			if exportAppend || exportEmbeddings {
+				cmd.PrintErrln("Error: --max-tokens cannot be used with --append or --embeddings-input")
+				os.Exit(1)
+			}
+			if exportFormat != "json" && exportFormat != "ndjson" {
+				cmd.PrintErrf("Error: --max-tokens only supports json and ndjson output, not %q\n", exportFormat)
+				os.Exit(1)
+			}
+		}
+
+		if exportEmbeddings {
+			if exportAgentPrompt || exportAppend || len(exportFields) > 0 {
+				cmd.PrintErrln("Error: --embeddings-input cannot be used with --agent-prompt, --append, or --fields")
+				os.Exit(1)
+			}
+			if cmd.Flags().Changed("format") {
+				cmd.PrintErrln("Error: --embeddings-input always writes JSONL and cannot be used with --format")
+				os.Exit(1)
+			}
+			// The text of each record comes from help text
+			exportWithMeta = true
+		}
+
+		// Read the safety overrides up front, so a bad file fails fast
+		var safetyOverrides *safety.Overrides
+		if exportWithSafety {
+			var err error
+			safetyOverrides, err = safety.Load(cfg.SafetyFile)
+			if err != nil {
+				cmd.PrintErrf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		// Explaining links, and describing package managers in the brief,
+		// require linking in the first place
+		if exportExplainLinks || exportAgentPrompt {
+			exportWithPackages = true
+		}
+		// A bill of materials lists packages, and tools no package owns
+		if isSBOMFormat(exportFormat) {
+			exportWithPackages = true
+		}
+		// Tool schemas are built from the flags parsed from help text
+		if isToolSchemaFormat(exportFormat) {
+			exportWithMeta = true
+		}
+		if exportHelpDepth < 0 {
+			cmd.PrintErrf("Error: --help-depth must not be negative\n")
+			os.Exit(1)
+		}
+		// Walking command trees starts from the help --with-meta parses
+		if exportHelpDepth > 0 {
+			exportWithMeta = true
+		}
+		// Without running tools, versions can only come from their packages
+		execDisabled := noExec || cfg.NoExec
+		if exportWithMeta && execDisabled {
+			exportWithPackages = true
+		}
+
+		s := newScanner(cmd)
 
 		if verbose {
 			fmt.Fprintln(os.Stderr, "Scanning for CLI tools...")
 		}
 
+		// If stopped early, export whatever was found
 		tools, err := s.ScanAllDetailed()
-		if err != nil {
+		if err != nil && !isCancelled(err) {
 			cmd.PrintErrf("Error scanning for tools: %v\n", err)
 			os.Exit(1)
 		}
+		if !exportWithAliases {
+			tools = scanner.WithoutLaunchers(tools)
+		}
+
+		// Present kubectl, gh, cargo, and git plugins as subcommands of
+		// their host, including plugins --match leaves out
+		plugins.Annotate(tools, newPluginDiscoverer(cmd).Discover(tools))
+
+		tools, err = matchTools(tools, exportMatch, exportRegex)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
 
 		if verbose {
 			fmt.Fprintf(os.Stderr, "Found %d tools\n", len(tools))
@@ -76,7 +388,7 @@ available CLI tools on the system.`,
 				fmt.Fprintln(os.Stderr, "Detecting packages...")
 			}
 
-			detector := packages.NewDetector()
+			detector := newDetector(cmd)
 			var err error
 			pkgs, err = detector.DetectAll()
 			if err != nil && verbose {
@@ -89,7 +401,8 @@ available CLI tools on the system.`,
 			}
 
 			// Link tools to packages
-			linker := packages.NewLinker(pkgs)
+			linker := newLinker(cmd, pkgs)
+			linker.SetExplain(exportExplainLinks)
 			tools = linker.LinkTools(tools)
 		}
 
@@ -99,29 +412,111 @@ available CLI tools on the system.`,
 				fmt.Fprintln(os.Stderr, "Collecting metadata (this may take a while)...")
 			}
 
-			c := collector.New()
-			for i := range tools {
-				if verbose && i%50 == 0 {
-					fmt.Fprintf(os.Stderr, "Processing tool %d/%d...\n", i+1, len(tools))
+			opts := collectorOptions(cmd.Context())
+			if exportConcurrency > 0 {
+				opts.Concurrency = exportConcurrency
+			}
+			opts.NoMetaCache = exportNoMetaCache
+			opts.HelpDepth = exportHelpDepth
+			c := collector.NewWithOptions(opts)
+			var progress func(done, total int)
+			if verbose {
+				progress = func(done, total int) {
+					if done%50 == 0 || done == total {
+						fmt.Fprintf(os.Stderr, "Processed %d/%d tools...\n", done, total)
+					}
 				}
-
-				enriched, err := c.CollectToolInfo(tools[i].Name, tools[i].Path)
-				if err == nil && enriched != nil {
+			}
+			for i, enriched := range c.CollectAll(tools, progress) {
+				if enriched != nil {
 					tools[i].Version = enriched.Version
 					tools[i].HelpText = enriched.HelpText
+					tools[i].Help = enriched.Help
+				}
+				if tools[i].Version == "" && execDisabled {
+					tools[i].Version = tools[i].PackageVersion
+				}
+			}
+
+			if err := c.SaveMetaCache(); err != nil && verbose {
+				fmt.Fprintf(os.Stderr, "Warning: could not save metadata cache: %v\n", err)
+			}
+		}
+
+		// Tag tools with their categories, using the descriptions of
+		// --with-meta when there are any
+		categories.Tag(tools)
+
+		if exportWithSafety {
+			safety.Annotate(tools, safetyOverrides)
+		}
+
+		// Hash tool contents if requested
+		if exportWithHash {
+			if verbose {
+				fmt.Fprintln(os.Stderr, "Hashing tool contents...")
+			}
+			for i := range tools {
+				if sum, err := duplicates.HashFile(tools[i].Path); err == nil {
+					tools[i].SHA256 = sum
 				}
 			}
 		}
 
 		// Build catalog
-		c := collector.New()
-		catalog := c.BuildCatalog(tools, s.GetPaths())
+		partial := warnIfPartial(cmd)
+		buildCatalog := func(tools []models.Tool) *models.ToolCatalog {
+			catalog := collector.New().BuildCatalog(tools, s.GetPaths())
+			catalog.Partial = partial
+
+			// Add package information to catalog if available
+			if exportWithPackages && len(pkgs) > 0 {
+				pkgsWithBinaries := packages.GetPackagesWithBinaries(pkgs, tools)
+				catalog.Packages = pkgsWithBinaries
+				catalog.TotalPackages = len(pkgsWithBinaries)
+			}
+			return catalog
+		}
+		catalog := buildCatalog(tools)
+
+		if trimCatalog {
+			total := len(tools)
+			ranked := budget.Prioritize(tools, exportPrioritize, history.Load())
+			for i := range ranked {
+				ranked[i] = budget.Compact(ranked[i])
+			}
+			kept, size := budget.Fit(ranked, exportMaxTokens, func(subset []models.Tool) int {
+				candidate := buildCatalog(subset)
+				// Measure with the report fields filled in, since they are
+				// part of the output too
+				candidate.OmittedTools = total - len(subset)
+				candidate.EstimatedTokens = exportMaxTokens
+				return catalogTokens(candidate, exportFormat, exportFields, exportPretty)
+			})
+			catalog = buildCatalog(kept)
+			catalog.OmittedTools = total - len(kept)
+			catalog.EstimatedTokens = size
+			fmt.Fprintf(os.Stderr, "Exported %d of %d tools in about %d tokens\n", len(kept), total, size)
+		}
+
+		if exportAppend {
+			hostname, err := os.Hostname()
+			if err != nil {
+				cmd.PrintErrf("Error determining hostname: %v\n", err)
+				os.Exit(1)
+			}
+			for i := range catalog.Tools {
+				catalog.Tools[i].Hostname = hostname
+			}
 
-		// Add package information to catalog if available
-		if exportWithPackages && len(pkgs) > 0 {
-			pkgsWithBinaries := packages.GetPackagesWithBinaries(pkgs, tools)
-			catalog.Packages = pkgsWithBinaries
-			catalog.TotalPackages = len(pkgsWithBinaries)
+			if err := appendCatalog(exportOutput, catalog, appendFields(exportFields)); err != nil {
+				cmd.PrintErrf("Error appending to %s: %v\n", exportOutput, err)
+				os.Exit(1)
+			}
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Appended %d tools from %s to %s\n", len(catalog.Tools), hostname, exportOutput)
+			}
+			return
 		}
 
 		// Determine output writer
@@ -136,11 +531,76 @@ available CLI tools on the system.`,
 			writer = file
 		}
 
+		if exportAgentPrompt {
+			fmt.Fprint(writer, agentBrief(tools, pkgs, s.GetPaths(), exportMaxTokens))
+			return
+		}
+
+		if exportEmbeddings {
+			descriptions := manDescriptions(collector.NewWithOptions(collectorOptions(cmd.Context())), tools, !execDisabled)
+			encoder := json.NewEncoder(writer)
+			for _, tool := range catalog.Tools {
+				if err := encoder.Encode(search.NewDocument(tool, descriptions[tool.Path])); err != nil {
+					cmd.PrintErrf("Error encoding JSON: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			return
+		}
+
 		// Output catalog
 		d := display.New(writer)
-		if err := d.ShowCatalogJSON(catalog, exportPretty); err != nil {
-			cmd.PrintErrf("Error encoding JSON: %v\n", err)
-			os.Exit(1)
+		d.SetFields(exportFields)
+		switch exportFormat {
+		case "env":
+			d.ShowCatalogEnv(catalog)
+		case "toml":
+			if err := d.ShowCatalogTOML(catalog); err != nil {
+				cmd.PrintErrf("Error encoding TOML: %v\n", err)
+				os.Exit(1)
+			}
+		case "yaml":
+			if err := d.ShowCatalogYAML(catalog); err != nil {
+				cmd.PrintErrf("Error encoding YAML: %v\n", err)
+				os.Exit(1)
+			}
+		case "cyclonedx", "spdx":
+			host, err := os.Hostname()
+			if err != nil {
+				host = "localhost"
+			}
+			creator := sbom.Creator{Name: "cli", Version: version}
+			if exportFormat == "cyclonedx" {
+				err = d.ShowCatalogCycloneDX(catalog, host, creator)
+			} else {
+				err = d.ShowCatalogSPDX(catalog, host, creator)
+			}
+			if err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "openai-tools", "anthropic-tools":
+			descriptions := manDescriptions(collector.NewWithOptions(collectorOptions(cmd.Context())), catalog.Tools, !execDisabled)
+			var err error
+			if exportFormat == "openai-tools" {
+				err = d.ShowCatalogOpenAITools(catalog, descriptions)
+			} else {
+				err = d.ShowCatalogAnthropicTools(catalog, descriptions)
+			}
+			if err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		case "ndjson":
+			if err := d.ShowCatalogNDJSON(catalog); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		default:
+			if err := d.ShowCatalogJSON(catalog, exportPretty); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
 		}
 
 		if verbose && exportOutput != "" {
@@ -149,11 +609,95 @@ available CLI tools on the system.`,
 	},
 }
 
+// appendCatalog appends the catalog's tools as NDJSON to path, projected to
+// fields if any are given. The records are encoded up front and written with
+// a single call while holding an exclusive lock, so a concurrent writer never
+// sees or produces a torn line.
+func appendCatalog(path string, catalog *models.ToolCatalog, fields []string) error {
+	var buf bytes.Buffer
+	d := display.New(&buf)
+	d.SetFields(fields)
+	if err := d.ShowCatalogNDJSON(catalog); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := filelock.Lock(file); err != nil {
+		return fmt.Errorf("locking file: %w", err)
+	}
+	defer filelock.Unlock(file)
+
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	return file.Sync()
+}
+
+// isSBOMFormat reports whether an export format is a bill of materials
+func isSBOMFormat(format string) bool {
+	return format == "cyclonedx" || format == "spdx"
+}
+
+// catalogTokens estimates the size in LLM tokens of the catalog written as
+// json or ndjson, reduced to fields if any are given
+func catalogTokens(catalog *models.ToolCatalog, format string, fields []string, pretty bool) int {
+	var buf bytes.Buffer
+	d := display.New(&buf)
+	d.SetFields(fields)
+	if format == "ndjson" {
+		d.ShowCatalogNDJSON(catalog)
+	} else {
+		d.ShowCatalogJSON(catalog, pretty)
+	}
+	return brief.EstimateTokens(buf.String())
+}
+
+// isToolSchemaFormat reports whether an export format is a list of LLM
+// tool definitions
+func isToolSchemaFormat(format string) bool {
+	return format == "openai-tools" || format == "anthropic-tools"
+}
+
+// appendFields adds hostname to a --fields projection, since appended
+// records from several machines are useless without it
+func appendFields(fields []string) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+	for _, field := range fields {
+		if field == "hostname" {
+			return fields
+		}
+	}
+	return append(fields, "hostname")
+}
+
 func init() {
 	rootCmd.AddCommand(exportCmd)
 	exportCmd.Flags().BoolVarP(&exportJSON, "json", "j", true, "output in JSON format (default)")
 	exportCmd.Flags().BoolVarP(&exportPretty, "pretty", "p", false, "pretty-print JSON output")
 	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "output file (default: stdout)")
-	exportCmd.Flags().BoolVarP(&exportWithMeta, "with-meta", "m", false, "include version and help text (slower)")
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "json", "output format (json, env, ndjson, toml, yaml, cyclonedx, spdx, openai-tools, anthropic-tools)")
+	exportCmd.Flags().BoolVarP(&exportWithMeta, "with-meta", "m", false, "include version, help text, and the flags and subcommands parsed from it (slower)")
+	exportCmd.Flags().BoolVar(&exportWithHash, "with-hash", false, "include a SHA-256 of each tool's content (reads every binary)")
+	exportCmd.Flags().IntVar(&exportHelpDepth, "help-depth", 0, "walk subcommands this many levels deep, running each one's help to record its usage, flags, and subcommands (implies --with-meta)")
+	exportCmd.Flags().IntVar(&exportConcurrency, "concurrency", 0, "how many tools --with-meta probes at once (default: probe_concurrency from the config, else twice the CPU count)")
+	exportCmd.Flags().BoolVar(&exportNoMetaCache, "no-meta-cache", false, "probe every tool for --with-meta instead of reusing cached version and help text")
+	exportCmd.Flags().BoolVar(&exportWithAliases, "include-aliases", false, "include Windows App Execution Aliases and .lnk shortcuts, which are not installed tools")
+	exportCmd.Flags().BoolVar(&exportWithSafety, "with-safety", false, "annotate risky tools and subcommands as destructive, network-write, or privileged")
 	exportCmd.Flags().BoolVarP(&exportWithPackages, "with-packages", "P", false, "include package information (npm, pip, brew, etc.)")
+	exportCmd.Flags().BoolVar(&exportExplainLinks, "explain-links", false, "record which strategy linked each tool to its package (implies --with-packages)")
+	exportCmd.Flags().BoolVar(&exportAppend, "append", false, "append hostname-tagged NDJSON records to the --output file instead of overwriting it")
+	exportCmd.Flags().BoolVar(&exportAgentPrompt, "agent-prompt", false, "print a compact environment brief for LLM prompts instead of the catalog")
+	exportCmd.Flags().IntVar(&exportMaxTokens, "max-tokens", brief.DefaultMaxTokens, "approximate token budget for --agent-prompt, or for the catalog, which is trimmed to fit")
+	exportCmd.Flags().StringVar(&exportPrioritize, "prioritize", budget.PriorityUsage, "which tools --max-tokens keeps first: usage (most run in shell history), recency (most recently run), or package (installed by package managers)")
+	exportCmd.Flags().BoolVar(&exportEmbeddings, "embeddings-input", false, "print one JSONL record per tool with a stable ID and normalized text for embedding pipelines (implies --with-meta)")
+	exportCmd.Flags().StringSliceVar(&exportFields, "fields", nil, "only include these tool fields in json/ndjson output (e.g. name,path,package_manager)")
+	exportCmd.Flags().StringVar(&exportMatch, "match", "", "only export tools whose name matches this shell glob (e.g. 'kube*')")
+	exportCmd.Flags().StringVar(&exportRegex, "regex", "", "only export tools whose name matches this regular expression")
 }
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/config"
+	"github.com/cli-ai-org/cli/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var (
+	findJSON         bool
+	findFormat       string
+	findLimit        int
+	findWithMeta     bool
+	findNoEmbeddings bool
+)
+
+// findCmd represents the find command
+var findCmd = &cobra.Command{
+	Use:   "find <what you want to do>",
+	Short: "Rank tools by how well they fit a task described in words",
+	Long: `Rank the tools in PATH against a task described in plain words, such as
+"convert video files", and show the best candidates with the reasons each
+was picked. It is meant for agents deciding which local tool to run.
+
+Each word of the task is looked for in each tool's name, package,
+description, subcommands, flags, and help text, in that order of weight, and
+counts more the fewer tools mention it, so "video" outweighs "files". Words
+are matched regardless of their ending ("converting" matches "convert"),
+and a few common synonyms count for less (transcode for convert). Tools
+matching more of the words rank higher.
+
+Help text and descriptions come from the caches export --with-meta and info
+fill, as for cli search; --with-meta probes the tools missing from them.
+
+With an embeddings endpoint in the config file, tools are ranked by the
+similarity of their text to the task instead, and the keyword matches are
+kept as reasons:
+
+  embeddings:
+    url: http://localhost:11434/v1/embeddings
+    model: nomic-embed-text
+
+Any OpenAI-compatible endpoint works; its API key is read from
+` + config.EnvEmbeddingsAPIKey + `. Tools' embeddings are cached, so only new or
+changed tools are sent again. If the endpoint fails, the keyword ranking is
+used. --no-embeddings always uses it.`,
+	Example: `  # Which tool converts video?
+  cli find "convert video files"
+
+  # The five best candidates as JSON, for an agent
+  cli find "pretty print json" --limit 5 --json
+
+  # Probe tools that were never probed before ranking
+  cli find "resize images" --with-meta`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveFormat(findFormat, findJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if findLimit < 0 {
+			cmd.PrintErrf("Error: --limit must not be negative\n")
+			os.Exit(1)
+		}
+		query := strings.Join(args, " ")
+
+		s := newScanner(cmd)
+		tools, err := s.ScanAllDetailed()
+		if err != nil && !isCancelled(err) {
+			cmd.PrintErrf("Error scanning tools: %v\n", err)
+			os.Exit(1)
+		}
+		pkgs, err := newDetector(cmd).DetectAll()
+		if err != nil && !isCancelled(err) {
+			cmd.PrintErrf("Error detecting packages: %v\n", err)
+			os.Exit(1)
+		}
+		tools = newLinker(cmd, pkgs).LinkTools(tools)
+		descriptions := loadToolText(cmd, tools, findWithMeta)
+
+		var candidates []search.Candidate
+		if cfg.Embeddings.URL != "" && !findNoEmbeddings {
+			embedder := search.NewEmbedder(cfg.Embeddings.URL, cfg.Embeddings.Model, os.Getenv(config.EnvEmbeddingsAPIKey))
+			candidates, err = search.RankSemantic(cmd.Context(), embedder, tools, descriptions, query, findLimit)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: embeddings: %v; ranking by keywords\n", err)
+				candidates = nil
+			} else if err := embedder.SaveCache(); err != nil && verbose {
+				fmt.Fprintf(os.Stderr, "Warning: could not save embeddings cache: %v\n", err)
+			}
+		}
+		if candidates == nil {
+			candidates, err = search.RankCapabilities(tools, descriptions, query, findLimit)
+			if err != nil {
+				cmd.PrintErrf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		warnIfPartial(cmd)
+
+		if format == formatJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(candidates); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		} else if len(candidates) == 0 {
+			fmt.Fprintf(os.Stdout, "No tools match %q.\n", query)
+		} else {
+			for i, candidate := range candidates {
+				fmt.Fprintf(os.Stdout, "%2d. %s (score %.2f)", i+1, candidate.Name, candidate.Score)
+				if candidate.Description != "" {
+					fmt.Fprintf(os.Stdout, " - %s", candidate.Description)
+				}
+				fmt.Fprintln(os.Stdout)
+				for _, reason := range candidate.Reasons {
+					fmt.Fprintf(os.Stdout, "      %s\n", reason)
+				}
+			}
+		}
+
+		if len(candidates) == 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(findCmd)
+	findCmd.Flags().BoolVarP(&findJSON, "json", "j", false, "output in JSON format")
+	findCmd.Flags().StringVarP(&findFormat, "format", "f", "", "output format: table or json (default: table in a terminal, json when piped)")
+	findCmd.Flags().IntVarP(&findLimit, "limit", "n", 10, "how many candidates to show (0 for all)")
+	findCmd.Flags().BoolVar(&findWithMeta, "with-meta", false, "probe tools whose help text is not cached, and look up their man pages")
+	findCmd.Flags().BoolVar(&findNoEmbeddings, "no-embeddings", false, "rank by keywords even when an embeddings endpoint is configured")
+}
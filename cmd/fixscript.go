@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/shims"
+)
+
+// fixAction is one command in a fix script, with the comments explaining
+// why it is there. Disabled actions are written commented out, with the
+// reason, for the user to review.
+type fixAction struct {
+	key      string
+	comments []string
+	command  string
+	disabled string
+	// tools are the tool names the action is meant to remove
+	tools map[string]bool
+}
+
+// generateFixScript turns the audit's shadowed installations into a shell
+// script of uninstall commands. Shadowed package-managed copies are
+// uninstalled so only the active one is left, except where the shadowed copy
+// is the newer one: then the older copy that runs is removed instead, so the
+// upgrade takes effect. Copies owned by the operating system are never
+// touched, and neither are version manager shims: for those, the script
+// notes how to select another version instead. A copy reached through
+// several paths is handled once. Commands that delete unmanaged files, or
+// uninstall a package that still provides tools in use, are commented out.
+func generateFixScript(result AuditResult, occurrences []models.Tool) string {
+	byPath := make(map[string]models.Tool)
+	inUse := make(map[string][]string)
+	for _, tool := range occurrences {
+		if _, ok := byPath[tool.Path]; !ok {
+			byPath[tool.Path] = tool
+		}
+		if !tool.Shadowed && tool.PackageName != "" {
+			key := tool.PackageManager + ":" + tool.PackageName
+			inUse[key] = append(inUse[key], tool.Name)
+		}
+	}
+
+	newer := make(map[[2]string]ShadowedTool)
+	for _, shadow := range result.NewerShadowed {
+		newer[[2]string{shadow.ToolName, shadow.ShadowedPath}] = shadow
+	}
+
+	var actions []*fixAction
+	index := make(map[string]*fixAction)
+	var notes []string
+
+	// A copy reached through several paths (/bin/pip and /usr/bin/pip on
+	// merged-/usr systems) is handled once
+	handled := make(map[[2]string]bool)
+
+	for _, shadow := range result.ShadowedTools {
+		if sameFile(shadow.ActivePath, shadow.ShadowedPath) {
+			continue
+		}
+		copyKey := [2]string{shadow.ToolName, resolvedPath(shadow.ShadowedPath)}
+		if handled[copyKey] {
+			continue
+		}
+		handled[copyKey] = true
+		active, shadowed := byPath[shadow.ActivePath], byPath[shadow.ShadowedPath]
+
+		target := shadowed
+		comment := fmt.Sprintf("%s: %s is shadowed by %s and never runs", shadow.ToolName, describeCopy(shadowed), shadow.ActivePath)
+		if upgrade, ok := newer[[2]string{shadow.ToolName, shadow.ShadowedPath}]; ok {
+			target = active
+			if active.Shim != nil {
+				// The shim runs whichever version is selected; deleting it
+				// only lasts until the next rehash
+				manager := shims.Manager(active.Shim.Manager)
+				global, local := shims.SelectCommands(manager, active.Shim.Plugin)
+				notes = append(notes, fmt.Sprintf("%s: the %s shim %s (%s) runs instead of the newer %s (%s); select another version with `%s` or `%s`",
+					shadow.ToolName, manager, active.Path, upgrade.ActiveVersion, shadow.ShadowedPath, upgrade.ShadowedVersion, global, local))
+				continue
+			}
+			comment = fmt.Sprintf("%s: %s (%s) runs instead of the newer %s (%s); removing it lets the upgrade take effect",
+				shadow.ToolName, describeCopy(active), upgrade.ActiveVersion, shadow.ShadowedPath, upgrade.ShadowedVersion)
+			if packages.IsSystemPath(active.Path) {
+				notes = append(notes, fmt.Sprintf("%s: the operating system's %s (%s) runs instead of the newer %s (%s); put %s earlier in PATH",
+					shadow.ToolName, active.Path, upgrade.ActiveVersion, shadow.ShadowedPath, upgrade.ShadowedVersion, filepath.Dir(shadow.ShadowedPath)))
+				continue
+			}
+		} else if packages.IsSystemPath(target.Path) {
+			// System copies shadowed by a managed install are expected
+			continue
+		} else if target.Shim != nil {
+			// Shims are recreated by their manager; it is the versions
+			// behind them that are installed
+			notes = append(notes, fmt.Sprintf("%s: the %s shim %s is shadowed by %s; put %s earlier in PATH to use it",
+				shadow.ToolName, target.Shim.Manager, target.Path, shadow.ActivePath, filepath.Dir(target.Path)))
+			continue
+		}
+
+		if target.PackageName != "" && active.PackageName == shadowed.PackageName && active.PackageManager == shadowed.PackageManager {
+			notes = append(notes, fmt.Sprintf("%s: %s and %s both belong to %s package %s; uninstalling would remove both",
+				shadow.ToolName, shadow.ActivePath, shadow.ShadowedPath, target.PackageManager, target.PackageName))
+			continue
+		}
+
+		action := fixActionFor(target, index, &actions)
+		action.comments = append(action.comments, comment)
+		action.tools[shadow.ToolName] = true
+	}
+
+	// A package is only safe to uninstall when every tool it provides that
+	// is in use is one the action is meant to remove
+	for _, action := range actions {
+		if action.disabled != "" || !strings.Contains(action.key, ":") {
+			continue
+		}
+		var others []string
+		for _, name := range inUse[action.key] {
+			if !action.tools[name] {
+				others = append(others, name)
+			}
+		}
+		if len(others) > 0 {
+			sort.Strings(others)
+			action.disabled = fmt.Sprintf("the package also provides %s, which %s in use", listNames(others), plural(len(others), "is", "are"))
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("#!/bin/sh\n")
+	sb.WriteString(fmt.Sprintf("# Generated by `cli audit --fix-script` on %s.\n", time.Now().Format("2006-01-02 15:04:05")))
+	sb.WriteString("# Review every command before running it. Commands that delete files no package\n")
+	sb.WriteString("# manager owns, or uninstall packages that still provide tools in use, are\n")
+	sb.WriteString("# commented out; uncomment them once you have checked them.\n")
+	sb.WriteString("set -e\n")
+
+	if len(actions) == 0 && len(notes) == 0 {
+		sb.WriteString("\n# Nothing to fix: no removable shadowed or clashing installations were found.\n")
+		return sb.String()
+	}
+
+	for _, action := range actions {
+		sb.WriteString("\n")
+		for _, comment := range action.comments {
+			sb.WriteString("# " + comment + "\n")
+		}
+		if action.disabled != "" {
+			sb.WriteString("# Review first: " + action.disabled + "\n")
+			sb.WriteString("# " + action.command + "\n")
+		} else {
+			sb.WriteString(action.command + "\n")
+		}
+	}
+
+	if len(notes) > 0 {
+		sb.WriteString("\n# Not fixable by uninstalling:\n")
+		for _, note := range notes {
+			sb.WriteString("#   " + note + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// fixActionFor returns the action that removes tool's installation, creating
+// it on first use so a package providing several shadowed tools is
+// uninstalled once
+func fixActionFor(tool models.Tool, index map[string]*fixAction, actions *[]*fixAction) *fixAction {
+	key := resolvedPath(tool.Path)
+	if tool.PackageName != "" {
+		key = tool.PackageManager + ":" + tool.PackageName
+	}
+	if action, ok := index[key]; ok {
+		return action
+	}
+
+	action := &fixAction{key: key, tools: make(map[string]bool)}
+	argv := packages.UninstallCommand(packages.PackageManager(tool.PackageManager), tool.PackageName)
+	switch {
+	case tool.PackageName == "":
+		action.command = "rm " + display.ShellQuote(tool.Path)
+		action.disabled = "not installed by a detected package manager; check where it came from"
+	case argv == nil && tool.PackageManager == string(packages.Poetry):
+		// Deleting the command would leave poetry's environment behind
+		action.command = "rm " + display.ShellQuote(tool.Path)
+		action.disabled = "poetry is removed by its installer; run it with --uninstall instead"
+	case argv == nil:
+		// go and deno have no uninstall for a package; its binaries are the
+		// whole installation
+		action.command = "rm " + display.ShellQuote(tool.Path)
+	default:
+		if tool.PackageManager == string(packages.Pip) {
+			// Uninstall from the environment the tool was installed into
+			if python := filepath.Join(filepath.Dir(tool.Path), "python3"); isFile(python) {
+				argv[0] = python
+			}
+		}
+		quoted := make([]string, len(argv))
+		for i, arg := range argv {
+			quoted[i] = display.ShellQuote(arg)
+		}
+		action.command = strings.Join(quoted, " ")
+	}
+
+	index[key] = action
+	*actions = append(*actions, action)
+	return action
+}
+
+// resolvedPath returns path with every symlink followed, or path itself
+// when it cannot be resolved
+func resolvedPath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return path
+}
+
+// describeCopy names an installation and its owner, e.g.
+// "/usr/local/bin/node (npm package node)"
+func describeCopy(tool models.Tool) string {
+	if tool.PackageName == "" {
+		return tool.Path
+	}
+	return fmt.Sprintf("%s (%s package %s)", tool.Path, tool.PackageManager, tool.PackageName)
+}
+
+// listNames joins up to five names, noting how many were left out
+func listNames(names []string) string {
+	if len(names) <= 5 {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s, and %d more", strings.Join(names[:5], ", "), len(names)-5)
+}
+
+// plural picks the singular or plural form for a count
+func plural(count int, singular, pluralForm string) string {
+	if count == 1 {
+		return singular
+	}
+	return pluralForm
+}
+
+// isFile reports whether path exists and is not a directory
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+func TestGenerateFixScriptShim(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fixture uses a symlinked directory")
+	}
+	root := t.TempDir()
+	shim := filepath.Join(root, "pyenv", "shims", "pip")
+	conda := filepath.Join(root, "miniconda", "bin", "pip")
+	local := filepath.Join(root, "local", "bin", "pip")
+	writeScript(t, shim, "")
+	writeScript(t, conda, "")
+	writeScript(t, local, "")
+	// The same local pip, reached through a symlinked directory
+	alias := filepath.Join(root, "alias")
+	if err := os.Symlink(filepath.Dir(local), alias); err != nil {
+		t.Fatal(err)
+	}
+	aliased := filepath.Join(alias, "pip")
+
+	occurrences := []models.Tool{
+		{Name: "pip", Path: shim, PackageName: "python", PackageManager: "pyenv", Shim: &models.ShimInfo{Manager: "pyenv"}},
+		{Name: "pip", Path: conda, PackageName: "pip", PackageManager: "pip", Shadowed: true, PathRank: 1},
+		{Name: "pip", Path: local, Shadowed: true, PathRank: 2},
+		{Name: "pip", Path: aliased, Shadowed: true, PathRank: 3},
+	}
+	var result AuditResult
+	for _, tool := range occurrences[1:] {
+		shadow := ShadowedTool{ToolName: "pip", ActivePath: shim, ShadowedPath: tool.Path, ActiveVersion: "23.0", ShadowedVersion: "23.2.1"}
+		result.ShadowedTools = append(result.ShadowedTools, shadow)
+		result.NewerShadowed = append(result.NewerShadowed, shadow)
+	}
+
+	script := generateFixScript(result, occurrences)
+	for _, line := range strings.Split(script, "\n") {
+		if !strings.HasPrefix(line, "#") && strings.Contains(line, shim) {
+			t.Errorf("script removes the shim: %q", line)
+		}
+	}
+	if !strings.Contains(script, "pyenv global <version>") {
+		t.Errorf("script does not point to pyenv global:\n%s", script)
+	}
+	if strings.Contains(script, aliased) {
+		t.Errorf("script lists %s, the same file as %s:\n%s", aliased, local, script)
+	}
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/binfmt"
+	"github.com/cli-ai-org/cli/internal/collector"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+var infoJSON bool
+
+// maxInfoHelpLines caps how much help text the human-readable report prints
+const maxInfoHelpLines = 20
+
+// maxSymlinkHops matches the kernel's limit on nested symlinks (MAXSYMLINKS)
+const maxSymlinkHops = 40
+
+// infoCmd represents the info command
+var infoCmd = &cobra.Command{
+	Use:   "info <tool>",
+	Short: "Show everything known about one tool",
+	Long: `Report everything cli can find out about one tool in a single place:
+  - the path that runs and the symlinks followed to reach the real file
+  - the file type and CPU architecture (ELF, Mach-O, PE, or a script and
+    its interpreter)
+  - the package and package manager that installed it
+  - its version, usage line, and help text, probed by running it with
+    --version and --help (cached like export --with-meta)
+  - the one-line summary from its man page
+  - installations later in PATH that it shadows
+
+Use --json to get the full report, including the complete help text and the
+flags and subcommands parsed from it, for automation and agents.`,
+	Example: `  # Everything about git
+  cli info git
+
+  # Structured report for agents
+  cli info kubectl --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		report := toolReport(cmd, args[0])
+
+		if infoJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(report); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		} else if report.Found {
+			showToolReport(report)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: not found in PATH\n", report.Name)
+		}
+
+		if !report.Found {
+			os.Exit(1)
+		}
+	},
+}
+
+// toolReport gathers the report for the installation of name that runs,
+// linking every installation to its package
+func toolReport(cmd *cobra.Command, name string) models.ToolReport {
+	s := newScanner(cmd)
+	tools := s.FindAll(name)
+	if len(tools) == 0 {
+		return models.ToolReport{Name: name}
+	}
+	scanner.CheckInterpreters(tools)
+
+	pkgs, err := newDetector(cmd).DetectAll()
+	if err != nil && !isCancelled(err) {
+		cmd.PrintErrf("Error detecting packages: %v\n", err)
+		os.Exit(1)
+	}
+	linker := newLinker(cmd, pkgs)
+	linker.SetExplain(true)
+	report := describeTool(cmd.Context(), name, linker.LinkTools(tools))
+
+	if cmd.Context().Err() != nil {
+		cmd.PrintErrf("Error: %v\n", cmd.Context().Err())
+		os.Exit(1)
+	}
+	return report
+}
+
+// describeTool builds the report for name from its installations, linked to
+// their packages and in PATH order, probing the one that runs
+func describeTool(ctx context.Context, name string, tools []models.Tool) models.ToolReport {
+	report := models.ToolReport{Name: name}
+	if len(tools) == 0 {
+		return report
+	}
+
+	active := tools[0]
+	report.Found = true
+	report.Path = active.Path
+	report.Size = active.Size
+	report.PackageName = active.PackageName
+	report.PackageManager = packages.Provenance(active)
+	report.PackageVersion = active.PackageVersion
+	report.LinkReason = active.LinkReason
+	report.Shim = active.Shim
+	report.Broken = active.Broken
+	report.BrokenReason = active.BrokenReason
+	report.AppExecAlias = active.AppExecAlias
+	report.Shortcut = active.Shortcut
+
+	report.SymlinkChain, report.ResolvedPath = symlinkChain(active.Path)
+	if format, err := binfmt.Identify(report.ResolvedPath); err == nil {
+		report.FileType = format.Format
+		report.Arch = format.Arch
+		report.Static = format.Static
+		report.Interpreter = format.Interpreter
+	}
+
+	// Broken tools, App Execution Aliases, and shortcuts cannot be run
+	// usefully
+	if !active.Broken && !active.AppExecAlias && !active.Shortcut {
+		c := collector.NewWithOptions(collectorOptions(ctx))
+		if probed, err := c.CollectToolInfo(active.Name, active.Path); err == nil {
+			report.Version = probed.Version
+			report.HelpText = probed.HelpText
+			report.Help = probed.Help
+			report.Usage = collector.UsageLine(probed.HelpText)
+		}
+		report.ManSummary = c.ManDescription(active.Name, active.Path)
+
+		if err := c.SaveMetaCache(); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: could not save metadata cache: %v\n", err)
+		}
+		if err := c.SaveManCache(); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: could not save man page cache: %v\n", err)
+		}
+	}
+
+	for _, tool := range tools[1:] {
+		// /bin/git is not a separate installation when /bin links to /usr/bin
+		if sameFile(tool.Path, active.Path) {
+			continue
+		}
+		report.Shadowed = append(report.Shadowed, models.InstallationInfo{
+			Path:           tool.Path,
+			PackageName:    tool.PackageName,
+			PackageManager: packages.Provenance(tool),
+			Version:        tool.PackageVersion,
+			Broken:         tool.Broken,
+			BrokenReason:   tool.BrokenReason,
+			Shim:           tool.Shim,
+		})
+	}
+	return report
+}
+
+// symlinkChain follows path through every symlink, returning each link
+// target in turn and the final file. A path that is not a symlink has an
+// empty chain and resolves to itself.
+func symlinkChain(path string) ([]string, string) {
+	var chain []string
+	current := path
+	for hops := 0; hops < maxSymlinkHops; hops++ {
+		info, err := os.Lstat(current)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			break
+		}
+		target, err := os.Readlink(current)
+		if err != nil {
+			break
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(current), target)
+		}
+		current = filepath.Clean(target)
+		chain = append(chain, current)
+	}
+	return chain, current
+}
+
+// showToolReport prints a tool report for people
+func showToolReport(report models.ToolReport) {
+	fmt.Fprintf(os.Stdout, "%s\n", report.Name)
+	fmt.Fprintf(os.Stdout, "  Path:      %s\n", report.Path)
+	for _, hop := range report.SymlinkChain {
+		fmt.Fprintf(os.Stdout, "             -> %s\n", hop)
+	}
+	if report.FileType != "" {
+		format := binfmt.Info{
+			Format:      report.FileType,
+			Arch:        report.Arch,
+			Static:      report.Static,
+			Interpreter: report.Interpreter,
+		}
+		fmt.Fprintf(os.Stdout, "  Type:      %s\n", format)
+	}
+	if report.Size > 0 {
+		fmt.Fprintf(os.Stdout, "  Size:      %d bytes\n", report.Size)
+	}
+
+	fmt.Fprintf(os.Stdout, "  Package:   %s\n", describeOwner(models.InstallationInfo{
+		PackageName:    report.PackageName,
+		PackageManager: report.PackageManager,
+		Version:        report.PackageVersion,
+		Shim:           report.Shim,
+	}))
+	if report.LinkReason != "" && report.PackageName != "" && report.Shim == nil {
+		fmt.Fprintf(os.Stdout, "             (%s)\n", report.LinkReason)
+	}
+	if report.Shim != nil && report.Shim.Source != "" {
+		fmt.Fprintf(os.Stdout, "  Selected:  by %s\n", report.Shim.Source)
+	}
+
+	if report.Version != "" {
+		fmt.Fprintf(os.Stdout, "  Version:   %s\n", firstLine(report.Version))
+	}
+	if report.ManSummary != "" {
+		fmt.Fprintf(os.Stdout, "  Summary:   %s\n", report.ManSummary)
+	}
+	if report.Usage != "" {
+		fmt.Fprintf(os.Stdout, "  Usage:     %s\n", report.Usage)
+	}
+
+	if report.Broken {
+		fmt.Fprintf(os.Stdout, "  ⚠ Broken: %s\n", report.BrokenReason)
+	}
+	if report.AppExecAlias {
+		fmt.Fprintln(os.Stdout, "  ⚠ App Execution Alias: not actually installed (opens the Microsoft Store)")
+	}
+	if report.Shortcut {
+		fmt.Fprintln(os.Stdout, "  ⚠ Shortcut: a .lnk file, not an installed tool (only cmd.exe can open it)")
+	}
+
+	if len(report.Shadowed) > 0 {
+		fmt.Fprintf(os.Stdout, "\nShadows %d installation(s) later in PATH:\n", len(report.Shadowed))
+		for _, inst := range report.Shadowed {
+			fmt.Fprintf(os.Stdout, "  %s\n", inst.Path)
+			fmt.Fprintf(os.Stdout, "      %s\n", describeOwner(inst))
+		}
+	}
+
+	if report.HelpText != "" {
+		lines := strings.Split(strings.TrimRight(report.HelpText, "\n"), "\n")
+		fmt.Fprintln(os.Stdout, "\nHelp:")
+		for i, line := range lines {
+			if i == maxInfoHelpLines {
+				fmt.Fprintf(os.Stdout, "  ... %d more lines (use --json for the full text)\n", len(lines)-i)
+				break
+			}
+			fmt.Fprintf(os.Stdout, "  %s\n", line)
+		}
+	}
+}
+
+// firstLine returns text up to its first newline
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+	infoCmd.Flags().BoolVarP(&infoJSON, "json", "j", false, "output the full report in JSON format")
+}
@@ -1,19 +1,37 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"strings"
 
+	"github.com/cli-ai-org/cli/internal/categories"
 	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/listfilter"
+	"github.com/cli-ai-org/cli/internal/models"
 	"github.com/cli-ai-org/cli/internal/packages"
 	"github.com/cli-ai-org/cli/internal/scanner"
+	"github.com/cli-ai-org/cli/internal/variants"
 	"github.com/spf13/cobra"
 )
 
 var (
-	listAll  bool
-	listJSON bool
+	listAll              bool
+	listJSON             bool
+	listFormat           string
+	listPins             []string
+	listMatch            string
+	listRegex            string
+	listCollapseVersions bool
+	listNoFilter         bool
+	listFields           []string
+	listTags             []string
+	listIncludeAliases   bool
 )
 
+// listColumns are the columns of csv and tsv output without --fields
+var listColumns = []string{"name", "path", "manager", "package", "version"}
+
 // listCmd represents the list command
 var listCmd = &cobra.Command{
 	Use:   "list",
@@ -23,7 +41,44 @@ var listCmd = &cobra.Command{
 By default, shows only tools from known packages to provide a clean list of intentionally
 installed CLI tools. Use --all flag to show all executables in your PATH.
 
-Use --json flag to output in JSON format for programmatic access or AI agent consumption.`,
+Only the main tool of each package is shown, and packages that are libraries,
+servers, or helpers are hidden: those in a built-in list (mostly Homebrew
+libraries), those in the config file's list_filter.exclude, and those caught by
+heuristics: more than 10 binaries, three or more binaries none named after the
+package, lib* packages without a binary named after them, and packages installed
+only as another package's dependency. list_filter.include shows a package anyway.
+Use --no-filter to show every tool of every package; with --verbose, hidden
+packages are listed on stderr with the rule that hid them.
+
+Tools named in the config file's always_show list, or with --pin, are always
+shown, bypassing every filter (for example: always_show: [pytest, httpd]).
+
+Windows App Execution Aliases (the WindowsApps python.exe that opens the
+Microsoft Store) and .lnk shortcuts run through PATHEXT are not installed
+tools and are left out, even with --all; --include-aliases lists them too.
+cli audit reports them.
+
+With --collapse-versions, version-suffixed variants of a tool (python3.11,
+python3.12, node18, clang-15) are shown once under a single entry, with the
+other variants listed as aliases.
+
+Each tool is tagged with its categories (build, cloud, compiler, compression,
+container, database, editor, language-runtime, network, package-manager,
+security, shell, vcs), from a built-in list of well-known tools and their
+packages and from the words of the tool's description. Tags are shown after
+each name, and as "tags" in JSON, YAML, TOML, csv, and tsv output. --tag only
+shows tools with one of the given tags; combine it with --all to search every
+executable in PATH.
+
+Output is a table in a terminal and JSON when piped or redirected. Use --format
+(or --json) to choose explicitly; --format yaml and --format toml write the
+same tool records as JSON does.
+
+--format csv and --format tsv write one row per tool, after a header row, for
+spreadsheets and awk. --fields picks the columns: any field of the JSON
+records, plus "manager" and "package" for package_manager and package_name.
+The default is name,path,manager,package,version, where version is the
+version of the tool's package.`,
 	Example: `  # List package-managed CLI tools (default)
   cli list
 
@@ -31,88 +86,93 @@ Use --json flag to output in JSON format for programmatic access or AI agent con
   cli list --all
 
   # List in JSON format for AI agents
-  cli list --json`,
+  cli list --json
+
+  # Only tools whose name starts with kube
+  cli list --match 'kube*'
+
+  # Same, with a regular expression
+  cli list --regex '^kube'
+
+  # Show python3, python3.11, python3.12 as one entry
+  cli list --all --collapse-versions
+
+  # Always show tools the filters would hide
+  cli list --pin pytest --pin httpd
+
+  # Every package-managed tool, without the library and server filters
+  cli list --no-filter
+
+  # Container tooling, wherever it came from
+  cli list --all --tag container
+
+  # YAML for a configuration repo
+  cli list --format yaml > tools.yaml
+
+  # Tab-separated columns for awk
+  cli list --format tsv --fields name,manager,version | awk -F'\t' '$2 == "brew"'
+
+  # Force the human-readable list even when piping
+  cli list --format table | less`,
 	Run: func(cmd *cobra.Command, args []string) {
-		s := scanner.New()
+		format, err := resolveDataFormat(listFormat, listJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		columns, err := checkDelimitedFields(format, listFields, listColumns)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := categories.CheckTags(listTags); err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fields := models.ResolveToolFields(columns)
+		if err := models.ValidateToolFields(fields); err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Pinned tools bypass every filter, in the scanner and below
+		pinned := make(map[string]bool)
+		for _, name := range append(cfg.AlwaysShow, listPins...) {
+			pinned[name] = true
+		}
+
+		s := newScanner(cmd)
+		s.SetAlwaysInclude(append(cfg.AlwaysShow, listPins...))
 		d := display.New(os.Stdout)
 
 		// Scan for tools
 		tools, err := s.ScanAllDetailed()
-		if err != nil {
+		if err != nil && !isCancelled(err) {
 			cmd.PrintErrf("Error scanning for tools: %v\n", err)
 			os.Exit(1)
 		}
+		if !listIncludeAliases {
+			tools = scanner.WithoutLaunchers(tools)
+		}
 
 		// By default, show only tools from packages (unless --all is specified)
 		if !listAll {
-			detector := packages.NewDetector()
+			detector := newDetector(cmd)
 			pkgs, err := detector.DetectAll()
-			if err != nil {
+			if err != nil && !isCancelled(err) {
 				cmd.PrintErrf("Error detecting packages: %v\n", err)
 				os.Exit(1)
 			}
 
-			linker := packages.NewLinker(pkgs)
+			linker := newLinker(cmd, pkgs)
 			linkedTools := linker.LinkTools(tools)
 
-			// Count binaries per package to filter out library packages
-			pkgBinaryCount := make(map[string]int)
-			for _, tool := range linkedTools {
-				if tool.PackageName != "" {
-					pkgBinaryCount[tool.PackageName]++
-				}
-			}
-
-			// Packages to exclude (libraries, servers, daemons, not user-facing CLIs)
-			excludePackages := map[string]bool{
-				// Development libraries
-				"gcc": true, "netpbm": true, "gd": true, "gdal": true,
-				"gettext": true, "libtiff": true, "libpng": true, "fontconfig": true,
-				"glib": true, "hdf5": true, "graphviz": true, "gts": true,
-				"mbedtls": true, "nss": true, "perl": true, "tesseract": true,
-				"pcre": true, "pcre2": true, "python@3.11": true, "python@3.13": true,
-				"xz": true, "ffmpeg": true, "libsndfile": true, "little-cms2": true,
-				"jpeg-xl": true, "libfido2": true, "libgcrypt": true, "libheif": true,
-				"c-ares": true, "libtasn1": true, "libavif": true, "libbluray": true,
-				"cairo": true, "jpeg-turbo": true, "zeromq": true, "tcl-tk": true,
-				"libdap": true, "libde265": true, "libgeotiff": true, "libidn2": true,
-				"librist": true, "libvmaf": true, "lua": true, "autoconf": true,
-				"brotli": true, "flac": true, "giflib": true, "lame": true,
-				"leptonica": true, "libassuan": true, "libdeflate": true, "libevent": true,
-				"libgpg-error": true, "libksba": true, "lz4": true, "m4": true,
-				"miniupnpc": true, "mpg123": true, "nettle": true, "nghttp2": true,
-				"oniguruma": true, "openexr": true, "openjpeg": true, "opus": true,
-				"p11-kit": true, "pango": true, "pkgconf": true, "proj": true,
-				"qhull": true, "rav1e": true, "rubberband": true, "sdl2": true,
-				"speex": true, "srt": true, "unbound": true, "uriparser": true,
-				"webp": true, "x264": true, "x265": true, "dav1d": true, "aom": true,
-				"gnupg": true, "gnutls": true, "gpgme": true, "gobject-introspection": true,
-				"grpc": true, "guile": true, "harfbuzz": true, "jasper": true,
-				"jemalloc": true, "libtool": true, "nspr": true,
-				"cfitsio": true, "gdbm": true, "netcdf": true, "freetype": true,
-				"fribidi": true, "fmt": true, "gdk-pixbuf": true, "geos": true,
-				"gflags": true, "fizz": true, "epsilon": true, "unixodbc": true,
-				"openssl@3": true, "shared-mime-info": true,
-				"apache-arrow": true, "protobuf": true, "protobuf@29": true,
-				// Python/Ruby library packages (not CLIs)
-				"aiosmtpd": true, "comm": true, "date": true, "distro": true,
-				"ecdsa": true, "email_validator": true, "httpx": true, "logger": true,
-				"pi": true, "screen": true, "sync": true, "typer": true,
-				"fonttools": true, "jsonpointer": true, "jsonschema": true,
-				"pycodestyle": true, "pyflakes": true, "tqdm": true, "tabulate": true,
-				"watchfiles": true, "webdriverdownloader": true,
-				// Servers/Daemons
-				"gunicorn": true, "uvicorn": true, "postgresql@14": true, "postgresql@17": true,
-				"redis": true, "transmission-cli": true,
-				// Editor variants and utilities
-				"emacs": true, "vim": true, "zsh": true, "grep": true,
-				// Compression utilities
-				"zstd": true, "xxhash": true,
-				// Development utilities
-				"tree-sitter": true, "luajit": true, "openssl": true, "pinentry": true,
-				"numpy": true, "librsvg": true, "telnet": true, "ssh-copy-id": true,
-				"solidity": true, "thrift": true, "fbthrift": true, "z3": true,
+			filter, err := listfilter.New(cfg.ListFilter)
+			if err != nil {
+				cmd.PrintErrf("Error: list_filter: %v\n", err)
+				os.Exit(1)
 			}
+			hidden := hiddenPackages(filter, linkedTools, pkgs)
 
 			// Build a map of package -> main binary name
 			packageMainBinary := make(map[string]string)
@@ -130,53 +190,161 @@ Use --json flag to output in JSON format for programmatic access or AI agent con
 
 			// Get CLI tools - show only main binary per package
 			seenTools := make(map[string]bool)
-			var cliTools []string
+			var cliTools []models.Tool
 			for _, tool := range linkedTools {
+				if seenTools[tool.Name] {
+					continue
+				}
+
+				if pinned[tool.Name] {
+					cliTools = append(cliTools, tool)
+					seenTools[tool.Name] = true
+					continue
+				}
+
 				pkgName := tool.PackageName
-				if pkgName == "" || seenTools[tool.Name] {
+				if pkgName == "" {
 					continue
 				}
 
-				// Skip excluded packages
-				if excludePackages[pkgName] {
+				// With --no-filter, every binary of every package is shown
+				if listNoFilter {
+					cliTools = append(cliTools, tool)
+					seenTools[tool.Name] = true
 					continue
 				}
 
-				// Skip packages with too many binaries (>10) - likely libraries
-				if pkgBinaryCount[pkgName] > 10 {
+				// Skip libraries, servers, and helpers
+				if hidden[tool.PackageManager+":"+pkgName] {
 					continue
 				}
 
 				// Only show the main binary for each package
 				mainBinary := packageMainBinary[pkgName]
 				if tool.Name == mainBinary {
-					cliTools = append(cliTools, tool.Name)
+					cliTools = append(cliTools, tool)
 					seenTools[tool.Name] = true
 				}
 			}
-			d.ShowTools(cliTools)
-			return
+			tools = cliTools
 		}
 
-		// With --all, show all executables
-		if listJSON {
+		tools, err = matchTools(tools, listMatch, listRegex)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		categories.Tag(tools)
+		if len(listTags) > 0 {
+			tools = categories.FilterTags(tools, listTags)
+		}
+
+		if listCollapseVersions {
+			tools = variants.Collapse(tools)
+		}
+
+		switch format {
+		case formatJSON:
 			if err := d.ShowToolsJSON(tools, true); err != nil {
 				cmd.PrintErrf("Error encoding JSON: %v\n", err)
 				os.Exit(1)
 			}
-		} else {
+		case formatYAML:
+			if err := d.ShowToolsYAML(tools); err != nil {
+				cmd.PrintErrf("Error encoding YAML: %v\n", err)
+				os.Exit(1)
+			}
+		case formatTOML:
+			if err := d.ShowToolsTOML(tools); err != nil {
+				cmd.PrintErrf("Error encoding TOML: %v\n", err)
+				os.Exit(1)
+			}
+		case formatCSV, formatTSV:
+			// list does not run tools, so a version column shows the
+			// version of the package that provides each one
+			for i := range tools {
+				if tools[i].Version == "" {
+					tools[i].Version = tools[i].PackageVersion
+				}
+			}
+			if err := d.ShowToolsDelimited(tools, columns, fields, delimiter(format)); err != nil {
+				cmd.PrintErrf("Error writing %s: %v\n", strings.ToUpper(format), err)
+				os.Exit(1)
+			}
+		default:
 			// Simple name list
 			var names []string
 			for _, tool := range tools {
-				names = append(names, tool.Name)
+				name := tool.Name
+				if len(tool.Aliases) > 0 {
+					name = fmt.Sprintf("%s (also %s)", name, strings.Join(tool.Aliases, ", "))
+				}
+				if len(tool.Tags) > 0 {
+					name = fmt.Sprintf("%s [%s]", name, strings.Join(tool.Tags, ", "))
+				}
+				names = append(names, name)
 			}
 			d.ShowTools(names)
 		}
+		warnIfPartial(cmd)
 	},
 }
 
+// hiddenPackages applies the list filter to every package that provides a
+// tool, returning the hidden ones keyed by manager and name. With --verbose,
+// each is reported on stderr with the reason.
+func hiddenPackages(filter *listfilter.Filter, tools []models.Tool, pkgs []packages.Package) map[string]bool {
+	dependency := make(map[string]bool)
+	for _, pkg := range pkgs {
+		if pkg.InstalledAsDependency {
+			dependency[string(pkg.Manager)+":"+pkg.Name] = true
+		}
+	}
+
+	var order []string
+	provided := make(map[string]*listfilter.Package)
+	for _, tool := range tools {
+		if tool.PackageName == "" {
+			continue
+		}
+		key := tool.PackageManager + ":" + tool.PackageName
+		pkg, ok := provided[key]
+		if !ok {
+			pkg = &listfilter.Package{
+				Name:       tool.PackageName,
+				Manager:    tool.PackageManager,
+				Dependency: dependency[key],
+			}
+			provided[key] = pkg
+			order = append(order, key)
+		}
+		pkg.Binaries = append(pkg.Binaries, tool.Name)
+	}
+
+	hidden := make(map[string]bool)
+	for _, key := range order {
+		if hide, reason := filter.Hidden(*provided[key]); hide {
+			hidden[key] = true
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Hiding %s (%s)\n", key, reason)
+			}
+		}
+	}
+	return hidden
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "show ALL executables in PATH (not just package-managed)")
 	listCmd.Flags().BoolVarP(&listJSON, "json", "j", false, "output in JSON format for AI agents")
+	listCmd.Flags().StringSliceVar(&listPins, "pin", nil, "always show this tool regardless of filtering (repeatable)")
+	listCmd.Flags().StringVar(&listMatch, "match", "", "only show tools whose name matches this shell glob (e.g. 'kube*')")
+	listCmd.Flags().StringVar(&listRegex, "regex", "", "only show tools whose name matches this regular expression")
+	listCmd.Flags().BoolVar(&listNoFilter, "no-filter", false, "show every tool of every package, without hiding libraries, servers, and helpers")
+	listCmd.Flags().BoolVar(&listIncludeAliases, "include-aliases", false, "also show Windows App Execution Aliases and .lnk shortcuts, which are not installed tools")
+	listCmd.Flags().BoolVar(&listCollapseVersions, "collapse-versions", false, "show version-suffixed variants (python3.11, python3.12) as one entry")
+	listCmd.Flags().StringVarP(&listFormat, "format", "f", "", "output format: table, json, yaml, toml, csv, or tsv (default: table in a terminal, json when piped)")
+	listCmd.Flags().StringSliceVar(&listTags, "tag", nil, "only show tools with this tag, e.g. container or vcs (repeatable)")
+	listCmd.Flags().StringSliceVar(&listFields, "fields", nil, "columns of csv and tsv output (default: name,path,manager,package,version)")
 }
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/merge"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeOutput string
+	mergeFormat string
+	mergePretty bool
+)
+
+// mergeCmd represents the merge command
+var mergeCmd = &cobra.Command{
+	Use:   "merge <catalog> <catalog>...",
+	Short: "Combine exported catalogs into one fleet catalog",
+	Long: `Combine catalogs written by "cli export" on several machines into one,
+for fleet-wide inventory. Both JSON catalogs and NDJSON files written by
+"export --format ndjson" or "export --append" are accepted.
+
+Tools are de-duplicated by name, path, and hostname. Tools that carry no
+hostname, as in a plain export, are attributed to their file's name without
+the extension, so export each machine to <hostname>.json to keep per-host
+attribution.
+
+When the same tool appears in several files, later files win for the fields
+they set and fields they leave empty keep the earlier value, so merging a
+--with-meta export after a plain one fills in versions and help text.
+Packages are de-duplicated by manager, name, version, and location.
+
+A summary of how many tools came from each file is printed to stderr.`,
+	Example: `  # Combine per-host exports into one catalog
+  cli merge web-01.json web-02.json db-01.json -o fleet.json
+
+  # Fold a day's appended records into an existing fleet catalog
+  cli merge fleet.json /mnt/shared/today.ndjson -o fleet.json --pretty
+
+  # Merge to NDJSON on stdout
+  cli merge a.json b.json --format ndjson`,
+	Args: cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if mergeFormat != "json" && mergeFormat != "ndjson" {
+			cmd.PrintErrf("Error: unknown format %q (valid: json, ndjson)\n", mergeFormat)
+			os.Exit(1)
+		}
+
+		var inputs []merge.Input
+		for _, path := range args {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				cmd.PrintErrf("Error reading catalog: %v\n", err)
+				os.Exit(1)
+			}
+			catalog, err := models.DecodeCatalog(data)
+			if err != nil {
+				cmd.PrintErrf("Error: %s is not a valid catalog: %v (run `cli validate %s` for details)\n", path, err, path)
+				os.Exit(1)
+			}
+			inputs = append(inputs, merge.Input{Name: path, Catalog: catalog})
+		}
+
+		result := merge.Catalogs(inputs)
+
+		// Read every input before creating the output, which may be one of them
+		writer := os.Stdout
+		if mergeOutput != "" {
+			file, err := os.Create(mergeOutput)
+			if err != nil {
+				cmd.PrintErrf("Error creating output file: %v\n", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			writer = file
+		}
+
+		d := display.New(writer)
+		var err error
+		if mergeFormat == "ndjson" {
+			err = d.ShowCatalogNDJSON(result.Catalog)
+		} else {
+			err = d.ShowCatalogJSON(result.Catalog, mergePretty)
+		}
+		if err != nil {
+			cmd.PrintErrf("Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+
+		table := display.NewTable(os.Stderr, "SOURCE", "TOOLS", "ADDED", "DUPLICATE", "HOSTS")
+		table.AlignRight(1, 2, 3)
+		for _, source := range result.Sources {
+			table.Row(source.Name, strconv.Itoa(source.Tools), strconv.Itoa(source.Added), strconv.Itoa(source.Duplicates),
+				strings.Join(source.Hostnames, ", "))
+		}
+		table.Flush()
+		fmt.Fprintf(os.Stderr, "\nMerged %d tools and %d packages from %d catalogs", result.Catalog.TotalTools, result.Catalog.TotalPackages, len(result.Sources))
+		if mergeOutput != "" {
+			fmt.Fprintf(os.Stderr, " into %s", mergeOutput)
+		}
+		fmt.Fprintln(os.Stderr)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+	mergeCmd.Flags().StringVarP(&mergeOutput, "output", "o", "", "output file (default: stdout)")
+	mergeCmd.Flags().StringVarP(&mergeFormat, "format", "f", "json", "output format (json, ndjson)")
+	mergeCmd.Flags().BoolVarP(&mergePretty, "pretty", "p", false, "pretty-print JSON output")
+}
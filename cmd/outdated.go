@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/outdated"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/spf13/cobra"
+)
+
+var (
+	outdatedJSON     bool
+	outdatedFormat   string
+	outdatedManager  string
+	outdatedRegistry bool
+)
+
+// outdatedCmd represents the outdated command
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "Show CLI-providing packages with newer versions available",
+	Long: `Report which packages that provide CLI tools have a newer version available.
+
+Each package manager's own outdated reporting is used because it answers for
+every package in a single call. When it fails, for example because the
+manager is not on PATH or cannot reach its index, the manager's registry is
+queried for each package instead:
+  - pip:   pip list --outdated, else the PyPI API
+  - npm:   npm outdated -g, else the npm registry
+  - brew:  brew outdated, else the Homebrew API (formulae.brew.sh)
+  - gem:   gem outdated, else the RubyGems API
+  - cargo: the crates.io API
+
+With --registry, the registries are queried for every manager, skipping the
+managers' own commands. Packages a registry does not know, such as ones
+installed from another index, are left out.
+
+Only packages that provide at least one CLI tool on your PATH are reported.`,
+	Example: `  # Show outdated CLI packages
+  cli outdated
+
+  # Only check Homebrew
+  cli outdated --manager brew
+
+  # Ask crates.io, PyPI, and the other registries directly
+  cli outdated --registry
+
+  # JSON output for scripts and agents
+  cli outdated --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveFormat(outdatedFormat, outdatedJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		managers := outdated.Managers()
+		if outdatedManager != "" {
+			var names []string
+			for _, manager := range managers {
+				names = append(names, string(manager))
+			}
+			found := false
+			for _, manager := range managers {
+				if string(manager) == outdatedManager {
+					found = true
+				}
+			}
+			if !found {
+				cmd.PrintErrf("Error: outdated check not supported for %q (valid: %s)\n", outdatedManager, strings.Join(names, ", "))
+				os.Exit(1)
+			}
+		}
+
+		detector := newDetector(cmd)
+		pkgs, err := detector.DetectAll()
+		if err != nil {
+			cmd.PrintErrf("Error detecting packages: %v\n", err)
+			os.Exit(1)
+		}
+
+		s := newScanner(cmd)
+		tools, err := s.ScanAllDetailed()
+		if err != nil {
+			cmd.PrintErrf("Error scanning tools: %v\n", err)
+			os.Exit(1)
+		}
+		tools = newLinker(cmd, pkgs).LinkTools(tools)
+
+		// Restrict to packages that actually provide a CLI
+		providers := make(map[string]bool)
+		for _, info := range packages.GetPackagesWithBinaries(pkgs, tools) {
+			providers[outdatedKey(info.Manager, info.Name)] = true
+		}
+
+		byManager := make(map[packages.PackageManager][]packages.Package)
+		for _, pkg := range pkgs {
+			if providers[outdatedKey(string(pkg.Manager), pkg.Name)] {
+				byManager[pkg.Manager] = append(byManager[pkg.Manager], pkg)
+			}
+		}
+
+		checker := outdated.NewChecker()
+		checker.SetContext(cmd.Context())
+		checker.SetRegistryOnly(outdatedRegistry)
+		var results []outdated.Result
+		for _, manager := range managers {
+			if outdatedManager != "" && string(manager) != outdatedManager {
+				continue
+			}
+			if len(byManager[manager]) == 0 {
+				continue
+			}
+
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Checking %s for outdated packages...\n", manager)
+			}
+			found, err := checker.Check(manager, byManager[manager])
+			if err != nil {
+				if verbose {
+					fmt.Fprintf(os.Stderr, "Warning: %s check failed: %v\n", manager, err)
+				}
+				continue
+			}
+			for _, r := range found {
+				if providers[outdatedKey(r.Manager, r.Name)] {
+					results = append(results, r)
+				}
+			}
+		}
+
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].Manager != results[j].Manager {
+				return results[i].Manager < results[j].Manager
+			}
+			return results[i].Name < results[j].Name
+		})
+
+		if format == formatJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if results == nil {
+				results = []outdated.Result{}
+			}
+			if err := encoder.Encode(results); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if len(results) == 0 {
+			fmt.Fprintln(os.Stdout, "All CLI-providing packages are up to date.")
+			return
+		}
+
+		fmt.Fprintf(os.Stdout, "Found %d outdated packages with CLI tools:\n\n", len(results))
+		table := display.NewTable(os.Stdout, "PACKAGE", "MANAGER", "CURRENT", "LATEST")
+		table.Rule = true
+		for _, r := range results {
+			table.Row(r.Name, display.Manager(string(r.Manager)), r.Current, r.Latest)
+		}
+		table.Flush()
+	},
+}
+
+// outdatedKey identifies a package across managers. pip normalizes names
+// case-insensitively, so keys are lowercased.
+func outdatedKey(manager, name string) string {
+	return manager + ":" + strings.ToLower(name)
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+	outdatedCmd.Flags().BoolVarP(&outdatedJSON, "json", "j", false, "output in JSON format")
+	outdatedCmd.Flags().StringVarP(&outdatedFormat, "format", "f", "", "output format: table or json (default: table in a terminal, json when piped)")
+	outdatedCmd.Flags().StringVarP(&outdatedManager, "manager", "m", "", "only check one package manager (pip, npm, brew, gem, cargo)")
+	outdatedCmd.Flags().BoolVar(&outdatedRegistry, "registry", false, "query package registries directly instead of the managers' outdated commands (needs network)")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cli-ai-org/cli/internal/display"
+)
+
+// Output formats shared by commands that offer both a human and a machine view
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// Machine formats for commands whose output suits configuration repos,
+// spreadsheets, and awk
+const (
+	formatYAML = "yaml"
+	formatTOML = "toml"
+	formatCSV  = "csv"
+	formatTSV  = "tsv"
+)
+
+// resolveFormat picks a command's output format. An explicit --format wins,
+// then --json, then the config file's output_format; otherwise JSON is used
+// when stdout is piped or redirected and a table when it is an interactive
+// terminal.
+func resolveFormat(format string, jsonFlag bool) (string, error) {
+	switch format {
+	case formatTable, formatJSON:
+		return format, nil
+	case "":
+	default:
+		return "", fmt.Errorf("unknown format %q (valid: %s, %s)", format, formatTable, formatJSON)
+	}
+
+	if jsonFlag {
+		return formatJSON, nil
+	}
+	if cfg.OutputFormat != "" {
+		return cfg.OutputFormat, nil
+	}
+	if !display.IsTerminal(os.Stdout) {
+		return formatJSON, nil
+	}
+	return formatTable, nil
+}
+
+// resolveDataFormat is resolveFormat for commands that can also write YAML,
+// TOML, CSV, or TSV. Those are only used when asked for by name.
+func resolveDataFormat(format string, jsonFlag bool) (string, error) {
+	switch format {
+	case formatYAML, formatTOML, formatCSV, formatTSV:
+		return format, nil
+	}
+	resolved, err := resolveFormat(format, jsonFlag)
+	if err != nil {
+		return "", fmt.Errorf("unknown format %q (valid: %s, %s, %s, %s, %s, %s)", format, formatTable, formatJSON, formatYAML, formatTOML, formatCSV, formatTSV)
+	}
+	return resolved, nil
+}
+
+// delimiter returns the cell separator of a delimited format
+func delimiter(format string) rune {
+	if format == formatTSV {
+		return '\t'
+	}
+	return ','
+}
+
+// checkDelimitedFields checks that --fields is only given with csv or tsv
+// output, and falls back to the default columns when it is not given
+func checkDelimitedFields(format string, fields, defaults []string) ([]string, error) {
+	if format != formatCSV && format != formatTSV {
+		if len(fields) > 0 {
+			return nil, fmt.Errorf("--fields only applies to csv and tsv output, not %q", format)
+		}
+		return nil, nil
+	}
+	if len(fields) == 0 {
+		return defaults, nil
+	}
+	return fields, nil
+}
@@ -5,17 +5,27 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
 	"github.com/cli-ai-org/cli/internal/packages"
-	"github.com/cli-ai-org/cli/internal/scanner"
 	"github.com/spf13/cobra"
 )
 
 var (
 	packagesJSON    bool
+	packagesFormat  string
 	packagesManager string
+	packagesProfile bool
+	packagesFields  []string
 )
 
+// packagesColumns are the columns of csv and tsv output without --fields
+var packagesColumns = []string{"name", "manager", "version", "binaries"}
+
 // packagesCmd represents the packages command
 var packagesCmd = &cobra.Command{
 	Use:   "packages",
@@ -24,7 +34,18 @@ var packagesCmd = &cobra.Command{
 that provide command-line tools.
 
 This helps identify which package a CLI tool comes from, useful for tools
-like vercel, supabase, aws-cli, etc.`,
+like vercel, supabase, aws-cli, etc.
+
+With --profile, each package manager's detection is run and timed instead,
+slowest first, to find which one makes detection slow on this machine.
+
+Output is a table in a terminal and JSON when piped or redirected. Use --format
+(or --json) to choose explicitly; --format yaml and --format toml write the
+same package records as JSON does.
+
+--format csv and --format tsv write one row per package, after a header row,
+with the columns --fields picks from the JSON records' fields (default:
+name,manager,version,binaries). A package's binaries are joined with commas.`,
 	Example: `  # List all packages with CLI tools
   cli packages
 
@@ -34,17 +55,51 @@ like vercel, supabase, aws-cli, etc.`,
   # List in JSON format
   cli packages --json
 
+  # TOML, as an array of [[packages]] tables
+  cli packages --format toml
+
+  # A spreadsheet of packages and where they live
+  cli packages --format csv --fields name,manager,version,location > packages.csv
+
+  # Find which package manager makes detection slow
+  cli packages --profile
+
   # Find which package provides a tool
-  cli packages | grep vercel`,
+  cli packages --format table | grep vercel`,
 	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveDataFormat(packagesFormat, packagesJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fields, err := checkDelimitedFields(format, packagesFields, packagesColumns)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := models.ValidatePackageFields(fields); err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if packagesProfile {
+			if format != formatTable && format != formatJSON {
+				cmd.PrintErrf("Error: --profile only supports table and json output, not %q\n", format)
+				os.Exit(1)
+			}
+			profilePackageManagers(cmd, format)
+			return
+		}
+
 		if verbose {
 			fmt.Fprintln(os.Stderr, "Detecting packages from package managers...")
 		}
 
 		// Detect packages
-		detector := packages.NewDetector()
+		detector := newDetector(cmd)
 		pkgs, err := detector.DetectAll()
-		if err != nil {
+		if err != nil && !isCancelled(err) {
 			cmd.PrintErrf("Error detecting packages: %v\n", err)
 			os.Exit(1)
 		}
@@ -61,28 +116,43 @@ like vercel, supabase, aws-cli, etc.`,
 		}
 
 		// Link packages to tools to find which packages provide CLIs
-		s := scanner.New()
+		s := newScanner(cmd)
 		tools, err := s.ScanAllDetailed()
-		if err != nil {
+		if err != nil && !isCancelled(err) {
 			cmd.PrintErrf("Error scanning tools: %v\n", err)
 			os.Exit(1)
 		}
 
-		linker := packages.NewLinker(pkgs)
+		linker := newLinker(cmd, pkgs)
 		enrichedTools := linker.LinkTools(tools)
 
 		// Get packages that have binaries
 		pkgsWithBinaries := packages.GetPackagesWithBinaries(pkgs, enrichedTools)
+		warnIfPartial(cmd)
 
-		if packagesJSON {
-			// JSON output
-			encoder := json.NewEncoder(os.Stdout)
-			encoder.SetIndent("", "  ")
-			if err := encoder.Encode(pkgsWithBinaries); err != nil {
+		d := display.New(os.Stdout)
+		switch format {
+		case formatJSON:
+			if err := d.ShowPackagesJSON(pkgsWithBinaries); err != nil {
 				cmd.PrintErrf("Error encoding JSON: %v\n", err)
 				os.Exit(1)
 			}
-		} else {
+		case formatYAML:
+			if err := d.ShowPackagesYAML(pkgsWithBinaries); err != nil {
+				cmd.PrintErrf("Error encoding YAML: %v\n", err)
+				os.Exit(1)
+			}
+		case formatTOML:
+			if err := d.ShowPackagesTOML(pkgsWithBinaries); err != nil {
+				cmd.PrintErrf("Error encoding TOML: %v\n", err)
+				os.Exit(1)
+			}
+		case formatCSV, formatTSV:
+			if err := d.ShowPackagesDelimited(pkgsWithBinaries, fields, fields, delimiter(format)); err != nil {
+				cmd.PrintErrf("Error writing %s: %v\n", strings.ToUpper(format), err)
+				os.Exit(1)
+			}
+		default:
 			// Human-readable output
 			if len(pkgsWithBinaries) == 0 {
 				fmt.Fprintln(os.Stdout, "No packages with CLI tools found.")
@@ -95,8 +165,8 @@ like vercel, supabase, aws-cli, etc.`,
 			})
 
 			fmt.Fprintf(os.Stdout, "Found %d packages with CLI tools:\n\n", len(pkgsWithBinaries))
-			fmt.Fprintf(os.Stdout, "%-30s %-10s %-15s %s\n", "PACKAGE", "MANAGER", "VERSION", "CLIs")
-			fmt.Fprintf(os.Stdout, "%-30s %-10s %-15s %s\n", "-------", "-------", "-------", "----")
+			table := display.NewTable(os.Stdout, "PACKAGE", "MANAGER", "VERSION", "CLIs")
+			table.Rule = true
 
 			for _, pkg := range pkgsWithBinaries {
 				binaries := "none"
@@ -109,13 +179,9 @@ like vercel, supabase, aws-cli, etc.`,
 						binaries = fmt.Sprintf("%d binaries", len(pkg.Binaries))
 					}
 				}
-				fmt.Fprintf(os.Stdout, "%-30s %-10s %-15s %s\n",
-					pkg.Name,
-					pkg.Manager,
-					pkg.Version,
-					binaries,
-				)
+				table.Row(pkg.Name, display.Manager(pkg.Manager), pkg.Version, binaries)
 			}
+			table.Flush()
 
 			if verbose {
 				fmt.Fprintf(os.Stderr, "\nTotal packages scanned: %d\n", len(pkgs))
@@ -125,8 +191,58 @@ like vercel, supabase, aws-cli, etc.`,
 	},
 }
 
+// profilePackageManagers times each package manager's detection and prints
+// the results slowest first
+func profilePackageManagers(cmd *cobra.Command, format string) {
+	detector := newDetector(cmd)
+	if packagesManager != "" {
+		detector.SetManagers([]packages.PackageManager{packages.PackageManager(packagesManager)})
+	}
+	timings := detector.Profile()
+	warnIfPartial(cmd)
+
+	if format == formatJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(timings); err != nil {
+			cmd.PrintErrf("Error encoding JSON: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var total time.Duration
+	for _, timing := range timings {
+		total += timing.Duration
+	}
+
+	table := display.NewTable(os.Stdout, "MANAGER", "DURATION", "PACKAGES", "STATUS")
+	table.Rule = true
+	table.AlignRight(1, 2)
+	for _, timing := range timings {
+		status := display.Severity("pass", "ok")
+		if timing.Error != "" {
+			status = display.Severity("fail", "failed: "+timing.Error)
+		}
+		table.Row(display.Manager(string(timing.Manager)), timing.Duration.Round(time.Millisecond).String(),
+			strconv.Itoa(timing.Packages), status)
+	}
+	table.Flush()
+	fmt.Fprintf(os.Stdout, "\nTotal: %s\n", total.Round(time.Millisecond))
+
+	if len(timings) > 0 && total > 0 {
+		slowest := timings[0]
+		fmt.Fprintf(os.Stdout, "Bottleneck: %s (%s, %.0f%% of total)\n",
+			slowest.Manager, slowest.Duration.Round(time.Millisecond),
+			float64(slowest.Duration)/float64(total)*100)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(packagesCmd)
 	packagesCmd.Flags().BoolVarP(&packagesJSON, "json", "j", false, "output in JSON format")
-	packagesCmd.Flags().StringVarP(&packagesManager, "manager", "m", "", "filter by package manager (npm, pip, brew, cargo, gem)")
+	packagesCmd.Flags().StringVarP(&packagesFormat, "format", "f", "", "output format: table, json, yaml, toml, csv, or tsv (default: table in a terminal, json when piped)")
+	packagesCmd.Flags().StringSliceVar(&packagesFields, "fields", nil, "columns of csv and tsv output (default: name,manager,version,binaries)")
+	packagesCmd.Flags().BoolVar(&packagesProfile, "profile", false, "time each package manager's detection instead of listing packages")
+	packagesCmd.Flags().StringVarP(&packagesManager, "manager", "m", "", "filter by package manager (npm, pip, pipx, uv, poetry, brew, cargo, go, deno, dotnet, composer, gem, macports, nix, snap, flatpak, scoop, choco, winget)")
 }
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/plugins"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pluginsJSON   bool
+	pluginsFormat string
+)
+
+// pluginsCmd represents the plugins command
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins [host]",
+	Short: "List kubectl, gh, cargo, and git plugins",
+	Long: `List the plugins installed for kubectl, gh, cargo, and git: executables
+each of them runs as one of its own subcommands.
+
+  - kubectl: kubectl-* executables in PATH; those in krew's bin directory
+    ($KREW_ROOT/bin or ~/.krew/bin) are shown with the version krew installed
+  - gh:      extensions, from gh extension list (or gh's extensions directory
+    with --no-exec)
+  - cargo:   cargo-* executables in PATH
+  - git:     git-* executables in PATH, except the ones git installs for its
+    own use (git-upload-pack, git-shell, ...)
+
+kubectl-view_secret runs as "kubectl view-secret", and kubectl-foo-bar as
+"kubectl foo bar", as kubectl does. Give a host to list only its plugins.
+
+cli export records the same plugins in the catalog: each host tool gets a
+"plugins" list, and each plugin executable a "plugin" object naming its host
+and the command that runs it.`,
+	Example: `  # Every plugin
+  cli plugins
+
+  # kubectl plugins, including those installed with krew
+  cli plugins kubectl
+
+  # JSON output for agents
+  cli plugins --json`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveFormat(pluginsFormat, pluginsJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		host := ""
+		if len(args) == 1 {
+			host = args[0]
+			known := false
+			for _, name := range plugins.Hosts {
+				if name == host {
+					known = true
+				}
+			}
+			if !known {
+				cmd.PrintErrf("Error: unknown host %q (valid: %s)\n", host, strings.Join(plugins.Hosts, ", "))
+				os.Exit(1)
+			}
+		}
+
+		s := newScanner(cmd)
+		tools, err := s.ScanAllDetailed()
+		if err != nil && !isCancelled(err) {
+			cmd.PrintErrf("Error scanning tools: %v\n", err)
+			os.Exit(1)
+		}
+
+		found := []models.PluginInfo{}
+		for _, plugin := range newPluginDiscoverer(cmd).Discover(tools) {
+			if host == "" || plugin.Host == host {
+				found = append(found, plugin)
+			}
+		}
+		warnIfPartial(cmd)
+
+		if format == formatJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(found); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		if len(found) == 0 {
+			fmt.Fprintln(os.Stdout, "No plugins found.")
+			return
+		}
+		table := display.NewTable(os.Stdout, "COMMAND", "SOURCE", "VERSION", "PATH")
+		for _, plugin := range found {
+			table.Row(plugin.Command, plugin.Source, plugin.Version, plugin.Path)
+		}
+		table.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pluginsCmd)
+	pluginsCmd.Flags().BoolVarP(&pluginsJSON, "json", "j", false, "output in JSON format")
+	pluginsCmd.Flags().StringVarP(&pluginsFormat, "format", "f", "", "output format: table or json (default: table in a terminal, json when piped)")
+}
@@ -0,0 +1,293 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/categories"
+	"github.com/cli-ai-org/cli/internal/collector"
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/policy"
+	"github.com/cli-ai-org/cli/internal/safety"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of cli policy check, for wrappers to act on without parsing
+// its output; errors exit with exitError
+const (
+	exitPolicyDeny    = 1
+	exitPolicyConfirm = 3
+)
+
+var (
+	policyOutput   string
+	policyFormat   string
+	policyRules    string
+	policyAllow    []string
+	policyConfirm  []string
+	policyDeny     []string
+	policyWithMeta bool
+	policyFile     string
+	policyJSON     bool
+)
+
+// policyCmd represents the policy command
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Generate and enforce a policy of which tools an agent may run",
+	Long: `Generate a policy file listing the tools in PATH an agent may run, grouped by
+category and risk, and check commands against it from the wrappers that
+run an agent's commands.
+
+Each tool is decided allow, confirm (ask the user first), or deny. Tools are
+decided by their risks (see cli export --with-safety), their categories
+(see cli list --tag), and rules naming them; tools the policy does not list,
+such as ones installed after it was generated, get its default decision.`,
+	Example: `  # Write the default policy
+  cli policy generate -o ~/.cli-policy.yaml
+
+  # Allow version control and build tools, deny everything in the cloud
+  cli policy generate --rules agent-rules.yaml -o ~/.cli-policy.yaml
+
+  # May the agent run this?
+  cli policy check git push --force origin main`,
+}
+
+var policyGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a policy file from the tools in PATH and rules",
+	Long: `Generate a policy file from the tools in PATH, their categories and risks,
+and rules.
+
+Each tool is decided by the first of:
+  1. a tools rule naming it, exactly or by shell glob (the longest wins)
+  2. the most restrictive of the risks and categories rules that apply
+  3. allow
+Its risky subcommands, and those the tools rules name as "tool
+subcommand", are decided the same way, and listed under the tool when
+their decision differs from the tool's. Tools are grouped by their first
+category and most severe risk.
+
+Without a rules file, destructive and network-write tools and subcommands
+need confirmation, privileged ones are denied, and tools the policy does
+not list are denied. A rules file (--rules) adds to these:
+
+  default: deny            # tools the policy does not list
+  risks:
+    network-write: allow
+  categories:
+    cloud: deny
+  tools:
+    "kube*": confirm
+    terraform destroy: deny
+    curl: allow
+
+--allow, --confirm, and --deny add tools rules on the command line, over
+the rules file's.
+
+With --with-meta, tools are run with --help (or their cached help is read),
+so the subcommands their help lists are judged by their names and their
+descriptions tag them with categories.
+
+The policy is written as YAML, or JSON with --format json or an output
+file ending in .json. Edit it freely: cli policy check only reads the
+default and the decisions.`,
+	Example: `  # Write the default policy to where cli policy check reads it
+  cli policy generate -o ~/.cli-policy.yaml
+
+  # Judge subcommands from parsed help, and trust kubectl
+  cli policy generate --with-meta --allow kubectl -o ~/.cli-policy.yaml
+
+  # JSON for an agent framework
+  cli policy generate --format json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if policyFormat == "" {
+			policyFormat = formatYAML
+			if strings.HasSuffix(policyOutput, ".json") {
+				policyFormat = formatJSON
+			}
+		}
+		if policyFormat != formatYAML && policyFormat != formatJSON {
+			cmd.PrintErrf("Error: unknown format %q (valid: yaml, json)\n", policyFormat)
+			os.Exit(1)
+		}
+
+		rules, err := policy.LoadRules(policyRules)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		for decision, patterns := range map[string][]string{
+			policy.Allow:   policyAllow,
+			policy.Confirm: policyConfirm,
+			policy.Deny:    policyDeny,
+		} {
+			for _, pattern := range patterns {
+				rules.Tools[pattern] = decision
+			}
+		}
+		if err := rules.Validate(); err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		overrides, err := safety.Load(cfg.SafetyFile)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// A policy of a partial scan would deny the tools it missed, so
+		// stopping early is an error
+		tools, err := newScanner(cmd).ScanAllDetailed()
+		if err != nil {
+			cmd.PrintErrf("Error scanning for tools: %v\n", err)
+			os.Exit(1)
+		}
+
+		if policyWithMeta {
+			if verbose {
+				fmt.Fprintln(os.Stderr, "Collecting help text (this may take a while)...")
+			}
+			c := collector.NewWithOptions(collectorOptions(cmd.Context()))
+			for i, enriched := range c.CollectAll(tools, nil) {
+				if enriched != nil {
+					tools[i].HelpText = enriched.HelpText
+					tools[i].Help = enriched.Help
+				}
+			}
+			if err := c.SaveMetaCache(); err != nil && verbose {
+				fmt.Fprintf(os.Stderr, "Warning: could not save metadata cache: %v\n", err)
+			}
+			if warnIfPartial(cmd) {
+				os.Exit(exitError)
+			}
+		}
+
+		categories.Tag(tools)
+		safety.Annotate(tools, overrides)
+		p := policy.Generate(tools, rules)
+
+		writer := os.Stdout
+		if policyOutput != "" {
+			file, err := os.Create(policyOutput)
+			if err != nil {
+				cmd.PrintErrf("Error creating output file: %v\n", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			writer = file
+		}
+
+		d := display.New(writer)
+		if policyFormat == formatJSON {
+			err = d.ShowPolicyJSON(p)
+		} else {
+			err = d.ShowPolicyYAML(p)
+		}
+		if err != nil {
+			cmd.PrintErrf("Error encoding %s: %v\n", strings.ToUpper(policyFormat), err)
+			os.Exit(1)
+		}
+
+		if policyOutput != "" {
+			counts := make(map[string]int)
+			for _, group := range p.Groups {
+				for _, entry := range group.Tools {
+					counts[entry.Decision]++
+				}
+			}
+			fmt.Fprintf(os.Stderr, "✓ Wrote a policy for %d tools to %s (%d allow, %d confirm, %d deny)\n",
+				counts[policy.Allow]+counts[policy.Confirm]+counts[policy.Deny], policyOutput,
+				counts[policy.Allow], counts[policy.Confirm], counts[policy.Deny])
+		}
+	},
+}
+
+var policyCheckCmd = &cobra.Command{
+	Use:   "check <tool> [args...]",
+	Short: "Check whether a policy lets an agent run a command",
+	Long: `Check a command line against the policy file, for wrappers that run an
+agent's commands to enforce it. The tool is looked up by name; a tool given
+as a path must be the one the policy lists, else it gets the default
+decision. The subcommand is the longest one the policy lists whose words
+begin the arguments that do not start with "-". Since a word after a flag
+may be the flag's value, when flags come before the subcommand
+(git -C /repo push) every word that may begin it is tried and the most
+restrictive decision wins.
+
+The policy is read from --policy, else policy_file in the config file, else
+~/.cli-policy.yaml. Flags after the tool name belong to the command being
+checked, not to cli.
+
+Exit codes:
+  0  allow
+  1  deny
+  2  error, such as a missing or invalid policy file
+  3  confirm: ask the user first`,
+	Example: `  # In a wrapper script
+  if cli policy check "$@" >/dev/null; then exec "$@"; fi
+
+  # See why
+  cli policy check terraform destroy -auto-approve
+
+  # Structured verdict
+  cli policy check --json rm -rf build`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := policyFile
+		if path == "" {
+			path = cfg.PolicyFile
+		}
+		if path == "" {
+			path = policy.DefaultPath()
+		}
+		p, err := policy.Load(path)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		verdict := p.Check(args[0], args[1:])
+		if policyJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(verdict); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(exitError)
+			}
+		} else {
+			command := verdict.Tool
+			if verdict.Subcommand != "" {
+				command += " " + verdict.Subcommand
+			}
+			fmt.Fprintf(os.Stdout, "%s: %s (%s)\n", verdict.Decision, command, verdict.Reason)
+		}
+
+		switch verdict.Decision {
+		case policy.Deny:
+			os.Exit(exitPolicyDeny)
+		case policy.Confirm:
+			os.Exit(exitPolicyConfirm)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(policyCmd)
+	policyCmd.AddCommand(policyGenerateCmd, policyCheckCmd)
+	policyGenerateCmd.Flags().StringVarP(&policyOutput, "output", "o", "", "output file (default: stdout)")
+	policyGenerateCmd.Flags().StringVarP(&policyFormat, "format", "f", "", "output format: yaml or json (default: json for a .json output file, else yaml)")
+	policyGenerateCmd.Flags().StringVar(&policyRules, "rules", "", "rules file deciding tools by risk, category, and name (YAML)")
+	policyGenerateCmd.Flags().StringSliceVar(&policyAllow, "allow", nil, "allow these tools (names, shell globs, or \"tool subcommand\")")
+	policyGenerateCmd.Flags().StringSliceVar(&policyConfirm, "confirm", nil, "ask before running these tools (names, shell globs, or \"tool subcommand\")")
+	policyGenerateCmd.Flags().StringSliceVar(&policyDeny, "deny", nil, "deny these tools (names, shell globs, or \"tool subcommand\")")
+	policyGenerateCmd.Flags().BoolVarP(&policyWithMeta, "with-meta", "m", false, "judge subcommands and categories from the tools' help text (slower)")
+	policyCheckCmd.Flags().StringVar(&policyFile, "policy", "", "policy file (default: policy_file from the config file, else ~/.cli-policy.yaml)")
+	policyCheckCmd.Flags().BoolVarP(&policyJSON, "json", "j", false, "output the verdict in JSON format")
+	// Flags after the tool name are the checked command's own
+	policyCheckCmd.Flags().SetInterspersed(false)
+}
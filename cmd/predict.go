@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/predict"
+	"github.com/spf13/cobra"
+)
+
+var (
+	predictManager  string
+	predictBinaries []string
+	predictJSON     bool
+	predictFormat   string
+)
+
+// predictResult is the JSON output of predict-clash
+type predictResult struct {
+	Package  string          `json:"package"`
+	Manager  string          `json:"manager"`
+	Binaries []string        `json:"binaries"`
+	Source   string          `json:"source"`
+	Clashes  []predict.Clash `json:"clashes"`
+}
+
+// predictCmd represents the predict-clash command
+var predictCmd = &cobra.Command{
+	Use:   "predict-clash PACKAGE",
+	Short: "Check whether installing a package would create a clash",
+	Long: `Check, before installing, whether a package would add a command that is
+already installed from somewhere else.
+
+The commands a package provides are looked up in its registry where the
+registry publishes them (npm, crates.io). Homebrew and PyPI do not, so the
+package is assumed to provide a command of its own name; use --binaries to
+give the list yourself.
+
+For each clash, the manager's bin directory is compared with the active
+installation's position in PATH to predict which copy would run afterwards.
+
+Exits with status 1 when a clash is predicted.`,
+	Example: `  # Before brew install jq
+  cli predict-clash jq
+
+  # npm packages: commands come from the registry
+  cli predict-clash typescript --manager npm
+
+  # Give the commands yourself
+  cli predict-clash coreutils --binaries ls,cat,date`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveFormat(predictFormat, predictJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		name := args[0]
+		manager := packages.PackageManager(predictManager)
+
+		binaries, source := predictBinaries, predict.SourceUser
+		if len(binaries) == 0 {
+			binaries, source = predict.NewResolver().Binaries(manager, name)
+		}
+
+		detector := newDetector(cmd)
+		pkgs, err := detector.DetectAll()
+		if err != nil {
+			cmd.PrintErrf("Error detecting packages: %v\n", err)
+			os.Exit(1)
+		}
+		linker := newLinker(cmd, pkgs)
+
+		s := newScanner(cmd)
+		existing := make(map[string][]models.Tool)
+		for _, binary := range binaries {
+			if found := s.FindAll(binary); len(found) > 0 {
+				existing[binary] = linker.LinkTools(found)
+			}
+		}
+
+		clashes := predict.Clashes(manager, name, binaries, existing, packages.BinDirs()[manager], s.GetPaths())
+
+		if format == formatJSON {
+			result := predictResult{
+				Package:  name,
+				Manager:  string(manager),
+				Binaries: binaries,
+				Source:   source,
+				Clashes:  clashes,
+			}
+			if result.Clashes == nil {
+				result.Clashes = []predict.Clash{}
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(result); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			showPredictedClashes(name, manager, binaries, source, clashes)
+		}
+
+		if len(clashes) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func showPredictedClashes(name string, manager packages.PackageManager, binaries []string, source string, clashes []predict.Clash) {
+	fmt.Fprintf(os.Stdout, "%s install %s provides: %s", manager, name, strings.Join(binaries, ", "))
+	if source == predict.SourceAssumed {
+		fmt.Fprint(os.Stdout, " (assumed; use --binaries to specify)")
+	}
+	fmt.Fprintln(os.Stdout)
+	fmt.Fprintln(os.Stdout)
+
+	if len(clashes) == 0 {
+		fmt.Fprintln(os.Stdout, "✓ No clashes: none of these commands are installed yet.")
+		return
+	}
+
+	fmt.Fprintf(os.Stdout, "⚠ Installing would create %d clash(es):\n\n", len(clashes))
+	for _, clash := range clashes {
+		fmt.Fprintf(os.Stdout, "🔴 %s\n", clash.Binary)
+		for i, tool := range clash.Existing {
+			active := ""
+			if i == 0 {
+				active = " " + display.Status("active", "✓ ACTIVE")
+			}
+			fmt.Fprintf(os.Stdout, "   %s via %s%s\n", tool.Path, display.Manager(packages.Provenance(tool)), active)
+		}
+
+		switch {
+		case clash.SameDir:
+			fmt.Fprintf(os.Stdout, "   → %s already has a %s; installing would overwrite it or fail to link\n", clash.BinDir, clash.Binary)
+		case clash.WouldShadow:
+			fmt.Fprintf(os.Stdout, "   → the new copy in %s would shadow the active one\n", clash.BinDir)
+		case clash.BinDir != "":
+			fmt.Fprintf(os.Stdout, "   → the new copy in %s would be shadowed and never run by name\n", clash.BinDir)
+		default:
+			fmt.Fprintln(os.Stdout, "   → the new copy would be installed alongside the existing one")
+		}
+		fmt.Fprintln(os.Stdout)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(predictCmd)
+	predictCmd.Flags().StringVarP(&predictManager, "manager", "m", string(packages.Brew), "package manager that would install it (brew, npm, pip, cargo, gem, go)")
+	predictCmd.Flags().StringSliceVar(&predictBinaries, "binaries", nil, "commands the package provides, instead of looking them up")
+	predictCmd.Flags().BoolVarP(&predictJSON, "json", "j", false, "output in JSON format")
+	predictCmd.Flags().StringVarP(&predictFormat, "format", "f", "", "output format: table or json (default: table in a terminal, json when piped)")
+}
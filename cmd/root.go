@@ -1,16 +1,52 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"syscall"
+	"time"
 
+	"github.com/cli-ai-org/cli/internal/collector"
+	"github.com/cli-ai-org/cli/internal/config"
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/plugins"
+	"github.com/cli-ai-org/cli/internal/scanner"
+	"github.com/cli-ai-org/cli/internal/shims"
 	"github.com/spf13/cobra"
 )
 
+// Exit codes of the commands that report findings (audit, doctor, vuln),
+// for CI jobs to rely on; they exit 0 when nothing reaches the --fail-on
+// threshold. Errors outside any one command, such as an unknown flag or an
+// unreadable config file, also exit with exitError.
+const (
+	exitFindings = 1
+	exitError    = 2
+)
+
 var (
 	// Used for flags
-	cfgFile string
-	verbose bool
+	cfgFile   string
+	verbose   bool
+	timeout   time.Duration
+	onlyDir   string
+	noCache   bool
+	refresh   bool
+	noExec    bool
+	colorMode string
+
+	// cancelTimeout releases the --timeout deadline once the command is done
+	cancelTimeout context.CancelFunc = func() {}
+
+	// cfg holds settings loaded from the config file
+	cfg = &config.Config{}
 
 	// Version information (set by main.go)
 	version = "dev"
@@ -35,10 +71,62 @@ Available Commands:
   cli export --output   Export catalog to a file
   cli debug <package>   Show debug information for a specific package
   cli debug --all       Show debug information for all packages
+  cli which <tool>      Show every installation of a tool and which one runs
+  cli info <tool>       Show everything known about one tool
+  cli package-of <tool> Show which package provides a tool
+  cli search <query>    Find tools by name, package, description, or help text
+  cli find <task>       Rank tools by how well they fit a task described in words
+  cli tui [query]       Browse tools interactively
+  cli env               Show PATH entries and which manager owns each
+  cli plugins [host]    List kubectl, gh, cargo, and git plugins
+  cli outdated          Show CLI-providing packages with newer versions available
+  cli vuln              Report known vulnerabilities in CLI-providing packages
+  cli update <tool>     Upgrade a tool's package with the manager that owns it
+  cli uninstall <tool>  Remove a tool with the package manager that installed it
+  cli trim-path         Propose a minimal PATH that keeps every reachable tool
+  cli doctor            Check the environment for common setup problems
+  cli predict-clash <pkg> Check whether installing a package would create a clash
+  cli snapshot save     Save a snapshot of the tools and packages on this machine
+  cli snapshot diff <a> [b] Show what changed between snapshots, or since one
+  cli diff --from <file> Compare an exported catalog with this machine or another catalog
+  cli validate <file>   Check that an exported catalog is well-formed
+  cli merge <files>...  Combine catalogs from several machines into one
+  cli policy generate   Generate a policy of which tools an agent may run
+  cli policy check <tool> [args...] Check whether the policy lets an agent run a command
+  cli serve --mcp       Serve the catalog to MCP clients such as Claude Desktop
+  cli serve --http <addr> Serve the catalog as a local JSON API
 
 Global Flags:
   -v, --verbose           Enable verbose output
   --config <file>         Specify config file (default: $HOME/.cli.yaml)
+  --timeout <duration>    Stop after this long (e.g. 30s), keeping partial results
+  --only-dir <dir>        Scan only this directory instead of PATH
+  --no-cache              Query package managers instead of using the package cache
+  --refresh               Query package managers again and update the package cache
+
+Cancellation:
+  When --timeout passes or Ctrl-C is pressed, running package manager
+  commands and tool probes are stopped. list, packages, export, and audit
+  print what they gathered so far with a note that it is partial; other
+  commands exit with an error. Press Ctrl-C twice to quit immediately.
+
+Package Cache:
+  Packages detected from each package manager are cached in the user cache
+  directory (e.g. ~/.cache/cli/packages.json) and reused for up to an hour,
+  or package_cache_ttl in the config file. A manager is queried again as
+  soon as PATH changes or any of its bin or metadata directories (such as
+  node_modules, site-packages, or brew's Cellar) is modified.
+
+Output Format:
+  Commands with a --format flag print a human-readable table when run in a
+  terminal and JSON when their output is piped or redirected. Pass
+  --format table or --format json to override the automatic choice.
+
+Color:
+  Tables and reports are colored by severity, package manager, and
+  installation status when stdout is a terminal, unless the NO_COLOR
+  environment variable is set. Pass --color always or --color never to
+  override, and set COLUMNS to size tables for a different width.
 
 Use "cli [command] --help" for more information about a command.`,
 	Example: `  # Show help
@@ -64,14 +152,42 @@ Use "cli [command] --help" for more information about a command.`,
 
   # Debug all packages
   cli debug --all`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if onlyDir != "" {
+			if info, err := os.Stat(onlyDir); err != nil || !info.IsDir() {
+				cmd.PrintErrf("Error: --only-dir %s is not a directory\n", onlyDir)
+				os.Exit(exitError)
+			}
+		}
+		if err := display.SetColor(colorMode); err != nil {
+			cmd.PrintErrf("Error: --color: %v\n", err)
+			os.Exit(exitError)
+		}
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			cmd.SetContext(ctx)
+			cancelTimeout = cancel
+		}
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// The command runs under a context that Ctrl-C cancels; a second Ctrl-C
+// quits immediately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	cancelTimeout()
+	stop()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		os.Exit(exitError)
 	}
 }
 
@@ -89,9 +205,157 @@ func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cli.yaml)")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().StringVar(&onlyDir, "only-dir", "", "scan only this directory instead of PATH (for auditing one directory or testing)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "stop after this long (e.g. 30s) and keep partial results (default: no limit)")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "query package managers directly, without reading or writing the package cache")
+	rootCmd.PersistentFlags().BoolVar(&refresh, "refresh", false, "query package managers again and update the package cache")
+	rootCmd.PersistentFlags().BoolVar(&noExec, "no-exec", false, "never run tools to probe their version or help; rely on package metadata")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", display.ColorAuto, "color human-readable output: auto (when stdout is a terminal and NO_COLOR is unset), always, or never")
+}
+
+// newScanner creates a scanner that stops when the command is cancelled. It
+// searches PATH, or the config file's search_paths, or only the --only-dir
+// directory when one is given, and leaves out the config's excluded tools.
+func newScanner(cmd *cobra.Command) *scanner.Scanner {
+	s := scanner.New()
+	if onlyDir != "" {
+		s = scanner.NewWithPaths([]string{onlyDir})
+	} else if len(cfg.SearchPaths) > 0 {
+		s = scanner.NewWithPaths(cfg.SearchPaths)
+	}
+	s.SetExclude(cfg.Exclude)
+	s.SetContext(cmd.Context())
+	return s
+}
+
+// newDetector creates a package detector whose package manager commands are
+// killed when the command is cancelled. It queries the config file's
+// package_managers, or all of them. It reuses cached packages unless
+// --no-cache is given; --refresh re-detects them and updates the cache.
+func newDetector(cmd *cobra.Command) *packages.Detector {
+	d := packages.NewDetector()
+	d.SetContext(cmd.Context())
+	if len(cfg.PackageManagers) > 0 {
+		var managers []packages.PackageManager
+		for _, name := range cfg.PackageManagers {
+			// Names were checked when the config was loaded
+			manager, _ := packages.ParseManager(name)
+			managers = append(managers, manager)
+		}
+		d.SetManagers(managers)
+	}
+	if !noCache {
+		ttl := cfg.PackageCacheTTL
+		if ttl <= 0 {
+			ttl = packages.DefaultCacheTTL
+		}
+		d.SetCache(ttl, refresh)
+	}
+	return d
+}
+
+// newLinker creates a package linker that also attributes asdf and mise
+// shims to the plugin and version they run, asking asdf and mise, which are
+// killed when the command is cancelled
+func newLinker(cmd *cobra.Command, pkgs []packages.Package) *packages.Linker {
+	resolver := shims.NewResolver(shims.DetectDirs())
+	resolver.SetContext(cmd.Context())
+	linker := packages.NewLinker(pkgs)
+	linker.SetShimResolver(resolver)
+	return linker
+}
+
+// newPluginDiscoverer creates a plugin discoverer whose gh command is
+// killed when the command is cancelled, and which does not run gh at all
+// with --no-exec
+func newPluginDiscoverer(cmd *cobra.Command) *plugins.Discoverer {
+	d := plugins.NewDiscoverer()
+	d.SetContext(cmd.Context())
+	d.SetNoExec(noExec || cfg.NoExec)
+	return d
+}
+
+// collectorOptions returns the probe settings from the config file and
+// --no-exec, with probes killed when ctx is cancelled
+func collectorOptions(ctx context.Context) collector.Options {
+	return collector.Options{
+		Timeout:         cfg.ProbeTimeout,
+		VersionTimeouts: cfg.VersionTimeouts,
+		Concurrency:     cfg.ProbeConcurrency,
+		NoExec:          noExec || cfg.NoExec,
+		ProbeAllow:      cfg.ProbeAllow,
+		ProbeDeny:       cfg.ProbeDeny,
+		Context:         ctx,
+	}
+}
+
+// isCancelled reports whether err comes from the command being stopped by
+// --timeout or Ctrl-C, in which case the results gathered so far are usable
+func isCancelled(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// warnIfPartial tells the user on stderr that the command was stopped early
+// and its results are incomplete. It reports whether that happened.
+func warnIfPartial(cmd *cobra.Command) bool {
+	switch cmd.Context().Err() {
+	case nil:
+		return false
+	case context.DeadlineExceeded:
+		fmt.Fprintf(os.Stderr, "Note: stopped after --timeout %s; results are partial\n", timeout)
+	default:
+		fmt.Fprintln(os.Stderr, "Note: interrupted; results are partial")
+	}
+	return true
+}
+
+// matchTools keeps the tools whose name matches a shell glob or a regular
+// expression. At most one of the two may be set; with neither, tools are
+// returned unchanged.
+func matchTools(tools []models.Tool, glob, pattern string) ([]models.Tool, error) {
+	var match func(name string) bool
+	switch {
+	case glob != "" && pattern != "":
+		return nil, fmt.Errorf("--match and --regex cannot be used together")
+	case glob != "":
+		if _, err := filepath.Match(glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid --match pattern %q: %w", glob, err)
+		}
+		match = func(name string) bool {
+			ok, _ := filepath.Match(glob, name)
+			return ok
+		}
+	case pattern != "":
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --regex pattern: %w", err)
+		}
+		match = re.MatchString
+	default:
+		return tools, nil
+	}
+
+	matched := []models.Tool{}
+	for _, tool := range tools {
+		if match(tool.Name) {
+			matched = append(matched, tool)
+		}
+	}
+	return matched, nil
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
-	// TODO: Implement config file reading if needed
+	loaded, err := config.Load(cfgFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(exitError)
+	}
+	for _, name := range loaded.PackageManagers {
+		if _, err := packages.ParseManager(name); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: package_managers: %v\n", err)
+			os.Exit(exitError)
+		}
+	}
+	cfg = loaded
 }
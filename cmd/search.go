@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/collector"
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/search"
+	"github.com/spf13/cobra"
+)
+
+var (
+	searchJSON     bool
+	searchFormat   string
+	searchRegex    bool
+	searchIn       []string
+	searchWithMeta bool
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Find tools by name, package, description, or help text",
+	Long: `Find the tools in PATH whose name, package, description, or help text
+contains the query, to answer questions like "what do I have that deals
+with JSON?". Matching ignores case; with --regex the query is a regular
+expression.
+
+Help text and descriptions come from what cli has cached: the help text
+export --with-meta and info probed, and the man page summaries they looked
+up. Tools that were never probed match only on their name and package. Use
+--with-meta to probe them first (and cache the results for later searches).
+
+Tools matching on their name are listed first, then those matching on their
+package, their description, and their help text. --in limits the search to
+some of these fields.`,
+	Example: `  # Tools that deal with JSON
+  cli search json
+
+  # Tools whose name or description mention containers
+  cli search container --in name,description
+
+  # Regular expression, probing tools that were never probed
+  cli search '^kube|helm' --regex --with-meta
+
+  # JSON output for agents
+  cli search yaml --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveFormat(searchFormat, searchJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := search.CheckFields(searchIn); err != nil {
+			cmd.PrintErrf("Error: --in: %v\n", err)
+			os.Exit(1)
+		}
+		query, err := search.NewQuery(args[0], searchRegex)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		s := newScanner(cmd)
+		tools, err := s.ScanAllDetailed()
+		if err != nil && !isCancelled(err) {
+			cmd.PrintErrf("Error scanning tools: %v\n", err)
+			os.Exit(1)
+		}
+		pkgs, err := newDetector(cmd).DetectAll()
+		if err != nil && !isCancelled(err) {
+			cmd.PrintErrf("Error detecting packages: %v\n", err)
+			os.Exit(1)
+		}
+		tools = newLinker(cmd, pkgs).LinkTools(tools)
+
+		descriptions := loadToolText(cmd, tools, searchWithMeta)
+		results := search.Search(tools, descriptions, query, searchIn)
+		warnIfPartial(cmd)
+
+		if format == formatJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(results); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		} else if len(results) == 0 {
+			fmt.Fprintf(os.Stdout, "No tools match %q.\n", args[0])
+		} else {
+			table := display.NewTable(os.Stdout, "NAME", "MATCHED", "DESCRIPTION")
+			for _, result := range results {
+				text := result.Description
+				if text == "" {
+					text = result.Snippet
+				}
+				table.Row(result.Name, strings.Join(result.Matched, ","), text)
+			}
+			table.Flush()
+		}
+
+		if len(results) == 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().BoolVarP(&searchJSON, "json", "j", false, "output in JSON format")
+	searchCmd.Flags().StringVarP(&searchFormat, "format", "f", "", "output format: table or json (default: table in a terminal, json when piped)")
+	searchCmd.Flags().BoolVar(&searchRegex, "regex", false, "treat the query as a regular expression")
+	searchCmd.Flags().StringSliceVar(&searchIn, "in", search.Fields, "fields to search: "+strings.Join(search.Fields, ", "))
+	searchCmd.Flags().BoolVar(&searchWithMeta, "with-meta", false, "probe tools whose help text is not cached, and look up their man pages")
+}
+
+// loadToolText fills in the help text of tools from the metadata cache and
+// returns their man page summaries, keyed by path, from the man page cache.
+// With probe set, tools missing from the caches are probed and looked up,
+// and the caches saved; otherwise nothing is run.
+func loadToolText(cmd *cobra.Command, tools []models.Tool, probe bool) map[string]string {
+	opts := collectorOptions(cmd.Context())
+	if !probe {
+		opts.NoExec = true
+	}
+	c := collector.NewWithOptions(opts)
+	probed := 0
+	for i, enriched := range c.CollectAll(tools, nil) {
+		if enriched != nil && enriched.HelpText != "" {
+			tools[i].HelpText = enriched.HelpText
+			tools[i].Help = enriched.Help
+			probed++
+		}
+	}
+	descriptions := manDescriptions(c, tools, probe)
+
+	if probe {
+		if err := c.SaveMetaCache(); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: could not save metadata cache: %v\n", err)
+		}
+	} else if probed == 0 && len(descriptions) == 0 {
+		fmt.Fprintln(os.Stderr, "Note: no help text is cached yet, so only names and packages were searched; use --with-meta to probe tools")
+	}
+	return descriptions
+}
+
+// manDescriptions returns the man page summaries of tools, keyed by path.
+// With probe set, man pages missing from the cache are looked up and the
+// cache saved; otherwise only cached summaries are returned.
+func manDescriptions(c *collector.Collector, tools []models.Tool, probe bool) map[string]string {
+	descriptions := make(map[string]string)
+	for _, tool := range tools {
+		if probe {
+			descriptions[tool.Path] = c.ManDescription(tool.Name, tool.Path)
+		} else if description, ok := c.CachedManDescription(tool.Name, tool.Path); ok {
+			descriptions[tool.Path] = description
+		}
+	}
+	if probe {
+		if err := c.SaveManCache(); err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: could not save man page cache: %v\n", err)
+		}
+	}
+	return descriptions
+}
@@ -0,0 +1,458 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/collector"
+	"github.com/cli-ai-org/cli/internal/mcp"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/plugins"
+	"github.com/cli-ai-org/cli/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveMCP    bool
+	serveHTTP   string
+	serveRescan time.Duration
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the tool catalog to agents",
+	Long: `Run a long-lived server that answers questions about the installed CLI
+tools, so agents can query the environment without shelling out to cli for
+every question.
+
+With --mcp, cli speaks the Model Context Protocol on stdin and stdout, for
+Claude Desktop and other MCP clients. It offers these tools:
+  list_tools              List the tools in PATH, optionally filtered by a
+                          name glob or package manager
+  describe_tool           Everything known about one tool, like cli info
+  find_clashes            Tools installed by more than one source
+  which_package_provides  The package behind each installation of a tool,
+                          and the packages that declare it
+
+and these resources:
+  cli://catalog           The full catalog, like cli export --with-packages
+  cli://packages          Every detected package
+
+PATH is scanned and packages are detected on the first request; the results
+are reused for the rest of the session.
+
+With --http ADDR, cli serves a read-only JSON API on ADDR instead:
+  GET /tools              The tools in PATH; ?match=GLOB, ?manager=NAME, and
+                          ?include_shadowed=true filter as in list_tools
+  GET /tools/{name}       Everything known about one tool, like cli info
+  GET /packages           Every detected package; ?manager=NAME filters
+  GET /audit              The audit report, like cli audit --json
+  GET /catalog            The full catalog, like cli export --with-packages
+
+PATH is scanned once at startup and again every --rescan interval (5m by
+default) in the background, so requests are answered from memory. Each
+response's Last-Modified header is the time of the scan it came from.
+Listen on a loopback address such as 127.0.0.1:8080 unless other machines
+should see your environment.`,
+	Example: `  # Register with an MCP client, e.g. in claude_desktop_config.json:
+  #   "mcpServers": {"cli": {"command": "cli", "args": ["serve", "--mcp"]}}
+  cli serve --mcp
+
+  # Local JSON API for dashboards, rescanning every minute
+  cli serve --http 127.0.0.1:8080 --rescan 1m
+  curl -s localhost:8080/tools/git`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch {
+		case serveMCP && serveHTTP != "":
+			cmd.PrintErrln("Error: --mcp and --http cannot be used together")
+			os.Exit(1)
+		case !serveMCP && serveHTTP == "":
+			cmd.PrintErrln("Error: choose a protocol to serve: --mcp or --http <addr>")
+			os.Exit(1)
+		}
+
+		store := &catalogStore{cmd: cmd}
+		if serveHTTP != "" {
+			if serveRescan <= 0 {
+				cmd.PrintErrf("Error: --rescan must be positive, not %s\n", serveRescan)
+				os.Exit(1)
+			}
+			if err := serveJSON(cmd.Context(), store, serveHTTP, serveRescan); err != nil {
+				cmd.PrintErrf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		server := newMCPServer(store)
+		if verbose {
+			fmt.Fprintln(os.Stderr, "Serving MCP on stdin/stdout")
+		}
+		if err := server.Serve(cmd.Context(), os.Stdin, os.Stdout); err != nil && !isCancelled(err) {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// catalogSnapshot is one scan of the environment: every installation in
+// PATH, linked to its package, and the detected packages
+type catalogSnapshot struct {
+	tools     []models.Tool
+	pkgs      []packages.Package
+	detector  *packages.Detector
+	paths     []string
+	scannedAt time.Time
+
+	// audit is computed on first request, once per snapshot
+	auditOnce sync.Once
+	audit     AuditResult
+}
+
+// active returns the installations that run when their name is typed
+func (s *catalogSnapshot) active() []models.Tool {
+	var tools []models.Tool
+	for _, tool := range s.tools {
+		if !tool.Shadowed {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// installations returns every installation of name, in PATH order
+func (s *catalogSnapshot) installations(name string) []models.Tool {
+	var tools []models.Tool
+	for _, tool := range s.tools {
+		if scanner.NameKey(tool.Name) == scanner.NameKey(name) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// catalog builds the export-style catalog of the active tools and the
+// packages that provide them
+func (s *catalogSnapshot) catalog() *models.ToolCatalog {
+	tools := s.active()
+	catalog := collector.New().BuildCatalog(tools, s.paths)
+	if len(s.pkgs) > 0 {
+		catalog.Packages = packages.GetPackagesWithBinaries(s.pkgs, tools)
+		catalog.TotalPackages = len(catalog.Packages)
+	}
+	return catalog
+}
+
+// list returns the tools matching a name glob and provenance (package
+// manager, "system", or "unmanaged"), either of which may be empty; shadowed
+// installations are included only when asked for
+func (s *catalogSnapshot) list(match, manager string, includeShadowed bool) (toolListing, error) {
+	tools := s.tools
+	if !includeShadowed {
+		tools = s.active()
+	}
+	tools, err := matchTools(tools, match, "")
+	if err != nil {
+		return toolListing{}, err
+	}
+	listing := toolListing{Tools: []models.Tool{}}
+	for _, tool := range tools {
+		if manager == "" || packages.Provenance(tool) == manager {
+			listing.Tools = append(listing.Tools, tool)
+		}
+	}
+	listing.Total = len(listing.Tools)
+	return listing, nil
+}
+
+// auditResult audits the snapshot, without hashing or registry lookups
+func (s *catalogSnapshot) auditResult(ctx context.Context) AuditResult {
+	s.auditOnce.Do(func() {
+		s.audit = performAudit(ctx, s.tools, s.pkgs, s.detector, false, false)
+	})
+	return s.audit
+}
+
+// catalogStore holds the current snapshot. It scans the environment on
+// first use, and again whenever refresh is called; readers keep the old
+// snapshot while a rescan runs.
+type catalogStore struct {
+	cmd *cobra.Command
+
+	mu       sync.Mutex
+	snapshot *catalogSnapshot
+	// scanMu makes concurrent first requests wait for one scan
+	scanMu sync.Mutex
+	// probeMu serializes running tools, whose probes share on-disk caches
+	probeMu sync.Mutex
+}
+
+func (c *catalogStore) get() (*catalogSnapshot, error) {
+	c.mu.Lock()
+	snapshot := c.snapshot
+	c.mu.Unlock()
+	if snapshot != nil {
+		return snapshot, nil
+	}
+
+	c.scanMu.Lock()
+	defer c.scanMu.Unlock()
+	c.mu.Lock()
+	snapshot = c.snapshot
+	c.mu.Unlock()
+	if snapshot != nil {
+		return snapshot, nil
+	}
+	return c.refreshLocked()
+}
+
+// refresh rescans the environment and replaces the snapshot
+func (c *catalogStore) refresh() (*catalogSnapshot, error) {
+	c.scanMu.Lock()
+	defer c.scanMu.Unlock()
+	return c.refreshLocked()
+}
+
+func (c *catalogStore) refreshLocked() (*catalogSnapshot, error) {
+	s := newScanner(c.cmd)
+	tools, err := s.ScanAllOccurrences()
+	if err != nil {
+		return nil, fmt.Errorf("scanning PATH: %w", err)
+	}
+	// A manager that fails leaves its packages out; the rest still link
+	detector := newDetector(c.cmd)
+	pkgs, err := detector.DetectAll()
+	if err != nil && isCancelled(err) {
+		return nil, fmt.Errorf("detecting packages: %w", err)
+	}
+	linker := newLinker(c.cmd, pkgs)
+	linker.SetExplain(true)
+
+	tools = linker.LinkTools(tools)
+	plugins.Annotate(tools, newPluginDiscoverer(c.cmd).Discover(tools))
+
+	snapshot := &catalogSnapshot{
+		tools:     tools,
+		pkgs:      pkgs,
+		detector:  detector,
+		paths:     s.GetPaths(),
+		scannedAt: time.Now(),
+	}
+	c.mu.Lock()
+	c.snapshot = snapshot
+	c.mu.Unlock()
+	return snapshot, nil
+}
+
+// describe reports on one tool from the snapshot, running it for its
+// version and help text
+func (c *catalogStore) describe(ctx context.Context, snapshot *catalogSnapshot, name string) (models.ToolReport, error) {
+	installations := snapshot.installations(name)
+	if len(installations) == 0 {
+		return models.ToolReport{}, fmt.Errorf("%s: not found in PATH", name)
+	}
+	c.probeMu.Lock()
+	defer c.probeMu.Unlock()
+	return describeTool(ctx, name, installations), nil
+}
+
+// toolListing is the list_tools result
+type toolListing struct {
+	Total int           `json:"total"`
+	Tools []models.Tool `json:"tools"`
+}
+
+// packageProviders is the which_package_provides result: the package behind
+// each installation of a tool in PATH, and every detected package that
+// declares a binary by that name, installed in PATH or not
+type packageProviders struct {
+	Name          string                    `json:"name"`
+	Installations []models.InstallationInfo `json:"installations"`
+	Packages      []models.PackageInfo      `json:"packages"`
+}
+
+// newMCPServer offers the catalog's tools and resources over MCP
+func newMCPServer(store *catalogStore) *mcp.Server {
+	server := mcp.NewServer("cli", version)
+
+	server.AddTool(mcp.Tool{
+		Name:        "list_tools",
+		Description: "List the CLI tools available in PATH with their paths and the package and package manager that installed them. By default only the installation that runs for each name is listed.",
+		InputSchema: mcp.ObjectSchema(map[string]interface{}{
+			"match":            mcp.StringProperty("Only list tools whose name matches this shell glob, e.g. 'kube*'"),
+			"manager":          mcp.StringProperty("Only list tools from this package manager (brew, npm, pip, ...), or 'system' or 'unmanaged'"),
+			"include_shadowed": mcp.BoolProperty("Also list installations hidden by an earlier one in PATH"),
+		}),
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				Match           string `json:"match"`
+				Manager         string `json:"manager"`
+				IncludeShadowed bool   `json:"include_shadowed"`
+			}
+			if err := decodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			snapshot, err := store.get()
+			if err != nil {
+				return nil, err
+			}
+			return snapshot.list(args.Match, args.Manager, args.IncludeShadowed)
+		},
+	})
+
+	server.AddTool(mcp.Tool{
+		Name:        "describe_tool",
+		Description: "Describe one CLI tool: the path that runs and its symlinks, file type and architecture, installing package, version, usage line, help text, man page summary, and installations it shadows. Runs the tool with --version and --help.",
+		InputSchema: mcp.ObjectSchema(map[string]interface{}{
+			"name": mcp.StringProperty("Command name, e.g. 'git'"),
+		}, "name"),
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				Name string `json:"name"`
+			}
+			if err := decodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			if args.Name == "" {
+				return nil, fmt.Errorf("name is required")
+			}
+			snapshot, err := store.get()
+			if err != nil {
+				return nil, err
+			}
+			return store.describe(ctx, snapshot, args.Name)
+		},
+	})
+
+	server.AddTool(mcp.Tool{
+		Name:        "find_clashes",
+		Description: "Find tools installed by more than one source (e.g. the system and Homebrew, or npm and pip), with every installation and which one runs.",
+		InputSchema: mcp.ObjectSchema(map[string]interface{}{}),
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			snapshot, err := store.get()
+			if err != nil {
+				return nil, err
+			}
+			clashes := findClashes(snapshot.tools)
+			if clashes == nil {
+				clashes = []ToolClash{}
+			}
+			return map[string]interface{}{"clashes": clashes}, nil
+		},
+	})
+
+	server.AddTool(mcp.Tool{
+		Name:        "which_package_provides",
+		Description: "Find which package provides a command: the package and manager behind each installation in PATH, and every detected package that declares a binary by that name.",
+		InputSchema: mcp.ObjectSchema(map[string]interface{}{
+			"name": mcp.StringProperty("Command name, e.g. 'tsc'"),
+		}, "name"),
+		Handler: func(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+			var args struct {
+				Name string `json:"name"`
+			}
+			if err := decodeArgs(raw, &args); err != nil {
+				return nil, err
+			}
+			if args.Name == "" {
+				return nil, fmt.Errorf("name is required")
+			}
+			snapshot, err := store.get()
+			if err != nil {
+				return nil, err
+			}
+
+			result := packageProviders{
+				Name:          args.Name,
+				Installations: []models.InstallationInfo{},
+				Packages:      []models.PackageInfo{},
+			}
+			for _, tool := range snapshot.installations(args.Name) {
+				result.Installations = append(result.Installations, models.InstallationInfo{
+					Path:           tool.Path,
+					PackageName:    tool.PackageName,
+					PackageManager: packages.Provenance(tool),
+					Version:        tool.PackageVersion,
+					IsActive:       !tool.Shadowed,
+					Broken:         tool.Broken,
+					BrokenReason:   tool.BrokenReason,
+				})
+			}
+			for _, pkg := range snapshot.pkgs {
+				for _, binary := range pkg.Binaries {
+					if scanner.NameKey(binary) == scanner.NameKey(args.Name) {
+						result.Packages = append(result.Packages, models.PackageInfo{
+							Name:     pkg.Name,
+							Version:  pkg.Version,
+							Manager:  string(pkg.Manager),
+							Binaries: pkg.Binaries,
+							Location: pkg.Location,
+							Global:   pkg.Global,
+							Source:   pkg.Source,
+						})
+						break
+					}
+				}
+			}
+			return result, nil
+		},
+	})
+
+	server.AddResource(mcp.Resource{
+		URI:         "cli://catalog",
+		Name:        "Tool catalog",
+		Description: "Every CLI tool in PATH with its package, and the packages that provide them",
+		MIMEType:    "application/json",
+		Read: func(ctx context.Context) (interface{}, error) {
+			snapshot, err := store.get()
+			if err != nil {
+				return nil, err
+			}
+			return snapshot.catalog(), nil
+		},
+	})
+
+	server.AddResource(mcp.Resource{
+		URI:         "cli://packages",
+		Name:        "Packages",
+		Description: "Every package detected from the installed package managers",
+		MIMEType:    "application/json",
+		Read: func(ctx context.Context) (interface{}, error) {
+			snapshot, err := store.get()
+			if err != nil {
+				return nil, err
+			}
+			if snapshot.pkgs == nil {
+				return []packages.Package{}, nil
+			}
+			return snapshot.pkgs, nil
+		},
+	})
+
+	return server
+}
+
+// decodeArgs unmarshals a tool call's arguments, which may be omitted
+func decodeArgs(raw json.RawMessage, v interface{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().BoolVar(&serveMCP, "mcp", false, "speak the Model Context Protocol on stdin and stdout")
+	serveCmd.Flags().StringVar(&serveHTTP, "http", "", "serve a JSON API on this address (e.g. 127.0.0.1:8080)")
+	serveCmd.Flags().DurationVar(&serveRescan, "rescan", 5*time.Minute, "how often --http rescans PATH and packages")
+}
@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/packages"
+)
+
+// serveJSON serves the read-only JSON API on addr until ctx is cancelled,
+// rescanning the environment every interval
+func serveJSON(ctx context.Context, store *catalogStore, addr string, interval time.Duration) error {
+	if verbose {
+		fmt.Fprintln(os.Stderr, "Scanning PATH and detecting packages...")
+	}
+	if _, err := store.get(); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// On failure, keep answering from the previous scan
+				if _, err := store.refresh(); err != nil && ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "Warning: rescan failed: %v\n", err)
+				} else if verbose {
+					fmt.Fprintln(os.Stderr, "Rescanned PATH and packages")
+				}
+			}
+		}
+	}()
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           newAPIHandler(store),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving JSON API on http://%s\n", addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// newAPIHandler routes the JSON API's endpoints
+func newAPIHandler(store *catalogStore) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/tools", apiHandler(store, func(r *http.Request, snapshot *catalogSnapshot) (interface{}, error) {
+		query := r.URL.Query()
+		includeShadowed, _ := strconv.ParseBool(query.Get("include_shadowed"))
+		listing, err := snapshot.list(query.Get("match"), query.Get("manager"), includeShadowed)
+		if err != nil {
+			return nil, apiError{http.StatusBadRequest, err}
+		}
+		return listing, nil
+	}))
+
+	mux.HandleFunc("/tools/", apiHandler(store, func(r *http.Request, snapshot *catalogSnapshot) (interface{}, error) {
+		name := strings.TrimPrefix(r.URL.Path, "/tools/")
+		if name == "" || strings.Contains(name, "/") {
+			return nil, apiError{http.StatusNotFound, fmt.Errorf("no such endpoint: %s", r.URL.Path)}
+		}
+		report, err := store.describe(r.Context(), snapshot, name)
+		if err != nil {
+			return nil, apiError{http.StatusNotFound, err}
+		}
+		return report, nil
+	}))
+
+	mux.HandleFunc("/packages", apiHandler(store, func(r *http.Request, snapshot *catalogSnapshot) (interface{}, error) {
+		manager := r.URL.Query().Get("manager")
+		pkgs := []packages.Package{}
+		for _, pkg := range snapshot.pkgs {
+			if manager == "" || string(pkg.Manager) == manager {
+				pkgs = append(pkgs, pkg)
+			}
+		}
+		return pkgs, nil
+	}))
+
+	mux.HandleFunc("/audit", apiHandler(store, func(r *http.Request, snapshot *catalogSnapshot) (interface{}, error) {
+		// Audits are shared by every request for the snapshot, so they run
+		// under the server's context rather than the first request's
+		return snapshot.auditResult(store.cmd.Context()), nil
+	}))
+
+	mux.HandleFunc("/catalog", apiHandler(store, func(r *http.Request, snapshot *catalogSnapshot) (interface{}, error) {
+		return snapshot.catalog(), nil
+	}))
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, apiError{http.StatusNotFound, fmt.Errorf("no such endpoint: %s", r.URL.Path)})
+	})
+	return mux
+}
+
+// apiError is an error with the HTTP status it is reported with
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e apiError) Error() string {
+	return e.err.Error()
+}
+
+// apiHandler adapts an endpoint to HTTP: it allows only GET, answers from
+// the current snapshot, and writes the result, or the error, as JSON
+func apiHandler(store *catalogStore, endpoint func(r *http.Request, snapshot *catalogSnapshot) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeAPIError(w, apiError{http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method)})
+			return
+		}
+
+		snapshot, err := store.get()
+		if err != nil {
+			writeAPIError(w, apiError{http.StatusServiceUnavailable, err})
+			return
+		}
+		result, err := endpoint(r, snapshot)
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			writeAPIError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Last-Modified", snapshot.scannedAt.UTC().Format(http.TimeFormat))
+		w.Write(append(data, '\n'))
+	}
+}
+
+// writeAPIError writes {"error": "..."} with the error's status, or 500
+func writeAPIError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var apiErr apiError
+	if errors.As(err, &apiErr) {
+		status = apiErr.status
+	}
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
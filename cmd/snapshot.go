@@ -0,0 +1,316 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+var (
+	snapshotLabel  string
+	snapshotJSON   bool
+	snapshotFormat string
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save catalogs of this machine and compare them over time",
+	Long: `Save timestamped snapshots of the tools in PATH and the packages that provide
+them, with their versions, and compare any two of them, or one with the
+machine as it is now. Useful for "what changed since last week?" debugging.
+
+Snapshots are kept in $XDG_DATA_HOME/cli-ai/snapshots (by default
+~/.local/share/cli-ai/snapshots). Each is a catalog like "cli export
+--with-packages" writes, so other commands can read it too.
+
+Snapshots are named by the UTC time they were taken, plus a label if given
+(20261016-093000-before-upgrade). Wherever a snapshot is expected, you can
+give its name, its label, an unambiguous prefix of its name, "latest", or
+"latest~N" for the Nth snapshot before the latest.`,
+	Example: `  # Save a snapshot before upgrading things
+  cli snapshot save --label before-upgrade
+
+  # See saved snapshots
+  cli snapshot list
+
+  # What changed since the last snapshot?
+  cli snapshot diff latest
+
+  # Compare two snapshots
+  cli snapshot diff latest~1 latest --json`,
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save a snapshot of the current tools and packages",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := snapshot.CheckLabel(snapshotLabel); err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		dir, err := snapshot.Dir()
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		catalog, takenAt, err := currentCatalog(cmd)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if catalog.Partial {
+			cmd.PrintErrf("Error: the scan did not finish, so the snapshot would be incomplete; not saving it\n")
+			os.Exit(1)
+		}
+
+		entry, err := snapshot.Save(dir, catalog, snapshotLabel, takenAt)
+		if err != nil {
+			cmd.PrintErrf("Error saving snapshot: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "✓ Saved snapshot %s (%d tools, %d packages)\n", entry.ID, entry.Tools, entry.Packages)
+		if verbose {
+			fmt.Fprintf(os.Stderr, "  %s\n", entry.Path)
+		}
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved snapshots, oldest first",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveFormat(snapshotFormat, snapshotJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		entries := savedSnapshots(cmd)
+
+		if format == formatJSON {
+			if entries == nil {
+				entries = []snapshot.Entry{}
+			}
+			printSnapshotJSON(cmd, entries)
+			return
+		}
+
+		if len(entries) == 0 {
+			fmt.Fprintln(os.Stdout, "No snapshots saved yet. Run `cli snapshot save` to take one.")
+			return
+		}
+		table := display.NewTable(os.Stdout, "SNAPSHOT", "TAKEN", "TOOLS", "PACKAGES")
+		table.AlignRight(2, 3)
+		for _, entry := range entries {
+			table.Row(entry.ID, entry.TakenAt.Local().Format("2006-01-02 15:04"), strconv.Itoa(entry.Tools), strconv.Itoa(entry.Packages))
+		}
+		table.Flush()
+	},
+}
+
+var snapshotDiffCmd = &cobra.Command{
+	Use:   "diff <snapshot> [snapshot]",
+	Short: "Show tools and packages added, removed, or changed between snapshots",
+	Long: `Compare two snapshots, or with one argument, a snapshot with the machine as
+it is now. Tools are compared by name, using the installation that runs;
+a tool that changed is reported as upgraded or downgraded (by version),
+reowned (now provided by another package or manager), moved (runs from
+another path), or modified (the file changed size without a version
+change). Packages are compared by manager and name.
+
+Either argument may also be the path of a catalog written by "cli export".`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveFormat(snapshotFormat, snapshotJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		entries := savedSnapshots(cmd)
+		from, fromName, err := loadSnapshot(entries, args[0])
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var to *models.ToolCatalog
+		toName := "now"
+		if len(args) == 2 {
+			to, toName, err = loadSnapshot(entries, args[1])
+		} else {
+			to, _, err = currentCatalog(cmd)
+		}
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		diff := snapshot.Compare(from, to)
+		diff.From, diff.To = fromName, toName
+
+		if format == formatJSON {
+			printSnapshotJSON(cmd, diff)
+			return
+		}
+		printSnapshotDiff(diff)
+	},
+}
+
+// currentCatalog scans the machine into a catalog of the tools that run
+// and the packages that provide them, as a snapshot records them
+func currentCatalog(cmd *cobra.Command) (*models.ToolCatalog, time.Time, error) {
+	if verbose {
+		fmt.Fprintln(os.Stderr, "Scanning PATH and detecting packages...")
+	}
+	store := &catalogStore{cmd: cmd}
+	scan, err := store.get()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	catalog := scan.catalog()
+	catalog.Partial = warnIfPartial(cmd)
+	return catalog, scan.scannedAt, nil
+}
+
+// savedSnapshots lists the saved snapshots, exiting on failure
+func savedSnapshots(cmd *cobra.Command) []snapshot.Entry {
+	dir, err := snapshot.Dir()
+	if err != nil {
+		cmd.PrintErrf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	entries, err := snapshot.List(dir)
+	if err != nil {
+		cmd.PrintErrf("Error reading snapshots: %v\n", err)
+		os.Exit(1)
+	}
+	return entries
+}
+
+// loadSnapshot reads the snapshot ref names, or the catalog file at ref,
+// and returns it with the name to show for it
+func loadSnapshot(entries []snapshot.Entry, ref string) (*models.ToolCatalog, string, error) {
+	if isFile(ref) {
+		catalog, err := snapshot.Load(ref)
+		return catalog, ref, err
+	}
+	entry, err := snapshot.Find(entries, ref)
+	if err != nil {
+		return nil, "", err
+	}
+	catalog, err := snapshot.Load(entry.Path)
+	return catalog, entry.ID, err
+}
+
+func printSnapshotJSON(cmd *cobra.Command, v interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		cmd.PrintErrf("Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printSnapshotDiff prints a diff for people: additions, removals, and
+// changes, tools first
+func printSnapshotDiff(diff snapshot.Diff) {
+	if diff.Empty() {
+		fmt.Fprintf(os.Stdout, "No changes from %s to %s.\n", diff.From, diff.To)
+		return
+	}
+	fmt.Fprintf(os.Stdout, "Changes from %s to %s:\n", diff.From, diff.To)
+	if !diff.PackagesCompared {
+		fmt.Fprintln(os.Stdout, "(One catalog has no package information; ownership and packages were not compared.)")
+	}
+
+	if len(diff.AddedTools) > 0 {
+		fmt.Fprintf(os.Stdout, "\nTools added (%d):\n", len(diff.AddedTools))
+		for _, tool := range diff.AddedTools {
+			fmt.Fprintf(os.Stdout, "  + %s\n", describeToolState(tool))
+		}
+	}
+	if len(diff.RemovedTools) > 0 {
+		fmt.Fprintf(os.Stdout, "\nTools removed (%d):\n", len(diff.RemovedTools))
+		for _, tool := range diff.RemovedTools {
+			fmt.Fprintf(os.Stdout, "  - %s\n", describeToolState(tool))
+		}
+	}
+	if len(diff.ChangedTools) > 0 {
+		fmt.Fprintf(os.Stdout, "\nTools changed (%d):\n", len(diff.ChangedTools))
+		for _, change := range diff.ChangedTools {
+			var detail string
+			switch change.Kind {
+			case snapshot.Upgraded, snapshot.Downgraded:
+				detail = fmt.Sprintf("%s → %s", change.Before.Version, change.After.Version)
+			case snapshot.Reowned:
+				detail = fmt.Sprintf("%s → %s", describeOwnerState(change.Before), describeOwnerState(change.After))
+			case snapshot.Moved:
+				detail = fmt.Sprintf("%s → %s", change.Before.Path, change.After.Path)
+			case snapshot.Modified:
+				detail = change.After.Path
+			}
+			fmt.Fprintf(os.Stdout, "  ~ %-20s %-10s %s\n", change.Name, change.Kind, detail)
+		}
+	}
+
+	if len(diff.AddedPackages) > 0 {
+		fmt.Fprintf(os.Stdout, "\nPackages added (%d):\n", len(diff.AddedPackages))
+		for _, pkg := range diff.AddedPackages {
+			fmt.Fprintf(os.Stdout, "  + %s %s (%s)\n", pkg.Name, pkg.Version, pkg.Manager)
+		}
+	}
+	if len(diff.RemovedPackages) > 0 {
+		fmt.Fprintf(os.Stdout, "\nPackages removed (%d):\n", len(diff.RemovedPackages))
+		for _, pkg := range diff.RemovedPackages {
+			fmt.Fprintf(os.Stdout, "  - %s %s (%s)\n", pkg.Name, pkg.Version, pkg.Manager)
+		}
+	}
+	if len(diff.ChangedPackages) > 0 {
+		fmt.Fprintf(os.Stdout, "\nPackages changed (%d):\n", len(diff.ChangedPackages))
+		for _, pkg := range diff.ChangedPackages {
+			fmt.Fprintf(os.Stdout, "  ~ %-20s %-10s %s → %s (%s)\n", pkg.Name, pkg.Kind, pkg.Before, pkg.After, pkg.Manager)
+		}
+	}
+}
+
+// describeToolState renders a tool as "name version (manager package) path"
+func describeToolState(tool snapshot.ToolState) string {
+	text := tool.Name
+	if tool.Version != "" {
+		text += " " + tool.Version
+	}
+	if tool.Package != "" {
+		text += " (" + describeOwnerState(tool) + ")"
+	}
+	return text + "  " + tool.Path
+}
+
+// describeOwnerState names the package that provides a tool, e.g.
+// "brew ripgrep", or "unmanaged"
+func describeOwnerState(tool snapshot.ToolState) string {
+	if tool.Package == "" {
+		return "unmanaged"
+	}
+	return tool.Manager + " " + tool.Package
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.AddCommand(snapshotSaveCmd, snapshotListCmd, snapshotDiffCmd)
+	snapshotSaveCmd.Flags().StringVarP(&snapshotLabel, "label", "l", "", "label to add to the snapshot's name (letters, digits, '.', '_', '-')")
+	for _, c := range []*cobra.Command{snapshotListCmd, snapshotDiffCmd} {
+		c.Flags().BoolVarP(&snapshotJSON, "json", "j", false, "output in JSON format")
+		c.Flags().StringVarP(&snapshotFormat, "format", "f", "", "output format: table or json (default: table in a terminal, json when piped)")
+	}
+}
@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/pathenv"
+	"github.com/spf13/cobra"
+)
+
+var (
+	trimPathDryRun bool
+)
+
+// trimPathCmd represents the trim-path command
+var trimPathCmd = &cobra.Command{
+	Use:   "trim-path",
+	Short: "Propose a minimal PATH that keeps every reachable tool",
+	Long: `Analyze your PATH and propose a shorter one that preserves access to every
+currently reachable tool.
+
+A PATH entry is only dropped when removing it cannot change which binary any
+command resolves to:
+  - the directory does not exist
+  - it duplicates an earlier entry (including symlinked aliases)
+  - it contains no executables
+  - every executable in it is shadowed by an earlier entry
+
+Relative entries such as "." or "bin" (and empty entries, which mean ".")
+are reported separately and left out of the suggestion: they resolve against
+the working directory, so they can shadow later entries in one directory and
+not in another. Replace one with an absolute path if you rely on it.
+
+Nothing is changed on your system:
+by default the command explains each removal and prints the suggested
+export line. With --dry-run=false it prints only the export line, for eval.`,
+	Example: `  # Show the suggested PATH and why entries were dropped
+  cli trim-path
+
+  # Apply the suggestion to the current shell
+  eval "$(cli trim-path --dry-run=false)"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		s := newScanner(cmd)
+		paths := s.GetPaths()
+
+		dirs := pathenv.Analyze(paths)
+		keep, removed := pathenv.Trim(dirs)
+		exportLine := fmt.Sprintf("export PATH=%s", display.ShellQuote(pathenv.Join(keep)))
+
+		if !trimPathDryRun {
+			fmt.Fprintln(os.Stdout, exportLine)
+			return
+		}
+
+		if len(removed) == 0 {
+			fmt.Fprintf(os.Stdout, "Your PATH is already minimal (%d entries).\n", len(paths))
+			return
+		}
+
+		fmt.Fprintf(os.Stdout, "Current PATH:   %d entries\n", len(paths))
+		fmt.Fprintf(os.Stdout, "Suggested PATH: %d entries\n\n", len(keep))
+
+		var redundant, relative []pathenv.Removal
+		for _, r := range removed {
+			if r.Relative {
+				relative = append(relative, r)
+			} else {
+				redundant = append(redundant, r)
+			}
+		}
+		if len(redundant) > 0 {
+			fmt.Fprintln(os.Stdout, "Entries that can be removed:")
+			printRemovals(redundant)
+		}
+		if len(relative) > 0 {
+			if len(redundant) > 0 {
+				fmt.Fprintln(os.Stdout)
+			}
+			fmt.Fprintln(os.Stdout, "Entries that depend on the working directory:")
+			printRemovals(relative)
+		}
+
+		fmt.Fprintln(os.Stdout, "\nSuggested PATH (add to your shell profile to keep it):")
+		fmt.Fprintf(os.Stdout, "  %s\n", exportLine)
+	},
+}
+
+// printRemovals lists PATH entries with the reason each is dropped
+func printRemovals(removed []pathenv.Removal) {
+	for _, r := range removed {
+		path := r.Dir.Path
+		if path == "" {
+			path = `""`
+		}
+		fmt.Fprintf(os.Stdout, "  #%-3d %s\n", r.Dir.Index+1, path)
+		fmt.Fprintf(os.Stdout, "        %s\n", r.Reason)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(trimPathCmd)
+	trimPathCmd.Flags().BoolVar(&trimPathDryRun, "dry-run", true, "explain removals instead of printing only the export line")
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli-ai-org/cli/internal/collector"
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui [query]",
+	Short: "Browse tools interactively",
+	Long: `Browse the tools in PATH in a full-screen terminal interface: search them
+by name or by the package that provides them, see the details of one, and
+act on it.
+
+The results narrow as you type. Searching is fuzzy: the letters typed must
+appear in the name in order, so "kctl" finds kubectl. Exact and prefix
+matches are listed first. Results are marked when a tool has several
+installations (the first in PATH runs, shadowing the others) or is broken.
+
+The detail pane shows the selected tool's path, the package or version
+manager that owns it, its version, the installations it shadows, and as
+much of its help as fits. The version and help are probed when a tool is
+first selected, or read from the cache --with-meta fills; with --no-exec,
+only cached ones.
+
+Keys:
+  ↑/↓, ctrl+p/ctrl+n  select a result (pgup/pgdown a page at a time)
+  ctrl+y              copy the selected tool's path to the clipboard of
+                      terminals that support OSC 52
+  ctrl+u              show the command that removes it, without running it
+  esc                 clear the search, or leave when it is empty
+  ctrl+c              leave
+
+cli tui needs a terminal; scripts can use cli search and cli info instead.`,
+	Example: `  # Start browsing
+  cli tui
+
+  # Start with the results of a search
+  cli tui docker`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !display.IsTerminal(os.Stdin) || !display.IsTerminal(os.Stdout) {
+			cmd.PrintErrln("Error: cli tui needs a terminal; use cli search or cli info in scripts")
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(os.Stderr, "Scanning for CLI tools...")
+		tools, err := newScanner(cmd).ScanAllOccurrences()
+		if err != nil && !isCancelled(err) {
+			cmd.PrintErrf("Error scanning for tools: %v\n", err)
+			os.Exit(1)
+		}
+		pkgs, err := newDetector(cmd).DetectAll()
+		if err != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: some package managers failed: %v\n", err)
+		}
+		tools = newLinker(cmd, pkgs).LinkTools(tools)
+		warnIfPartial(cmd)
+
+		query := ""
+		if len(args) == 1 {
+			query = args[0]
+		}
+		c := collector.NewWithOptions(collectorOptions(cmd.Context()))
+		browser := tui.New(tools, query)
+		browser.Clipboard = os.Stdout
+		browser.Describe = func(tool *models.Tool) {
+			enriched := c.CollectAll([]models.Tool{*tool}, nil)
+			if len(enriched) == 1 && enriched[0] != nil {
+				tool.Version = enriched[0].Version
+				tool.HelpText = enriched[0].HelpText
+			}
+		}
+
+		_, err = tea.NewProgram(browser, tea.WithAltScreen(), tea.WithContext(cmd.Context())).Run()
+		if saveErr := c.SaveMetaCache(); saveErr != nil && verbose {
+			fmt.Fprintf(os.Stderr, "Warning: could not save metadata cache: %v\n", saveErr)
+		}
+		// A cancelled context kills the program, which is not an error
+		if err != nil && !errors.Is(err, tea.ErrProgramKilled) {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+}
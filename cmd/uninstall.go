@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/shims"
+	"github.com/spf13/cobra"
+)
+
+var (
+	uninstallYes     bool
+	uninstallForce   bool
+	uninstallManager string
+)
+
+// uninstallCmd represents the uninstall command
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall <tool>",
+	Short: "Remove a tool with the package manager that installed it",
+	Long: `Remove the package behind a tool, using the package manager that installed it
+(brew uninstall, npm uninstall -g, pip uninstall, pipx uninstall, cargo
+uninstall, ...). Binaries installed with go install have no uninstall
+command; the package's binaries are deleted instead.
+
+The tool is looked up in PATH and the installation that runs is removed;
+use --manager to remove a shadowed installation from that manager instead.
+If no tool has the name, it is taken as a package name.
+
+Removing a package removes every tool it provides, so they are listed
+before you are asked to confirm. Use --yes to skip the question, which is
+required when stdin is not a terminal.
+
+Binaries no detected package manager owns are refused, because deleting
+the file may not be the whole story (an installer, a tarball, a build from
+source). Use --force to delete the file anyway. Files in operating system
+directories are never deleted.`,
+	Example: `  # Remove the package that provides the active http
+  cli uninstall http
+
+  # Remove the npm copy of a tool, leaving the brew one
+  cli uninstall prettier --manager npm
+
+  # Delete a stray binary no package manager owns, without asking
+  cli uninstall mytool --force --yes`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if uninstallManager != "" {
+			if _, err := packages.ParseManager(uninstallManager); err != nil {
+				cmd.PrintErrf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		detector := newDetector(cmd)
+		pkgs, err := detector.DetectAll()
+		if err != nil {
+			cmd.PrintErrf("Error detecting packages: %v\n", err)
+			os.Exit(1)
+		}
+		tool, err := findInstallation(cmd, name, uninstallManager, pkgs)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if tool.Shim != nil {
+			// Deleting a shim only breaks it until the manager reshims
+			cmd.PrintErrf("Error: %s is a %s shim", tool.Path, tool.Shim.Manager)
+			if tool.Shim.Plugin != "" && tool.Shim.Version != "" {
+				cmd.PrintErrf(" for %s %s; remove that version with `%s`", tool.Shim.Plugin, tool.Shim.Version,
+					shims.UninstallCommand(shims.Manager(tool.Shim.Manager), tool.Shim.Plugin, tool.Shim.Version))
+			}
+			cmd.PrintErrf("\n")
+			os.Exit(1)
+		}
+
+		if tool.PackageName == "" {
+			if packages.IsSystemPath(tool.Path) {
+				cmd.PrintErrf("Error: %s belongs to the operating system; remove it with the system's package manager\n", tool.Path)
+				os.Exit(1)
+			}
+			if !uninstallForce {
+				cmd.PrintErrf("Error: %s is not owned by a detected package manager, so it cannot be uninstalled cleanly\n", tool.Path)
+				cmd.PrintErrf("Use --force to delete the file anyway.\n")
+				os.Exit(1)
+			}
+			if !confirmUninstall(cmd, fmt.Sprintf("Delete %s?", tool.Path)) {
+				return
+			}
+			removeFiles(cmd, []string{tool.Path})
+			return
+		}
+
+		manager := packages.PackageManager(tool.PackageManager)
+		pkg := findPackage(pkgs, manager, tool.PackageName)
+		fmt.Fprintf(os.Stderr, "%s is provided by %s package %s", name, manager, tool.PackageName)
+		if tool.Path != "" {
+			fmt.Fprintf(os.Stderr, " (%s)", tool.Path)
+		}
+		fmt.Fprintln(os.Stderr)
+		var others []string
+		for _, binary := range pkg.Binaries {
+			if binary != name {
+				others = append(others, binary)
+			}
+		}
+		if len(others) > 0 {
+			sort.Strings(others)
+			fmt.Fprintf(os.Stderr, "It also provides %d other %s: %s\n", len(others), plural(len(others), "tool", "tools"), listNames(others))
+		}
+
+		argv := packages.UninstallCommand(manager, tool.PackageName)
+		if argv == nil && manager == packages.Poetry {
+			// Deleting the command would leave poetry's environment behind
+			cmd.PrintErrf("Error: poetry is removed by its installer; run it with --uninstall\n")
+			os.Exit(1)
+		}
+		if argv == nil {
+			// go and deno have no uninstall for a package; its binaries are
+			// the whole installation
+			files := packageBinaries(pkg, tool)
+			if !confirmUninstall(cmd, fmt.Sprintf("Delete %s?", strings.Join(files, ", "))) {
+				return
+			}
+			removeFiles(cmd, files)
+			return
+		}
+		if manager == packages.Pip && tool.Path != "" {
+			// Uninstall from the environment the tool was installed into
+			if python := filepath.Join(filepath.Dir(tool.Path), "python3"); isFile(python) {
+				argv[0] = python
+			}
+		}
+		if !confirmUninstall(cmd, fmt.Sprintf("Run %s?", quoteCommand(argv))) {
+			return
+		}
+		runManagerCommand(cmd, argv)
+	},
+}
+
+// findPackage returns the detected package with the manager and name, or
+// one holding just those if it was not detected
+func findPackage(pkgs []packages.Package, manager packages.PackageManager, name string) packages.Package {
+	for _, pkg := range pkgs {
+		if pkg.Manager == manager && pkg.Name == name {
+			return pkg
+		}
+	}
+	return packages.Package{Name: name, Manager: manager}
+}
+
+// packageBinaries lists the files a go or deno package installed: each of
+// its binaries in its bin directory, or just the tool when the package has
+// no record
+func packageBinaries(pkg packages.Package, tool models.Tool) []string {
+	if pkg.Location == "" || len(pkg.Binaries) == 0 {
+		return []string{tool.Path}
+	}
+	var files []string
+	for _, binary := range pkg.Binaries {
+		if runtime.GOOS == "windows" {
+			// deno installs .cmd scripts
+			if isFile(filepath.Join(pkg.Location, binary+".cmd")) {
+				binary += ".cmd"
+			} else {
+				binary += ".exe"
+			}
+		}
+		files = append(files, filepath.Join(pkg.Location, binary))
+	}
+	return files
+}
+
+// confirmUninstall asks question on the terminal unless --yes was given.
+// Without a terminal to ask on, it fails rather than guess.
+func confirmUninstall(cmd *cobra.Command, question string) bool {
+	if uninstallYes {
+		return true
+	}
+	if !display.IsTerminal(os.Stdin) {
+		cmd.PrintErrf("Error: stdin is not a terminal; pass --yes to confirm\n")
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	fmt.Fprintln(os.Stderr, "Cancelled.")
+	return false
+}
+
+// removeFiles deletes files, exiting on the first failure
+func removeFiles(cmd *cobra.Command, files []string) {
+	for _, file := range files {
+		if err := os.Remove(file); err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "✓ Deleted %s\n", file)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd)
+	uninstallCmd.Flags().BoolVarP(&uninstallYes, "yes", "y", false, "don't ask for confirmation")
+	uninstallCmd.Flags().BoolVar(&uninstallForce, "force", false, "delete binaries no package manager owns")
+	uninstallCmd.Flags().StringVarP(&uninstallManager, "manager", "m", "", "remove the installation from this manager (brew, npm, pip, ...)")
+}
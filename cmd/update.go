@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"debug/buildinfo"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/spf13/cobra"
+)
+
+var (
+	updateDryRun  bool
+	updateManager string
+)
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:   "update <tool|package>",
+	Short: "Upgrade a tool's package with the manager that owns it",
+	Long: `Upgrade the package behind a tool, using the package manager that installed it:
+  - brew:  brew upgrade <formula>
+  - npm:   npm update -g <package>
+  - pip:   python3 -m pip install --upgrade <package> (the tool's own environment)
+  - pipx:  pipx upgrade <package>
+  - uv:    uv tool upgrade <tool>
+  - poetry: poetry self update, or poetry self add <plugin>@latest for plugins
+  - cargo: cargo install <crate>
+  - go:    go install <package>@latest
+  - nix:   nix profile upgrade <name> (nix-env --upgrade for nix-env profiles)
+  - snap, flatpak: sudo snap refresh <snap>, flatpak update -y <app-id>
+  - dotnet: dotnet tool update --global <package>
+  - composer: composer global update <vendor/package>
+  - gem, port, scoop, choco, winget: their upgrade commands
+
+The argument is first looked up as a tool in PATH, and the package behind
+the installation that runs is upgraded. If no tool has that name, it is
+taken as a package name. Use --manager when several managers provide it,
+or to upgrade a shadowed installation from that manager.
+
+The manager's command runs with your terminal attached, so its prompts and
+progress appear as usual. Use --dry-run to print the command instead.`,
+	Example: `  # Upgrade whatever package provides the active rg
+  cli update rg
+
+  # See what would run without running it
+  cli update tsc --dry-run
+
+  # Upgrade the npm copy of a tool also installed by brew
+  cli update prettier --manager npm`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if updateManager != "" {
+			if _, err := packages.ParseManager(updateManager); err != nil {
+				cmd.PrintErrf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		argv, err := updateCommand(cmd, args[0])
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if updateDryRun {
+			fmt.Fprintln(os.Stdout, quoteCommand(argv))
+			return
+		}
+		runManagerCommand(cmd, argv)
+	},
+}
+
+// updateCommand resolves a tool or package name to the command that
+// upgrades its package
+func updateCommand(cmd *cobra.Command, name string) ([]string, error) {
+	detector := newDetector(cmd)
+	pkgs, err := detector.DetectAll()
+	if err != nil {
+		return nil, fmt.Errorf("detecting packages: %w", err)
+	}
+
+	tool, err := findInstallation(cmd, name, updateManager, pkgs)
+	if err != nil {
+		return nil, err
+	}
+	if tool.PackageName == "" {
+		return nil, fmt.Errorf("%s (%s) is not owned by a detected package manager (%s)", name, tool.Path, packages.Provenance(tool))
+	}
+
+	manager := packages.PackageManager(tool.PackageManager)
+	pkgName := tool.PackageName
+	if manager == packages.Go && tool.Path != "" {
+		// The package is the main module, which need not be the package
+		// that builds the binary
+		if info, err := buildinfo.ReadFile(tool.Path); err == nil && info.Path != "" {
+			pkgName = info.Path
+		}
+	}
+
+	argv := packages.UpgradeCommand(manager, pkgName)
+	if argv == nil {
+		return nil, fmt.Errorf("don't know how to upgrade %s packages; upgrade %s with %s directly", manager, tool.PackageName, manager)
+	}
+	if manager == packages.Pip && tool.Path != "" {
+		// Upgrade in the environment the tool was installed into
+		if python := filepath.Join(filepath.Dir(tool.Path), "python3"); isFile(python) {
+			argv[0] = python
+		}
+	}
+	return argv, nil
+}
+
+// findInstallation finds the installation update and uninstall act on: the
+// active installation of the tool called name, or with manager that
+// manager's copy of it, else a package called name. A package found by name has no installation path, except a
+// go package, whose binaries are in its Location.
+func findInstallation(cmd *cobra.Command, name, manager string, pkgs []packages.Package) (models.Tool, error) {
+	tools := newLinker(cmd, pkgs).LinkTools(newScanner(cmd).FindAll(name))
+	if len(tools) > 0 {
+		if manager == "" {
+			return tools[0], nil
+		}
+		for _, tool := range tools {
+			if tool.PackageManager == manager && tool.PackageName != "" {
+				return tool, nil
+			}
+		}
+		return models.Tool{}, fmt.Errorf("no installation of %s is owned by %s", name, manager)
+	}
+
+	var matches []packages.Package
+	for _, pkg := range pkgs {
+		if manager != "" && string(pkg.Manager) != manager {
+			continue
+		}
+		if strings.EqualFold(pkg.Name, name) {
+			matches = append(matches, pkg)
+		}
+	}
+	switch {
+	case len(matches) == 0:
+		return models.Tool{}, fmt.Errorf("no tool or package named %q", name)
+	case len(matches) > 1:
+		var managers []string
+		for _, pkg := range matches {
+			managers = append(managers, string(pkg.Manager))
+		}
+		sort.Strings(managers)
+		return models.Tool{}, fmt.Errorf("%q is installed by several managers (%s); pick one with --manager", name, strings.Join(managers, ", "))
+	}
+
+	pkg := matches[0]
+	tool := models.Tool{Name: name, PackageName: pkg.Name, PackageManager: string(pkg.Manager)}
+	if pkg.Manager == packages.Go && len(pkg.Binaries) > 0 {
+		binary := pkg.Binaries[0]
+		if runtime.GOOS == "windows" {
+			binary += ".exe"
+		}
+		tool.Path = filepath.Join(pkg.Location, binary)
+	}
+	return tool, nil
+}
+
+// quoteCommand renders an argument list as a shell command line
+func quoteCommand(argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = display.ShellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// runManagerCommand runs a package manager's command with the terminal
+// attached and exits with its exit code if it fails
+func runManagerCommand(cmd *cobra.Command, argv []string) {
+	fmt.Fprintf(os.Stderr, "Running: %s\n", quoteCommand(argv))
+	run := exec.CommandContext(cmd.Context(), argv[0], argv[1:]...)
+	run.Stdin = os.Stdin
+	run.Stdout = os.Stdout
+	run.Stderr = os.Stderr
+	if err := run.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			os.Exit(exitErr.ExitCode())
+		}
+		cmd.PrintErrf("Error: running %s: %v\n", argv[0], err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().BoolVar(&updateDryRun, "dry-run", false, "print the upgrade command instead of running it")
+	updateCmd.Flags().StringVarP(&updateManager, "manager", "m", "", "upgrade the package from this manager (brew, npm, pip, ...)")
+}
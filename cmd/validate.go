@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cli-ai-org/cli/internal/validate"
+	"github.com/spf13/cobra"
+)
+
+var (
+	validateJSON   bool
+	validateFormat string
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate <catalog.json>",
+	Short: "Check that an exported catalog is well-formed",
+	Long: `Load a catalog written by "cli export" and check it against the catalog
+format: unknown fields, missing required fields (a tool's name and path, a
+package's name and manager), values of the wrong type, and total_tools or
+total_packages that do not match the entries present.
+
+NDJSON files written by "export --format ndjson" or "export --append" are
+recognized and checked one tool record per line.
+
+Useful for catalogs produced by older versions or edited by hand. Fields
+added in later versions, such as path_index, are optional.
+
+Exits with status 1 when any problem is found.`,
+	Example: `  # Check a catalog
+  cli validate tools.json
+
+  # Check a fleet inventory built with --append
+  cli validate /mnt/shared/fleet.ndjson
+
+  # Problems as JSON
+  cli validate tools.json --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		format, err := resolveFormat(validateFormat, validateJSON)
+		if err != nil {
+			cmd.PrintErrf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			cmd.PrintErrf("Error reading catalog: %v\n", err)
+			os.Exit(1)
+		}
+
+		result := validate.Data(data)
+
+		if format == formatJSON {
+			if result.Problems == nil {
+				result.Problems = []validate.Problem{}
+			}
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(result); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(1)
+			}
+		} else if result.Valid() {
+			fmt.Fprintf(os.Stdout, "✓ %s: valid %s with %d tools\n", args[0], result.Format, result.Tools)
+		} else {
+			fmt.Fprintf(os.Stdout, "✗ %s: %d problem(s) in %s with %d tools\n\n", args[0], len(result.Problems), result.Format, result.Tools)
+			for _, problem := range result.Problems {
+				fmt.Fprintf(os.Stdout, "  %s\n", problem)
+			}
+		}
+
+		if !result.Valid() {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().BoolVarP(&validateJSON, "json", "j", false, "output in JSON format")
+	validateCmd.Flags().StringVarP(&validateFormat, "format", "f", "", "output format: table or json (default: table in a terminal, json when piped)")
+}
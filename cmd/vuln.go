@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/vuln"
+	"github.com/spf13/cobra"
+)
+
+// formatSARIF is the static analysis interchange format code scanning
+// services read
+const formatSARIF = "sarif"
+
+var (
+	vulnJSON     bool
+	vulnFormat   string
+	vulnManager  string
+	vulnSeverity string
+	vulnOffline  bool
+	vulnUpdateDB bool
+	vulnFailOn   string
+)
+
+// vulnCmd represents the vuln command
+var vulnCmd = &cobra.Command{
+	Use:   "vuln",
+	Short: "Report known vulnerabilities in CLI-providing packages",
+	Long: `Check the installed versions of packages that provide CLI tools against the
+OSV database (https://osv.dev) and report the known vulnerabilities that
+affect them, with their CVE, severity, and the versions that fix them.
+
+OSV covers these package managers:
+  - pip, pipx, uv, poetry: PyPI
+  - npm:   npm
+  - cargo: crates.io
+  - go:    Go
+  - gem:   RubyGems
+Packages of other managers, such as Homebrew, are not checked.
+
+By default all packages are sent to the OSV API in one batch query, and the
+records of the vulnerabilities found are kept under the user cache
+directory so later runs only fetch what changed. With --update-db, the OSV
+database of each ecosystem is downloaded instead, and with --offline the
+packages are matched against that copy without using the network. Offline
+matching compares versions loosely, so the API is the more precise check.
+
+Severity is the CVSS v3 rating of the vulnerability, or the advisory's own
+rating when it has no CVSS v3 vector. --severity reports only findings at
+least that severe, leaving out those of unknown severity.
+
+Output formats:
+  table  Findings, most severe first
+  json   The findings, with aliases, summary, score, and fixing versions
+  sarif  A SARIF 2.1.0 log for code scanning, one rule per vulnerability
+
+With --fail-on SEVERITY, vuln exits with status 1 when a reported finding
+is at least that severe, after writing its output, so it can gate CI jobs.
+It exits with status 2 when the check cannot run.`,
+	Example: `  # Check every CLI-providing package
+  cli vuln
+
+  # Only high and critical findings in npm packages
+  cli vuln --manager npm --severity high
+
+  # Download the database, then check without the network
+  cli vuln --update-db
+  cli vuln --offline
+
+  # SARIF for code scanning
+  cli vuln --format sarif > vuln.sarif
+
+  # Fail a CI job on critical findings
+  cli vuln --fail-on critical`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format := vulnFormat
+		if format != formatSARIF {
+			var err error
+			if format, err = resolveFormat(vulnFormat, vulnJSON); err != nil {
+				cmd.PrintErrf("Error: unknown format %q (valid: %s, %s, %s)\n", vulnFormat, formatTable, formatJSON, formatSARIF)
+				os.Exit(exitError)
+			}
+		}
+
+		minSeverity, ok := parseVulnSeverity(vulnSeverity)
+		if !ok {
+			cmd.PrintErrf("Error: unknown severity %q (valid: %s)\n", vulnSeverity, vulnSeverityNames())
+			os.Exit(exitError)
+		}
+		failOn, ok := parseVulnSeverity(vulnFailOn)
+		if !ok {
+			cmd.PrintErrf("Error: unknown --fail-on severity %q (valid: %s)\n", vulnFailOn, vulnSeverityNames())
+			os.Exit(exitError)
+		}
+
+		if vulnManager != "" {
+			if _, ok := vuln.Ecosystem(vulnManager); !ok {
+				cmd.PrintErrf("Error: vulnerability check not supported for %q (valid: %s)\n", vulnManager, strings.Join(vuln.Managers(), ", "))
+				os.Exit(exitError)
+			}
+		}
+
+		detector := newDetector(cmd)
+		pkgs, err := detector.DetectAll()
+		if err != nil {
+			cmd.PrintErrf("Error detecting packages: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		s := newScanner(cmd)
+		tools, err := s.ScanAllDetailed()
+		if err != nil {
+			cmd.PrintErrf("Error scanning tools: %v\n", err)
+			os.Exit(exitError)
+		}
+		tools = newLinker(cmd, pkgs).LinkTools(tools)
+
+		// Locate each package at the first of its tools that runs
+		paths := make(map[string]string)
+		for _, tool := range tools {
+			key := outdatedKey(tool.PackageManager, tool.PackageName)
+			if tool.PackageName != "" && !tool.Shadowed && paths[key] == "" {
+				paths[key] = tool.Path
+			}
+		}
+
+		var checked []vuln.Package
+		skipped := make(map[string]int)
+		for _, info := range packages.GetPackagesWithBinaries(pkgs, tools) {
+			if vulnManager != "" && info.Manager != vulnManager {
+				continue
+			}
+			if _, ok := vuln.Ecosystem(info.Manager); !ok {
+				skipped[info.Manager]++
+				continue
+			}
+			if info.Version == "" || strings.HasPrefix(info.Version, "(") {
+				// Go reports "(devel)" for binaries built from a checkout
+				skipped[info.Manager]++
+				continue
+			}
+			checked = append(checked, vuln.Package{
+				Name:     info.Name,
+				Version:  info.Version,
+				Manager:  info.Manager,
+				Binaries: info.Binaries,
+				Path:     paths[outdatedKey(info.Manager, info.Name)],
+			})
+		}
+		if verbose {
+			var managers []string
+			for manager := range skipped {
+				managers = append(managers, manager)
+			}
+			sort.Strings(managers)
+			for _, manager := range managers {
+				fmt.Fprintf(os.Stderr, "Skipping %d %s %s: not covered by OSV or no version\n", skipped[manager], manager, plural(skipped[manager], "package", "packages"))
+			}
+		}
+
+		checker := vuln.NewChecker()
+		checker.SetContext(cmd.Context())
+		if vulnUpdateDB {
+			ecosystems := make(map[string]bool)
+			for _, pkg := range checked {
+				ecosystem, _ := vuln.Ecosystem(pkg.Manager)
+				ecosystems[ecosystem] = true
+			}
+			var names []string
+			for ecosystem := range ecosystems {
+				names = append(names, ecosystem)
+			}
+			sort.Strings(names)
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Downloading the OSV database for %s into %s...\n", strings.Join(names, ", "), checker.DBDir())
+			}
+			if err := checker.UpdateDB(names); err != nil {
+				cmd.PrintErrf("Error updating vulnerability database: %v\n", err)
+				os.Exit(exitError)
+			}
+		}
+		checker.SetOffline(vulnOffline || vulnUpdateDB)
+
+		var findings []vuln.Finding
+		if len(checked) > 0 {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Checking %d %s against OSV...\n", len(checked), plural(len(checked), "package", "packages"))
+			}
+			findings, err = checker.Check(checked)
+			if err != nil {
+				var missing *vuln.MissingDBError
+				if errors.As(err, &missing) {
+					cmd.PrintErrf("Error: %v\n", err)
+				} else {
+					cmd.PrintErrf("Error checking vulnerabilities: %v\n", err)
+				}
+				os.Exit(exitError)
+			}
+		}
+		findings = vuln.Filter(findings, minSeverity)
+		vuln.Sort(findings)
+
+		switch format {
+		case formatSARIF:
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if err := encoder.Encode(vuln.SARIF(findings, "cli", version)); err != nil {
+				cmd.PrintErrf("Error encoding SARIF: %v\n", err)
+				os.Exit(exitError)
+			}
+		case formatJSON:
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			if findings == nil {
+				findings = []vuln.Finding{}
+			}
+			if err := encoder.Encode(findings); err != nil {
+				cmd.PrintErrf("Error encoding JSON: %v\n", err)
+				os.Exit(exitError)
+			}
+		default:
+			printVulnTable(findings, len(checked))
+		}
+
+		if vulnFailOn != "" && len(vuln.Filter(findings, failOn)) > 0 {
+			os.Exit(exitFindings)
+		}
+	},
+}
+
+// printVulnTable prints findings, most severe first, for the checked
+// packages
+func printVulnTable(findings []vuln.Finding, checked int) {
+	if len(findings) == 0 {
+		fmt.Fprintf(os.Stdout, "No known vulnerabilities in %d CLI-providing %s.\n", checked, plural(checked, "package", "packages"))
+		return
+	}
+
+	affected := make(map[string]bool)
+	for _, f := range findings {
+		affected[outdatedKey(f.Manager, f.Package)] = true
+	}
+	fmt.Fprintf(os.Stdout, "Found %d %s in %d of %d CLI-providing packages:\n\n", len(findings), plural(len(findings), "vulnerability", "vulnerabilities"), len(affected), checked)
+	table := display.NewTable(os.Stdout, "SEVERITY", "PACKAGE", "VERSION", "ID", "FIXED IN")
+	table.Rule = true
+	for _, f := range findings {
+		fixed := strings.Join(f.Fixed, ", ")
+		if fixed == "" {
+			fixed = "-"
+		}
+		table.Row(display.Severity(string(f.Severity), string(f.Severity)), display.Manager(f.Manager)+"/"+f.Package, f.Version, f.CVE(), fixed)
+	}
+	table.Flush()
+}
+
+// parseVulnSeverity parses a --severity or --fail-on level; "" is every
+// finding
+func parseVulnSeverity(name string) (vuln.Severity, bool) {
+	if name == "" {
+		return vuln.SeverityUnknown, true
+	}
+	return vuln.ParseSeverity(name)
+}
+
+func vulnSeverityNames() string {
+	var names []string
+	for _, severity := range vuln.Severities() {
+		names = append(names, string(severity))
+	}
+	return strings.Join(names, ", ")
+}
+
+func init() {
+	rootCmd.AddCommand(vulnCmd)
+	vulnCmd.Flags().BoolVarP(&vulnJSON, "json", "j", false, "output in JSON format")
+	vulnCmd.Flags().StringVarP(&vulnFormat, "format", "f", "", "output format: table, json, or sarif (default: table in a terminal, json when piped)")
+	vulnCmd.Flags().StringVarP(&vulnManager, "manager", "m", "", "only check one package manager (pip, pipx, uv, poetry, npm, cargo, go, gem)")
+	vulnCmd.Flags().StringVar(&vulnSeverity, "severity", "", "only report findings at least this severe: low, medium, high, or critical")
+	vulnCmd.Flags().BoolVar(&vulnOffline, "offline", false, "match against the downloaded OSV database instead of querying the API")
+	vulnCmd.Flags().BoolVar(&vulnUpdateDB, "update-db", false, "download the OSV database for the detected ecosystems, then check offline (needs network)")
+	vulnCmd.Flags().StringVar(&vulnFailOn, "fail-on", "", "exit with status 1 when a finding is at least this severe: low, medium, high, or critical")
+}
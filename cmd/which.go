@@ -0,0 +1,315 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/builtins"
+	"github.com/cli-ai-org/cli/internal/collector"
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/scanner"
+	"github.com/cli-ai-org/cli/internal/semver"
+	"github.com/cli-ai-org/cli/internal/shellrc"
+	"github.com/cli-ai-org/cli/internal/shims"
+	"github.com/spf13/cobra"
+)
+
+var (
+	whichJSON     bool
+	whichShell    string
+	whichVersions bool
+	packageOfJSON bool
+)
+
+// whichCmd represents the which command
+var whichCmd = &cobra.Command{
+	Use:   "which <tool>",
+	Short: "Show every installation of a tool and which one runs",
+	Long: `Like "which -a", but enriched: every installation of the tool in PATH order,
+the active one marked, the package and manager that own each, and where each
+symlink finally leads.
+
+With --versions, each installation is also run with --version and the version
+it reports is shown, which tells copies apart that no package manager owns.
+
+Aliases and functions defined in your shell's startup files, and shell
+builtins, run before anything in PATH. They are reported first, in the order
+the shell consults them: alias, function, builtin, then PATH. Startup files
+are read for the shell in $SHELL (or --shell); aliases and functions defined
+only in an interactive session cannot be seen.
+
+Use --json to get full installation records (path, manager, version, active
+and broken flags) for automation.`,
+	Example: `  # Show all installations of git
+  cli which git
+
+  # Structured output for agents
+  cli which python3 --json
+
+  # Compare the versions of every copy of node
+  cli which node --versions
+
+  # Check aliases in zsh's startup files
+  cli which ls --shell zsh`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		lookup := lookupTool(cmd, args[0])
+		if whichShell == "" {
+			whichShell = shellrc.CurrentShell()
+		}
+		lookup.Overrides = shellOverrides(args[0], whichShell)
+		if whichVersions {
+			probeInstallVersions(cmd, &lookup)
+		}
+
+		if whichJSON {
+			printLookupJSON(cmd, lookup)
+			if !lookup.Found && len(lookup.Overrides) == 0 {
+				os.Exit(1)
+			}
+			return
+		}
+
+		for _, override := range lookup.Overrides {
+			marker := ""
+			if override.Active {
+				marker = "  ← runs when typed in " + override.Shell
+			}
+			fmt.Fprintf(os.Stdout, "%s%s\n", describeOverride(lookup.Name, override), marker)
+		}
+		if len(lookup.Overrides) > 0 && lookup.Found {
+			fmt.Fprintf(os.Stdout, "Typing %s does not run the PATH executables below unless invoked as `command %s`.\n\n", lookup.Name, lookup.Name)
+		}
+
+		if lookup.Found {
+			fmt.Fprintf(os.Stdout, "%s (%d installation(s)):\n", lookup.Name, len(lookup.Installations))
+			for _, inst := range lookup.Installations {
+				marker := "  "
+				status := display.Status("shadowed", "shadowed")
+				if inst.IsActive {
+					marker = display.Status("active", "✓") + " "
+					status = display.Status("active", "ACTIVE")
+				}
+				fmt.Fprintf(os.Stdout, "  %s%s\n", marker, inst.Path)
+				if inst.ResolvedPath != "" {
+					fmt.Fprintf(os.Stdout, "      -> %s\n", inst.ResolvedPath)
+				}
+				fmt.Fprintf(os.Stdout, "      %s, %s\n", describeOwner(inst), status)
+				if inst.ReportedVersion != "" {
+					fmt.Fprintf(os.Stdout, "      reports version %s\n", inst.ReportedVersion)
+				}
+				if inst.Broken {
+					fmt.Fprintf(os.Stdout, "      %s: %s\n", display.Status("broken", "⚠ broken"), inst.BrokenReason)
+				}
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: not found in PATH\n", lookup.Name)
+		}
+
+		if !lookup.Found && len(lookup.Overrides) == 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// packageOfCmd represents the package-of command
+var packageOfCmd = &cobra.Command{
+	Use:   "package-of <tool>",
+	Short: "Show which package provides a tool",
+	Long: `Reverse lookup from a command name to the package and package manager that
+installed the copy your shell runs.
+
+Use --json to get full installation records for every copy in PATH.`,
+	Example: `  # Which package provides tsc?
+  cli package-of tsc
+
+  # Structured output for agents
+  cli package-of tsc --json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		lookup := lookupTool(cmd, args[0])
+
+		if packageOfJSON {
+			printLookupJSON(cmd, lookup)
+		} else if lookup.Found {
+			active := lookup.Installations[0]
+			fmt.Fprintf(os.Stdout, "%s: %s\n", lookup.Name, describeOwner(active))
+			fmt.Fprintf(os.Stdout, "  %s\n", active.Path)
+		} else {
+			fmt.Fprintf(os.Stderr, "%s: not found in PATH\n", lookup.Name)
+		}
+
+		if !lookup.Found {
+			os.Exit(1)
+		}
+	},
+}
+
+// lookupTool resolves a tool name against PATH and links every installation
+// to its package
+func lookupTool(cmd *cobra.Command, name string) models.ToolLookup {
+	s := newScanner(cmd)
+	tools := s.FindAll(name)
+	scanner.CheckInterpreters(tools)
+
+	lookup := models.ToolLookup{
+		Name:          name,
+		Found:         len(tools) > 0,
+		Installations: []models.InstallationInfo{},
+	}
+	if len(tools) == 0 {
+		return lookup
+	}
+
+	detector := newDetector(cmd)
+	pkgs, _ := detector.DetectAll()
+	tools = newLinker(cmd, pkgs).LinkTools(tools)
+
+	for i, tool := range tools {
+		inst := models.InstallationInfo{
+			Path:           tool.Path,
+			PackageName:    tool.PackageName,
+			PackageManager: packages.Provenance(tool),
+			Version:        tool.PackageVersion,
+			IsActive:       i == 0,
+			Broken:         tool.Broken,
+			BrokenReason:   tool.BrokenReason,
+			SymlinkTo:      tool.SymlinkTo,
+			Shim:           tool.Shim,
+		}
+		if tool.IsSymlink {
+			_, inst.ResolvedPath = symlinkChain(tool.Path)
+		}
+		lookup.Installations = append(lookup.Installations, inst)
+	}
+	return lookup
+}
+
+// probeInstallVersions runs every working installation with --version and
+// records the version it reports. Copies that are the same file are only
+// run once.
+func probeInstallVersions(cmd *cobra.Command, lookup *models.ToolLookup) {
+	c := collector.NewWithOptions(collectorOptions(cmd.Context()))
+	for i := range lookup.Installations {
+		inst := &lookup.Installations[i]
+		if inst.Broken || cmd.Context().Err() != nil {
+			continue
+		}
+		for _, earlier := range lookup.Installations[:i] {
+			if earlier.ReportedVersion != "" && sameFile(earlier.Path, inst.Path) {
+				inst.ReportedVersion = earlier.ReportedVersion
+			}
+		}
+		if inst.ReportedVersion != "" {
+			continue
+		}
+		output := c.ProbeVersion(lookup.Name, inst.Path)
+		if version := semver.Extract(output); version != "" {
+			inst.ReportedVersion = version
+		} else {
+			inst.ReportedVersion = firstLine(output)
+		}
+	}
+}
+
+// shellOverrides finds what each shell runs instead of PATH when name is
+// typed, in resolution order: aliases, then functions, then builtins. The
+// first override of each shell is the one that runs in it.
+func shellOverrides(name, shell string) []models.ShellOverride {
+	defs := shellrc.Lookup(shellrc.Parse(shellrc.Shells(shell)), name)
+	builtinShells := make(map[string]bool)
+	for _, s := range builtins.Shells(name) {
+		builtinShells[s] = true
+	}
+
+	var overrides []models.ShellOverride
+	for _, s := range shellrc.Shells(shell) {
+		first := len(overrides)
+		for _, def := range defs {
+			if def.Shell == s {
+				overrides = append(overrides, models.ShellOverride{
+					Kind:   def.Kind,
+					Shell:  def.Shell,
+					Value:  def.Value,
+					Source: fmt.Sprintf("%s:%d", def.File, def.Line),
+				})
+			}
+		}
+		if builtinShells[s] {
+			overrides = append(overrides, models.ShellOverride{Kind: "builtin", Shell: s})
+		}
+		if len(overrides) > first {
+			overrides[first].Active = true
+		}
+	}
+	return overrides
+}
+
+// describeOverride summarizes an alias, function, or builtin, e.g.
+// "ls: aliased to 'eza' in zsh (~/.zshrc:12)"
+func describeOverride(name string, override models.ShellOverride) string {
+	switch override.Kind {
+	case shellrc.Alias:
+		return fmt.Sprintf("%s: aliased to '%s' in %s (%s)", name, override.Value, override.Shell, override.Source)
+	case shellrc.Function:
+		return fmt.Sprintf("%s: shell function in %s (%s)", name, override.Shell, override.Source)
+	}
+	return fmt.Sprintf("%s: %s builtin", name, override.Shell)
+}
+
+// describeOwner summarizes who installed a tool, e.g. "brew package git 2.43.0"
+func describeOwner(inst models.InstallationInfo) string {
+	if inst.Shim != nil {
+		return describeShim(inst.Shim)
+	}
+	if inst.PackageName == "" {
+		if inst.PackageManager == "system" {
+			return "provided by the system"
+		}
+		return "not managed by a detected package manager"
+	}
+	owner := fmt.Sprintf("%s package %s", inst.PackageManager, inst.PackageName)
+	if inst.Version != "" {
+		owner += " " + inst.Version
+	}
+	return owner
+}
+
+// describeShim summarizes what a version manager's shim runs, e.g. "asdf
+// shim for nodejs 20.11.0, runs ~/.asdf/installs/nodejs/20.11.0/bin/node"
+func describeShim(shim *models.ShimInfo) string {
+	owner := shim.Manager + " shim"
+	if shim.Plugin != "" {
+		owner += " for " + strings.TrimSpace(shim.Plugin+" "+shim.Version)
+	}
+	if shim.Manager == string(shims.Nvm) {
+		// nvm puts the install itself on PATH rather than a shim
+		owner = strings.TrimSpace(shim.Plugin+" "+shim.Version) + " installed by nvm"
+	}
+	if shim.Target != "" {
+		owner += ", runs " + shim.Target
+	}
+	return owner
+}
+
+func printLookupJSON(cmd *cobra.Command, lookup models.ToolLookup) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(lookup); err != nil {
+		cmd.PrintErrf("Error encoding JSON: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+	rootCmd.AddCommand(packageOfCmd)
+	whichCmd.Flags().BoolVarP(&whichJSON, "json", "j", false, "output full installation records in JSON format")
+	whichCmd.Flags().BoolVar(&whichVersions, "versions", false, "run each installation with --version and show the version it reports")
+	whichCmd.Flags().StringVar(&whichShell, "shell", "", "read aliases and functions from this shell's startup files: bash, zsh, or fish (default: $SHELL)")
+	packageOfCmd.Flags().BoolVarP(&packageOfJSON, "json", "j", false, "output full installation records in JSON format")
+}
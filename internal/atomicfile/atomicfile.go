@@ -0,0 +1,38 @@
+// Package atomicfile writes files by replacing them whole, so a concurrent
+// reader sees either the old content or the new, never a partial write.
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Write writes data to path, creating its directory if needed. The data
+// goes to a uniquely named temporary file in the same directory, which is
+// then renamed over path, so concurrent writers never clobber each other's
+// temporary file: the last rename wins.
+func Write(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// Removing the temporary file fails harmlessly once it is renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,37 @@
+package atomicfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestWriteConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "entries.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := Write(path, []byte(fmt.Sprintf("writer %02d", i)), 0644); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != len("writer 00") {
+		t.Errorf("got %q, want one writer's whole content", data)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("got %d files, want the temporary files cleaned up", len(entries))
+	}
+}
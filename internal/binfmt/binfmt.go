@@ -0,0 +1,158 @@
+// Package binfmt identifies the file format and CPU architecture of
+// executables: ELF, Mach-O (including universal binaries), PE, and scripts.
+package binfmt
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"os"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/scanner"
+)
+
+// File formats reported in Info.Format
+const (
+	ELF            = "ELF"
+	MachO          = "Mach-O"
+	MachOUniversal = "Mach-O universal"
+	PE             = "PE"
+	Script         = "script"
+	Unknown        = "unknown"
+)
+
+// Info describes an executable file's format
+type Info struct {
+	Format string `json:"format"`
+	// Arch lists the CPU architectures the file contains code for, in Go's
+	// GOARCH spelling (amd64, arm64); universal binaries have several
+	Arch []string `json:"arch,omitempty"`
+	// Static is set for ELF executables that need no dynamic loader
+	Static bool `json:"static,omitempty"`
+	// Interpreter is the program a script's #! line runs
+	Interpreter string `json:"interpreter,omitempty"`
+}
+
+// String summarizes the format, e.g. "ELF amd64, dynamically linked" or
+// "script run by python3"
+func (i Info) String() string {
+	switch i.Format {
+	case Script:
+		if i.Interpreter == "" {
+			return "script"
+		}
+		return "script run by " + i.Interpreter
+	case Unknown:
+		return "unknown format"
+	}
+
+	s := i.Format
+	if len(i.Arch) > 0 {
+		s += " " + strings.Join(i.Arch, ", ")
+	}
+	if i.Format == ELF {
+		if i.Static {
+			s += ", statically linked"
+		} else {
+			s += ", dynamically linked"
+		}
+	}
+	return s
+}
+
+// Identify reads the header of the file at path, following symlinks
+func Identify(path string) (Info, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return Info{}, err
+	}
+	defer file.Close()
+
+	if f, err := elf.NewFile(file); err == nil {
+		info := Info{Format: ELF, Arch: []string{elfArch(f)}, Static: true}
+		for _, prog := range f.Progs {
+			if prog.Type == elf.PT_INTERP {
+				info.Static = false
+			}
+		}
+		return info, nil
+	}
+	if f, err := macho.NewFatFile(file); err == nil {
+		info := Info{Format: MachOUniversal}
+		for _, arch := range f.Arches {
+			info.Arch = append(info.Arch, machoArch(arch.Cpu))
+		}
+		return info, nil
+	}
+	if f, err := macho.NewFile(file); err == nil {
+		return Info{Format: MachO, Arch: []string{machoArch(f.Cpu)}}, nil
+	}
+	if f, err := pe.NewFile(file); err == nil {
+		return Info{Format: PE, Arch: []string{peArch(f.Machine)}}, nil
+	}
+
+	buf := make([]byte, 2)
+	if n, _ := file.ReadAt(buf, 0); n == 2 && string(buf) == "#!" {
+		return Info{Format: Script, Interpreter: scanner.Shebang(path)}, nil
+	}
+	return Info{Format: Unknown}, nil
+}
+
+func elfArch(f *elf.File) string {
+	switch f.Machine {
+	case elf.EM_X86_64:
+		return "amd64"
+	case elf.EM_386:
+		return "386"
+	case elf.EM_AARCH64:
+		return "arm64"
+	case elf.EM_ARM:
+		return "arm"
+	case elf.EM_RISCV:
+		return "riscv64"
+	case elf.EM_PPC64:
+		if f.ByteOrder == binary.LittleEndian {
+			return "ppc64le"
+		}
+		return "ppc64"
+	case elf.EM_S390:
+		return "s390x"
+	case elf.EM_LOONGARCH:
+		return "loong64"
+	}
+	return strings.ToLower(strings.TrimPrefix(f.Machine.String(), "EM_"))
+}
+
+func machoArch(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.CpuAmd64:
+		return "amd64"
+	case macho.Cpu386:
+		return "386"
+	case macho.CpuArm64:
+		return "arm64"
+	case macho.CpuArm:
+		return "arm"
+	case macho.CpuPpc64:
+		return "ppc64"
+	case macho.CpuPpc:
+		return "ppc"
+	}
+	return strings.ToLower(cpu.String())
+}
+
+func peArch(machine uint16) string {
+	switch machine {
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		return "amd64"
+	case pe.IMAGE_FILE_MACHINE_I386:
+		return "386"
+	case pe.IMAGE_FILE_MACHINE_ARM64:
+		return "arm64"
+	case pe.IMAGE_FILE_MACHINE_ARMNT, pe.IMAGE_FILE_MACHINE_ARM:
+		return "arm"
+	}
+	return "unknown"
+}
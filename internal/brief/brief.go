@@ -0,0 +1,73 @@
+package brief
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CharsPerToken approximates how many characters of English prose, tool
+// names, and paths make up one LLM token
+const CharsPerToken = 4
+
+// DefaultMaxTokens is the budget used when none is given
+const DefaultMaxTokens = 500
+
+// moreSuffixLen reserves room for the " (+N more)" marker on a cut section
+const moreSuffixLen = len(" (+999 more)")
+
+// Section is one line of a brief: a label and its items, most important first
+type Section struct {
+	Label string
+	Items []string
+}
+
+// EstimateTokens approximates the number of LLM tokens in text
+func EstimateTokens(text string) int {
+	return (len(text) + CharsPerToken - 1) / CharsPerToken
+}
+
+// Render writes sections in order as one line each, fitting as many items
+// as the token budget allows. Earlier sections and items take priority:
+// once the budget runs out, the remaining items of a section are replaced
+// by a count and later sections are dropped.
+func Render(sections []Section, maxTokens int) string {
+	if maxTokens <= 0 {
+		maxTokens = DefaultMaxTokens
+	}
+	budget := maxTokens * CharsPerToken
+
+	var sb strings.Builder
+	for _, section := range sections {
+		line := ""
+		if section.Label != "" {
+			line = section.Label + ": "
+		}
+
+		added := 0
+		for i, item := range section.Items {
+			candidate := line + item
+			if added > 0 {
+				candidate = line + "; " + item
+			}
+			reserve := 0
+			if i < len(section.Items)-1 {
+				reserve = moreSuffixLen
+			}
+			// +1 for the newline ending the line
+			if sb.Len()+len(candidate)+reserve+1 > budget {
+				break
+			}
+			line = candidate
+			added++
+		}
+		if added == 0 {
+			continue
+		}
+		if added < len(section.Items) {
+			line += fmt.Sprintf(" (+%d more)", len(section.Items)-added)
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,161 @@
+// Package budget shrinks a tool catalog to fit an LLM context window:
+// tools an agent cannot run are dropped, the rest are ordered by how useful
+// they are likely to be, their help is trimmed, and as many as fit the
+// token budget are kept.
+package budget
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/history"
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// Priorities for ordering tools
+const (
+	// PriorityUsage puts the tools run most often in the shell histories
+	// first
+	PriorityUsage = "usage"
+	// PriorityRecency puts the tools run most recently first
+	PriorityRecency = "recency"
+	// PriorityPackage puts tools installed with package managers first,
+	// the command named after each package before its other binaries
+	PriorityPackage = "package"
+)
+
+// Priorities are the valid priorities, in the order they are listed
+var Priorities = []string{PriorityUsage, PriorityRecency, PriorityPackage}
+
+// Limits on what a compacted tool keeps of its help
+const (
+	maxHelpText    = 800
+	maxFlags       = 15
+	maxSubcommands = 25
+	maxExamples    = 2
+)
+
+// CheckPriority returns an error naming the valid priorities if priority is
+// not one of them
+func CheckPriority(priority string) error {
+	for _, p := range Priorities {
+		if p == priority {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown priority %q (valid: %s)", priority, strings.Join(Priorities, ", "))
+}
+
+// Prioritize drops the tools an agent cannot use, broken ones and ones
+// shadowed by an earlier installation of the same name, and orders the
+// rest by priority, most valuable first. Ties go to tools from package
+// managers, then by name.
+func Prioritize(tools []models.Tool, priority string, usage history.Usage) []models.Tool {
+	var kept []models.Tool
+	for _, tool := range tools {
+		if !tool.Broken && !tool.Shadowed {
+			kept = append(kept, tool)
+		}
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		a, b := kept[i], kept[j]
+		ua, ub := usage[a.Name], usage[b.Name]
+		switch priority {
+		case PriorityRecency:
+			if !ua.Last.Equal(ub.Last) {
+				return ua.Last.After(ub.Last)
+			}
+			if ua.Count != ub.Count {
+				return ua.Count > ub.Count
+			}
+		case PriorityPackage:
+			if primary(a) != primary(b) {
+				return primary(a)
+			}
+			if ua.Count != ub.Count {
+				return ua.Count > ub.Count
+			}
+		default:
+			if ua.Count != ub.Count {
+				return ua.Count > ub.Count
+			}
+			if !ua.Last.Equal(ub.Last) {
+				return ua.Last.After(ub.Last)
+			}
+		}
+		if managed(a) != managed(b) {
+			return managed(a)
+		}
+		return a.Name < b.Name
+	})
+	return kept
+}
+
+// managed reports whether a package manager installed the tool
+func managed(tool models.Tool) bool {
+	return tool.PackageName != ""
+}
+
+// primary reports whether the tool is the command its package is named
+// after, which is usually why the package was installed
+func primary(tool models.Tool) bool {
+	return managed(tool) && tool.Name == tool.PackageName
+}
+
+// Compact trims the help of a tool: its help text to the first lines, and
+// its parsed help to the leading flags, subcommands, and examples, without
+// the command trees of subcommands
+func Compact(tool models.Tool) models.Tool {
+	if runes := []rune(tool.HelpText); len(runes) > maxHelpText {
+		text := string(runes[:maxHelpText])
+		if i := strings.LastIndexByte(text, '\n'); i > 0 {
+			text = text[:i]
+		}
+		tool.HelpText = text + "\n..."
+	}
+	if tool.Help == nil {
+		return tool
+	}
+
+	help := *tool.Help
+	help.Metadata = nil
+	if len(help.CommonFlags) > maxFlags {
+		help.CommonFlags = help.CommonFlags[:maxFlags]
+	}
+	if len(help.Examples) > maxExamples {
+		help.Examples = help.Examples[:maxExamples]
+	}
+	if len(help.Subcommands) > maxSubcommands {
+		help.Subcommands = help.Subcommands[:maxSubcommands]
+	}
+	subcommands := make([]models.Subcommand, len(help.Subcommands))
+	for i, sub := range help.Subcommands {
+		subcommands[i] = models.Subcommand{Name: sub.Name, Description: sub.Description}
+	}
+	help.Subcommands = subcommands
+	tool.Help = &help
+	return tool
+}
+
+// Fit returns the longest leading run of tools whose output, as measured
+// by tokens, fits in maxTokens, and its size. Since output grows with each
+// tool added, the run is found by bisection, measuring about log2(n)
+// candidate outputs.
+func Fit(tools []models.Tool, maxTokens int, tokens func([]models.Tool) int) ([]models.Tool, int) {
+	if size := tokens(tools); size <= maxTokens {
+		return tools, size
+	}
+	// tools[:lo] fits and tools[:hi] does not
+	lo, hi := 0, len(tools)
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		if tokens(tools[:mid]) <= maxTokens {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return tools[:lo], tokens(tools[:lo])
+}
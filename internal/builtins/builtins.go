@@ -0,0 +1,47 @@
+package builtins
+
+// shells lists, for each command that commonly exists both as a PATH
+// executable and as a shell builtin or keyword, the shells that handle it
+// themselves. When typed interactively the builtin wins, so the executable
+// only runs when invoked by path or through `command`/`env`.
+var shells = map[string][]string{
+	"[":        {"bash", "zsh", "sh", "fish"},
+	"alias":    {"bash", "zsh", "sh"},
+	"bg":       {"bash", "zsh", "sh", "fish"},
+	"cd":       {"bash", "zsh", "sh", "fish"},
+	"command":  {"bash", "zsh", "sh", "fish"},
+	"echo":     {"bash", "zsh", "sh", "fish"},
+	"false":    {"bash", "zsh", "sh", "fish"},
+	"fc":       {"bash", "zsh", "sh"},
+	"fg":       {"bash", "zsh", "sh", "fish"},
+	"getopts":  {"bash", "zsh", "sh"},
+	"hash":     {"bash", "zsh", "sh"},
+	"jobs":     {"bash", "zsh", "sh", "fish"},
+	"kill":     {"bash", "zsh", "sh"},
+	"printf":   {"bash", "zsh", "sh", "fish"},
+	"pwd":      {"bash", "zsh", "sh", "fish"},
+	"read":     {"bash", "zsh", "sh", "fish"},
+	"test":     {"bash", "zsh", "sh", "fish"},
+	"time":     {"bash", "zsh", "fish"},
+	"true":     {"bash", "zsh", "sh", "fish"},
+	"type":     {"bash", "zsh", "sh", "fish"},
+	"ulimit":   {"bash", "zsh", "sh", "fish"},
+	"umask":    {"bash", "zsh", "sh", "fish"},
+	"unalias":  {"bash", "zsh", "sh"},
+	"wait":     {"bash", "zsh", "sh", "fish"},
+	"where":    {"zsh"},
+	"whence":   {"zsh"},
+	"which":    {"zsh"},
+	"history":  {"bash", "zsh", "fish"},
+	"source":   {"bash", "zsh", "fish"},
+	"disown":   {"bash", "zsh", "fish"},
+	"suspend":  {"bash", "zsh", "fish"},
+	"builtin":  {"bash", "zsh", "fish"},
+	"realpath": {"fish"},
+}
+
+// Shells returns the shells in which name is a builtin or keyword, or nil
+// when it is not a common builtin
+func Shells(name string) []string {
+	return shells[name]
+}
@@ -0,0 +1,231 @@
+package categories
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/variants"
+)
+
+// Category names used to classify tools
+const (
+	VCS             = "vcs"
+	Container       = "container"
+	Cloud           = "cloud"
+	LanguageRuntime = "language-runtime"
+	PackageManager  = "package-manager"
+	Build           = "build"
+	Compiler        = "compiler"
+	Editor          = "editor"
+	Network         = "network"
+	Compression     = "compression"
+	Database        = "database"
+	Security        = "security"
+	Shell           = "shell"
+	Other           = "other"
+)
+
+// known maps tool names to their category
+var known = map[string]string{
+	// Version control
+	"git": VCS, "hg": VCS, "svn": VCS, "bzr": VCS, "fossil": VCS, "gh": VCS,
+	"glab": VCS, "tig": VCS, "lazygit": VCS, "git-lfs": VCS, "jj": VCS,
+
+	// Containers and orchestration
+	"docker": Container, "docker-compose": Container, "podman": Container,
+	"buildah": Container, "skopeo": Container, "nerdctl": Container,
+	"containerd": Container, "ctr": Container, "crictl": Container,
+	"kubectl": Container, "helm": Container, "kind": Container,
+	"minikube": Container, "k9s": Container, "kustomize": Container,
+	"k3d": Container, "kubectx": Container, "kubens": Container,
+	"stern": Container, "colima": Container, "lima": Container, "limactl": Container,
+
+	// Cloud CLIs and infrastructure as code
+	"aws": Cloud, "gcloud": Cloud, "gsutil": Cloud, "az": Cloud,
+	"doctl": Cloud, "flyctl": Cloud, "heroku": Cloud, "vercel": Cloud,
+	"netlify": Cloud, "terraform": Cloud, "tofu": Cloud, "pulumi": Cloud,
+	"eksctl": Cloud, "sam": Cloud, "cdk": Cloud, "wrangler": Cloud,
+	"oci": Cloud, "ibmcloud": Cloud, "linode-cli": Cloud, "packer": Cloud,
+	"vagrant": Cloud, "ansible": Cloud, "ansible-playbook": Cloud,
+
+	// Language runtimes and interpreters
+	"python": LanguageRuntime, "python2": LanguageRuntime, "python3": LanguageRuntime,
+	"node": LanguageRuntime, "deno": LanguageRuntime, "bun": LanguageRuntime,
+	"ruby": LanguageRuntime, "perl": LanguageRuntime, "php": LanguageRuntime,
+	"java": LanguageRuntime, "lua": LanguageRuntime, "luajit": LanguageRuntime,
+	"R": LanguageRuntime, "Rscript": LanguageRuntime, "julia": LanguageRuntime,
+	"elixir": LanguageRuntime, "erl": LanguageRuntime, "dotnet": LanguageRuntime,
+	"tclsh": LanguageRuntime, "guile": LanguageRuntime, "racket": LanguageRuntime,
+	"ghci": LanguageRuntime, "scala": LanguageRuntime, "kotlin": LanguageRuntime,
+
+	// Package and version managers
+	"brew": PackageManager, "port": PackageManager, "apt": PackageManager,
+	"apt-get": PackageManager, "dpkg": PackageManager, "yum": PackageManager,
+	"dnf": PackageManager, "rpm": PackageManager, "pacman": PackageManager,
+	"apk": PackageManager, "zypper": PackageManager, "nix": PackageManager,
+	"nix-env": PackageManager, "snap": PackageManager, "flatpak": PackageManager,
+	"npm": PackageManager, "npx": PackageManager, "yarn": PackageManager,
+	"pnpm": PackageManager, "pip": PackageManager, "pip3": PackageManager,
+	"pipx": PackageManager, "uv": PackageManager, "poetry": PackageManager,
+	"conda": PackageManager, "mamba": PackageManager, "gem": PackageManager,
+	"bundle": PackageManager, "bundler": PackageManager, "cargo": PackageManager,
+	"rustup": PackageManager, "composer": PackageManager, "scoop": PackageManager,
+	"choco": PackageManager, "winget": PackageManager, "pyenv": PackageManager,
+	"rbenv": PackageManager, "nvm": PackageManager, "asdf": PackageManager,
+	"mise": PackageManager, "volta": PackageManager, "fnm": PackageManager,
+	"sdk": PackageManager,
+
+	// Build systems
+	"make": Build, "gmake": Build, "cmake": Build, "ninja": Build,
+	"meson": Build, "bazel": Build, "bazelisk": Build, "buck": Build,
+	"buck2": Build, "gradle": Build, "mvn": Build, "ant": Build,
+	"sbt": Build, "scons": Build, "autoconf": Build, "automake": Build,
+	"libtool": Build, "pkg-config": Build, "pkgconf": Build, "just": Build,
+	"task": Build, "earthly": Build, "goreleaser": Build,
+
+	// Compilers and toolchains
+	"gcc": Compiler, "g++": Compiler, "cc": Compiler, "c++": Compiler,
+	"clang": Compiler, "clang++": Compiler, "rustc": Compiler, "go": Compiler,
+	"javac": Compiler, "swift": Compiler, "swiftc": Compiler, "tsc": Compiler,
+	"zig": Compiler, "ghc": Compiler, "nim": Compiler, "kotlinc": Compiler,
+	"gfortran": Compiler, "ld": Compiler, "as": Compiler,
+
+	// Editors
+	"vim": Editor, "nvim": Editor, "vi": Editor, "emacs": Editor,
+	"nano": Editor, "code": Editor, "subl": Editor, "hx": Editor,
+	"helix": Editor, "micro": Editor, "kak": Editor, "ed": Editor,
+	"zed": Editor, "cursor": Editor, "idea": Editor, "pico": Editor,
+
+	// Networking
+	"curl": Network, "wget": Network, "ssh": Network, "scp": Network,
+	"sftp": Network, "rsync": Network, "nc": Network, "ncat": Network,
+	"nmap": Network, "dig": Network, "nslookup": Network, "host": Network,
+	"ping": Network, "traceroute": Network, "mtr": Network, "telnet": Network,
+	"httpie": Network, "http": Network, "xh": Network, "socat": Network,
+	"tcpdump": Network, "iperf3": Network, "ip": Network, "ifconfig": Network,
+	"netstat": Network, "ss": Network, "aria2c": Network, "grpcurl": Network,
+
+	// Compression and archives
+	"gzip": Compression, "gunzip": Compression, "zip": Compression,
+	"unzip": Compression, "tar": Compression, "bzip2": Compression,
+	"xz": Compression, "zstd": Compression, "lz4": Compression,
+	"7z": Compression, "7za": Compression, "rar": Compression,
+	"unrar": Compression, "brotli": Compression, "pigz": Compression,
+	"zcat": Compression, "lzma": Compression,
+
+	// Databases and clients
+	"psql": Database, "pg_dump": Database, "mysql": Database,
+	"mysqldump": Database, "mariadb": Database, "sqlite3": Database,
+	"redis-cli": Database, "mongosh": Database, "mongo": Database,
+	"cqlsh": Database, "duckdb": Database, "clickhouse": Database,
+	"influx": Database, "etcdctl": Database,
+
+	// Security and secrets
+	"gpg": Security, "gpg2": Security, "openssl": Security,
+	"ssh-keygen": Security, "age": Security, "sops": Security,
+	"vault": Security, "pass": Security, "op": Security, "bw": Security,
+	"trivy": Security, "grype": Security, "syft": Security,
+	"cosign": Security, "step": Security, "mkcert": Security,
+
+	// Shells and shell utilities
+	"bash": Shell, "zsh": Shell, "fish": Shell, "sh": Shell, "dash": Shell,
+	"ksh": Shell, "tcsh": Shell, "nu": Shell, "pwsh": Shell,
+	"tmux": Shell, "screen": Shell, "fzf": Shell, "rg": Shell,
+	"grep": Shell, "sed": Shell, "awk": Shell, "gawk": Shell,
+	"jq": Shell, "yq": Shell, "fd": Shell, "bat": Shell, "eza": Shell,
+	"exa": Shell, "zoxide": Shell, "direnv": Shell, "starship": Shell,
+	"xargs": Shell, "find": Shell, "htop": Shell, "btop": Shell,
+}
+
+// prefixes classify families of tools that share a name prefix
+var prefixes = []struct {
+	prefix   string
+	category string
+}{
+	{"git-", VCS},
+	{"docker-", Container},
+	{"kube", Container},
+	{"aws", Cloud},
+	{"gcloud", Cloud},
+	{"terraform", Cloud},
+	{"python", LanguageRuntime},
+	{"ruby", LanguageRuntime},
+	{"node", LanguageRuntime},
+	{"pip", PackageManager},
+	{"gcc", Compiler},
+	{"clang", Compiler},
+	{"llvm-", Compiler},
+	{"pg_", Database},
+	{"mysql", Database},
+	{"redis-", Database},
+	{"ssh", Network},
+	{"gpg", Security},
+}
+
+// Count is the number of tools in one category
+type Count struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+	// Examples lists a few of the category's tools, alphabetically
+	Examples []string `json:"examples"`
+}
+
+// maxExamples caps how many tools are listed per category
+const maxExamples = 5
+
+// Classify returns the category for a tool name, or Other when unknown.
+// Exact names are checked first, then name prefixes, then the name with any
+// version suffix removed (python3.12 is a language runtime).
+func Classify(name string) string {
+	if category, ok := known[name]; ok {
+		return category
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(name, p.prefix) {
+			return p.category
+		}
+	}
+	if base, suffix := variants.BaseName(name); suffix != "" {
+		if category, ok := known[base]; ok {
+			return category
+		}
+	}
+	return Other
+}
+
+// Summarize counts tools per category, largest categories first. Tools
+// are counted once per name.
+func Summarize(tools []models.Tool) []Count {
+	byCategory := make(map[string][]string)
+	seen := make(map[string]bool)
+	for _, tool := range tools {
+		if seen[tool.Name] {
+			continue
+		}
+		seen[tool.Name] = true
+		category := Classify(tool.Name)
+		byCategory[category] = append(byCategory[category], tool.Name)
+	}
+
+	var counts []Count
+	for category, names := range byCategory {
+		sort.Strings(names)
+		examples := names
+		if len(examples) > maxExamples {
+			examples = examples[:maxExamples]
+		}
+		counts = append(counts, Count{Category: category, Count: len(names), Examples: examples})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		// Keep unclassified tools last regardless of size
+		if (counts[i].Category == Other) != (counts[j].Category == Other) {
+			return counts[j].Category == Other
+		}
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Category < counts[j].Category
+	})
+	return counts
+}
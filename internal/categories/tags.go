@@ -0,0 +1,116 @@
+package categories
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// All lists every category a tool can be tagged with, alphabetically
+var All = []string{
+	Build, Cloud, Compiler, Compression, Container, Database, Editor,
+	LanguageRuntime, Network, PackageManager, Security, Shell, VCS,
+}
+
+// keywords recognize a category from the words a tool describes itself
+// with, for tools the built-in list does not know
+var keywords = []struct {
+	category string
+	pattern  *regexp.Regexp
+}{
+	{VCS, regexp.MustCompile(`\b(version control|revision control|git repositor(y|ies)|pull requests?)\b`)},
+	{Container, regexp.MustCompile(`\b(containers?|kubernetes|docker|oci images?)\b`)},
+	{Cloud, regexp.MustCompile(`\b(cloud|aws|azure|gcp|serverless|infrastructure as code)\b`)},
+	{LanguageRuntime, regexp.MustCompile(`\b(interpreter|(javascript|language) runtime|repl)\b`)},
+	{PackageManager, regexp.MustCompile(`\b(package manager|version manager|install packages)\b`)},
+	{Build, regexp.MustCompile(`\b(build (system|tool|automation)|makefiles?|build targets?)\b`)},
+	{Compiler, regexp.MustCompile(`\b(compilers?|linker|assembler)\b`)},
+	{Editor, regexp.MustCompile(`\b(text editor|code editor|editor)\b`)},
+	{Network, regexp.MustCompile(`\b(https?|urls?|network|dns|tcp|udp|proxy|download|ssh|sockets?)\b`)},
+	{Compression, regexp.MustCompile(`\b(compress(ion|es|ed)?|decompress|archives?|zip)\b`)},
+	{Database, regexp.MustCompile(`\b(databases?|sql|postgres(ql)?|mysql|sqlite|redis)\b`)},
+	{Security, regexp.MustCompile(`\b(encrypt(ion|s)?|decrypt|certificates?|secrets?|vulnerabilit(y|ies)|tls)\b`)},
+}
+
+// CheckTags returns an error naming the valid tags if any tag is not one
+func CheckTags(tags []string) error {
+	for _, tag := range tags {
+		if !isCategory(tag) {
+			return fmt.Errorf("unknown tag %q (valid: %s)", tag, strings.Join(All, ", "))
+		}
+	}
+	return nil
+}
+
+// isCategory reports whether name is one of All
+func isCategory(name string) bool {
+	for _, category := range All {
+		if category == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Tags returns the categories of a tool: the one the built-in list gives
+// its name, then the one it gives its package (kubernetes-cli is a
+// container tool), then any its description or parsed help recognize.
+func Tags(tool models.Tool) []string {
+	var tags []string
+	add := func(category string) {
+		if category == Other {
+			return
+		}
+		for _, tag := range tags {
+			if tag == category {
+				return
+			}
+		}
+		tags = append(tags, category)
+	}
+
+	add(Classify(tool.Name))
+	if tool.PackageName != "" {
+		add(Classify(tool.PackageName))
+	}
+
+	description := tool.Description
+	if tool.Help != nil && tool.Help.Description != "" {
+		description += "\n" + tool.Help.Description
+	}
+	if description = strings.ToLower(description); description != "" {
+		for _, k := range keywords {
+			if k.pattern.MatchString(description) {
+				add(k.category)
+			}
+		}
+	}
+	return tags
+}
+
+// Tag sets the tags of each tool
+func Tag(tools []models.Tool) {
+	for i := range tools {
+		tools[i].Tags = Tags(tools[i])
+	}
+}
+
+// FilterTags returns the tools tagged with any of tags, in order
+func FilterTags(tools []models.Tool, tags []string) []models.Tool {
+	want := make(map[string]bool)
+	for _, tag := range tags {
+		want[tag] = true
+	}
+	var kept []models.Tool
+	for _, tool := range tools {
+		for _, tag := range tool.Tags {
+			if want[tag] {
+				kept = append(kept, tool)
+				break
+			}
+		}
+	}
+	return kept
+}
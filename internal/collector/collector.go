@@ -1,9 +1,13 @@
 package collector
 
 import (
+	"context"
+	"errors"
 	"os"
 	"os/exec"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cli-ai-org/cli/internal/models"
@@ -11,17 +15,118 @@ import (
 
 // Collector gathers detailed information about CLI tools
 type Collector struct {
-	timeoutSeconds int
+	timeout         time.Duration
+	versionTimeouts map[string]time.Duration
+	concurrency     int
+	noExec          bool
+	probeAllow      []string
+	probeDeny       []string
+	helpDepth       int
+	// manCache holds parsed man page descriptions, loaded on first use
+	manCache *manCache
+	// metaCache holds probed version and help text, loaded on first use;
+	// disabled means every tool is probed
+	metaCache         *metaCache
+	metaCacheDisabled bool
+	// metaMu guards metaCache, which CollectAll's workers share
+	metaMu sync.Mutex
+	// ctx kills running probes when cancelled
+	ctx context.Context
+}
+
+// DefaultTimeout is how long a tool may take to answer a version or help probe
+const DefaultTimeout = 3 * time.Second
+
+// probeWaitDelay is how long a probe's output is waited for after the tool
+// is killed, in case a child it started still holds the pipe open
+const probeWaitDelay = time.Second
+
+// maxHelpText is how much of a tool's help text is kept in its HelpText;
+// the structured Help is parsed from all of it
+const maxHelpText = 5000
+
+// DefaultConcurrency is how many tools CollectAll probes at once. Probes
+// mostly wait on process startup, so it is a multiple of the CPU count.
+var DefaultConcurrency = 2 * runtime.NumCPU()
+
+// Options configures a Collector
+type Options struct {
+	// Timeout bounds each version and help probe (default DefaultTimeout)
+	Timeout time.Duration
+	// VersionTimeouts overrides Timeout for the version probe of specific
+	// tools, keyed by tool name, for slow starters like java
+	VersionTimeouts map[string]time.Duration
+	// Concurrency is how many tools CollectAll probes at once (default
+	// DefaultConcurrency)
+	Concurrency int
+	// NoExec never runs tools: version and help text come only from the
+	// metadata cache, and callers fall back to package metadata
+	NoExec bool
+	// ProbeAllow, when set, limits probing to tools whose names match these
+	// shell globs; it also overrides ProbeDeny and the built-in deny list
+	ProbeAllow []string
+	// ProbeDeny lists shell globs of tool names never run, on top of the
+	// built-in deny list
+	ProbeDeny []string
+	// HelpDepth is how many levels of subcommands CollectToolInfo walks,
+	// running each one's help to record its usage, flags, and subcommands
+	// in the tool's Help; 0 walks none
+	HelpDepth int
+	// NoMetaCache disables the on-disk version and help text cache, so every
+	// tool is probed even if it has not changed since the last run
+	NoMetaCache bool
+	// Context cancels running probes and man page lookups; probes cut short
+	// by it are not cached (default context.Background())
+	Context context.Context
 }
 
 // New creates a new Collector instance
 func New() *Collector {
+	return NewWithOptions(Options{})
+}
+
+// NewWithOptions creates a Collector with the given options
+func NewWithOptions(opts Options) *Collector {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
 	return &Collector{
-		timeoutSeconds: 3,
+		timeout:           opts.Timeout,
+		versionTimeouts:   opts.VersionTimeouts,
+		concurrency:       opts.Concurrency,
+		noExec:            opts.NoExec,
+		probeAllow:        opts.ProbeAllow,
+		probeDeny:         opts.ProbeDeny,
+		helpDepth:         opts.HelpDepth,
+		metaCacheDisabled: opts.NoMetaCache,
+		ctx:               opts.Context,
+	}
+}
+
+// versionTimeout returns the version probe timeout for a tool, using its
+// override when one is configured
+func (c *Collector) versionTimeout(toolName string) time.Duration {
+	if timeout, ok := c.versionTimeouts[toolName]; ok && timeout > 0 {
+		return timeout
 	}
+	return c.timeout
 }
 
-// CollectToolInfo gathers detailed information about a specific tool
+// CollectToolInfo gathers detailed information about a specific tool.
+// Version and help text are cached by path, size, and mtime unless the
+// cache is disabled, so unchanged binaries are not run again; call
+// SaveMetaCache to persist newly probed tools. All of a tool's probes share
+// one deadline, its version timeout plus the help timeout, so a tool that
+// hangs on every flag costs no more than one that answers slowly; walking
+// its command tree (see Options.HelpDepth) has a deadline of its own. Tools
+// that may not be run (see Options.NoExec and Options.ProbeAllow) are not
+// probed.
 func (c *Collector) CollectToolInfo(toolName string, toolPath string) (*models.Tool, error) {
 	tool := &models.Tool{
 		Name: toolName,
@@ -44,22 +149,158 @@ func (c *Collector) CollectToolInfo(toolName string, toolPath string) (*models.T
 		}
 	}
 
+	// Follow symlinks so the cache tracks the binary that actually runs
+	target, err := os.Stat(toolPath)
+	if err == nil {
+		if entry, ok := c.cachedMeta(toolPath, target); ok {
+			tool.Version = entry.Version
+			tool.HelpText = entry.HelpText
+			tool.Help = entry.Help
+			if tool.Help == nil && tool.HelpText != "" {
+				// Cached before help was parsed
+				tool.Help = ParseHelp(tool.HelpText)
+			}
+			if entry.TreeDepth >= c.helpDepth || tool.Help == nil || !c.mayRun(toolName) {
+				tool.Help = pruneHelp(tool.Help, c.helpDepth)
+				return tool, nil
+			}
+			// The cached command tree is not as deep as asked for
+			tool.Help = pruneHelp(tool.Help, 0)
+			treeDepth := 0
+			if c.walkHelpTree(toolPath, tool.Help, c.helpDepth) {
+				treeDepth = c.helpDepth
+			}
+			if c.ctx.Err() == nil {
+				c.cacheMeta(toolPath, target, tool, treeDepth)
+			}
+			return tool, nil
+		}
+	}
+	if !c.mayRun(toolName) {
+		return tool, nil
+	}
+
+	versionTimeout := c.versionTimeout(toolName)
+	ctx, cancel := context.WithTimeout(c.ctx, versionTimeout+c.timeout)
+	defer cancel()
+
 	// Try to get version
-	tool.Version = c.getVersion(toolPath)
+	tool.Version = c.getVersion(ctx, toolPath, versionTimeout)
+
+	// Try to get help text, parsing all of it before it is truncated
+	helpText := c.getHelpText(ctx, toolPath)
+	if helpText != "" {
+		tool.Help = ParseHelp(helpText)
+	}
+	if len(helpText) > maxHelpText {
+		helpText = helpText[:maxHelpText] + "\n... (truncated)"
+	}
+	tool.HelpText = helpText
+
+	treeDepth := 0
+	if c.helpDepth > 0 && tool.Help != nil && c.ctx.Err() == nil {
+		if c.walkHelpTree(toolPath, tool.Help, c.helpDepth) {
+			treeDepth = c.helpDepth
+		}
+	}
 
-	// Try to get help text
-	tool.HelpText = c.getHelpText(toolPath)
+	if err == nil && c.ctx.Err() == nil {
+		c.cacheMeta(toolPath, target, tool, treeDepth)
+	}
 
 	return tool, nil
 }
 
-// getVersion attempts to extract version information from a tool
-func (c *Collector) getVersion(toolPath string) string {
+// CollectAll runs CollectToolInfo for each tool, several at once, and
+// returns the results in the tools' order. Tools not reached before the
+// collector's context is cancelled have a nil result. progress, if not nil,
+// is called after each tool with how many are done.
+func (c *Collector) CollectAll(tools []models.Tool, progress func(done, total int)) []*models.Tool {
+	results := make([]*models.Tool, len(tools))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var progressMu sync.Mutex
+	done := 0
+	for w := 0; w < c.concurrency && w < len(tools); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if c.ctx.Err() != nil {
+					continue
+				}
+				results[index], _ = c.CollectToolInfo(tools[index].Name, tools[index].Path)
+				if progress != nil {
+					progressMu.Lock()
+					done++
+					progress(done, len(tools))
+					progressMu.Unlock()
+				}
+			}
+		}()
+	}
+	for index := range tools {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// cachedMeta returns the cached version and help text of the binary at
+// path, if the cache is enabled and the binary is unchanged
+func (c *Collector) cachedMeta(path string, info os.FileInfo) (metaCacheEntry, bool) {
+	if c.metaCacheDisabled {
+		return metaCacheEntry{}, false
+	}
+	c.metaMu.Lock()
+	defer c.metaMu.Unlock()
+	if c.metaCache == nil {
+		c.metaCache = loadMetaCache(metaCachePath())
+	}
+	return c.metaCache.get(path, info)
+}
+
+// cacheMeta records a binary's probed version and help text, and its help
+// with the command tree walked treeDepth levels down
+func (c *Collector) cacheMeta(path string, info os.FileInfo, tool *models.Tool, treeDepth int) {
+	c.metaMu.Lock()
+	defer c.metaMu.Unlock()
+	if c.metaCache != nil {
+		c.metaCache.put(path, info, tool.Version, tool.HelpText, tool.Help, treeDepth)
+	}
+}
+
+// ProbeVersion returns a tool's version line, from the metadata cache when
+// the binary is unchanged since it was cached and otherwise by running it.
+// Unlike CollectToolInfo it does not probe help text, and its result is not
+// cached. All the version flags it tries share the tool's version timeout.
+func (c *Collector) ProbeVersion(toolName, toolPath string) string {
+	if target, err := os.Stat(toolPath); err == nil {
+		if entry, ok := c.cachedMeta(toolPath, target); ok {
+			return entry.Version
+		}
+	}
+	if !c.mayRun(toolName) {
+		return ""
+	}
+	timeout := c.versionTimeout(toolName)
+	ctx, cancel := context.WithTimeout(c.ctx, timeout)
+	defer cancel()
+	return c.getVersion(ctx, toolPath, timeout)
+}
+
+// getVersion attempts to extract version information from a tool. Each
+// probe is bounded by timeout, and all of them by ctx, so a tool that waits
+// for input cannot hang the scan.
+func (c *Collector) getVersion(ctx context.Context, toolPath string, timeout time.Duration) string {
 	versionFlags := []string{"--version", "-version", "version", "-v"}
 
 	for _, flag := range versionFlags {
-		cmd := exec.Command(toolPath, flag)
-		output, err := cmd.CombinedOutput()
+		if ctx.Err() != nil {
+			break
+		}
+		output, err := runProbe(ctx, toolPath, timeout, flag)
 		if err == nil && len(output) > 0 {
 			// Take first line of version output
 			lines := strings.Split(string(output), "\n")
@@ -72,26 +313,68 @@ func (c *Collector) getVersion(toolPath string) string {
 	return ""
 }
 
-// getHelpText attempts to extract help information from a tool
-func (c *Collector) getHelpText(toolPath string) string {
+// getHelpText attempts to extract help information from a tool, returning
+// all of its output
+func (c *Collector) getHelpText(ctx context.Context, toolPath string) string {
 	helpFlags := []string{"--help", "-help", "help", "-h"}
 
 	for _, flag := range helpFlags {
-		cmd := exec.Command(toolPath, flag)
-		output, err := cmd.CombinedOutput()
+		if ctx.Err() != nil {
+			break
+		}
+		output, err := runProbe(ctx, toolPath, c.timeout, flag)
 		if err == nil && len(output) > 0 {
-			// Limit help text size
-			helpText := string(output)
-			if len(helpText) > 5000 {
-				helpText = helpText[:5000] + "\n... (truncated)"
-			}
-			return helpText
+			return string(output)
 		}
 	}
 
 	return ""
 }
 
+// UsageLine returns the usage synopsis from help text: the rest of the
+// first line starting with "usage:", or the line after it when the synopsis
+// is on its own line as cobra prints it. It returns an empty string when the
+// help text has no usage line.
+func UsageLine(helpText string) string {
+	lines := strings.Split(helpText, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if len(trimmed) < len("usage:") || !strings.EqualFold(trimmed[:len("usage:")], "usage:") {
+			continue
+		}
+		if rest := strings.TrimSpace(trimmed[len("usage:"):]); rest != "" {
+			return rest
+		}
+		for _, next := range lines[i+1:] {
+			if next = strings.TrimSpace(next); next != "" {
+				return next
+			}
+		}
+	}
+	return ""
+}
+
+// runProbe runs a tool with the given arguments, killing it after timeout or
+// when ctx is cancelled. It runs in an empty temporary directory, which is
+// removed afterwards, with stdin from the null device so a tool that prompts
+// sees EOF, and with a scrubbed environment (see probeEnv).
+func runProbe(ctx context.Context, toolPath string, timeout time.Duration, args ...string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "cli-probe-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, toolPath, args...)
+	cmd.Dir = dir
+	cmd.Env = probeEnv(dir)
+	cmd.Stdin = nil
+	cmd.WaitDelay = probeWaitDelay
+	return cmd.CombinedOutput()
+}
+
 // BuildCatalog creates a comprehensive catalog of all tools
 func (c *Collector) BuildCatalog(tools []models.Tool, searchPaths []string) *models.ToolCatalog {
 	return &models.ToolCatalog{
@@ -130,17 +413,41 @@ func GetToolPath(toolName string) (string, error) {
 
 // ParseManPage attempts to extract information from a man page
 func (c *Collector) ParseManPage(toolName string) string {
-	cmd := exec.Command("man", toolName)
+	manText, _ := c.readManPage(toolName)
+	return manText
+}
+
+// errNoManPage is returned by readManPage when man has no page for a tool,
+// as opposed to failing to render one
+var errNoManPage = errors.New("no manual entry")
+
+// readManPage renders a tool's man page as plain text, killing man after
+// the probe timeout. It returns errNoManPage when the tool has no page.
+func (c *Collector) readManPage(toolName string) (string, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "man", toolName)
+	// Render as plain text without paging; some man implementations still
+	// emit backspace overstrike for bold and underline, which is stripped below
+	cmd.Env = append(os.Environ(), "MANPAGER=cat", "PAGER=cat", "MAN_KEEP_FORMATTING=")
+	cmd.WaitDelay = probeWaitDelay
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return ""
+		// man-db exits 16 when there is no page; mandoc and BSD man exit 1
+		// and say so. Without man there are no pages at all.
+		var exitErr *exec.ExitError
+		if errors.Is(err, exec.ErrNotFound) || ctx.Err() == nil && (errors.As(err, &exitErr) && exitErr.ExitCode() == 16 ||
+			strings.Contains(strings.ToLower(string(output)), "no manual entry")) {
+			return "", errNoManPage
+		}
+		return "", err
 	}
 
 	// Limit man page size
-	manText := string(output)
+	manText := StripOverstrike(string(output))
 	if len(manText) > 10000 {
 		manText = manText[:10000] + "\n... (truncated)"
 	}
 
-	return manText
+	return manText, nil
 }
@@ -0,0 +1,333 @@
+package collector
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// Kinds of help text section, by what their indented lines list
+const (
+	sectionText = iota
+	sectionFlags
+	sectionCommands
+	sectionExamples
+	sectionPositional
+)
+
+var (
+	// helpColumnGap separates a flag or command from its description: two
+	// or more spaces, or a tab
+	helpColumnGap = regexp.MustCompile(`\t|  +`)
+	// helpDefault finds a default value in a flag's description, as cobra
+	// ("(default "x")"), argparse ("(default: x)"), and clap ("[default: x]")
+	// print them
+	helpDefault = regexp.MustCompile(`[(\[]default:? "?([^"\])]*)"?[)\]]`)
+	// helpCommandName is what a subcommand's name may look like
+	helpCommandName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.:-]*$`)
+	// helpChoices is argparse's list of subcommands, "{install,download}"
+	helpChoices = regexp.MustCompile(`^\{[A-Za-z0-9_.:,-]+\}`)
+	// helpVersion spots a version number in a banner line ("ripgrep 14.1.0")
+	helpVersion = regexp.MustCompile(`\bv?\d+\.\d+`)
+)
+
+// ParseHelp extracts the usage line, description, flags, subcommands, and
+// examples from a tool's --help output. It understands the layouts cobra,
+// clap, argparse, and GNU getopt tools print: section headers ending in a
+// colon, with indented entries whose descriptions start after a gap of two
+// or more spaces or on the lines below. It returns nil when the help text
+// has no usage line, flags, or subcommands.
+func ParseHelp(helpText string) *models.ToolInfo {
+	helpText = strings.ReplaceAll(StripOverstrike(helpText), "\r\n", "\n")
+	lines := strings.Split(helpText, "\n")
+	info := &models.ToolInfo{
+		Usage:       UsageLine(helpText),
+		Description: helpDescription(lines),
+	}
+
+	seenFlags := make(map[string]bool)
+	seenCommands := make(map[string]bool)
+	kind := sectionText
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if indent == 0 && !strings.HasPrefix(trimmed, "-") {
+			if header, ok := strings.CutSuffix(trimmed, ":"); ok {
+				kind = helpSectionKind(header)
+			}
+			// Other unindented lines are prose or, as in git's help, the
+			// headings of groups of commands; the section carries on
+			continue
+		}
+
+		if isFlagLine(trimmed) {
+			flag, next := parseFlagEntry(lines, i, indent)
+			if flag.Description == "" && kind != sectionFlags {
+				// Outside an options section, a line starting with a
+				// flag and describing nothing is prose that wrapped there
+				continue
+			}
+			i = next
+			if flag.Name != "" && !seenFlags[flag.Name] {
+				seenFlags[flag.Name] = true
+				info.CommonFlags = append(info.CommonFlags, flag)
+			}
+			continue
+		}
+
+		switch kind {
+		case sectionPositional:
+			// argparse lists subcommands under positional arguments, after
+			// their choices in braces
+			if helpChoices.MatchString(trimmed) {
+				kind = sectionCommands
+			}
+		case sectionCommands:
+			if names, ok := commandList(trimmed); ok {
+				for _, name := range names {
+					if !seenCommands[name] {
+						seenCommands[name] = true
+						info.Subcommands = append(info.Subcommands, models.Subcommand{Name: name})
+					}
+				}
+				continue
+			}
+			command, next := parseCommandEntry(lines, i, indent)
+			i = next
+			if command.Name != "" && !seenCommands[command.Name] {
+				seenCommands[command.Name] = true
+				info.Subcommands = append(info.Subcommands, command)
+			}
+		case sectionExamples:
+			if !strings.HasPrefix(trimmed, "#") {
+				info.Examples = append(info.Examples, strings.TrimPrefix(trimmed, "$ "))
+			}
+		}
+	}
+
+	if info.Usage == "" && len(info.CommonFlags) == 0 && len(info.Subcommands) == 0 {
+		// A description alone is usually a version line or an error
+		return nil
+	}
+	return info
+}
+
+// helpSectionKind classifies a section by its header
+func helpSectionKind(header string) int {
+	header = strings.ToLower(header)
+	switch {
+	case strings.Contains(header, "example"):
+		return sectionExamples
+	case strings.Contains(header, "commands"):
+		// Plural, so prose such as "This command analyzes:" is not one
+		return sectionCommands
+	case strings.Contains(header, "positional"):
+		return sectionPositional
+	case strings.Contains(header, "option") || strings.Contains(header, "flag"):
+		return sectionFlags
+	}
+	return sectionText
+}
+
+// helpDescription returns the first paragraph of prose before the first
+// section: the line after a GNU usage line, an argparse description, or
+// the long description cobra prints first. Banners with a version number,
+// as clap prints, are skipped.
+func helpDescription(lines []string) string {
+	var paragraph []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			if len(paragraph) > 0 && paragraph[0] != "" {
+				return joinDescription(paragraph)
+			}
+			paragraph = nil
+			continue
+		case strings.HasSuffix(trimmed, ":") || strings.HasPrefix(trimmed, "-"):
+			// A section starts; there is no description before it
+			return joinDescription(paragraph)
+		case len(line) != len(strings.TrimLeft(line, " \t")):
+			// Indented lines continue a usage synopsis
+			continue
+		}
+		if _, ok := cutUsage(trimmed); ok {
+			paragraph = nil
+			continue
+		}
+		if len(paragraph) == 0 && helpVersion.MatchString(trimmed) && len(strings.Fields(trimmed)) <= 3 {
+			// A banner such as "ripgrep 14.1.0"; skip its whole paragraph,
+			// marked by an empty first line
+			paragraph = append(paragraph, "")
+			continue
+		}
+		paragraph = append(paragraph, trimmed)
+	}
+	return joinDescription(paragraph)
+}
+
+func joinDescription(paragraph []string) string {
+	if len(paragraph) > 0 && paragraph[0] == "" {
+		return ""
+	}
+	description := strings.Join(paragraph, " ")
+	if len(description) > 300 {
+		description = description[:297] + "..."
+	}
+	return description
+}
+
+// cutUsage returns the rest of a line starting with "usage:"
+func cutUsage(line string) (string, bool) {
+	if len(line) < len("usage:") || !strings.EqualFold(line[:len("usage:")], "usage:") {
+		return "", false
+	}
+	return strings.TrimSpace(line[len("usage:"):]), true
+}
+
+// isFlagLine reports whether a trimmed line starts a flag entry, such as
+// "-a, --all" or "--color[=WHEN]"
+func isFlagLine(trimmed string) bool {
+	name := strings.TrimLeft(trimmed, "-")
+	dashes := len(trimmed) - len(name)
+	if dashes == 0 || dashes > 2 || name == "" {
+		return false
+	}
+	c := name[0]
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '?' || c == '#'
+}
+
+// parseFlagEntry parses the flag at lines[i], indented by indent, with its
+// description: after a column gap on the same line, continued on the more
+// indented lines below it; or wholly on the lines below, as clap's long
+// help prints it, where only its first paragraph is kept. It returns the
+// index of the entry's last line.
+func parseFlagEntry(lines []string, i, indent int) (models.Flag, int) {
+	trimmed := strings.TrimSpace(lines[i])
+	spec, description := trimmed, ""
+	if loc := helpColumnGap.FindStringIndex(trimmed); loc != nil {
+		spec, description = trimmed[:loc[0]], strings.TrimSpace(trimmed[loc[1]:])
+	}
+	flag := parseFlagSpec(spec)
+
+	description, last := continueDescription(lines, i, indent, description)
+	if match := helpDefault.FindStringSubmatch(description); match != nil {
+		flag.Default = strings.TrimSpace(match[1])
+	}
+	flag.Description = description
+	return flag, last
+}
+
+// parseCommandEntry parses the subcommand at lines[i] as parseFlagEntry
+// parses a flag
+func parseCommandEntry(lines []string, i, indent int) (models.Subcommand, int) {
+	trimmed := strings.TrimSpace(lines[i])
+	name, description := trimmed, ""
+	if loc := helpColumnGap.FindStringIndex(trimmed); loc != nil {
+		name, description = trimmed[:loc[0]], strings.TrimSpace(trimmed[loc[1]:])
+	}
+	// clap may list aliases after the name: "build, b"
+	name, _, _ = strings.Cut(name, ",")
+	if !helpCommandName.MatchString(name) {
+		return models.Subcommand{}, i
+	}
+	description, last := continueDescription(lines, i, indent, description)
+	return models.Subcommand{Name: name, Description: description}, last
+}
+
+// continueDescription appends to an entry's description the lines below
+// lines[i] that are indented further than indent and do not start another
+// flag. An entry with no description of its own may skip blank lines to
+// reach its first paragraph.
+func continueDescription(lines []string, i, indent int, description string) (string, int) {
+	last := i
+	for j := i + 1; j < len(lines); j++ {
+		line := strings.TrimRight(lines[j], " \t")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if description == "" {
+				continue
+			}
+			break
+		}
+		if len(line)-len(strings.TrimLeft(line, " \t")) <= indent || isFlagLine(trimmed) {
+			break
+		}
+		if description == "" {
+			description = trimmed
+		} else {
+			description += " " + trimmed
+		}
+		last = j
+	}
+	return description, last
+}
+
+// parseFlagSpec parses the flag part of an entry: its forms separated by
+// commas or spaces, each with an optional argument, as in "-o, --output
+// FILE", "--block-size=SIZE", "-e, --regexp <PATTERN>", "--color[=WHEN]",
+// or "-r REQUIREMENT, --requirement REQUIREMENT"
+func parseFlagSpec(spec string) models.Flag {
+	var flag models.Flag
+	var short, long string
+	for _, part := range strings.Split(spec, ",") {
+		fields := strings.Fields(part)
+		for n, field := range fields {
+			if !isFlagLine(field) {
+				if flag.Argument == "" && n > 0 {
+					flag.Argument = strings.Join(fields[n:], " ")
+				}
+				break
+			}
+			name := field
+			if cut := strings.IndexAny(field, "=[<"); cut > 0 {
+				name = field[:cut]
+				if flag.Argument == "" {
+					flag.Argument = strings.Trim(field[cut:], "=[]")
+				}
+			}
+			name = strings.TrimSuffix(name, "...")
+			if len(name) == 2 && name[0] == '-' {
+				if short == "" {
+					short = name
+				}
+			} else if long == "" {
+				long = name
+			}
+		}
+	}
+	flag.Argument = strings.TrimSuffix(strings.TrimPrefix(flag.Argument, "="), "...")
+
+	if long == "" {
+		flag.Name = short
+	} else {
+		flag.Name, flag.Short = long, short
+	}
+	return flag
+}
+
+// commandList splits the comma-separated list of commands npm prints under
+// "All commands:", reporting false for any other line
+func commandList(trimmed string) ([]string, bool) {
+	if !strings.Contains(trimmed, ",") || helpColumnGap.MatchString(trimmed) {
+		return nil, false
+	}
+	var names []string
+	for _, part := range strings.Split(trimmed, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if !helpCommandName.MatchString(part) {
+			return nil, false
+		}
+		names = append(names, part)
+	}
+	return names, len(names) > 0
+}
@@ -0,0 +1,144 @@
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// helpTreeTimeout bounds walking one tool's command tree; the tree is kept
+// as far as the walk got
+const helpTreeTimeout = 30 * time.Second
+
+// maxHelpTreeProbes caps how many times one tool is run to walk its
+// command tree
+const maxHelpTreeProbes = 500
+
+// helpWalk is the state of walking one tool's command tree
+type helpWalk struct {
+	collector *Collector
+	toolPath  string
+	rootUsage string
+	maxDepth  int
+	probes    int
+	complete  bool
+}
+
+// walkHelpTree fills in the usage, flags, and subcommands of each of
+// help's subcommands from its own help, down to depth levels. A tool that
+// lists a "help" command is run as "tool help <sub>", falling back to
+// "tool <sub> --help", which is how other tools are run. It reports whether
+// the walk finished before helpTreeTimeout and maxHelpTreeProbes.
+func (c *Collector) walkHelpTree(toolPath string, help *models.ToolInfo, depth int) bool {
+	ctx, cancel := context.WithTimeout(c.ctx, helpTreeTimeout)
+	defer cancel()
+
+	w := &helpWalk{
+		collector: c,
+		toolPath:  toolPath,
+		rootUsage: help.Usage,
+		maxDepth:  depth,
+		complete:  true,
+	}
+	help.Subcommands = w.walk(ctx, nil, help.Usage, help.Subcommands, hasSubcommand(help.Subcommands, "help"))
+	return w.complete
+}
+
+// walk returns a copy of subs, the subcommands of the command parents
+// name, with the help of each filled in
+func (w *helpWalk) walk(ctx context.Context, parents []string, parentUsage string, subs []models.Subcommand, helpCommand bool) []models.Subcommand {
+	walked := pruneHelpTree(subs, 0)
+	if len(parents) >= w.maxDepth {
+		return walked
+	}
+
+	for i := range walked {
+		sub := &walked[i]
+		if sub.Name == "help" {
+			continue
+		}
+		if ctx.Err() != nil || w.probes >= maxHelpTreeProbes {
+			w.complete = false
+			break
+		}
+
+		path := append(append([]string{}, parents...), sub.Name)
+		info := w.probe(ctx, path, helpCommand)
+		// A tool that ignores the subcommand prints its own help again
+		if info == nil || info.Usage != "" && (info.Usage == parentUsage || info.Usage == w.rootUsage) {
+			continue
+		}
+		sub.Usage = info.Usage
+		sub.Flags = info.CommonFlags
+		if sub.Description == "" {
+			sub.Description = info.Description
+		}
+		sub.Subcommands = w.walk(ctx, path, info.Usage, info.Subcommands, helpCommand || hasSubcommand(info.Subcommands, "help"))
+	}
+	return walked
+}
+
+// probe runs the help of the subcommand path names and parses it. Tools
+// often exit non-zero when printing help for a subcommand, so the output
+// is parsed either way.
+func (w *helpWalk) probe(ctx context.Context, path []string, helpCommand bool) *models.ToolInfo {
+	forms := [][]string{append(append([]string{}, path...), "--help")}
+	if helpCommand {
+		forms = append([][]string{append([]string{"help"}, path...)}, forms...)
+	}
+
+	for _, args := range forms {
+		if ctx.Err() != nil || w.probes >= maxHelpTreeProbes {
+			return nil
+		}
+		w.probes++
+		output, _ := runProbe(ctx, w.toolPath, w.collector.timeout, args...)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if info := ParseHelp(string(output)); info != nil {
+			return info
+		}
+	}
+	return nil
+}
+
+// pruneHelpTree returns a copy of subs that keeps the walked help of
+// subcommands depth levels down, and only the names and descriptions of
+// the ones below that
+func pruneHelpTree(subs []models.Subcommand, depth int) []models.Subcommand {
+	if subs == nil {
+		return nil
+	}
+	pruned := make([]models.Subcommand, len(subs))
+	for i, sub := range subs {
+		pruned[i] = models.Subcommand{Name: sub.Name, Description: sub.Description}
+		if depth > 0 {
+			pruned[i].Usage = sub.Usage
+			pruned[i].Flags = sub.Flags
+			pruned[i].Subcommands = pruneHelpTree(sub.Subcommands, depth-1)
+		}
+	}
+	return pruned
+}
+
+// pruneHelp returns a copy of help with its command tree pruned to depth
+// levels
+func pruneHelp(help *models.ToolInfo, depth int) *models.ToolInfo {
+	if help == nil {
+		return nil
+	}
+	pruned := *help
+	pruned.Subcommands = pruneHelpTree(help.Subcommands, depth)
+	return &pruned
+}
+
+func hasSubcommand(subs []models.Subcommand, name string) bool {
+	for _, sub := range subs {
+		if sub.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,199 @@
+package collector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/atomicfile"
+)
+
+// manAttempts is how many times man is run for a tool before giving up
+// when it fails for a reason other than the tool having no page
+const manAttempts = 2
+
+// manRetryDelay is how long ManDescription waits before running man again
+var manRetryDelay = 200 * time.Millisecond
+
+// manCacheEntry is a cached man page NAME description. An empty description
+// is cached too, so tools without a man page are not looked up every run.
+type manCacheEntry struct {
+	ModTime     int64  `json:"mtime"`
+	Description string `json:"description"`
+}
+
+// manCache stores parsed man page descriptions keyed by tool name. Entries
+// are tied to the tool binary's modification time, so reinstalling or
+// upgrading a tool invalidates its entry.
+type manCache struct {
+	path    string
+	entries map[string]manCacheEntry
+	dirty   bool
+}
+
+// manCachePath returns the cache file location under the user cache directory
+func manCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cli", "man-descriptions.json")
+}
+
+// loadManCache reads the cache file. A missing or unreadable cache starts empty.
+func loadManCache(path string) *manCache {
+	cache := &manCache{path: path, entries: make(map[string]manCacheEntry)}
+	if path == "" {
+		return cache
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &cache.entries)
+	}
+	return cache
+}
+
+func (m *manCache) get(name string, modTime int64) (string, bool) {
+	entry, ok := m.entries[name]
+	if !ok || entry.ModTime != modTime {
+		return "", false
+	}
+	return entry.Description, true
+}
+
+func (m *manCache) put(name string, modTime int64, description string) {
+	m.entries[name] = manCacheEntry{ModTime: modTime, Description: description}
+	m.dirty = true
+}
+
+// save writes the cache if any description was added since it was loaded
+func (m *manCache) save() error {
+	if !m.dirty || m.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(m.entries)
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.Write(m.path, data, 0644); err != nil {
+		return err
+	}
+	m.dirty = false
+	return nil
+}
+
+// ManDescription returns the one-line description from a tool's man page
+// NAME section ("git - the stupid content tracker" yields "the stupid
+// content tracker"). Results are cached by tool name and binary mtime, so
+// repeated runs only fork man for new or changed tools; call SaveManCache
+// to persist new entries. A man that fails, rather than reporting that
+// there is no page, is run once more, and nothing is cached if it fails
+// again.
+func (c *Collector) ManDescription(toolName, toolPath string) string {
+	if c.manCache == nil {
+		c.manCache = loadManCache(manCachePath())
+	}
+
+	var modTime int64
+	if info, err := os.Stat(toolPath); err == nil {
+		modTime = info.ModTime().Unix()
+	}
+	if description, ok := c.manCache.get(toolName, modTime); ok {
+		return description
+	}
+
+	manText, err := c.readManPage(toolName)
+	for attempt := 1; err != nil && err != errNoManPage && attempt < manAttempts; attempt++ {
+		select {
+		case <-c.ctx.Done():
+			return ""
+		case <-time.After(manRetryDelay):
+		}
+		manText, err = c.readManPage(toolName)
+	}
+	if err != nil && err != errNoManPage {
+		// man failed for another reason, such as timing out on a cold
+		// index; leave the tool uncached so the next run tries again
+		return ""
+	}
+
+	description := parseManName(manText)
+	c.manCache.put(toolName, modTime, description)
+	return description
+}
+
+// CachedManDescription returns a tool's man page description if it was
+// looked up before and the binary has not changed since, without running man
+func (c *Collector) CachedManDescription(toolName, toolPath string) (string, bool) {
+	if c.manCache == nil {
+		c.manCache = loadManCache(manCachePath())
+	}
+
+	var modTime int64
+	if info, err := os.Stat(toolPath); err == nil {
+		modTime = info.ModTime().Unix()
+	}
+	return c.manCache.get(toolName, modTime)
+}
+
+// SaveManCache persists man page descriptions looked up since the cache was loaded
+func (c *Collector) SaveManCache() error {
+	if c.manCache == nil {
+		return nil
+	}
+	return c.manCache.save()
+}
+
+// StripOverstrike removes backspace overstrike formatting from man output.
+// Bold is rendered as "X\bX" and underline as "_\bX"; in both cases the
+// character before each backspace is dropped.
+func StripOverstrike(text string) string {
+	if !strings.ContainsRune(text, '\b') {
+		return text
+	}
+
+	out := make([]rune, 0, len(text))
+	for _, r := range text {
+		if r == '\b' {
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// parseManName extracts the description from the NAME section of rendered
+// man page text. The section may wrap over several indented lines.
+func parseManName(manText string) string {
+	var parts []string
+	inName := false
+	for _, line := range strings.Split(manText, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !inName {
+			if trimmed == "NAME" {
+				inName = true
+			}
+			continue
+		}
+		// The section ends at a blank line or the next unindented heading
+		if trimmed == "" || !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") {
+			if len(parts) > 0 {
+				break
+			}
+			continue
+		}
+		parts = append(parts, trimmed)
+	}
+
+	name := strings.Join(parts, " ")
+	for _, sep := range []string{" - ", " — ", " – ", " \\- "} {
+		if i := strings.Index(name, sep); i >= 0 {
+			return strings.TrimSpace(name[i+len(sep):])
+		}
+	}
+	return name
+}
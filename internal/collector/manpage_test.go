@@ -0,0 +1,104 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// rawManOutput is the start of git(1) as man renders it for a terminal,
+// with headings in overstrike bold and the command name underlined
+const rawManOutput = "GIT(1)                    Git Manual                    GIT(1)\n" +
+	"\n" +
+	"N\bNA\bAM\bME\bE\n" +
+	"       _\bg_\bi_\bt - the stupid content tracker\n" +
+	"\n" +
+	"S\bSY\bYN\bNO\bOP\bPS\bSI\bIS\bS\n" +
+	"       g\bgi\bit\bt [--version] [--help]\n"
+
+func TestStripOverstrike(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"N\bNA\bAM\bME\bE", "NAME"},
+		{"_\bx", "x"},
+		{"_\bf_\bi_\bl_\be", "file"},
+		{"plain text", "plain text"},
+		{"é\bé", "é"},
+	}
+	for _, tt := range tests {
+		if got := StripOverstrike(tt.in); got != tt.want {
+			t.Errorf("StripOverstrike(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseManNameOverstrike(t *testing.T) {
+	got := parseManName(StripOverstrike(rawManOutput))
+	if want := "the stupid content tracker"; got != want {
+		t.Errorf("parseManName = %q, want %q", got, want)
+	}
+}
+
+func TestParseManNameWrapped(t *testing.T) {
+	text := "NAME\n       ls - list\n       directory contents\n\nSYNOPSIS\n       ls [OPTION]...\n"
+	if got, want := parseManName(text), "list directory contents"; got != want {
+		t.Errorf("parseManName = %q, want %q", got, want)
+	}
+}
+
+// fakeMan puts a man on PATH that runs script, and points the cache
+// directory at a temporary one
+func fakeMan(t *testing.T, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake man is a shell script")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "man"), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	t.Setenv("COUNT_FILE", filepath.Join(dir, "count"))
+
+	delay := manRetryDelay
+	manRetryDelay = time.Millisecond
+	t.Cleanup(func() { manRetryDelay = delay })
+}
+
+func TestManDescriptionRetries(t *testing.T) {
+	// Fails the first time, then prints the page
+	fakeMan(t, `if [ ! -e "$COUNT_FILE" ]; then : > "$COUNT_FILE"; exit 3; fi
+printf 'NAME\n       git - the stupid content tracker\n'
+`)
+	c := New()
+	if got, want := c.ManDescription("git", ""), "the stupid content tracker"; got != want {
+		t.Errorf("ManDescription = %q, want %q", got, want)
+	}
+}
+
+func TestManDescriptionFailureNotCached(t *testing.T) {
+	fakeMan(t, "exit 3\n")
+	c := New()
+	if got := c.ManDescription("git", ""); got != "" {
+		t.Errorf("ManDescription = %q, want empty", got)
+	}
+	if _, ok := c.CachedManDescription("git", ""); ok {
+		t.Error("a failed man lookup was cached")
+	}
+}
+
+func TestManDescriptionMissingPageCached(t *testing.T) {
+	fakeMan(t, "echo \"No manual entry for $1\" >&2\nexit 16\n")
+	c := New()
+	if got := c.ManDescription("nosuchtool", ""); got != "" {
+		t.Errorf("ManDescription = %q, want empty", got)
+	}
+	if _, ok := c.CachedManDescription("nosuchtool", ""); !ok {
+		t.Error("a tool without a man page was not cached")
+	}
+}
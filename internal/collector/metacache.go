@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/cli-ai-org/cli/internal/atomicfile"
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// metaCacheEntry is the version and help text probed from one binary, and
+// the help parsed from it, with the size and modification time the binary
+// had at the time
+type metaCacheEntry struct {
+	Size     int64            `json:"size"`
+	ModTime  int64            `json:"mtime"`
+	Version  string           `json:"version,omitempty"`
+	HelpText string           `json:"help_text,omitempty"`
+	Help     *models.ToolInfo `json:"help,omitempty"`
+	// TreeDepth is how many levels of Help's command tree were walked
+	// completely
+	TreeDepth int `json:"tree_depth,omitempty"`
+}
+
+// metaCache stores probed tool metadata keyed by binary path. An entry is
+// only used while the binary's size and mtime are unchanged, so upgrading a
+// tool in place re-probes it.
+type metaCache struct {
+	path    string
+	entries map[string]metaCacheEntry
+	dirty   bool
+}
+
+// metaCachePath returns the cache file location under the user cache directory
+func metaCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cli", "tool-metadata.json")
+}
+
+// loadMetaCache reads the cache file. A missing or unreadable cache starts empty.
+func loadMetaCache(path string) *metaCache {
+	cache := &metaCache{path: path, entries: make(map[string]metaCacheEntry)}
+	if path == "" {
+		return cache
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &cache.entries)
+	}
+	return cache
+}
+
+func (m *metaCache) get(path string, info os.FileInfo) (metaCacheEntry, bool) {
+	entry, ok := m.entries[path]
+	if !ok || entry.Size != info.Size() || entry.ModTime != info.ModTime().UnixNano() {
+		return metaCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (m *metaCache) put(path string, info os.FileInfo, version, helpText string, help *models.ToolInfo, treeDepth int) {
+	m.entries[path] = metaCacheEntry{
+		Size:      info.Size(),
+		ModTime:   info.ModTime().UnixNano(),
+		Version:   version,
+		HelpText:  helpText,
+		Help:      help,
+		TreeDepth: treeDepth,
+	}
+	m.dirty = true
+}
+
+// save writes the cache if a tool was probed since it was loaded
+func (m *metaCache) save() error {
+	if !m.dirty || m.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(m.entries)
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.Write(m.path, data, 0644); err != nil {
+		return err
+	}
+	m.dirty = false
+	return nil
+}
+
+// SaveMetaCache persists version and help text probed since the cache was
+// loaded. It does nothing when the cache is disabled.
+func (c *Collector) SaveMetaCache() error {
+	c.metaMu.Lock()
+	defer c.metaMu.Unlock()
+	if c.metaCache == nil {
+		return nil
+	}
+	return c.metaCache.save()
+}
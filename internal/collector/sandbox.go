@@ -0,0 +1,116 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// defaultDeny are tools never run for a version or help probe unless the
+// allow list names them: ones that change system state when run, or that
+// take a bare "version" or "help" probe as an operand to act on
+var defaultDeny = []string{
+	// Power and init control
+	"reboot", "shutdown", "halt", "poweroff", "init", "telinit", "runlevel",
+	// Process control
+	"kill", "killall", "pkill", "xkill", "skill",
+	// Disks and filesystems
+	"mkfs", "mkfs.*", "mke2fs", "mkswap", "swapon", "swapoff", "fdisk", "sfdisk",
+	"cfdisk", "gdisk", "sgdisk", "parted", "wipefs", "dd", "shred", "badblocks",
+	"mount", "umount", "losetup", "cryptsetup", "diskutil", "format",
+	// Privilege escalation and sessions, which prompt
+	"sudo", "su", "doas", "pkexec", "login", "passwd", "chsh", "chfn",
+	// Endless output
+	"yes",
+}
+
+// probeEnvKeep are the environment variables probes inherit; everything
+// else, including credentials and tokens, is dropped
+var probeEnvKeep = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "LANG", "LC_ALL", "LC_CTYPE",
+	// Windows needs these to start most programs
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT", "USERPROFILE",
+	"APPDATA", "LOCALAPPDATA", "PROGRAMDATA", "PROGRAMFILES", "PROGRAMFILES(X86)",
+	"NUMBER_OF_PROCESSORS", "PROCESSOR_ARCHITECTURE",
+}
+
+// probeEnvSet are added to every probe's environment: no color, no pager,
+// no update checks or telemetry, and proxies that point nowhere so tools
+// that reach for the network fail fast instead of phoning home
+var probeEnvSet = []string{
+	"TERM=dumb",
+	"NO_COLOR=1",
+	"PAGER=cat",
+	"GIT_PAGER=cat",
+	"CI=1",
+	"DO_NOT_TRACK=1",
+	"NO_UPDATE_NOTIFIER=1",
+	"HOMEBREW_NO_AUTO_UPDATE=1",
+	"HOMEBREW_NO_ANALYTICS=1",
+	"DOTNET_CLI_TELEMETRY_OPTOUT=1",
+	"HTTP_PROXY=http://127.0.0.1:9",
+	"HTTPS_PROXY=http://127.0.0.1:9",
+	"ALL_PROXY=http://127.0.0.1:9",
+	"http_proxy=http://127.0.0.1:9",
+	"https_proxy=http://127.0.0.1:9",
+	"all_proxy=http://127.0.0.1:9",
+	"NO_PROXY=",
+	"no_proxy=",
+}
+
+var (
+	baseProbeEnv     []string
+	baseProbeEnvOnce sync.Once
+)
+
+// probeEnv returns the scrubbed environment a probe runs with, with its
+// temporary directory pointing at dir
+func probeEnv(dir string) []string {
+	baseProbeEnvOnce.Do(func() {
+		keep := make(map[string]bool)
+		for _, name := range probeEnvKeep {
+			keep[name] = true
+		}
+		for _, entry := range os.Environ() {
+			name, _, _ := strings.Cut(entry, "=")
+			if runtime.GOOS == "windows" {
+				name = strings.ToUpper(name)
+			}
+			if keep[name] {
+				baseProbeEnv = append(baseProbeEnv, entry)
+			}
+		}
+		baseProbeEnv = append(baseProbeEnv, probeEnvSet...)
+	})
+
+	env := append([]string{}, baseProbeEnv...)
+	return append(env, "TMPDIR="+dir, "TMP="+dir, "TEMP="+dir)
+}
+
+// mayRun reports whether a tool may be run for a probe: never with
+// --no-exec, always when the allow list names it, otherwise only when it is
+// not denied and there is no allow list
+func (c *Collector) mayRun(toolName string) bool {
+	if c.noExec {
+		return false
+	}
+	name := strings.TrimSuffix(strings.ToLower(toolName), ".exe")
+	if matchesAny(c.probeAllow, name) {
+		return true
+	}
+	if len(c.probeAllow) > 0 {
+		return false
+	}
+	return !matchesAny(c.probeDeny, name) && !matchesAny(defaultDeny, name)
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
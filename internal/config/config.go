@@ -0,0 +1,262 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/listfilter"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFileName is the config file looked up in the user's home directory
+const DefaultFileName = ".cli.yaml"
+
+// EnvPrefix starts the environment variables that override config file
+// settings, e.g. CLI_AI_OUTPUT_FORMAT for output_format
+const EnvPrefix = "CLI_AI_"
+
+// EnvConfig names the config file when --config is not given
+const EnvConfig = EnvPrefix + "CONFIG"
+
+// EnvEmbeddingsAPIKey is the API key sent to the embeddings endpoint. It is
+// only read from the environment, to keep it out of the config file.
+const EnvEmbeddingsAPIKey = EnvPrefix + "EMBEDDINGS_API_KEY"
+
+// Config holds user settings read from the config file
+type Config struct {
+	// AlwaysShow lists tool names that bypass every list filter
+	AlwaysShow []string `yaml:"always_show"`
+	// VersionTimeouts overrides the version probe timeout for specific
+	// tools (java: 10s), leaving the global timeout for everything else
+	VersionTimeouts map[string]time.Duration `yaml:"version_timeouts"`
+	// PackageCacheTTL is how long detected packages are reused while the
+	// package managers' directories are unchanged (default 1h)
+	PackageCacheTTL time.Duration `yaml:"package_cache_ttl"`
+	// SearchPaths are the directories scanned for tools, in order, instead
+	// of PATH
+	SearchPaths []string `yaml:"search_paths"`
+	// PackageManagers limits package detection to these managers (default
+	// all of them)
+	PackageManagers []string `yaml:"package_managers"`
+	// Exclude lists shell globs of tool names that are never reported
+	Exclude []string `yaml:"exclude"`
+	// ProbeTimeout bounds each version and help probe of a tool (default 3s)
+	ProbeTimeout time.Duration `yaml:"probe_timeout"`
+	// ProbeConcurrency is how many tools are probed at once (default twice
+	// the CPU count)
+	ProbeConcurrency int `yaml:"probe_concurrency"`
+	// NoExec never runs tools to probe them; versions come from package
+	// metadata only
+	NoExec bool `yaml:"no_exec"`
+	// ProbeAllow, when set, limits probing to tools matching these shell
+	// globs, overriding ProbeDeny and the built-in deny list
+	ProbeAllow []string `yaml:"probe_allow"`
+	// ProbeDeny lists shell globs of tools never run to probe them
+	ProbeDeny []string `yaml:"probe_deny"`
+	// OutputFormat is the format of commands that choose between a table
+	// and JSON by whether stdout is a terminal: table or json
+	OutputFormat string `yaml:"output_format"`
+	// ListFilter decides which packages' tools `cli list` hides
+	ListFilter listfilter.Config `yaml:"list_filter"`
+	// Embeddings is the embeddings endpoint `cli find` ranks tools with;
+	// without one it ranks them by keywords
+	Embeddings EmbeddingsConfig `yaml:"embeddings"`
+	// SafetyFile holds the user's overrides of the risks export
+	// --with-safety annotates tools with (default ~/.cli-safety.yaml)
+	SafetyFile string `yaml:"safety_file"`
+	// PolicyFile is the policy `cli policy check` enforces (default
+	// ~/.cli-policy.yaml)
+	PolicyFile string `yaml:"policy_file"`
+}
+
+// EmbeddingsConfig names an OpenAI-compatible embeddings endpoint
+type EmbeddingsConfig struct {
+	// URL is the endpoint, such as https://api.openai.com/v1/embeddings or
+	// http://localhost:11434/v1/embeddings for Ollama
+	URL   string `yaml:"url"`
+	Model string `yaml:"model"`
+}
+
+// DefaultPath returns the default config file location ($HOME/.cli.yaml)
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, DefaultFileName)
+}
+
+// Load reads the config file at path, or $CLI_AI_CONFIG, or the default
+// location when both are empty, then applies CLI_AI_* environment overrides.
+// A missing default file yields an empty config; a missing file that was
+// asked for explicitly is an error.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv(EnvConfig)
+	}
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+
+	cfg := &Config{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := yaml.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+			}
+		case !explicit && errors.Is(err, os.ErrNotExist):
+		default:
+			return nil, fmt.Errorf("reading config file: %w", err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides settings with the CLI_AI_* environment variables that
+// are set. Lists are comma-separated, except CLI_AI_SEARCH_PATHS, which is
+// separated like PATH.
+func (c *Config) applyEnv() error {
+	lists := map[string]*[]string{
+		"ALWAYS_SHOW":      &c.AlwaysShow,
+		"PACKAGE_MANAGERS": &c.PackageManagers,
+		"EXCLUDE":          &c.Exclude,
+		"PROBE_ALLOW":      &c.ProbeAllow,
+		"PROBE_DENY":       &c.ProbeDeny,
+	}
+	for name, field := range lists {
+		if value, ok := os.LookupEnv(EnvPrefix + name); ok {
+			*field = splitList(value, ",")
+		}
+	}
+	if value, ok := os.LookupEnv(EnvPrefix + "SEARCH_PATHS"); ok {
+		c.SearchPaths = splitList(value, string(os.PathListSeparator))
+	}
+
+	durations := map[string]*time.Duration{
+		"PACKAGE_CACHE_TTL": &c.PackageCacheTTL,
+		"PROBE_TIMEOUT":     &c.ProbeTimeout,
+	}
+	for name, field := range durations {
+		value, ok := os.LookupEnv(EnvPrefix + name)
+		if !ok || value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s%s: %w", EnvPrefix, name, err)
+		}
+		*field = d
+	}
+
+	if value, ok := os.LookupEnv(EnvPrefix + "PROBE_CONCURRENCY"); ok && value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%sPROBE_CONCURRENCY: %w", EnvPrefix, err)
+		}
+		c.ProbeConcurrency = n
+	}
+
+	if value, ok := os.LookupEnv(EnvPrefix + "NO_EXEC"); ok && value != "" {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%sNO_EXEC: %w", EnvPrefix, err)
+		}
+		c.NoExec = b
+	}
+
+	if value, ok := os.LookupEnv(EnvPrefix + "OUTPUT_FORMAT"); ok {
+		c.OutputFormat = value
+	}
+	if value, ok := os.LookupEnv(EnvPrefix + "EMBEDDINGS_URL"); ok {
+		c.Embeddings.URL = value
+	}
+	if value, ok := os.LookupEnv(EnvPrefix + "EMBEDDINGS_MODEL"); ok {
+		c.Embeddings.Model = value
+	}
+	if value, ok := os.LookupEnv(EnvPrefix + "SAFETY_FILE"); ok {
+		c.SafetyFile = value
+	}
+	if value, ok := os.LookupEnv(EnvPrefix + "POLICY_FILE"); ok {
+		c.PolicyFile = value
+	}
+	return nil
+}
+
+// validate checks the settings whose values are not checked by their type
+func (c *Config) validate() error {
+	switch c.OutputFormat {
+	case "", "table", "json":
+	default:
+		return fmt.Errorf("output_format: unknown format %q (valid: table, json)", c.OutputFormat)
+	}
+	if url := c.Embeddings.URL; url != "" {
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return fmt.Errorf("embeddings.url: must be an http or https URL, got %q", url)
+		}
+		if c.Embeddings.Model == "" {
+			return fmt.Errorf("embeddings.model: required with embeddings.url")
+		}
+	}
+	if c.ProbeConcurrency < 0 {
+		return fmt.Errorf("probe_concurrency: must not be negative, got %d", c.ProbeConcurrency)
+	}
+	globs := map[string][]string{
+		"exclude":     c.Exclude,
+		"probe_allow": c.ProbeAllow,
+		"probe_deny":  c.ProbeDeny,
+	}
+	for setting, patterns := range globs {
+		for _, pattern := range patterns {
+			if _, err := filepath.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s: invalid pattern %q: %w", setting, pattern, err)
+			}
+		}
+	}
+	if _, err := listfilter.New(c.ListFilter); err != nil {
+		return fmt.Errorf("list_filter: %w", err)
+	}
+	for i, dir := range c.SearchPaths {
+		c.SearchPaths[i] = expandHome(dir)
+	}
+	c.SafetyFile = expandHome(c.SafetyFile)
+	c.PolicyFile = expandHome(c.PolicyFile)
+	return nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// splitList splits value on sep, dropping empty items and surrounding space
+func splitList(value, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
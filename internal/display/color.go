@@ -0,0 +1,123 @@
+package display
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Color modes of the --color flag
+const (
+	ColorAuto   = "auto"
+	ColorAlways = "always"
+	ColorNever  = "never"
+)
+
+// colorEnabled is whether human-readable output is colored; see SetColor
+var colorEnabled bool
+
+// ANSI SGR codes
+const (
+	sgrBold    = "1"
+	sgrRed     = "31"
+	sgrGreen   = "32"
+	sgrYellow  = "33"
+	sgrBlue    = "34"
+	sgrMagenta = "35"
+	sgrCyan    = "36"
+)
+
+// managerColors are the colors manager names are given, one per name
+var managerColors = []string{sgrBlue, sgrMagenta, sgrCyan, sgrGreen, sgrYellow}
+
+// ansiPattern matches the escape sequences Paint writes
+var ansiPattern = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// SetColor decides whether human-readable output is colored: always, never,
+// or auto, which colors it when stdout is a terminal that understands color
+// and the NO_COLOR environment variable is not set
+func SetColor(mode string) error {
+	switch mode {
+	case ColorAlways:
+		colorEnabled = true
+	case ColorNever:
+		colorEnabled = false
+	case ColorAuto, "":
+		_, noColor := os.LookupEnv("NO_COLOR")
+		colorEnabled = !noColor && os.Getenv("TERM") != "dumb" &&
+			IsTerminal(os.Stdout) && enableColor(os.Stdout)
+	default:
+		return fmt.Errorf("unknown color mode %q (valid: %s, %s, %s)", mode, ColorAuto, ColorAlways, ColorNever)
+	}
+	return nil
+}
+
+// ColorEnabled reports whether human-readable output is colored
+func ColorEnabled() bool {
+	return colorEnabled
+}
+
+// paint wraps text in an SGR escape sequence when color is enabled
+func paint(code, text string) string {
+	if !colorEnabled || text == "" {
+		return text
+	}
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+// Severity colors text by a severity or check status: critical, high, and
+// fail red, medium and warn yellow, low cyan, and pass green
+func Severity(severity, text string) string {
+	switch strings.ToLower(severity) {
+	case "critical":
+		return paint(sgrBold+";"+sgrRed, text)
+	case "high", "fail":
+		return paint(sgrRed, text)
+	case "medium", "warn":
+		return paint(sgrYellow, text)
+	case "low":
+		return paint(sgrCyan, text)
+	case "pass":
+		return paint(sgrGreen, text)
+	}
+	return text
+}
+
+// Status colors text by the state of an installation: active green,
+// shadowed yellow, and broken red
+func Status(status, text string) string {
+	switch strings.ToLower(status) {
+	case "active":
+		return paint(sgrGreen, text)
+	case "shadowed":
+		return paint(sgrYellow, text)
+	case "broken":
+		return paint(sgrRed, text)
+	}
+	return text
+}
+
+// Manager colors a package manager's name, each manager always the same
+// color; owners that are not managers (system, user, unknown, unmanaged)
+// are left plain
+func Manager(name string) string {
+	switch name {
+	case "", "system", "user", "unknown", "unmanaged":
+		return name
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return paint(managerColors[h.Sum32()%uint32(len(managerColors))], name)
+}
+
+// visibleWidth is the number of characters text takes on screen, leaving
+// out escape sequences
+func visibleWidth(text string) int {
+	if strings.Contains(text, "\x1b") {
+		text = ansiPattern.ReplaceAllString(text, "")
+	}
+	return utf8.RuneCountInString(text)
+}
@@ -1,17 +1,29 @@
 package display
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/BurntSushi/toml"
 	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/policy"
+	"github.com/cli-ai-org/cli/internal/sbom"
+	"github.com/cli-ai-org/cli/internal/toolschema"
+	"gopkg.in/yaml.v3"
 )
 
 // Display handles the output formatting for CLI tools
 type Display struct {
 	writer io.Writer
+	// fields, when set, projects tools in JSON catalog output to these
+	// fields only
+	fields []string
 }
 
 // New creates a new Display instance
@@ -19,6 +31,45 @@ func New(w io.Writer) *Display {
 	return &Display{writer: w}
 }
 
+// SetFields limits the tool objects written by ShowCatalogJSON and
+// ShowCatalogNDJSON to the given JSON fields, in that order. Fields must be
+// valid (see models.ValidateToolFields).
+func (d *Display) SetFields(fields []string) {
+	d.fields = fields
+}
+
+// projectedCatalog is a catalog whose tools have been projected to a subset
+// of their fields; the outer Tools field replaces the embedded one
+type projectedCatalog struct {
+	*models.ToolCatalog
+	Tools []json.RawMessage `json:"tools"`
+}
+
+// catalogValue returns what to encode for a catalog, honoring SetFields
+func (d *Display) catalogValue(catalog *models.ToolCatalog) (interface{}, error) {
+	if len(d.fields) == 0 {
+		return catalog, nil
+	}
+	projected := projectedCatalog{ToolCatalog: catalog, Tools: []json.RawMessage{}}
+	for _, tool := range catalog.Tools {
+		raw, err := tool.Project(d.fields)
+		if err != nil {
+			return nil, err
+		}
+		projected.Tools = append(projected.Tools, raw)
+	}
+	return projected, nil
+}
+
+// IsTerminal reports whether f is an interactive terminal rather than a pipe or file
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
 // ShowTools displays a list of tools
 func (d *Display) ShowTools(tools []string) {
 	if len(tools) == 0 {
@@ -98,14 +149,289 @@ func (d *Display) ShowToolsJSON(tools []models.Tool, pretty bool) error {
 
 // ShowCatalogJSON outputs a complete tool catalog in JSON format
 func (d *Display) ShowCatalogJSON(catalog *models.ToolCatalog, pretty bool) error {
+	value, err := d.catalogValue(catalog)
+	if err != nil {
+		return err
+	}
+
 	if pretty {
 		encoder := json.NewEncoder(d.writer)
 		encoder.SetIndent("", "  ")
-		return encoder.Encode(catalog)
+		return encoder.Encode(value)
+	}
+
+	encoder := json.NewEncoder(d.writer)
+	return encoder.Encode(value)
+}
+
+// ShowCatalogTOML outputs a complete tool catalog as TOML. Tools and
+// packages become arrays of tables ([[tools]], [[packages]]).
+func (d *Display) ShowCatalogTOML(catalog *models.ToolCatalog) error {
+	return toml.NewEncoder(d.writer).Encode(catalog)
+}
+
+// ShowCatalogCycloneDX outputs the catalog as a CycloneDX JSON bill of
+// materials for the machine named host
+func (d *Display) ShowCatalogCycloneDX(catalog *models.ToolCatalog, host string, creator sbom.Creator) error {
+	encoder := json.NewEncoder(d.writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sbom.CycloneDX(catalog, host, creator))
+}
+
+// ShowCatalogSPDX outputs the catalog as an SPDX JSON document for the
+// machine named host
+func (d *Display) ShowCatalogSPDX(catalog *models.ToolCatalog, host string, creator sbom.Creator) error {
+	encoder := json.NewEncoder(d.writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sbom.SPDX(catalog, host, creator))
+}
+
+// ShowCatalogOpenAITools outputs the catalog's tools as OpenAI function
+// tool definitions, described by their man page summaries from descriptions
+func (d *Display) ShowCatalogOpenAITools(catalog *models.ToolCatalog, descriptions map[string]string) error {
+	encoder := json.NewEncoder(d.writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(toolschema.OpenAI(toolschema.Functions(catalog.Tools, descriptions)))
+}
+
+// ShowCatalogAnthropicTools outputs the catalog's tools as Anthropic tool
+// definitions, described by their man page summaries from descriptions
+func (d *Display) ShowCatalogAnthropicTools(catalog *models.ToolCatalog, descriptions map[string]string) error {
+	encoder := json.NewEncoder(d.writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(toolschema.Anthropic(toolschema.Functions(catalog.Tools, descriptions)))
+}
+
+// ShowCatalogYAML outputs a complete tool catalog as a YAML document, with
+// the same field names as the JSON catalog
+func (d *Display) ShowCatalogYAML(catalog *models.ToolCatalog) error {
+	return d.writeYAML(catalog)
+}
+
+// ShowPolicyJSON outputs an agent policy as indented JSON
+func (d *Display) ShowPolicyJSON(p *policy.Policy) error {
+	encoder := json.NewEncoder(d.writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+// ShowPolicyYAML outputs an agent policy as a YAML document
+func (d *Display) ShowPolicyYAML(p *policy.Policy) error {
+	return d.writeYAML(p)
+}
+
+// ShowToolsYAML outputs tools as a YAML sequence
+func (d *Display) ShowToolsYAML(tools []models.Tool) error {
+	if tools == nil {
+		tools = []models.Tool{}
 	}
+	return d.writeYAML(tools)
+}
 
+// ShowToolsTOML outputs tools as TOML. TOML documents are tables, so the
+// tools become an array of tables ([[tools]]).
+func (d *Display) ShowToolsTOML(tools []models.Tool) error {
+	return toml.NewEncoder(d.writer).Encode(struct {
+		Tools []models.Tool `toml:"tools"`
+	}{tools})
+}
+
+// ShowPackagesJSON outputs packages as an indented JSON array
+func (d *Display) ShowPackagesJSON(pkgs []models.PackageInfo) error {
 	encoder := json.NewEncoder(d.writer)
-	return encoder.Encode(catalog)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(pkgs)
+}
+
+// ShowPackagesYAML outputs packages as a YAML sequence
+func (d *Display) ShowPackagesYAML(pkgs []models.PackageInfo) error {
+	if pkgs == nil {
+		pkgs = []models.PackageInfo{}
+	}
+	return d.writeYAML(pkgs)
+}
+
+// ShowPackagesTOML outputs packages as an array of tables ([[packages]])
+func (d *Display) ShowPackagesTOML(pkgs []models.PackageInfo) error {
+	return toml.NewEncoder(d.writer).Encode(struct {
+		Packages []models.PackageInfo `toml:"packages"`
+	}{pkgs})
+}
+
+// ShowToolsDelimited outputs tools as CSV (sep ',') or TSV (sep '\t'): a
+// header row of columns, then one row per tool holding its JSON fields in
+// the same order
+func (d *Display) ShowToolsDelimited(tools []models.Tool, columns, fields []string, sep rune) error {
+	rows := make([][]string, 0, len(tools))
+	for _, tool := range tools {
+		row, err := delimitedRow(tool, fields)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+	return d.writeDelimited(columns, rows, sep)
+}
+
+// ShowPackagesDelimited outputs packages as ShowToolsDelimited outputs tools
+func (d *Display) ShowPackagesDelimited(pkgs []models.PackageInfo, columns, fields []string, sep rune) error {
+	rows := make([][]string, 0, len(pkgs))
+	for _, pkg := range pkgs {
+		row, err := delimitedRow(pkg, fields)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+	return d.writeDelimited(columns, rows, sep)
+}
+
+// delimitedRow renders the given JSON fields of v as cells. Lists are
+// joined with commas and missing fields are empty.
+func delimitedRow(v interface{}, fields []string) ([]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	row := make([]string, len(fields))
+	for i, field := range fields {
+		switch value := all[field].(type) {
+		case nil:
+		case string:
+			row[i] = value
+		case bool:
+			row[i] = strconv.FormatBool(value)
+		case float64:
+			row[i] = strconv.FormatFloat(value, 'f', -1, 64)
+		case []interface{}:
+			var items []string
+			for _, item := range value {
+				items = append(items, fmt.Sprint(item))
+			}
+			row[i] = strings.Join(items, ",")
+		default:
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			row[i] = string(encoded)
+		}
+	}
+	return row, nil
+}
+
+// writeDelimited writes a header and rows as CSV, quoted as RFC 4180
+// requires, or as TSV, where tabs and newlines in cells become spaces so
+// every row stays one line for awk and cut
+func (d *Display) writeDelimited(header []string, rows [][]string, sep rune) error {
+	if sep != '\t' {
+		writer := csv.NewWriter(d.writer)
+		writer.Comma = sep
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		return writer.WriteAll(rows)
+	}
+
+	clean := strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+	for _, row := range append([][]string{header}, rows...) {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = clean.Replace(cell)
+		}
+		if _, err := fmt.Fprintln(d.writer, strings.Join(cells, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Display) writeYAML(value interface{}) error {
+	encoder := yaml.NewEncoder(d.writer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// ShowCatalogNDJSON outputs the catalog's tools as newline-delimited JSON,
+// one tool per line, so catalogs can be concatenated and streamed
+func (d *Display) ShowCatalogNDJSON(catalog *models.ToolCatalog) error {
+	encoder := json.NewEncoder(d.writer)
+	for _, tool := range catalog.Tools {
+		var value interface{} = tool
+		if len(d.fields) > 0 {
+			raw, err := tool.Project(d.fields)
+			if err != nil {
+				return err
+			}
+			value = raw
+		}
+		if err := encoder.Encode(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ShowCatalogEnv outputs the catalog as shell variable assignments
+// (TOOL_GIT=/usr/bin/git) that can be eval'd by scripts
+func (d *Display) ShowCatalogEnv(catalog *models.ToolCatalog) {
+	// Sort by name so collision suffixes are stable between runs
+	sorted := make([]models.Tool, len(catalog.Tools))
+	copy(sorted, catalog.Tools)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	used := make(map[string]bool)
+	for _, tool := range sorted {
+		base := envVarName(tool.Name)
+		name := base
+		// Different tools can sanitize to the same name (foo-bar, foo.bar)
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[name] = true
+
+		fmt.Fprintf(d.writer, "%s=%s\n", name, ShellQuote(tool.Path))
+	}
+}
+
+// envVarName converts a tool name into a valid shell variable name
+func envVarName(toolName string) string {
+	var sb strings.Builder
+	sb.WriteString("TOOL_")
+	for _, r := range strings.ToUpper(toolName) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
+
+// ShellQuote quotes a value for safe use in a shell assignment
+func ShellQuote(value string) string {
+	safe := true
+	for _, r := range value {
+		if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') &&
+			!strings.ContainsRune("/._-+:,@%=", r) {
+			safe = false
+			break
+		}
+	}
+	if safe && value != "" {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
 }
 
 // ShowToolInfo displays detailed information about a single tool
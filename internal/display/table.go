@@ -0,0 +1,157 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// columnGap separates the columns of a table
+const columnGap = "  "
+
+// minColumnWidth is the narrowest a column is truncated to when a table is
+// fitted to the terminal
+const minColumnWidth = 8
+
+// Table writes rows as aligned columns, each as wide as its widest cell.
+// Written to a terminal, it is fitted to the terminal's width by truncating
+// its widest columns.
+type Table struct {
+	w      io.Writer
+	header []string
+	rows   [][]string
+	right  map[int]bool
+
+	// Indent is written before every line
+	Indent string
+	// Rule underlines each heading with dashes
+	Rule bool
+	// Width is the most characters a line may take; 0 is unlimited
+	Width int
+}
+
+// NewTable creates a table with the given headings, sized to the terminal
+// when w is one
+func NewTable(w io.Writer, header ...string) *Table {
+	t := &Table{w: w, header: header, right: make(map[int]bool)}
+	if f, ok := w.(*os.File); ok {
+		t.Width = TerminalWidth(f)
+	}
+	return t
+}
+
+// AlignRight right-aligns the columns at the given indexes, for numbers
+func (t *Table) AlignRight(columns ...int) {
+	for _, column := range columns {
+		t.right[column] = true
+	}
+}
+
+// Row adds a row; cells may be colored
+func (t *Table) Row(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Flush writes the table
+func (t *Table) Flush() {
+	widths := make([]int, len(t.header))
+	for i, heading := range t.header {
+		widths[i] = visibleWidth(heading)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i < len(widths) && visibleWidth(cell) > widths[i] {
+				widths[i] = visibleWidth(cell)
+			}
+		}
+	}
+	t.fit(widths)
+
+	lines := [][]string{t.header}
+	if t.Rule {
+		rule := make([]string, len(t.header))
+		for i, heading := range t.header {
+			rule[i] = strings.Repeat("-", visibleWidth(heading))
+		}
+		lines = append(lines, rule)
+	}
+	for _, line := range append(lines, t.rows...) {
+		fmt.Fprintln(t.w, t.Indent+t.format(line, widths))
+	}
+}
+
+// fit narrows the widest left-aligned column, a character at a time, until
+// a line fits in Width or every such column is at its narrowest
+func (t *Table) fit(widths []int) {
+	if t.Width <= 0 {
+		return
+	}
+	total := len(t.Indent) + len(columnGap)*(len(widths)-1)
+	for _, width := range widths {
+		total += width
+	}
+	for total > t.Width {
+		widest := -1
+		for i, width := range widths {
+			if !t.right[i] && width > minColumnWidth && (widest < 0 || width > widths[widest]) {
+				widest = i
+			}
+		}
+		if widest < 0 {
+			return
+		}
+		widths[widest]--
+		total--
+	}
+}
+
+// format lays out one line, padding every column but the last
+func (t *Table) format(cells []string, widths []int) string {
+	var sb strings.Builder
+	for i, width := range widths {
+		cell := ""
+		if i < len(cells) {
+			cell = truncate(cells[i], width)
+		}
+		pad := strings.Repeat(" ", width-visibleWidth(cell))
+		if i > 0 {
+			sb.WriteString(columnGap)
+		}
+		switch {
+		case t.right[i]:
+			sb.WriteString(pad + cell)
+		case i == len(widths)-1:
+			sb.WriteString(cell)
+		default:
+			sb.WriteString(cell + pad)
+		}
+	}
+	return strings.TrimRight(sb.String(), " ")
+}
+
+// truncate shortens text to width characters, ending it with "…"; colored
+// text loses its color when shortened
+func truncate(text string, width int) string {
+	if visibleWidth(text) <= width {
+		return text
+	}
+	runes := []rune(ansiPattern.ReplaceAllString(text, ""))
+	if width < 1 {
+		return ""
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// TerminalWidth returns the width of the terminal f writes to, or 0 when it
+// is not a terminal. $COLUMNS overrides it.
+func TerminalWidth(f *os.File) int {
+	if !IsTerminal(f) {
+		return 0
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return terminalWidth(f)
+}
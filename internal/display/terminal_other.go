@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package display
+
+import "os"
+
+// terminalWidth is unknown on platforms without a supported terminal query
+func terminalWidth(f *os.File) int {
+	return 0
+}
+
+// enableColor assumes no color on platforms without a supported terminal
+func enableColor(f *os.File) bool {
+	return false
+}
@@ -0,0 +1,30 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package display
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// winsize is the terminal size TIOCGWINSZ reports
+type winsize struct {
+	rows, cols, xpixel, ypixel uint16
+}
+
+// terminalWidth asks the terminal f writes to for its width, or returns 0
+func terminalWidth(f *os.File) int {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 {
+		return 0
+	}
+	return int(ws.cols)
+}
+
+// enableColor reports whether the terminal f writes to can show color;
+// Unix terminals understand ANSI escape sequences
+func enableColor(f *os.File) bool {
+	return true
+}
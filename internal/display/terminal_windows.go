@@ -0,0 +1,52 @@
+//go:build windows
+
+package display
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+	procGetConsoleMode             = kernel32.NewProc("GetConsoleMode")
+	procSetConsoleMode             = kernel32.NewProc("SetConsoleMode")
+)
+
+const enableVirtualTerminalProcessing = 0x4
+
+// consoleScreenBufferInfo is CONSOLE_SCREEN_BUFFER_INFO
+type consoleScreenBufferInfo struct {
+	size              [2]int16
+	cursorPosition    [2]int16
+	attributes        uint16
+	window            [4]int16
+	maximumWindowSize [2]int16
+}
+
+// terminalWidth asks the console f writes to for its width, or returns 0
+func terminalWidth(f *os.File) int {
+	var info consoleScreenBufferInfo
+	r, _, _ := procGetConsoleScreenBufferInfo.Call(f.Fd(), uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		return 0
+	}
+	// The window's left and right columns are inclusive
+	return int(info.window[2]-info.window[0]) + 1
+}
+
+// enableColor turns on ANSI escape sequences in the console f writes to,
+// reporting whether it supports them (Windows 10 and later)
+func enableColor(f *os.File) bool {
+	var mode uint32
+	if r, _, _ := procGetConsoleMode.Call(f.Fd(), uintptr(unsafe.Pointer(&mode))); r == 0 {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	r, _, _ := procSetConsoleMode.Call(f.Fd(), uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
@@ -0,0 +1,424 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/pathenv"
+	"github.com/cli-ai-org/cli/internal/scanner"
+	"github.com/cli-ai-org/cli/internal/shims"
+)
+
+// Status is the outcome of a check
+type Status string
+
+const (
+	Pass Status = "pass"
+	Warn Status = "warn"
+	Fail Status = "fail"
+)
+
+// Check is the result of one environment check
+type Check struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	// Fix is a command or instruction that resolves the problem
+	Fix string `json:"fix,omitempty"`
+}
+
+// maxListed caps how many unreachable tools are named in a message
+const maxListed = 5
+
+// PathSize checks the PATH length against the platform limit
+func PathSize(usage pathenv.Usage) Check {
+	check := Check{
+		Name:    "PATH size",
+		Status:  Pass,
+		Message: fmt.Sprintf("%d characters in %d entries", usage.Length, usage.Entries),
+	}
+	if usage.Warning != "" {
+		check.Status = Warn
+		if usage.Length*100 >= usage.Limit*75 {
+			check.Status = Fail
+		}
+		check.Message = usage.Warning
+		check.Fix = "run `cli trim-path` to drop duplicate, missing, and fully shadowed entries"
+	}
+	return check
+}
+
+// ManagerBinDirs checks that every package manager with tools installed has
+// its bin directory on PATH. A manager whose bin directory is missing from
+// PATH installs tools that cannot be run by name, the classic "installed it
+// with brew but command not found".
+func ManagerBinDirs(binDirs map[packages.PackageManager][]string, paths []string) []Check {
+	managers := make([]string, 0, len(binDirs))
+	for manager := range binDirs {
+		managers = append(managers, string(manager))
+	}
+	sort.Strings(managers)
+
+	var checks []Check
+	for _, name := range managers {
+		dirs := binDirs[packages.PackageManager(name)]
+
+		onPath := false
+		for _, dir := range dirs {
+			if pathenv.Contains(paths, dir) {
+				onPath = true
+				break
+			}
+		}
+		if onPath {
+			checks = append(checks, Check{
+				Name:    name + " bin directory",
+				Status:  Pass,
+				Message: "on PATH",
+			})
+			continue
+		}
+
+		for _, dir := range dirs {
+			// nvm keeps one bin directory per Node version and puts only the
+			// active one on PATH; the others are inactive on purpose
+			if strings.Contains(filepath.ToSlash(dir), "/.nvm/versions/") {
+				continue
+			}
+
+			unreachable := unreachableTools(dir)
+			if len(unreachable) == 0 {
+				continue
+			}
+			listed := unreachable
+			if len(listed) > maxListed {
+				listed = listed[:maxListed]
+			}
+			noun := "tools"
+			if len(unreachable) == 1 {
+				noun = "tool"
+			}
+			message := fmt.Sprintf("%s is not on PATH; %d %s cannot be run by name (%s",
+				dir, len(unreachable), noun, strings.Join(listed, ", "))
+			if len(unreachable) > len(listed) {
+				message += ", ..."
+			}
+			checks = append(checks, Check{
+				Name:    name + " bin directory",
+				Status:  Warn,
+				Message: message + ")",
+				Fix:     pathExport(dir),
+			})
+		}
+	}
+	return checks
+}
+
+// unreachableTools lists the executables in dir that do not resolve on PATH
+// from anywhere
+func unreachableTools(dir string) []string {
+	names, err := scanner.ListExecutables(dir)
+	if err != nil {
+		return nil
+	}
+	var unreachable []string
+	for _, name := range names {
+		if _, err := exec.LookPath(name); err != nil {
+			unreachable = append(unreachable, name)
+		}
+	}
+	return unreachable
+}
+
+// pathExport returns the shell line that prepends dir to PATH, using $HOME
+// when the directory is under the home directory
+func pathExport(dir string) string {
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(dir, home+string(os.PathSeparator)) {
+		dir = "$HOME" + strings.TrimPrefix(dir, home)
+	}
+	return fmt.Sprintf(`export PATH="%s:$PATH"`, dir)
+}
+
+// MissingPathEntries checks for PATH entries that do not exist. They are
+// harmless to command lookup but slow it down and usually point at
+// something uninstalled.
+func MissingPathEntries(dirs []pathenv.Dir) Check {
+	var missing []string
+	for _, dir := range dirs {
+		if !dir.Exists && dir.DuplicateOf < 0 {
+			missing = append(missing, displayPath(dir.Path))
+		}
+	}
+	check := Check{Name: "PATH entries exist", Status: Pass, Message: "every PATH entry exists"}
+	if len(missing) > 0 {
+		check.Status = Warn
+		check.Message = fmt.Sprintf("%d PATH %s missing: %s", len(missing), plural(len(missing), "entry is", "entries are"), listSome(missing))
+		check.Fix = "remove them from your shell startup files, or run `cli trim-path`"
+	}
+	return check
+}
+
+// DuplicatePathEntries checks for directories that appear in PATH more than
+// once, directly or through a symlinked alias
+func DuplicatePathEntries(dirs []pathenv.Dir) Check {
+	var duplicates []string
+	for _, dir := range dirs {
+		if dir.DuplicateOf >= 0 {
+			duplicates = append(duplicates, fmt.Sprintf("%s (#%d, same as #%d)", displayPath(dir.Path), dir.Index+1, dir.DuplicateOf+1))
+		}
+	}
+	check := Check{Name: "PATH duplicates", Status: Pass, Message: "no directory appears twice"}
+	if len(duplicates) > 0 {
+		check.Status = Warn
+		check.Message = fmt.Sprintf("%d duplicate PATH %s: %s", len(duplicates), plural(len(duplicates), "entry", "entries"), listSome(duplicates))
+		check.Fix = "guard PATH additions in your shell startup files so they only run once, or run `cli trim-path`"
+	}
+	return check
+}
+
+// WorldWritableDirs checks for PATH directories any user can write to.
+// Anyone on the machine could drop an executable there named after a common
+// command and have it run as you, so this fails.
+func WorldWritableDirs(dirs []pathenv.Dir) Check {
+	check := Check{Name: "PATH permissions", Status: Pass, Message: "no PATH directory is world-writable"}
+	if runtime.GOOS == "windows" {
+		check.Message = "not checked on Windows, where permissions are ACLs"
+		return check
+	}
+
+	var writable []string
+	for _, dir := range dirs {
+		if !dir.Exists || dir.DuplicateOf >= 0 {
+			continue
+		}
+		if info, err := os.Stat(dir.Path); err == nil && info.Mode().Perm()&0002 != 0 {
+			writable = append(writable, displayPath(dir.Path))
+		}
+	}
+	if len(writable) > 0 {
+		check.Status = Fail
+		check.Message = fmt.Sprintf("%d PATH %s world-writable: %s", len(writable), plural(len(writable), "directory is", "directories are"), listSome(writable))
+		check.Fix = "chmod o-w " + strings.Join(writable, " ") + ", or remove them from PATH"
+	}
+	return check
+}
+
+// BrokenSymlinks checks PATH directories for symlinks whose target no
+// longer exists, typically left behind by an uninstalled package. Typing
+// their name fails with a confusing "no such file or directory".
+func BrokenSymlinks(dirs []pathenv.Dir) Check {
+	var broken []string
+	for _, dir := range dirs {
+		if !dir.Exists || dir.DuplicateOf >= 0 {
+			continue
+		}
+		entries, err := os.ReadDir(dir.Path)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.Type()&os.ModeSymlink == 0 {
+				continue
+			}
+			path := filepath.Join(dir.Path, entry.Name())
+			if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+				broken = append(broken, path)
+			}
+		}
+	}
+	check := Check{Name: "PATH symlinks", Status: Pass, Message: "no broken symlinks in PATH directories"}
+	if len(broken) > 0 {
+		check.Status = Warn
+		check.Message = fmt.Sprintf("%d broken %s: %s", len(broken), plural(len(broken), "symlink", "symlinks"), listSome(broken))
+		check.Fix = "remove them with `rm`, or reinstall the packages they belonged to"
+	}
+	return check
+}
+
+// managerCommands are the commands each package manager is driven by; any
+// one of them being on PATH is enough
+var managerCommands = map[packages.PackageManager][]string{
+	packages.Brew:       {"brew"},
+	packages.MacPorts:   {"port"},
+	packages.Nix:        {"nix", "nix-env"},
+	packages.Snap:       {"snap"},
+	packages.Flatpak:    {"flatpak"},
+	packages.Cargo:      {"cargo"},
+	packages.Go:         {"go"},
+	packages.Deno:       {"deno"},
+	packages.Dotnet:     {"dotnet"},
+	packages.Composer:   {"composer"},
+	packages.NPM:        {"npm"},
+	packages.Pip:        {"pip", "pip3"},
+	packages.Pipx:       {"pipx"},
+	packages.Uv:         {"uv"},
+	packages.Poetry:     {"poetry"},
+	packages.Gem:        {"gem"},
+	packages.Scoop:      {"scoop"},
+	packages.Chocolatey: {"choco"},
+	packages.Winget:     {"winget"},
+}
+
+// ManagerCommands checks that each package manager with a bin directory on
+// this machine can itself be run. When it cannot, the tools it installed
+// still run but cannot be upgraded or removed, and cli cannot tell which
+// package they belong to.
+func ManagerCommands(binDirs map[packages.PackageManager][]string) []Check {
+	managers := make([]string, 0, len(binDirs))
+	for manager := range binDirs {
+		managers = append(managers, string(manager))
+	}
+	sort.Strings(managers)
+
+	var checks []Check
+	for _, name := range managers {
+		commands := managerCommands[packages.PackageManager(name)]
+		if len(commands) == 0 {
+			continue
+		}
+
+		check := Check{Name: name + " command", Status: Pass}
+		for _, command := range commands {
+			if path, err := exec.LookPath(command); err == nil {
+				check.Message = "found at " + path
+				break
+			}
+		}
+		if check.Message == "" {
+			check.Status = Warn
+			check.Message = fmt.Sprintf("%s has tools installed in %s but `%s` is not on PATH, so they cannot be upgraded or linked to their packages",
+				name, displayPath(binDirs[packages.PackageManager(name)][0]), commands[0])
+			check.Fix = fmt.Sprintf("reinstall %s, or add its bin directory to PATH", name)
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// ShadowedShims checks that each version manager whose shims directory is
+// on PATH actually wins for the commands it shims. A Homebrew python3
+// earlier in PATH than ~/.pyenv/shims means `python3` ignores the version
+// pyenv selects, which fails.
+func ShadowedShims(dirs []pathenv.Dir, shimDirs []shims.ShimDir) []Check {
+	paths := make([]string, len(dirs))
+	for i, dir := range dirs {
+		paths[i] = dir.Path
+	}
+
+	var checks []Check
+	for _, shimDir := range shimDirs {
+		check := Check{Name: string(shimDir.Manager) + " shims", Status: Pass}
+
+		position := pathenv.Index(paths, shimDir.ShimsPath)
+		if position < 0 {
+			check.Status = Warn
+			check.Message = displayPath(shimDir.ShimsPath) + " is not on PATH, so versions selected with " + string(shimDir.Manager) + " are ignored"
+			check.Fix = pathExport(shimDir.ShimsPath)
+			checks = append(checks, check)
+			continue
+		}
+
+		earlier := make(map[string]string)
+		for _, dir := range dirs[:position] {
+			for _, name := range dir.Executables {
+				if _, ok := earlier[scanner.NameKey(name)]; !ok {
+					earlier[scanner.NameKey(name)] = dir.Path
+				}
+			}
+		}
+
+		names, _ := scanner.ListExecutables(shimDir.ShimsPath)
+		var shadowed []string
+		for _, name := range names {
+			if dir, ok := earlier[scanner.NameKey(name)]; ok {
+				shadowed = append(shadowed, fmt.Sprintf("%s (%s)", name, displayPath(dir)))
+			}
+		}
+
+		if len(shadowed) == 0 {
+			check.Message = fmt.Sprintf("%s is on PATH ahead of every other copy of its %d shims", displayPath(shimDir.ShimsPath), len(names))
+		} else {
+			check.Status = Fail
+			check.Message = fmt.Sprintf("%d %s shadowed by earlier PATH entries, so %s's selected version is ignored: %s",
+				len(shadowed), plural(len(shadowed), "shim is", "shims are"), shimDir.Manager, listSome(shadowed))
+			check.Fix = pathExport(shimDir.ShimsPath) + " at the end of your shell startup file"
+			switch shimDir.Manager {
+			case shims.Nvm:
+				check.Fix += " (or source nvm.sh there)"
+			case shims.Volta:
+				// volta has no init step; `volta setup` only edits PATH
+			default:
+				check.Fix += ` (or move "` + string(shimDir.Manager) + ` init" there)`
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// StaleShims checks that the version each version manager selects for a
+// runtime is installed. A .python-version naming a version pyenv no longer
+// has makes every python shim fail with "version not installed".
+func StaleShims(shimDirs []shims.ShimDir) []Check {
+	stale := shims.FindStale(shimDirs)
+
+	var checks []Check
+	for _, shimDir := range shimDirs {
+		name := string(shimDir.Manager) + " versions"
+		var found []shims.StaleShim
+		for _, entry := range stale {
+			if entry.Manager == shimDir.Manager {
+				found = append(found, entry)
+			}
+		}
+		if len(found) == 0 {
+			checks = append(checks, Check{Name: name, Status: Pass, Message: "every version " + string(shimDir.Manager) + " selects is installed"})
+			continue
+		}
+		for _, entry := range found {
+			selected := "selected"
+			if entry.Source != "" {
+				selected += " in " + displayPath(entry.Source)
+			}
+			checks = append(checks, Check{
+				Name:   name,
+				Status: Fail,
+				Message: fmt.Sprintf("%s %s is %s but not installed, so %d %s: %s",
+					entry.Runtime, entry.Version, selected, len(entry.Shims), plural(len(entry.Shims), "shim fails", "shims fail"), listSome(entry.Shims)),
+				Fix: entry.InstallCommand() + ", or select an installed version",
+			})
+		}
+	}
+	return checks
+}
+
+// listSome joins up to maxListed items, noting how many were left out
+func listSome(items []string) string {
+	if len(items) <= maxListed {
+		return strings.Join(items, ", ")
+	}
+	return fmt.Sprintf("%s, and %d more", strings.Join(items[:maxListed], ", "), len(items)-maxListed)
+}
+
+// plural picks the singular or plural form for a count
+func plural(count int, singular, pluralForm string) string {
+	if count == 1 {
+		return singular
+	}
+	return pluralForm
+}
+
+// displayPath shortens a path under the home directory to ~/...
+func displayPath(path string) string {
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, home+string(os.PathSeparator)) {
+		return "~" + strings.TrimPrefix(path, home)
+	}
+	return path
+}
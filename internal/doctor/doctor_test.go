@@ -0,0 +1,53 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cli-ai-org/cli/internal/shims"
+)
+
+// pyenvDir creates a pyenv root with a python shim and the given versions
+// installed
+func pyenvDir(t *testing.T, versions ...string) shims.ShimDir {
+	t.Helper()
+	root := t.TempDir()
+	dir := shims.ShimDir{
+		Manager:      shims.Pyenv,
+		Root:         root,
+		ShimsPath:    filepath.Join(root, "shims"),
+		InstallsPath: filepath.Join(root, "versions"),
+	}
+	if err := os.MkdirAll(dir.ShimsPath, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir.ShimsPath, "python"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range versions {
+		if err := os.MkdirAll(filepath.Join(dir.InstallsPath, version), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PYENV_ROOT", root)
+	return dir
+}
+
+func TestStaleShims(t *testing.T) {
+	t.Setenv("PYENV_VERSION", "3.11.7")
+
+	checks := StaleShims([]shims.ShimDir{pyenvDir(t, "3.12.1")})
+	if len(checks) != 1 || checks[0].Status != Fail {
+		t.Fatalf("got %+v, want one failing check", checks)
+	}
+	if !strings.Contains(checks[0].Message, "3.11.7") || checks[0].Fix == "" {
+		t.Errorf("check does not name the missing version or its fix: %+v", checks[0])
+	}
+
+	checks = StaleShims([]shims.ShimDir{pyenvDir(t, "3.11.7")})
+	if len(checks) != 1 || checks[0].Status != Pass {
+		t.Errorf("got %+v, want one passing check when the version is installed", checks)
+	}
+}
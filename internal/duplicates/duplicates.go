@@ -0,0 +1,121 @@
+package duplicates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// Group is a set of tools with different names whose files are separate
+// copies of the same content
+type Group struct {
+	SHA256 string        `json:"sha256"`
+	Size   int64         `json:"size"`
+	Tools  []models.Tool `json:"tools"`
+}
+
+// Wasted returns the bytes taken by the redundant copies
+func (g Group) Wasted() int64 {
+	return g.Size * int64(len(g.Tools)-1)
+}
+
+// HashFile returns the hex SHA-256 of a file's content, following symlinks
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// file is one distinct file on disk behind one or more tools
+type file struct {
+	info  os.FileInfo
+	tools []models.Tool
+}
+
+// FindByContent groups tools with different names that are separate copies
+// of identical content. Symlinks and hardlinks to the same file are one file
+// (a multi-call binary like busybox is not a duplicate), and zero-byte files
+// are skipped. Only files that share a size are hashed; a tool's SHA256 is
+// reused when already set.
+func FindByContent(tools []models.Tool) []Group {
+	// Bucket by size, collapsing tools that resolve to the same file
+	bySize := make(map[int64][]*file)
+	for _, tool := range tools {
+		info, err := os.Stat(tool.Path)
+		if err != nil || info.IsDir() || info.Size() == 0 {
+			continue
+		}
+		found := false
+		for _, f := range bySize[info.Size()] {
+			if os.SameFile(f.info, info) {
+				f.tools = append(f.tools, tool)
+				found = true
+				break
+			}
+		}
+		if !found {
+			bySize[info.Size()] = append(bySize[info.Size()], &file{info: info, tools: []models.Tool{tool}})
+		}
+	}
+
+	var groups []Group
+	for size, candidates := range bySize {
+		if len(candidates) < 2 {
+			continue
+		}
+
+		byHash := make(map[string][]*file)
+		for _, f := range candidates {
+			sum := f.tools[0].SHA256
+			if sum == "" {
+				var err error
+				if sum, err = HashFile(f.tools[0].Path); err != nil {
+					continue
+				}
+			}
+			byHash[sum] = append(byHash[sum], f)
+		}
+
+		for sum, copies := range byHash {
+			if len(copies) < 2 {
+				continue
+			}
+			group := Group{SHA256: sum, Size: size}
+			names := make(map[string]bool)
+			for _, f := range copies {
+				// One representative per copy; the others are links to it
+				group.Tools = append(group.Tools, f.tools[0])
+				names[f.tools[0].Name] = true
+			}
+			// The same name in several directories is a clash, not a
+			// duplicate under another name
+			if len(names) < 2 {
+				continue
+			}
+			sort.Slice(group.Tools, func(i, j int) bool {
+				return group.Tools[i].Name < group.Tools[j].Name
+			})
+			groups = append(groups, group)
+		}
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Wasted() != groups[j].Wasted() {
+			return groups[i].Wasted() > groups[j].Wasted()
+		}
+		return groups[i].Tools[0].Name < groups[j].Tools[0].Name
+	})
+	return groups
+}
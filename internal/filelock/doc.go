@@ -0,0 +1,4 @@
+// Package filelock provides exclusive advisory locks on open files, so
+// concurrent writers (e.g. several hosts appending to one catalog on a shared
+// mount) do not interleave their output.
+package filelock
@@ -0,0 +1,15 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd || windows)
+
+package filelock
+
+import "os"
+
+// Lock is a no-op on platforms without a supported locking primitive
+func Lock(f *os.File) error {
+	return nil
+}
+
+// Unlock is a no-op on platforms without a supported locking primitive
+func Unlock(f *os.File) error {
+	return nil
+}
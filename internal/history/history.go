@@ -0,0 +1,263 @@
+// Package history reads the command histories of bash, zsh, fish, and
+// PowerShell to tell how often, and how recently, each tool was run.
+package history
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLineLength bounds a history line, since a pasted blob would otherwise
+// stop the scanner
+const maxLineLength = 1 << 20
+
+// wrappers are commands that run the command after them, which is the one
+// counted
+var wrappers = map[string]bool{
+	"sudo":    true,
+	"doas":    true,
+	"env":     true,
+	"time":    true,
+	"nohup":   true,
+	"nice":    true,
+	"command": true,
+	"exec":    true,
+	"builtin": true,
+	"noglob":  true,
+}
+
+// Stat is how a tool appears in the shell histories
+type Stat struct {
+	// Count is how many commands ran the tool
+	Count int
+	// Last is when the tool was last run. Entries a history has no
+	// timestamp for are dated back from the history file's modification
+	// time by their position, one second apart, which keeps their order.
+	Last time.Time
+}
+
+// Usage maps tool names to how they appear in the shell histories
+type Usage map[string]Stat
+
+// Load reads the histories of every shell that has one for the current
+// user. Missing or unreadable histories are skipped.
+func Load() Usage {
+	usage := make(Usage)
+	for _, file := range historyFiles() {
+		entries, err := readFile(file.path, file.parse)
+		if err != nil {
+			continue
+		}
+		usage.add(entries)
+	}
+	return usage
+}
+
+// entry is one command line from a history, with its timestamp if the
+// history records one
+type entry struct {
+	command string
+	when    time.Time
+}
+
+// historyFile is a history and the parser for its format
+type historyFile struct {
+	path  string
+	parse func(lines []string) []entry
+}
+
+// historyFiles returns the history files of the shells this user may use
+func historyFiles() []historyFile {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	var files []historyFile
+	bash := filepath.Join(home, ".bash_history")
+	if histfile := os.Getenv("HISTFILE"); histfile != "" && !strings.Contains(histfile, "zsh") {
+		bash = histfile
+	}
+	files = append(files, historyFile{bash, parseBash})
+
+	zdotdir := os.Getenv("ZDOTDIR")
+	if zdotdir == "" {
+		zdotdir = home
+	}
+	files = append(files,
+		historyFile{filepath.Join(zdotdir, ".zsh_history"), parseZsh},
+		historyFile{filepath.Join(home, ".zhistory"), parseZsh})
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	files = append(files, historyFile{filepath.Join(dataHome, "fish", "fish_history"), parseFish})
+
+	// PSReadLine keeps PowerShell's history, with no timestamps
+	psReadLine := filepath.Join(dataHome, "powershell", "PSReadLine")
+	if runtime.GOOS == "windows" {
+		psReadLine = filepath.Join(os.Getenv("APPDATA"), "Microsoft", "Windows", "PowerShell", "PSReadLine")
+	}
+	files = append(files, historyFile{filepath.Join(psReadLine, "ConsoleHost_history.txt"), parsePlain})
+	return files
+}
+
+// readFile parses a history file, dating entries without a timestamp back
+// from the file's modification time
+func readFile(path string, parse func(lines []string) []entry) ([]entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	entries := parse(lines)
+	for i := range entries {
+		if entries[i].when.IsZero() {
+			entries[i].when = info.ModTime().Add(-time.Duration(len(entries)-i) * time.Second)
+		}
+	}
+	return entries, nil
+}
+
+// add counts the tools each entry runs
+func (u Usage) add(entries []entry) {
+	for _, e := range entries {
+		for _, name := range Commands(e.command) {
+			stat := u[name]
+			stat.Count++
+			if e.when.After(stat.Last) {
+				stat.Last = e.when
+			}
+			u[name] = stat
+		}
+	}
+}
+
+// Commands returns the names of the tools a command line runs: the first
+// word of each command in a pipeline or list, after any variable
+// assignments and wrappers such as sudo, and without its directory
+func Commands(line string) []string {
+	// Redirections such as 2>&1 are not command separators
+	replacer := strings.NewReplacer(">&", " ", "<&", " ", "&>", " ", "&&", "\n", "||", "\n", "|", "\n", ";", "\n", "&", "\n", "$(", "\n", "`", "\n", "(", "\n", ")", "\n")
+	var names []string
+	for _, segment := range strings.Split(replacer.Replace(line), "\n") {
+		wrapped := false
+		for _, word := range strings.Fields(segment) {
+			word = strings.Trim(word, `"'`)
+			if word == "" || strings.Contains(word, "=") && !strings.HasPrefix(word, "=") {
+				continue
+			}
+			if wrapped && strings.HasPrefix(word, "-") {
+				continue
+			}
+			name := word
+			if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+				name = name[i+1:]
+			}
+			if wrappers[name] {
+				wrapped = true
+				continue
+			}
+			if name != "" && !strings.HasPrefix(name, "-") && !strings.HasPrefix(name, "#") {
+				names = append(names, name)
+			}
+			break
+		}
+	}
+	return names
+}
+
+// parseBash reads a bash history, in which a "#1700000000" line before a
+// command gives its timestamp when HISTTIMEFORMAT is set
+func parseBash(lines []string) []entry {
+	var entries []entry
+	var when time.Time
+	for _, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			if seconds, err := strconv.ParseInt(line[1:], 10, 64); err == nil {
+				when = time.Unix(seconds, 0)
+				continue
+			}
+		}
+		if strings.TrimSpace(line) != "" {
+			entries = append(entries, entry{command: line, when: when})
+		}
+		when = time.Time{}
+	}
+	return entries
+}
+
+// parseZsh reads a zsh history, in the extended format
+// (": 1700000000:0;git status") or as plain lines
+func parseZsh(lines []string) []entry {
+	var entries []entry
+	for _, line := range lines {
+		var when time.Time
+		if strings.HasPrefix(line, ": ") {
+			if semi := strings.IndexByte(line, ';'); semi > 0 {
+				stamp := strings.TrimPrefix(line[:semi], ": ")
+				if colon := strings.IndexByte(stamp, ':'); colon > 0 {
+					stamp = stamp[:colon]
+				}
+				if seconds, err := strconv.ParseInt(stamp, 10, 64); err == nil {
+					when = time.Unix(seconds, 0)
+				}
+				line = line[semi+1:]
+			}
+		}
+		if strings.TrimSpace(line) != "" {
+			entries = append(entries, entry{command: line, when: when})
+		}
+	}
+	return entries
+}
+
+// parseFish reads a fish history, a YAML-like list of "- cmd: ..." items
+// each followed by "  when: 1700000000"
+func parseFish(lines []string) []entry {
+	var entries []entry
+	for _, line := range lines {
+		if command := strings.TrimPrefix(line, "- cmd: "); command != line {
+			entries = append(entries, entry{command: command})
+			continue
+		}
+		if stamp := strings.TrimPrefix(line, "  when: "); stamp != line && len(entries) > 0 {
+			if seconds, err := strconv.ParseInt(strings.TrimSpace(stamp), 10, 64); err == nil {
+				entries[len(entries)-1].when = time.Unix(seconds, 0)
+			}
+		}
+	}
+	return entries
+}
+
+// parsePlain reads a history of one command per line
+func parsePlain(lines []string) []entry {
+	var entries []entry
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			entries = append(entries, entry{command: line})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,56 @@
+package listfilter
+
+// defaultExclude are packages whose tools `cli list` hides by default
+// because they are libraries, servers, or system utilities rather than CLIs
+// people install on purpose. Patterns may be scoped to one manager with a
+// "manager:" prefix; Homebrew formula names are scoped to brew, since other
+// managers use the same names for unrelated packages.
+var defaultExclude = []string{
+	// Homebrew development libraries
+	"brew:gcc", "brew:netpbm", "brew:gd", "brew:gdal",
+	"brew:gettext", "brew:libtiff", "brew:libpng", "brew:fontconfig",
+	"brew:glib", "brew:hdf5", "brew:graphviz", "brew:gts",
+	"brew:mbedtls", "brew:nss", "brew:perl", "brew:tesseract",
+	"brew:pcre", "brew:pcre2", "brew:python@*",
+	"brew:xz", "brew:ffmpeg", "brew:libsndfile", "brew:little-cms2",
+	"brew:jpeg-xl", "brew:libfido2", "brew:libgcrypt", "brew:libheif",
+	"brew:c-ares", "brew:libtasn1", "brew:libavif", "brew:libbluray",
+	"brew:cairo", "brew:jpeg-turbo", "brew:zeromq", "brew:tcl-tk",
+	"brew:libdap", "brew:libde265", "brew:libgeotiff", "brew:libidn2",
+	"brew:librist", "brew:libvmaf", "brew:lua", "brew:autoconf",
+	"brew:brotli", "brew:flac", "brew:giflib", "brew:lame",
+	"brew:leptonica", "brew:libassuan", "brew:libdeflate", "brew:libevent",
+	"brew:libgpg-error", "brew:libksba", "brew:lz4", "brew:m4",
+	"brew:miniupnpc", "brew:mpg123", "brew:nettle", "brew:nghttp2",
+	"brew:oniguruma", "brew:openexr", "brew:openjpeg", "brew:opus",
+	"brew:p11-kit", "brew:pango", "brew:pkgconf", "brew:proj",
+	"brew:qhull", "brew:rav1e", "brew:rubberband", "brew:sdl2",
+	"brew:speex", "brew:srt", "brew:unbound", "brew:uriparser",
+	"brew:webp", "brew:x264", "brew:x265", "brew:dav1d", "brew:aom",
+	"brew:gnupg", "brew:gnutls", "brew:gpgme", "brew:gobject-introspection",
+	"brew:grpc", "brew:guile", "brew:harfbuzz", "brew:jasper",
+	"brew:jemalloc", "brew:libtool", "brew:nspr",
+	"brew:cfitsio", "brew:gdbm", "brew:netcdf", "brew:freetype",
+	"brew:fribidi", "brew:fmt", "brew:gdk-pixbuf", "brew:geos",
+	"brew:gflags", "brew:fizz", "brew:epsilon", "brew:unixodbc",
+	"brew:openssl@*", "brew:shared-mime-info",
+	"brew:apache-arrow", "brew:protobuf", "brew:protobuf@*",
+	"brew:postgresql@*",
+	// Python and Ruby library packages (not CLIs)
+	"aiosmtpd", "comm", "date", "distro",
+	"ecdsa", "email_validator", "httpx", "logger",
+	"pi", "screen", "sync", "typer",
+	"fonttools", "jsonpointer", "jsonschema",
+	"pycodestyle", "pyflakes", "tqdm", "tabulate",
+	"watchfiles", "webdriverdownloader", "numpy",
+	// Servers and daemons
+	"gunicorn", "uvicorn", "redis", "transmission-cli",
+	// Editor variants and utilities
+	"emacs", "vim", "zsh", "grep",
+	// Compression utilities
+	"zstd", "xxhash",
+	// Development utilities
+	"tree-sitter", "luajit", "openssl", "pinentry",
+	"librsvg", "telnet", "ssh-copy-id",
+	"solidity", "thrift", "fbthrift", "z3",
+}
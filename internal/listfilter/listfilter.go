@@ -0,0 +1,213 @@
+// Package listfilter decides which packages' tools `cli list` shows by
+// default: the CLIs people install on purpose, not the libraries, servers,
+// and helpers that package managers pull in alongside them.
+package listfilter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Heuristics that hide a package without naming it in a rule
+const (
+	// ManyBinaries hides packages with more binaries in PATH than
+	// Config.MaxBinaries, which are usually libraries' helper suites
+	ManyBinaries = "many-binaries"
+	// NoMatchingBinary hides packages with three or more binaries none of
+	// which is named after the package (libtiff's tiffinfo, tiffcp, ...)
+	NoMatchingBinary = "no-matching-binary"
+	// LibPrefix hides lib* packages that have no binary named after them
+	LibPrefix = "lib-prefix"
+	// DependencyOnly hides packages the manager installed only to satisfy
+	// another package (Homebrew records this)
+	DependencyOnly = "dependency-only"
+)
+
+// DefaultMaxBinaries is the most binaries a package may have before
+// ManyBinaries hides it
+const DefaultMaxBinaries = 10
+
+// noMatchMinBinaries is how many binaries a package needs before
+// NoMatchingBinary applies, so single-purpose tools like ripgrep (rg) and
+// typescript (tsc, tsserver) are kept
+const noMatchMinBinaries = 3
+
+// Config is the list_filter section of the config file. Package patterns
+// are shell globs of package names, optionally scoped to one manager with a
+// "manager:" prefix (brew:gcc, pip:*-lib).
+type Config struct {
+	// Exclude hides these packages' tools
+	Exclude []string `yaml:"exclude"`
+	// Include shows these packages' main tool even when a built-in rule or
+	// heuristic would hide it
+	Include []string `yaml:"include"`
+	// NoDefaults drops the built-in list of library and server packages
+	NoDefaults bool `yaml:"no_defaults"`
+	// MaxBinaries overrides DefaultMaxBinaries
+	MaxBinaries int `yaml:"max_binaries"`
+	// Heuristics lists the heuristics to apply (default all of them); an
+	// empty list in the config file turns them all off
+	Heuristics *[]string `yaml:"heuristics"`
+}
+
+// Package is what the filter knows about a package
+type Package struct {
+	Name    string
+	Manager string
+	// Binaries are the package's tools found in PATH
+	Binaries []string
+	// Dependency is set when the package was installed only as another
+	// package's dependency
+	Dependency bool
+}
+
+// Filter applies the rules
+type Filter struct {
+	include     []pattern
+	exclude     []pattern
+	defaults    []pattern
+	maxBinaries int
+	heuristics  map[string]bool
+}
+
+type pattern struct {
+	manager string
+	glob    string
+}
+
+func (p pattern) matches(pkg Package) bool {
+	if p.manager != "" && p.manager != pkg.Manager {
+		return false
+	}
+	matched, _ := filepath.Match(p.glob, pkg.Name)
+	return matched
+}
+
+func (p pattern) String() string {
+	if p.manager == "" {
+		return p.glob
+	}
+	return p.manager + ":" + p.glob
+}
+
+// New builds a filter from the config file's list_filter section
+func New(cfg Config) (*Filter, error) {
+	f := &Filter{
+		maxBinaries: cfg.MaxBinaries,
+		heuristics:  make(map[string]bool),
+	}
+	if f.maxBinaries <= 0 {
+		f.maxBinaries = DefaultMaxBinaries
+	}
+
+	var err error
+	if f.include, err = parsePatterns(cfg.Include); err != nil {
+		return nil, fmt.Errorf("include: %w", err)
+	}
+	if f.exclude, err = parsePatterns(cfg.Exclude); err != nil {
+		return nil, fmt.Errorf("exclude: %w", err)
+	}
+	if !cfg.NoDefaults {
+		f.defaults, _ = parsePatterns(defaultExclude)
+	}
+
+	heuristics := Heuristics()
+	if cfg.Heuristics != nil {
+		heuristics = *cfg.Heuristics
+	}
+	for _, name := range heuristics {
+		if !isHeuristic(name) {
+			return nil, fmt.Errorf("unknown heuristic %q (valid: %s)", name, strings.Join(Heuristics(), ", "))
+		}
+		f.heuristics[name] = true
+	}
+	return f, nil
+}
+
+// Heuristics returns the names of every heuristic
+func Heuristics() []string {
+	return []string{ManyBinaries, NoMatchingBinary, LibPrefix, DependencyOnly}
+}
+
+func isHeuristic(name string) bool {
+	for _, h := range Heuristics() {
+		if h == name {
+			return true
+		}
+	}
+	return false
+}
+
+func parsePatterns(specs []string) ([]pattern, error) {
+	var patterns []pattern
+	for _, spec := range specs {
+		p := pattern{glob: spec}
+		if manager, glob, ok := strings.Cut(spec, ":"); ok {
+			p = pattern{manager: manager, glob: glob}
+		}
+		if _, err := filepath.Match(p.glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", spec, err)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// Hidden reports whether the package's tools are hidden by default, and
+// the rule or heuristic that hides them
+func (f *Filter) Hidden(pkg Package) (bool, string) {
+	for _, p := range f.include {
+		if p.matches(pkg) {
+			return false, ""
+		}
+	}
+	for _, p := range f.exclude {
+		if p.matches(pkg) {
+			return true, "excluded by " + p.String()
+		}
+	}
+	for _, p := range f.defaults {
+		if p.matches(pkg) {
+			return true, "built-in exclude " + p.String()
+		}
+	}
+
+	if f.heuristics[DependencyOnly] && pkg.Dependency {
+		return true, DependencyOnly
+	}
+	if f.heuristics[ManyBinaries] && len(pkg.Binaries) > f.maxBinaries {
+		return true, fmt.Sprintf("%s (%d)", ManyBinaries, len(pkg.Binaries))
+	}
+	matching := hasMatchingBinary(pkg)
+	if f.heuristics[LibPrefix] && strings.HasPrefix(strings.ToLower(pkg.Name), "lib") && !matching {
+		return true, LibPrefix
+	}
+	if f.heuristics[NoMatchingBinary] && len(pkg.Binaries) >= noMatchMinBinaries && !matching {
+		return true, NoMatchingBinary
+	}
+	return false, ""
+}
+
+// hasMatchingBinary reports whether one of the package's binaries is named
+// after it: the same name, or one containing the other (imagemagick's
+// magick), ignoring an npm scope and a brew version suffix
+func hasMatchingBinary(pkg Package) bool {
+	name := strings.ToLower(pkg.Name)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "@"); i > 0 {
+		name = name[:i]
+	}
+	for _, binary := range pkg.Binaries {
+		binary = strings.ToLower(binary)
+		if len(binary) < 2 {
+			continue
+		}
+		if binary == name || strings.Contains(name, binary) || strings.Contains(binary, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+// Package mcp implements a Model Context Protocol server over stdio: JSON-RPC
+// 2.0 messages, one per line, offering tools a client can call and resources
+// it can read.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ProtocolVersion is the MCP revision the server implements, offered when
+// the client asks for one it does not know
+const ProtocolVersion = "2024-11-05"
+
+// supportedVersions are the protocol revisions whose messages the server
+// understands; a client asking for one of them gets it back
+var supportedVersions = map[string]bool{
+	"2024-11-05": true,
+	"2025-03-26": true,
+	"2025-06-18": true,
+}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// maxMessageSize bounds one incoming message
+const maxMessageSize = 16 << 20
+
+// Tool is a function the client can call. Its result is sent to the client
+// as JSON text; an error is reported as a failed call, not a protocol error,
+// so the model sees it.
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// InputSchema is the JSON Schema of the call's arguments
+	InputSchema map[string]interface{}                                               `json:"inputSchema"`
+	Handler     func(ctx context.Context, args json.RawMessage) (interface{}, error) `json:"-"`
+}
+
+// Resource is a document the client can read. Its content is sent as JSON
+// text.
+type Resource struct {
+	URI         string                                         `json:"uri"`
+	Name        string                                         `json:"name"`
+	Description string                                         `json:"description,omitempty"`
+	MIMEType    string                                         `json:"mimeType,omitempty"`
+	Read        func(ctx context.Context) (interface{}, error) `json:"-"`
+}
+
+// Server answers MCP requests with its registered tools and resources
+type Server struct {
+	name      string
+	version   string
+	tools     []Tool
+	resources []Resource
+
+	mu  sync.Mutex
+	out *json.Encoder
+}
+
+// NewServer creates a server that introduces itself with name and version
+func NewServer(name, version string) *Server {
+	return &Server{name: name, version: version}
+}
+
+// AddTool registers a tool
+func (s *Server) AddTool(tool Tool) {
+	s.tools = append(s.tools, tool)
+}
+
+// AddResource registers a resource
+func (s *Server) AddResource(resource Resource) {
+	s.resources = append(s.resources, resource)
+}
+
+// ObjectSchema builds the JSON Schema of an arguments object from its
+// properties and the names of the required ones
+func ObjectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// StringProperty describes a string argument for ObjectSchema
+func StringProperty(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+// BoolProperty describes a boolean argument for ObjectSchema
+func BoolProperty(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "boolean", "description": description}
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type textContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Serve reads requests from r and writes responses to w until r is closed or
+// ctx is cancelled. Requests are answered one at a time, in order.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.out = json.NewEncoder(w)
+
+	lines := bufio.NewScanner(r)
+	lines.Buffer(make([]byte, 64*1024), maxMessageSize)
+	for lines.Scan() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		line := lines.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.reply(response{ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error: " + err.Error()}})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			if len(req.ID) > 0 {
+				s.reply(response{ID: req.ID, Error: &rpcError{codeInvalidRequest, "invalid request"}})
+			}
+			continue
+		}
+
+		result, rerr := s.handle(ctx, req)
+		// Notifications have no id and get no response
+		if len(req.ID) == 0 {
+			continue
+		}
+		if rerr != nil {
+			s.reply(response{ID: req.ID, Error: rerr})
+		} else {
+			s.reply(response{ID: req.ID, Result: result})
+		}
+	}
+	if err := lines.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (s *Server) reply(resp response) {
+	resp.JSONRPC = "2.0"
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.out.Encode(resp)
+}
+
+func (s *Server) handle(ctx context.Context, req request) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := ProtocolVersion
+		if supportedVersions[params.ProtocolVersion] {
+			version = params.ProtocolVersion
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities": map[string]interface{}{
+				"tools":     map[string]interface{}{},
+				"resources": map[string]interface{}{},
+			},
+			"serverInfo": map[string]string{"name": s.name, "version": s.version},
+		}, nil
+
+	case "ping":
+		return map[string]interface{}{}, nil
+
+	case "tools/list":
+		tools := s.tools
+		if tools == nil {
+			tools = []Tool{}
+		}
+		return map[string]interface{}{"tools": tools}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{codeInvalidParams, "invalid params: " + err.Error()}
+		}
+		for _, tool := range s.tools {
+			if tool.Name == params.Name {
+				return callResult(tool.Handler(ctx, params.Arguments)), nil
+			}
+		}
+		return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown tool %q", params.Name)}
+
+	case "resources/list":
+		resources := s.resources
+		if resources == nil {
+			resources = []Resource{}
+		}
+		return map[string]interface{}{"resources": resources}, nil
+
+	case "resources/read":
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{codeInvalidParams, "invalid params: " + err.Error()}
+		}
+		for _, resource := range s.resources {
+			if resource.URI != params.URI {
+				continue
+			}
+			content, err := resource.Read(ctx)
+			if err != nil {
+				return nil, &rpcError{codeInvalidParams, err.Error()}
+			}
+			text, err := json.MarshalIndent(content, "", "  ")
+			if err != nil {
+				return nil, &rpcError{codeInvalidParams, err.Error()}
+			}
+			return map[string]interface{}{
+				"contents": []map[string]string{{
+					"uri":      resource.URI,
+					"mimeType": resource.MIMEType,
+					"text":     string(text),
+				}},
+			}, nil
+		}
+		return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown resource %q", params.URI)}
+	}
+
+	// Other notifications (initialized, cancelled) need no action
+	return nil, &rpcError{codeMethodNotFound, fmt.Sprintf("method %q not found", req.Method)}
+}
+
+// callResult wraps a tool's result, or its error, as call content
+func callResult(result interface{}, err error) map[string]interface{} {
+	if err != nil {
+		return map[string]interface{}{
+			"content": []textContent{{Type: "text", Text: err.Error()}},
+			"isError": true,
+		}
+	}
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return callResult(nil, err)
+	}
+	return map[string]interface{}{
+		"content": []textContent{{Type: "text", Text: string(text)}},
+	}
+}
@@ -0,0 +1,129 @@
+package merge
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// Input is one catalog to merge, named by the file it was read from
+type Input struct {
+	Name    string
+	Catalog *models.ToolCatalog
+}
+
+// Source reports what one input contributed to the merged catalog
+type Source struct {
+	Name string `json:"name"`
+	// Tools is how many tools the input held
+	Tools int `json:"tools"`
+	// Added is how many of them were new; the rest duplicated a tool from an
+	// earlier input and were merged into it
+	Added      int `json:"added"`
+	Duplicates int `json:"duplicates"`
+	// Hostnames are the machines the input's tools were attributed to
+	Hostnames []string `json:"hostnames"`
+}
+
+// Result is a merged catalog and how each input contributed to it
+type Result struct {
+	Catalog *models.ToolCatalog
+	Sources []Source
+}
+
+// Catalogs combines catalogs into one, de-duplicating tools by name, path,
+// and hostname. Tools without a hostname, as in a plain export, are
+// attributed to the stem of their input's name (web-01.json gives web-01) so
+// per-host attribution survives the merge.
+//
+// When the same tool appears in several inputs, later inputs win for fields
+// they set, and fields they leave empty keep the earlier value, so merging a
+// catalog exported with --with-meta after a plain one adds versions and help
+// text without losing anything. Packages are de-duplicated by manager, name,
+// version, and location, and search paths are combined in first-seen order.
+func Catalogs(inputs []Input) Result {
+	merged := &models.ToolCatalog{
+		Paths: []string{},
+		Tools: []models.Tool{},
+	}
+	var sources []Source
+
+	toolIndex := make(map[[3]string]int)
+	packageSeen := make(map[[4]string]bool)
+	pathSeen := make(map[string]bool)
+
+	for _, input := range inputs {
+		source := Source{Name: input.Name, Tools: len(input.Catalog.Tools), Hostnames: []string{}}
+		hosts := make(map[string]bool)
+
+		for _, tool := range input.Catalog.Tools {
+			if tool.Hostname == "" {
+				tool.Hostname = HostFromName(input.Name)
+			}
+			hosts[tool.Hostname] = true
+
+			key := [3]string{tool.Name, tool.Path, tool.Hostname}
+			if i, ok := toolIndex[key]; ok {
+				overlay(&merged.Tools[i], tool)
+				source.Duplicates++
+				continue
+			}
+			toolIndex[key] = len(merged.Tools)
+			merged.Tools = append(merged.Tools, tool)
+			source.Added++
+		}
+
+		for _, pkg := range input.Catalog.Packages {
+			key := [4]string{pkg.Manager, pkg.Name, pkg.Version, pkg.Location}
+			if packageSeen[key] {
+				continue
+			}
+			packageSeen[key] = true
+			merged.Packages = append(merged.Packages, pkg)
+		}
+
+		for _, path := range input.Catalog.Paths {
+			if !pathSeen[path] {
+				pathSeen[path] = true
+				merged.Paths = append(merged.Paths, path)
+			}
+		}
+
+		if input.Catalog.Partial {
+			merged.Partial = true
+		}
+
+		for host := range hosts {
+			source.Hostnames = append(source.Hostnames, host)
+		}
+		sort.Strings(source.Hostnames)
+		sources = append(sources, source)
+	}
+
+	merged.TotalTools = len(merged.Tools)
+	merged.TotalPackages = len(merged.Packages)
+	merged.GeneratedAt = time.Now().Format(time.RFC3339)
+	return Result{Catalog: merged, Sources: sources}
+}
+
+// HostFromName derives a hostname from an input file name by dropping its
+// directory and extension
+func HostFromName(name string) string {
+	base := filepath.Base(name)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// overlay copies every field that is set in src onto dst
+func overlay(dst *models.Tool, src models.Tool) {
+	d := reflect.ValueOf(dst).Elem()
+	s := reflect.ValueOf(src)
+	for i := 0; i < s.NumField(); i++ {
+		if !s.Field(i).IsZero() {
+			d.Field(i).Set(s.Field(i))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// IsNDJSON reports whether data holds tool records, as written by
+// `export --format ndjson` and `--append`, rather than a catalog: more than
+// one top-level JSON value, or a single tool object
+func IsNDJSON(data []byte) bool {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	var first map[string]json.RawMessage
+	if err := decoder.Decode(&first); err != nil {
+		return false
+	}
+	var second json.RawMessage
+	if decoder.Decode(&second) != io.EOF {
+		return true
+	}
+	_, hasTools := first["tools"]
+	_, hasName := first["name"]
+	return hasName && !hasTools
+}
+
+// DecodeCatalog decodes an exported catalog, either a JSON document or NDJSON
+// tool records. NDJSON yields a catalog holding only tools.
+func DecodeCatalog(data []byte) (*ToolCatalog, error) {
+	if !IsNDJSON(data) {
+		var catalog ToolCatalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, err
+		}
+		return &catalog, nil
+	}
+
+	catalog := &ToolCatalog{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	// Records carrying help text can be long
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var tool Tool
+		if err := json.Unmarshal(scanner.Bytes(), &tool); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		catalog.Tools = append(catalog.Tools, tool)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	catalog.TotalTools = len(catalog.Tools)
+	return catalog, nil
+}
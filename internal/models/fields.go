@@ -0,0 +1,115 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ToolFields returns the JSON field names of Tool, in declaration order
+func ToolFields() []string {
+	return JSONFields(Tool{})
+}
+
+// JSONFields returns the JSON field names of a struct value, in declaration
+// order
+func JSONFields(v interface{}) []string {
+	var fields []string
+	t := reflect.TypeOf(v)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// PackageFields returns the JSON field names of PackageInfo, in
+// declaration order
+func PackageFields() []string {
+	return JSONFields(PackageInfo{})
+}
+
+// toolFieldAliases are short names accepted for Tool fields in delimited
+// output, where columns are typed by hand
+var toolFieldAliases = map[string]string{
+	"manager": "package_manager",
+	"package": "package_name",
+}
+
+// ResolveToolFields replaces the short names "manager" and "package" with
+// the Tool JSON fields they stand for
+func ResolveToolFields(fields []string) []string {
+	resolved := make([]string, len(fields))
+	for i, field := range fields {
+		if name, ok := toolFieldAliases[field]; ok {
+			field = name
+		}
+		resolved[i] = field
+	}
+	return resolved
+}
+
+// ValidateToolFields checks that every field is a Tool JSON field name
+func ValidateToolFields(fields []string) error {
+	return validateFields(fields, ToolFields())
+}
+
+// ValidatePackageFields checks that every field is a PackageInfo JSON field
+// name
+func ValidatePackageFields(fields []string) error {
+	return validateFields(fields, PackageFields())
+}
+
+func validateFields(fields, valid []string) error {
+	for _, field := range fields {
+		found := false
+		for _, name := range valid {
+			if field == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown field %q (valid: %s)", field, strings.Join(valid, ", "))
+		}
+	}
+	return nil
+}
+
+// Project serializes the tool as a JSON object holding only the given
+// fields, in the order given. Fields the tool omits when empty are still
+// omitted.
+func (t Tool) Project(fields []string) (json.RawMessage, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	written := 0
+	for _, field := range fields {
+		value, ok := all[field]
+		if !ok {
+			continue
+		}
+		if written > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(field)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+		written++
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
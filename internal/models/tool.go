@@ -2,57 +2,269 @@ package models
 
 // Tool represents a CLI tool discovered on the system
 type Tool struct {
-	Name           string   `json:"name"`
-	Path           string   `json:"path"`
-	Description    string   `json:"description,omitempty"`
-	Version        string   `json:"version,omitempty"`
-	HelpText       string   `json:"help_text,omitempty"`
-	IsSymlink      bool     `json:"is_symlink"`
-	SymlinkTo      string   `json:"symlink_to,omitempty"`
-	Size           int64    `json:"size"`
-	Aliases        []string `json:"aliases,omitempty"`
-	PackageName    string   `json:"package_name,omitempty"`
-	PackageManager string   `json:"package_manager,omitempty"`
-	PackageVersion string   `json:"package_version,omitempty"`
+	Name           string   `json:"name" toml:"name" yaml:"name"`
+	Path           string   `json:"path" toml:"path" yaml:"path"`
+	Description    string   `json:"description,omitempty" toml:"description,omitempty" yaml:"description,omitempty"`
+	Version        string   `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty"`
+	HelpText       string   `json:"help_text,omitempty" toml:"help_text,omitempty" yaml:"help_text,omitempty"`
+	IsSymlink      bool     `json:"is_symlink" toml:"is_symlink" yaml:"is_symlink"`
+	SymlinkTo      string   `json:"symlink_to,omitempty" toml:"symlink_to,omitempty" yaml:"symlink_to,omitempty"`
+	Size           int64    `json:"size" toml:"size" yaml:"size"`
+	Aliases        []string `json:"aliases,omitempty" toml:"aliases,omitempty" yaml:"aliases,omitempty"`
+	PackageName    string   `json:"package_name,omitempty" toml:"package_name,omitempty" yaml:"package_name,omitempty"`
+	PackageManager string   `json:"package_manager,omitempty" toml:"package_manager,omitempty" yaml:"package_manager,omitempty"`
+	PackageVersion string   `json:"package_version,omitempty" toml:"package_version,omitempty" yaml:"package_version,omitempty"`
+	LinkStrategy   string   `json:"link_strategy,omitempty" toml:"link_strategy,omitempty" yaml:"link_strategy,omitempty"`
+	LinkReason     string   `json:"link_reason,omitempty" toml:"link_reason,omitempty" yaml:"link_reason,omitempty"`
+	Broken         bool     `json:"broken,omitempty" toml:"broken,omitempty" yaml:"broken,omitempty"`
+	BrokenReason   string   `json:"broken_reason,omitempty" toml:"broken_reason,omitempty" yaml:"broken_reason,omitempty"`
+	AppExecAlias   bool     `json:"app_exec_alias,omitempty" toml:"app_exec_alias,omitempty" yaml:"app_exec_alias,omitempty"`
+	// Shortcut is set for Windows .lnk shortcuts run through PATHEXT, which
+	// cmd.exe opens via the shell but other programs cannot execute
+	Shortcut bool `json:"shortcut,omitempty" toml:"shortcut,omitempty" yaml:"shortcut,omitempty"`
+	// PathIndex is the position (0-based) of the PATH directory the tool was
+	// found in; lower indexes take precedence
+	PathIndex int `json:"path_index" toml:"path_index" yaml:"path_index"`
+	// PathRank is the tool's position among the installations of its name,
+	// in PATH order: 0 for the one the shell runs, 1 for the first one it
+	// shadows, and so on
+	PathRank int `json:"path_rank" toml:"path_rank" yaml:"path_rank"`
+	// Shadowed is set when an installation earlier in PATH has the same
+	// name, so this one never runs when the name is typed
+	Shadowed bool `json:"shadowed,omitempty" toml:"shadowed,omitempty" yaml:"shadowed,omitempty"`
+	// SHA256 is the hex digest of the tool's content (the symlink target for
+	// symlinks), recorded only when hashing is requested
+	SHA256 string `json:"sha256,omitempty" toml:"sha256,omitempty" yaml:"sha256,omitempty"`
+	// Hostname records which machine the tool was found on when catalogs
+	// from several machines are accumulated in one file
+	Hostname string `json:"hostname,omitempty" toml:"hostname,omitempty" yaml:"hostname,omitempty"`
+	// Tags are the categories of the tool (vcs, container, network, ...),
+	// from a built-in list of tools and the words of its description
+	Tags []string `json:"tags,omitempty" toml:"tags,omitempty" yaml:"tags,omitempty"`
+	// Safety is what an agent should know before running the tool,
+	// recorded only when safety annotations are requested
+	Safety *SafetyInfo `json:"safety,omitempty" toml:"safety,omitempty" yaml:"safety,omitempty"`
+	// Help is the usage, flags, and subcommands parsed from HelpText
+	Help *ToolInfo `json:"help,omitempty" toml:"help,omitempty" yaml:"help,omitempty"`
+	// Shim is what the tool runs when it is a version manager's shim
+	Shim *ShimInfo `json:"shim,omitempty" toml:"shim,omitempty" yaml:"shim,omitempty"`
+	// Plugin is set when the tool is a plugin that another tool runs as one
+	// of its subcommands, such as git-lfs for git
+	Plugin *PluginInfo `json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty"`
+	// Plugins are the plugins installed for the tool, on the installation
+	// that runs
+	Plugins []PluginInfo `json:"plugins,omitempty" toml:"plugins,omitempty" yaml:"plugins,omitempty"`
+}
+
+// SafetyInfo describes the risks of running a tool, so agent policies can
+// ask for confirmation first. Risks are destructive (deletes or overwrites
+// data, stops processes or the machine), network-write (changes remote
+// state, uploads data, or downloads code to run), and privileged (runs with
+// or changes elevated privileges).
+type SafetyInfo struct {
+	// Risks are the risks of running the tool at all
+	Risks  []string `json:"risks,omitempty" toml:"risks,omitempty" yaml:"risks,omitempty"`
+	Reason string   `json:"reason,omitempty" toml:"reason,omitempty" yaml:"reason,omitempty"`
+	// Subcommands are the subcommands with risks of their own, such as
+	// terraform destroy; their names may span levels ("system prune")
+	Subcommands []SubcommandRisk `json:"subcommands,omitempty" toml:"subcommands,omitempty" yaml:"subcommands,omitempty"`
+	// Source says where the annotation came from: builtin, heuristic (the
+	// subcommands the tool's help lists), or override (the safety file)
+	Source string `json:"source" toml:"source" yaml:"source"`
+}
+
+// SubcommandRisk describes the risks of one subcommand of a tool
+type SubcommandRisk struct {
+	Name   string   `json:"name" toml:"name" yaml:"name"`
+	Risks  []string `json:"risks" toml:"risks" yaml:"risks"`
+	Reason string   `json:"reason,omitempty" toml:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// PluginInfo describes a plugin: an executable a host tool runs as one of
+// its subcommands, such as kubectl-ctx for `kubectl ctx`
+type PluginInfo struct {
+	// Host is the tool the plugin extends: kubectl, gh, cargo, or git
+	Host string `json:"host" toml:"host" yaml:"host"`
+	// Name is the subcommand the plugin adds, and Command how it is run
+	Name    string `json:"name" toml:"name" yaml:"name"`
+	Command string `json:"command" toml:"command" yaml:"command"`
+	Path    string `json:"path,omitempty" toml:"path,omitempty" yaml:"path,omitempty"`
+	// Source is how the plugin was installed: krew, gh extension, or path
+	// for an executable found in PATH
+	Source  string `json:"source" toml:"source" yaml:"source"`
+	Version string `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty"`
+	// Repository is the repository a gh extension was installed from
+	Repository string `json:"repository,omitempty" toml:"repository,omitempty" yaml:"repository,omitempty"`
+}
+
+// ShimInfo describes the binary a version manager's shim runs
+type ShimInfo struct {
+	// Manager is the version manager, such as asdf or mise
+	Manager string `json:"manager" toml:"manager" yaml:"manager"`
+	// Plugin is the runtime or plugin that provides the tool, such as nodejs
+	Plugin  string `json:"plugin,omitempty" toml:"plugin,omitempty" yaml:"plugin,omitempty"`
+	Version string `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty"`
+	// Target is the binary the shim runs for the selected version
+	Target string `json:"target,omitempty" toml:"target,omitempty" yaml:"target,omitempty"`
+	// Source is the file or environment variable that selected the version
+	Source string `json:"source,omitempty" toml:"source,omitempty" yaml:"source,omitempty"`
+}
+
+// InstallationInfo describes one installation of a tool found in PATH
+type InstallationInfo struct {
+	Path           string `json:"path"`
+	PackageName    string `json:"package_name,omitempty"`
+	PackageManager string `json:"package_manager"`
+	Version        string `json:"version,omitempty"`
+	IsActive       bool   `json:"active"`
+	Broken         bool   `json:"broken"`
+	BrokenReason   string `json:"broken_reason,omitempty"`
+	// SymlinkTo is the target of the symlink at Path, as written, and
+	// ResolvedPath the file reached by following every link
+	SymlinkTo    string `json:"symlink_to,omitempty"`
+	ResolvedPath string `json:"resolved_path,omitempty"`
+	// ReportedVersion is the version the binary prints for --version, when
+	// it was probed
+	ReportedVersion string `json:"reported_version,omitempty"`
+	// Shim is what the installation runs when it is a version manager's shim
+	Shim *ShimInfo `json:"shim,omitempty"`
+}
+
+// ShellOverride is something the shell runs instead of a PATH executable
+// when a command name is typed: an alias, a function, or a builtin
+type ShellOverride struct {
+	Kind  string `json:"kind"`
+	Shell string `json:"shell"`
+	// Value is what an alias expands to
+	Value string `json:"value,omitempty"`
+	// Source is the file and line that defines an alias or function
+	Source string `json:"source,omitempty"`
+	// Active marks the override that runs when the name is typed in Shell:
+	// its alias, else its function, else its builtin
+	Active bool `json:"active"`
+}
+
+// ToolLookup is the result of resolving a command name against PATH
+type ToolLookup struct {
+	Name          string             `json:"name"`
+	Found         bool               `json:"found"`
+	Installations []InstallationInfo `json:"installations"`
+	// Overrides are the aliases, functions, and builtins that take
+	// precedence over PATH, in the order the shell consults them
+	Overrides []ShellOverride `json:"overrides,omitempty"`
+}
+
+// ToolReport is everything known about one tool: where it is, what the
+// file is, who installed it, what it reports about itself, and what it
+// shadows
+type ToolReport struct {
+	Name  string `json:"name"`
+	Found bool   `json:"found"`
+	Path  string `json:"path,omitempty"`
+	// SymlinkChain is every link followed from Path, ending at the file
+	// that runs; empty when Path is not a symlink
+	SymlinkChain []string `json:"symlink_chain,omitempty"`
+	ResolvedPath string   `json:"resolved_path,omitempty"`
+	Size         int64    `json:"size,omitempty"`
+	// FileType is the executable format: ELF, Mach-O, Mach-O universal, PE,
+	// script, or unknown
+	FileType    string   `json:"file_type,omitempty"`
+	Arch        []string `json:"arch,omitempty"`
+	Static      bool     `json:"static,omitempty"`
+	Interpreter string   `json:"interpreter,omitempty"`
+
+	PackageName    string `json:"package_name,omitempty"`
+	PackageManager string `json:"package_manager,omitempty"`
+	PackageVersion string `json:"package_version,omitempty"`
+	LinkReason     string `json:"link_reason,omitempty"`
+	// Shim is what the tool runs when it is a version manager's shim
+	Shim *ShimInfo `json:"shim,omitempty"`
+
+	Version string `json:"version,omitempty"`
+	// Usage is the usage line from the tool's --help output
+	Usage    string `json:"usage,omitempty"`
+	HelpText string `json:"help_text,omitempty"`
+	// Help is the flags and subcommands parsed from the help text
+	Help *ToolInfo `json:"help,omitempty"`
+	// ManSummary is the one-line description from the man page's NAME
+	// section
+	ManSummary string `json:"man_summary,omitempty"`
+
+	Broken       bool   `json:"broken,omitempty"`
+	BrokenReason string `json:"broken_reason,omitempty"`
+	AppExecAlias bool   `json:"app_exec_alias,omitempty"`
+	Shortcut     bool   `json:"shortcut,omitempty"`
+	// Shadowed are the installations later in PATH that never run, leaving
+	// out paths to the same file as the one that does
+	Shadowed []InstallationInfo `json:"shadowed,omitempty"`
 }
 
 // ToolCatalog represents a collection of tools for AI agent consumption
 type ToolCatalog struct {
-	TotalTools    int              `json:"total_tools"`
-	TotalPackages int              `json:"total_packages,omitempty"`
-	Paths         []string         `json:"search_paths"`
-	Tools         []Tool           `json:"tools"`
-	Packages      []PackageInfo    `json:"packages,omitempty"`
-	GeneratedAt   string           `json:"generated_at"`
+	TotalTools    int           `json:"total_tools" toml:"total_tools" yaml:"total_tools"`
+	TotalPackages int           `json:"total_packages,omitempty" toml:"total_packages,omitempty" yaml:"total_packages,omitempty"`
+	Paths         []string      `json:"search_paths" toml:"search_paths" yaml:"search_paths"`
+	Tools         []Tool        `json:"tools" toml:"tools" yaml:"tools"`
+	Packages      []PackageInfo `json:"packages,omitempty" toml:"packages,omitempty" yaml:"packages,omitempty"`
+	GeneratedAt   string        `json:"generated_at" toml:"generated_at" yaml:"generated_at"`
+	// Partial is set when the export was stopped by --timeout or Ctrl-C
+	// before every tool was scanned or probed
+	Partial bool `json:"partial,omitempty" toml:"partial,omitempty" yaml:"partial,omitempty"`
+	// OmittedTools counts the tools left out to fit export --max-tokens
+	OmittedTools int `json:"omitted_tools,omitempty" toml:"omitted_tools,omitempty" yaml:"omitted_tools,omitempty"`
+	// EstimatedTokens approximates the size of the catalog in LLM tokens,
+	// set when it was trimmed to fit export --max-tokens
+	EstimatedTokens int `json:"estimated_tokens,omitempty" toml:"estimated_tokens,omitempty" yaml:"estimated_tokens,omitempty"`
 }
 
 // PackageInfo represents a package that provides CLI tools
 type PackageInfo struct {
-	Name     string   `json:"name"`
-	Version  string   `json:"version"`
-	Manager  string   `json:"manager"`
-	Binaries []string `json:"binaries,omitempty"`
-	Location string   `json:"location,omitempty"`
-	Global   bool     `json:"global"`
+	Name     string   `json:"name" toml:"name" yaml:"name"`
+	Version  string   `json:"version" toml:"version" yaml:"version"`
+	Manager  string   `json:"manager" toml:"manager" yaml:"manager"`
+	Binaries []string `json:"binaries,omitempty" toml:"binaries,omitempty" yaml:"binaries,omitempty"`
+	Location string   `json:"location,omitempty" toml:"location,omitempty" yaml:"location,omitempty"`
+	Global   bool     `json:"global" toml:"global" yaml:"global"`
+	Source   string   `json:"source,omitempty" toml:"source,omitempty" yaml:"source,omitempty"`
 }
 
 // ToolInfo provides structured information about a tool for AI agents
 type ToolInfo struct {
-	Name         string            `json:"name"`
-	Location     string            `json:"location"`
-	Version      string            `json:"version,omitempty"`
-	Description  string            `json:"description,omitempty"`
-	Usage        string            `json:"usage,omitempty"`
-	CommonFlags  []Flag            `json:"common_flags,omitempty"`
-	Examples     []string          `json:"examples,omitempty"`
-	Dependencies []string          `json:"dependencies,omitempty"`
-	Metadata     map[string]string `json:"metadata,omitempty"`
+	Name        string `json:"name,omitempty" toml:"name,omitempty" yaml:"name,omitempty"`
+	Location    string `json:"location,omitempty" toml:"location,omitempty" yaml:"location,omitempty"`
+	Version     string `json:"version,omitempty" toml:"version,omitempty" yaml:"version,omitempty"`
+	Description string `json:"description,omitempty" toml:"description,omitempty" yaml:"description,omitempty"`
+	Usage       string `json:"usage,omitempty" toml:"usage,omitempty" yaml:"usage,omitempty"`
+	// CommonFlags are the options the help text lists, in its order
+	CommonFlags []Flag `json:"common_flags,omitempty" toml:"common_flags,omitempty" yaml:"common_flags,omitempty"`
+	// Subcommands are the commands the help text lists, in its order
+	Subcommands  []Subcommand      `json:"subcommands,omitempty" toml:"subcommands,omitempty" yaml:"subcommands,omitempty"`
+	Examples     []string          `json:"examples,omitempty" toml:"examples,omitempty" yaml:"examples,omitempty"`
+	Dependencies []string          `json:"dependencies,omitempty" toml:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty" toml:"metadata,omitempty" yaml:"metadata,omitempty"`
 }
 
 // Flag represents a command-line flag
 type Flag struct {
-	Name        string `json:"name"`
-	Short       string `json:"short,omitempty"`
-	Description string `json:"description"`
-	Default     string `json:"default,omitempty"`
+	// Name is the long form with its dashes ("--output"), or the short form
+	// when the flag has no long one
+	Name string `json:"name" toml:"name" yaml:"name"`
+	// Short is the one-letter form ("-o") of a flag that also has a long one
+	Short string `json:"short,omitempty" toml:"short,omitempty" yaml:"short,omitempty"`
+	// Argument is the placeholder for the flag's value ("FILE", "string"),
+	// empty for a switch
+	Argument    string `json:"argument,omitempty" toml:"argument,omitempty" yaml:"argument,omitempty"`
+	Description string `json:"description" toml:"description" yaml:"description"`
+	Default     string `json:"default,omitempty" toml:"default,omitempty" yaml:"default,omitempty"`
+}
+
+// Subcommand is a command a tool lists in its help text. Usage, Flags, and
+// Subcommands are only filled in when its own help was walked (see
+// export --help-depth).
+type Subcommand struct {
+	Name        string       `json:"name" toml:"name" yaml:"name"`
+	Description string       `json:"description,omitempty" toml:"description,omitempty" yaml:"description,omitempty"`
+	Usage       string       `json:"usage,omitempty" toml:"usage,omitempty" yaml:"usage,omitempty"`
+	Flags       []Flag       `json:"flags,omitempty" toml:"flags,omitempty" yaml:"flags,omitempty"`
+	Subcommands []Subcommand `json:"subcommands,omitempty" toml:"subcommands,omitempty" yaml:"subcommands,omitempty"`
 }
@@ -0,0 +1,124 @@
+package outdated
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/cli-ai-org/cli/internal/packages"
+)
+
+// npmRegistry is the public npm registry
+const npmRegistry = "https://registry.npmjs.org/"
+
+// npmAbbreviated asks the registry for the abbreviated package document,
+// which still carries per-version deprecation messages
+const npmAbbreviated = "application/vnd.npm.install-v1+json"
+
+// NPMStatus is a globally installed npm package that is deprecated, whose
+// installed version is no longer published, or that its registry does not
+// know
+type NPMStatus struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Registry is the registry the package was checked against
+	Registry string `json:"registry,omitempty"`
+	// Deprecated is the maintainer's deprecation message for the installed
+	// version
+	Deprecated string `json:"deprecated,omitempty"`
+	// Unpublished is set when the package is on the registry but the
+	// installed version, or every version, has been unpublished
+	Unpublished bool `json:"unpublished,omitempty"`
+	// NotFound is set when the registry does not know the package at all,
+	// which is as likely to mean it came from elsewhere as that it was
+	// removed
+	NotFound bool   `json:"not_found,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	Latest   string `json:"latest,omitempty"`
+}
+
+// npmRegistryFor returns the registry of an npm package, loading the npm
+// configuration on first use; see npmConfig.registryFor
+func (c *Checker) npmRegistryFor(name, resolved string) (string, bool) {
+	if c.npmConfig == nil {
+		c.npmConfig = loadNPMConfig()
+	}
+	return c.npmConfig.registryFor(name, resolved)
+}
+
+// CheckNPMStatus queries the registry each npm package was installed from,
+// per its resolved URL or the registry and scope settings in .npmrc, and
+// returns those that are deprecated, unpublished, or not found. Packages
+// not installed from a registry (npm link, git) are skipped. If a registry
+// cannot be reached, checking stops at the first failure and the statuses
+// found so far are returned with an error, so callers can carry on offline.
+func (c *Checker) CheckNPMStatus(pkgs []packages.Package) ([]NPMStatus, error) {
+	var statuses []NPMStatus
+	for _, pkg := range pkgs {
+		if pkg.Manager != packages.NPM {
+			continue
+		}
+		registry, ok := c.npmRegistryFor(pkg.Name, pkg.Source)
+		if !ok {
+			continue
+		}
+
+		var doc struct {
+			DistTags map[string]string `json:"dist-tags"`
+			Versions map[string]struct {
+				Deprecated string `json:"deprecated"`
+			} `json:"versions"`
+		}
+		err := c.getJSONWithAccept(registry+url.PathEscape(pkg.Name), npmAbbreviated, &doc)
+
+		var status *statusError
+		switch {
+		case errors.As(err, &status) && status.code == http.StatusNotFound:
+			statuses = append(statuses, NPMStatus{
+				Name:     pkg.Name,
+				Version:  pkg.Version,
+				Registry: registry,
+				NotFound: true,
+				Reason:   "package not found on " + registry + " (installed from another registry or a private one, or removed)",
+			})
+			continue
+		case err != nil && status != nil:
+			// One bad response says nothing about the other packages
+			continue
+		case err != nil:
+			return statuses, fmt.Errorf("npm registry unreachable: %w", err)
+		}
+
+		latest := doc.DistTags["latest"]
+		version, published := doc.Versions[pkg.Version]
+		switch {
+		case len(doc.Versions) == 0:
+			statuses = append(statuses, NPMStatus{
+				Name:        pkg.Name,
+				Version:     pkg.Version,
+				Registry:    registry,
+				Unpublished: true,
+				Reason:      "every version of the package has been unpublished",
+			})
+		case !published:
+			statuses = append(statuses, NPMStatus{
+				Name:        pkg.Name,
+				Version:     pkg.Version,
+				Registry:    registry,
+				Unpublished: true,
+				Reason:      "installed version has been unpublished",
+				Latest:      latest,
+			})
+		case version.Deprecated != "":
+			statuses = append(statuses, NPMStatus{
+				Name:       pkg.Name,
+				Version:    pkg.Version,
+				Registry:   registry,
+				Deprecated: version.Deprecated,
+				Latest:     latest,
+			})
+		}
+	}
+	return statuses, nil
+}
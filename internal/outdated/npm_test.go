@@ -0,0 +1,107 @@
+package outdated
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cli-ai-org/cli/internal/packages"
+)
+
+// fakeRegistry serves abbreviated package documents for the given packages,
+// keyed by escaped name, and 404 for the rest
+func fakeRegistry(t *testing.T, docs map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc, ok := docs[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, doc)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writeNpmrc points npm's user config at a temporary .npmrc
+func writeNpmrc(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".npmrc")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("npm_config_userconfig", path)
+	t.Setenv("npm_config_registry", "")
+	t.Setenv("NPM_CONFIG_REGISTRY", "")
+}
+
+func TestCheckNPMStatus(t *testing.T) {
+	public := fakeRegistry(t, map[string]string{
+		"/gone-version": `{"dist-tags":{"latest":"2.0.0"},"versions":{"2.0.0":{}}}`,
+		"/old":          `{"dist-tags":{"latest":"1.0.0"},"versions":{"1.0.0":{"deprecated":"use new"}}}`,
+		"/fine":         `{"dist-tags":{"latest":"1.0.0"},"versions":{"1.0.0":{}}}`,
+	})
+	private := fakeRegistry(t, map[string]string{
+		"/@corp%2Ftool": `{"dist-tags":{"latest":"1.0.0"},"versions":{"1.0.0":{}}}`,
+	})
+	writeNpmrc(t, "registry="+public.URL+"\n@corp:registry=${CORP_REGISTRY}\n")
+	t.Setenv("CORP_REGISTRY", private.URL)
+
+	pkgs := []packages.Package{
+		{Name: "gone-version", Version: "1.0.0", Manager: packages.NPM},
+		{Name: "old", Version: "1.0.0", Manager: packages.NPM},
+		{Name: "fine", Version: "1.0.0", Manager: packages.NPM},
+		{Name: "missing", Version: "1.0.0", Manager: packages.NPM},
+		// Found on the scope's registry, not the default one
+		{Name: "@corp/tool", Version: "1.0.0", Manager: packages.NPM},
+		// npm link and git installs are not looked up
+		{Name: "linked", Version: "1.0.0", Manager: packages.NPM, Source: "file:../../src/linked"},
+		{Name: "from-git", Version: "1.0.0", Manager: packages.NPM, Source: "git+ssh://git@example.com/from-git.git"},
+	}
+	statuses, err := NewChecker().CheckNPMStatus(pkgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]NPMStatus)
+	for _, status := range statuses {
+		got[status.Name] = status
+	}
+	if len(got) != 3 {
+		t.Errorf("got statuses for %v, want gone-version, old, and missing", statuses)
+	}
+	if s := got["gone-version"]; !s.Unpublished || s.NotFound || s.Latest != "2.0.0" {
+		t.Errorf("gone-version = %+v, want unpublished with latest 2.0.0", s)
+	}
+	if s := got["old"]; s.Deprecated != "use new" || s.Unpublished {
+		t.Errorf("old = %+v, want deprecated", s)
+	}
+	if s := got["missing"]; !s.NotFound || s.Unpublished {
+		t.Errorf("missing = %+v, want not found, not unpublished", s)
+	}
+}
+
+func TestRegistryForResolvedURL(t *testing.T) {
+	config := &npmConfig{registry: npmRegistry, scopes: map[string]string{"@corp": "https://npm.corp.example/"}}
+	tests := []struct {
+		name, resolved string
+		want           string
+		ok             bool
+	}{
+		{"left-pad", "", npmRegistry, true},
+		{"@corp/tool", "", "https://npm.corp.example/", true},
+		{"tool", "https://mirror.example/npm/tool/-/tool-1.0.0.tgz", "https://mirror.example/npm/", true},
+		{"@scope/x", "https://registry.npmjs.org/@scope/x/-/x-1.0.0.tgz", npmRegistry, true},
+		{"linked", "file:../linked", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := config.registryFor(tt.name, tt.resolved)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("registryFor(%s, %s) = %q, %v; want %q, %v", tt.name, tt.resolved, got, ok, tt.want, tt.ok)
+		}
+	}
+}
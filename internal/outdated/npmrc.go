@@ -0,0 +1,107 @@
+package outdated
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// npmConfig is the registry configuration npm uses for global installs: the
+// default registry and the registries of scopes (@scope:registry=...)
+type npmConfig struct {
+	registry string
+	scopes   map[string]string
+}
+
+// npmEnvVar matches the ${VAR} references npm expands in .npmrc values
+var npmEnvVar = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// loadNPMConfig reads the user's .npmrc (or the file npm_config_userconfig
+// names), then the npm_config_registry environment variable, which npm
+// lets override it. Without either, packages come from the public registry.
+func loadNPMConfig() *npmConfig {
+	config := &npmConfig{registry: npmRegistry, scopes: make(map[string]string)}
+
+	path := npmEnv("userconfig")
+	if path == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, ".npmrc")
+		}
+	}
+	if file, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+				continue
+			}
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			key = strings.TrimSpace(key)
+			value = strings.Trim(strings.TrimSpace(value), `"'`)
+			value = npmEnvVar.ReplaceAllStringFunc(value, func(ref string) string {
+				return os.Getenv(ref[2 : len(ref)-1])
+			})
+			switch {
+			case key == "registry":
+				config.registry = value
+			case strings.HasPrefix(key, "@") && strings.HasSuffix(key, ":registry"):
+				config.scopes[strings.TrimSuffix(key, ":registry")] = value
+			}
+		}
+		file.Close()
+	}
+
+	if registry := npmEnv("registry"); registry != "" {
+		config.registry = registry
+	}
+	config.registry = withSlash(config.registry)
+	for scope, registry := range config.scopes {
+		config.scopes[scope] = withSlash(registry)
+	}
+	return config
+}
+
+// npmEnv returns an npm setting from the environment, which npm reads in
+// either case (npm_config_registry, NPM_CONFIG_REGISTRY)
+func npmEnv(key string) string {
+	if value := os.Getenv("npm_config_" + key); value != "" {
+		return value
+	}
+	return os.Getenv("NPM_CONFIG_" + strings.ToUpper(key))
+}
+
+// registryFor returns the registry a package was installed from: the one
+// its resolved tarball URL points into, else the registry configured for
+// its scope, else the default. ok is false for packages that did not come
+// from a registry, such as `npm link`ed directories and git dependencies.
+func (c *npmConfig) registryFor(name, resolved string) (registry string, ok bool) {
+	switch {
+	case strings.HasPrefix(resolved, "http://"), strings.HasPrefix(resolved, "https://"):
+		// Tarballs live at <registry>/<name>/-/<file>.tgz
+		if i := strings.Index(resolved, "/"+name+"/-/"); i >= 0 {
+			return resolved[:i+1], true
+		}
+	case resolved != "":
+		// file:, link:, git+https:, github:, and the like
+		return "", false
+	}
+	if scope, _, ok := strings.Cut(name, "/"); ok && strings.HasPrefix(scope, "@") {
+		if registry, ok := c.scopes[scope]; ok {
+			return registry, true
+		}
+	}
+	return c.registry, true
+}
+
+// withSlash ends a registry URL with "/", so package names can be appended
+func withSlash(registry string) string {
+	if registry == "" || strings.HasSuffix(registry, "/") {
+		return registry
+	}
+	return registry + "/"
+}
@@ -0,0 +1,286 @@
+package outdated
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/packages"
+)
+
+// Result describes a package with a newer version available
+type Result struct {
+	Name    string `json:"name"`
+	Manager string `json:"manager"`
+	Current string `json:"current"`
+	Latest  string `json:"latest"`
+	// Source is where the latest version came from: the manager's native
+	// outdated command or a registry API
+	Source string `json:"source"`
+}
+
+// Checker finds outdated packages, preferring each manager's native
+// outdated reporting over per-package registry queries
+type Checker struct {
+	timeout time.Duration
+	client  *http.Client
+	// ctx cancels running manager commands and registry requests
+	ctx context.Context
+	// registryOnly skips the managers' own outdated commands
+	registryOnly bool
+	// npmConfig holds the npm registry settings, loaded on first use
+	npmConfig *npmConfig
+}
+
+// NewChecker creates a new outdated checker
+func NewChecker() *Checker {
+	return &Checker{
+		timeout: 60 * time.Second,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		ctx:     context.Background(),
+	}
+}
+
+// SetContext makes running manager commands and registry requests stop
+// when ctx is cancelled
+func (c *Checker) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// SetRegistryOnly makes Check query the registries (PyPI, the npm
+// registry, crates.io, RubyGems, and the Homebrew API) for every package
+// instead of running the managers' own outdated commands
+func (c *Checker) SetRegistryOnly(registryOnly bool) {
+	c.registryOnly = registryOnly
+}
+
+// Managers returns the package managers Check supports
+func Managers() []packages.PackageManager {
+	return []packages.PackageManager{packages.Pip, packages.NPM, packages.Brew, packages.Cargo, packages.Gem}
+}
+
+// Check returns the outdated packages for a manager. pkgs are the detected
+// packages of that manager, looked up on the manager's registry when it has
+// no outdated command of its own (cargo), when that command fails (for
+// example because the manager is not on PATH), or with SetRegistryOnly.
+func (c *Checker) Check(manager packages.PackageManager, pkgs []packages.Package) ([]Result, error) {
+	var native func() ([]Result, error)
+	switch manager {
+	case packages.Pip:
+		native = c.checkPip
+	case packages.NPM:
+		native = c.checkNPM
+	case packages.Brew:
+		native = c.checkBrew
+	case packages.Gem:
+		native = c.checkGem
+	case packages.Cargo:
+	default:
+		return nil, errUnsupported(manager)
+	}
+
+	if native != nil && !c.registryOnly {
+		if results, err := native(); err == nil || c.ctx.Err() != nil {
+			return results, err
+		}
+	}
+	return c.checkRegistry(manager, pkgs)
+}
+
+func errUnsupported(manager packages.PackageManager) error {
+	return fmt.Errorf("outdated check not supported for %s", manager)
+}
+
+// checkPip uses `pip list --outdated`, which reports current and latest in one call
+func (c *Checker) checkPip() ([]Result, error) {
+	output, err := c.run("pip", "list", "--outdated", "--format=json")
+	if err != nil {
+		output, err = c.run("pip3", "list", "--outdated", "--format=json")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var entries []struct {
+		Name          string `json:"name"`
+		Version       string `json:"version"`
+		LatestVersion string `json:"latest_version"`
+	}
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, e := range entries {
+		results = append(results, Result{
+			Name:    e.Name,
+			Manager: string(packages.Pip),
+			Current: e.Version,
+			Latest:  e.LatestVersion,
+			Source:  "pip",
+		})
+	}
+	return results, nil
+}
+
+// checkNPM uses `npm outdated -g`
+func (c *Checker) checkNPM() ([]Result, error) {
+	output, err := c.run("npm", "outdated", "-g", "--json")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries map[string]struct {
+		Current string `json:"current"`
+		Latest  string `json:"latest"`
+	}
+	if len(output) > 0 {
+		if err := json.Unmarshal(output, &entries); err != nil {
+			return nil, err
+		}
+	}
+
+	var results []Result
+	for name, e := range entries {
+		results = append(results, Result{
+			Name:    name,
+			Manager: string(packages.NPM),
+			Current: e.Current,
+			Latest:  e.Latest,
+			Source:  "npm",
+		})
+	}
+	return results, nil
+}
+
+// checkBrew uses `brew outdated --json=v2`
+func (c *Checker) checkBrew() ([]Result, error) {
+	output, err := c.run("brew", "outdated", "--json=v2")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries struct {
+		Formulae []struct {
+			Name              string   `json:"name"`
+			InstalledVersions []string `json:"installed_versions"`
+			CurrentVersion    string   `json:"current_version"`
+		} `json:"formulae"`
+		Casks []struct {
+			Name              string   `json:"name"`
+			InstalledVersions []string `json:"installed_versions"`
+			CurrentVersion    string   `json:"current_version"`
+		} `json:"casks"`
+	}
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	add := func(name string, installed []string, latest string) {
+		current := ""
+		if len(installed) > 0 {
+			current = installed[len(installed)-1]
+		}
+		results = append(results, Result{
+			Name:    name,
+			Manager: string(packages.Brew),
+			Current: current,
+			Latest:  latest,
+			Source:  "brew",
+		})
+	}
+	for _, f := range entries.Formulae {
+		add(f.Name, f.InstalledVersions, f.CurrentVersion)
+	}
+	for _, f := range entries.Casks {
+		add(f.Name, f.InstalledVersions, f.CurrentVersion)
+	}
+	return results, nil
+}
+
+// checkGem uses `gem outdated`, which prints "name (current < latest)"
+func (c *Checker) checkGem() ([]Result, error) {
+	output, err := c.run("gem", "outdated")
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, line := range strings.Split(string(output), "\n") {
+		name, versions, ok := strings.Cut(strings.TrimSpace(line), " (")
+		if !ok {
+			continue
+		}
+		current, latest, ok := strings.Cut(strings.TrimSuffix(versions, ")"), " < ")
+		if !ok {
+			continue
+		}
+		results = append(results, Result{
+			Name:    name,
+			Manager: string(packages.Gem),
+			Current: current,
+			Latest:  latest,
+			Source:  "gem",
+		})
+	}
+	return results, nil
+}
+
+// run executes a manager command with the checker's timeout. Outdated
+// commands like `npm outdated` exit non-zero when they find something, so
+// output is returned whenever the command produced any.
+func (c *Checker) run(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, name, args...).Output()
+	var exitErr *exec.ExitError
+	if err != nil && errors.As(err, &exitErr) && len(output) > 0 && ctx.Err() == nil {
+		return output, nil
+	}
+	return output, err
+}
+
+// getJSON fetches a URL and decodes its JSON body
+func (c *Checker) getJSON(url string, v interface{}) error {
+	return c.getJSONWithAccept(url, "application/json", v)
+}
+
+// getJSONWithAccept is getJSON with an explicit Accept header, for
+// registries that serve smaller documents to some clients
+func (c *Checker) getJSONWithAccept(url, accept string, v interface{}) error {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &statusError{url: url, status: resp.Status, code: resp.StatusCode}
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// statusError is a registry response other than 200 OK
+type statusError struct {
+	url    string
+	status string
+	code   int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.url, e.status)
+}
@@ -0,0 +1,194 @@
+package outdated
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/semver"
+)
+
+// Registry APIs that report a package's latest version
+const (
+	pypiAPI      = "https://pypi.org/pypi/"
+	cratesAPI    = "https://crates.io/api/v1/crates/"
+	rubyGemsAPI  = "https://rubygems.org/api/v1/versions/"
+	brewFormulae = "https://formulae.brew.sh/api/formula/"
+	brewCasks    = "https://formulae.brew.sh/api/cask/"
+)
+
+// userAgent identifies registry requests; crates.io rejects requests
+// without one
+const userAgent = "cli (https://github.com/cli-ai-org/cli)"
+
+// registryWorkers bounds how many registry requests are in flight at once
+const registryWorkers = 8
+
+// errNoVersion is a registry document without a usable latest version
+var errNoVersion = errors.New("no latest version in registry response")
+
+// checkRegistry asks the manager's registry for the latest version of each
+// package and returns those with a newer one. Packages the registry does
+// not know, such as ones installed from elsewhere, are skipped. If the
+// registry cannot be reached at all, the error is returned.
+func (c *Checker) checkRegistry(manager packages.PackageManager, pkgs []packages.Package) ([]Result, error) {
+	var latest func(name string) (string, error)
+	var source string
+	switch manager {
+	case packages.Pip:
+		latest, source = c.latestPyPI, "pypi"
+	case packages.NPM:
+		latest, source = c.latestNPM, "npm-registry"
+	case packages.Cargo:
+		latest, source = c.latestCrate, "crates.io"
+	case packages.Gem:
+		latest, source = c.latestGem, "rubygems"
+	case packages.Brew:
+		latest, source = c.latestBrew, "brew-api"
+	default:
+		return nil, errUnsupported(manager)
+	}
+
+	// Each package's answer goes into its own slot so results keep the
+	// packages' order
+	versions := make([]string, len(pkgs))
+	errs := make([]error, len(pkgs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < registryWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if c.ctx.Err() != nil {
+					errs[index] = c.ctx.Err()
+					continue
+				}
+				versions[index], errs[index] = latest(pkgs[index].Name)
+			}
+		}()
+	}
+	for index := range pkgs {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+
+	var results []Result
+	reached := false
+	var unreachable error
+	for index, pkg := range pkgs {
+		var status *statusError
+		switch err := errs[index]; {
+		case err == nil:
+			reached = true
+		case errors.As(err, &status), errors.Is(err, errNoVersion):
+			// The registry answered, just not for this package
+			reached = true
+			continue
+		default:
+			unreachable = err
+			continue
+		}
+		if semver.Newer(versions[index], pkg.Version) {
+			results = append(results, Result{
+				Name:    pkg.Name,
+				Manager: string(manager),
+				Current: pkg.Version,
+				Latest:  versions[index],
+				Source:  source,
+			})
+		}
+	}
+	if !reached && unreachable != nil {
+		return nil, unreachable
+	}
+	return results, nil
+}
+
+func (c *Checker) latestPyPI(name string) (string, error) {
+	var doc struct {
+		Info struct {
+			Version string `json:"version"`
+		} `json:"info"`
+	}
+	if err := c.getJSON(pypiAPI+url.PathEscape(name)+"/json", &doc); err != nil {
+		return "", err
+	}
+	return nonEmpty(doc.Info.Version)
+}
+
+func (c *Checker) latestNPM(name string) (string, error) {
+	var doc struct {
+		DistTags map[string]string `json:"dist-tags"`
+	}
+	registry, _ := c.npmRegistryFor(name, "")
+	if err := c.getJSONWithAccept(registry+url.PathEscape(name), npmAbbreviated, &doc); err != nil {
+		return "", err
+	}
+	return nonEmpty(doc.DistTags["latest"])
+}
+
+func (c *Checker) latestCrate(name string) (string, error) {
+	var doc struct {
+		Crate struct {
+			MaxStableVersion string `json:"max_stable_version"`
+			MaxVersion       string `json:"max_version"`
+		} `json:"crate"`
+	}
+	if err := c.getJSON(cratesAPI+url.PathEscape(name), &doc); err != nil {
+		return "", err
+	}
+	if doc.Crate.MaxStableVersion != "" {
+		return doc.Crate.MaxStableVersion, nil
+	}
+	return nonEmpty(doc.Crate.MaxVersion)
+}
+
+func (c *Checker) latestGem(name string) (string, error) {
+	var doc struct {
+		Version string `json:"version"`
+	}
+	if err := c.getJSON(rubyGemsAPI+url.PathEscape(name)+"/latest.json", &doc); err != nil {
+		return "", err
+	}
+	// RubyGems answers "unknown" for gems it does not have
+	if doc.Version == "unknown" {
+		return "", errNoVersion
+	}
+	return nonEmpty(doc.Version)
+}
+
+// latestBrew looks the name up as a formula, then as a cask
+func (c *Checker) latestBrew(name string) (string, error) {
+	var formula struct {
+		Versions struct {
+			Stable string `json:"stable"`
+		} `json:"versions"`
+	}
+	err := c.getJSON(brewFormulae+url.PathEscape(name)+".json", &formula)
+	var status *statusError
+	if err == nil {
+		return nonEmpty(formula.Versions.Stable)
+	}
+	if !errors.As(err, &status) || status.code != http.StatusNotFound {
+		return "", err
+	}
+
+	var cask struct {
+		Version string `json:"version"`
+	}
+	if err := c.getJSON(brewCasks+url.PathEscape(name)+".json", &cask); err != nil {
+		return "", err
+	}
+	return nonEmpty(cask.Version)
+}
+
+func nonEmpty(version string) (string, error) {
+	if version == "" {
+		return "", errNoVersion
+	}
+	return version, nil
+}
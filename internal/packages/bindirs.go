@@ -0,0 +1,149 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// BinDirs returns the existing directories where each package manager
+// installs executables on this machine, based on each manager's conventions
+// and its environment overrides
+func BinDirs() map[PackageManager][]string {
+	home, _ := os.UserHomeDir()
+	dirs := make(map[PackageManager][]string)
+
+	add := func(manager PackageManager, patterns ...string) {
+		for _, pattern := range patterns {
+			matches, _ := filepath.Glob(pattern)
+			for _, match := range matches {
+				if info, err := os.Stat(match); err == nil && info.IsDir() && !containsString(dirs[manager], match) {
+					dirs[manager] = append(dirs[manager], match)
+				}
+			}
+		}
+	}
+
+	// Homebrew: Apple Silicon, Intel (only when /usr/local is a brew prefix), Linuxbrew
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		add(Brew, filepath.Join(prefix, "bin"), filepath.Join(prefix, "sbin"))
+	}
+	add(Brew, "/opt/homebrew/bin", "/opt/homebrew/sbin",
+		"/home/linuxbrew/.linuxbrew/bin", filepath.Join(home, ".linuxbrew", "bin"))
+	if _, err := os.Stat("/usr/local/Cellar"); err == nil {
+		add(Brew, "/usr/local/bin", "/usr/local/sbin")
+	}
+
+	add(MacPorts, macPortsPrefix+"/bin", macPortsPrefix+"/sbin")
+	add(Nix, nixProfileBins()...)
+	add(Snap, snapBinDirs...)
+	for _, root := range flatpakRoots() {
+		add(Flatpak, filepath.Join(root, "exports", "bin"))
+	}
+
+	add(Cargo, filepath.Join(cargoInstallRoot(), "bin"))
+	if cargoHome := os.Getenv("CARGO_HOME"); cargoHome != "" {
+		add(Cargo, filepath.Join(cargoHome, "bin"))
+	}
+	add(Cargo, filepath.Join(home, ".cargo", "bin"))
+
+	if gobin := os.Getenv("GOBIN"); gobin != "" {
+		add(Go, gobin)
+	}
+	for _, gopath := range filepath.SplitList(os.Getenv("GOPATH")) {
+		add(Go, filepath.Join(gopath, "bin"))
+	}
+	add(Go, filepath.Join(home, "go", "bin"))
+
+	add(Deno, denoBinDirs()...)
+	add(Dotnet, dotnetToolsDir())
+	add(Composer, composerBinDirs()...)
+
+	if prefix := os.Getenv("npm_config_prefix"); prefix != "" {
+		add(NPM, filepath.Join(prefix, "bin"))
+	}
+	if nvmBin := os.Getenv("NVM_BIN"); nvmBin != "" {
+		add(NPM, nvmBin)
+	}
+	add(NPM, filepath.Join(home, ".npm-global", "bin"),
+		filepath.Join(home, ".nvm", "versions", "node", "*", "bin"))
+
+	// pipx shares ~/.local/bin with pip by default; only a custom directory
+	// is owned by pipx alone
+	if pipxBin := os.Getenv("PIPX_BIN_DIR"); pipxBin != "" {
+		add(Pipx, pipxBin)
+	}
+	// So do uv and poetry, unless told otherwise
+	if uvBin := os.Getenv("UV_TOOL_BIN_DIR"); uvBin != "" {
+		add(Uv, uvBin)
+	}
+	if os.Getenv("POETRY_HOME") != "" {
+		add(Poetry, poetryBinDir())
+	}
+	add(Pip, filepath.Join(home, ".local", "bin"),
+		filepath.Join(home, "Library", "Python", "*", "bin"))
+
+	if gemHome := os.Getenv("GEM_HOME"); gemHome != "" {
+		add(Gem, filepath.Join(gemHome, "bin"))
+	}
+	add(Gem, filepath.Join(home, ".gem", "ruby", "*", "bin"),
+		filepath.Join(home, ".local", "share", "gem", "ruby", "*", "bin"))
+
+	add(Scoop, filepath.Join(scoopRoot(), "shims"))
+	if root := scoopGlobalRoot(); root != "" {
+		add(Scoop, filepath.Join(root, "shims"))
+	}
+	if root := chocolateyRoot(); root != "" {
+		add(Chocolatey, filepath.Join(root, "bin"))
+	}
+	if links := wingetLinks(); links != "" {
+		add(Winget, links)
+	}
+
+	return dirs
+}
+
+// WithPackageDirs returns binDirs extended with the directories the
+// detected packages' executables are in, from each package's Location, so
+// a manager owns the directories it actually installed into as well as
+// its conventional ones
+func WithPackageDirs(binDirs map[PackageManager][]string, pkgs []Package) map[PackageManager][]string {
+	dirs := make(map[PackageManager][]string, len(binDirs))
+	for manager, list := range binDirs {
+		dirs[manager] = append([]string(nil), list...)
+	}
+	for _, pkg := range pkgs {
+		if dir := pkg.binDir(); dir != "" && !containsString(dirs[pkg.Manager], dir) {
+			dirs[pkg.Manager] = append(dirs[pkg.Manager], dir)
+		}
+	}
+	return dirs
+}
+
+// binDir returns the directory the package's executables are run from. For
+// most managers that is its Location; a Homebrew keg's are linked into the
+// bin directory of the prefix holding the Cellar, and MacPorts' Location is
+// its prefix. Nix store paths are reached through profiles, which BinDirs
+// already lists.
+func (p Package) binDir() string {
+	if p.Location == "" {
+		return ""
+	}
+	switch p.Manager {
+	case Brew:
+		return filepath.Join(filepath.Dir(filepath.Dir(p.Location)), "bin")
+	case MacPorts:
+		return filepath.Join(p.Location, "bin")
+	case Nix:
+		return ""
+	}
+	return filepath.Clean(p.Location)
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
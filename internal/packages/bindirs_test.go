@@ -0,0 +1,33 @@
+package packages
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWithPackageDirs(t *testing.T) {
+	root := t.TempDir()
+	cargoBin := filepath.Join(root, ".cargo", "bin")
+	binDirs := map[PackageManager][]string{Cargo: {cargoBin}}
+	pkgs := []Package{
+		{Name: "ripgrep", Manager: Cargo, Location: cargoBin},
+		{Name: "jq", Manager: Brew, Location: filepath.Join(root, "homebrew", "Cellar", "jq")},
+		{Name: "wget", Manager: MacPorts, Location: filepath.Join(root, "opt", "local")},
+		{Name: "hello", Manager: Nix, Location: filepath.Join(root, "nix", "store", "abc-hello-2.12")},
+		{Name: "black", Manager: Pip},
+	}
+
+	got := WithPackageDirs(binDirs, pkgs)
+	want := map[PackageManager][]string{
+		Cargo:    {cargoBin},
+		Brew:     {filepath.Join(root, "homebrew", "bin")},
+		MacPorts: {filepath.Join(root, "opt", "local", "bin")},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if len(binDirs) != 1 {
+		t.Errorf("binDirs was modified: %v", binDirs)
+	}
+}
@@ -0,0 +1,334 @@
+package packages
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// brewInfo is the subset of `brew info --json=v2 --installed` we use
+type brewInfo struct {
+	Formulae []brewFormula `json:"formulae"`
+	Casks    []brewCask    `json:"casks"`
+}
+
+type brewFormula struct {
+	Name         string   `json:"name"`
+	FullName     string   `json:"full_name"`
+	LinkedKeg    *string  `json:"linked_keg"`
+	KegOnly      bool     `json:"keg_only"`
+	Dependencies []string `json:"dependencies"`
+	Installed    []struct {
+		Version               string `json:"version"`
+		InstalledAsDependency bool   `json:"installed_as_dependency"`
+		InstalledOnRequest    bool   `json:"installed_on_request"`
+		RuntimeDependencies   []struct {
+			FullName string `json:"full_name"`
+		} `json:"runtime_dependencies"`
+	} `json:"installed"`
+}
+
+type brewCask struct {
+	Token     string  `json:"token"`
+	Installed *string `json:"installed"`
+	DependsOn struct {
+		Formula []string `json:"formula"`
+	} `json:"depends_on"`
+}
+
+// loadBrewInfo runs `brew info --json=v2 --installed` once and caches the
+// parsed result on the detector for reuse by later checks
+func (d *Detector) loadBrewInfo() (*brewInfo, error) {
+	if d.brewInfo != nil {
+		return d.brewInfo, nil
+	}
+
+	cmd := d.command("brew", "info", "--json=v2", "--installed")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var info brewInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, err
+	}
+
+	d.brewInfo = &info
+	return d.brewInfo, nil
+}
+
+// brewPackages converts brew's JSON info into packages. The version is the
+// linked keg when there is one, since that is the version on PATH, otherwise
+// the most recently installed version. Formulae are located in cellar.
+func brewPackages(info *brewInfo, cellar string) []Package {
+	var packages []Package
+
+	for _, formula := range info.Formulae {
+		var versions []string
+		for _, installed := range formula.Installed {
+			versions = append(versions, installed.Version)
+		}
+		if len(versions) == 0 {
+			continue
+		}
+
+		// The latest install records how the formula came to be installed
+		// and the runtime dependencies it was actually built against
+		latest := formula.Installed[len(formula.Installed)-1]
+		deps := formula.Dependencies
+		if len(latest.RuntimeDependencies) > 0 {
+			deps = nil
+			for _, dep := range latest.RuntimeDependencies {
+				deps = append(deps, dep.FullName)
+			}
+		}
+
+		pkg := Package{
+			Name:                  formula.Name,
+			Version:               versions[len(versions)-1],
+			Manager:               Brew,
+			Global:                true,
+			InstalledVersions:     versions,
+			KegOnly:               formula.KegOnly,
+			InstalledAsDependency: latest.InstalledAsDependency && !latest.InstalledOnRequest,
+			Dependencies:          deps,
+		}
+		if formula.LinkedKeg != nil && *formula.LinkedKeg != "" {
+			pkg.Version = *formula.LinkedKeg
+			pkg.LinkedKeg = *formula.LinkedKeg
+		}
+		if cellar != "" {
+			pkg.Location = filepath.Join(cellar, formula.Name)
+		}
+		packages = append(packages, pkg)
+	}
+
+	for _, cask := range info.Casks {
+		if cask.Installed == nil {
+			continue
+		}
+		packages = append(packages, Package{
+			Name:    cask.Token,
+			Version: *cask.Installed,
+			Manager: Brew,
+			Global:  true,
+			// Formulae a cask depends on are kept by brew autoremove
+			Dependencies: cask.DependsOn.Formula,
+		})
+	}
+
+	return packages
+}
+
+// UnlinkedKeg is a Homebrew formula that is installed but not linked into
+// the brew prefix (after `brew unlink`, or an interrupted link), so its
+// tools are not on PATH even though brew lists it as installed
+type UnlinkedKeg struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Binaries are the executables the keg would link into the brew bin dir
+	Binaries []string `json:"binaries"`
+}
+
+// LinkCommand returns the command that links the keg
+func (k UnlinkedKeg) LinkCommand() string {
+	return "brew link " + k.Name
+}
+
+// UnlinkedKegs finds brew formulae that are installed and would provide CLI
+// tools but are not linked. Keg-only formulae are never linked by design and
+// are skipped, as are packages detected without brew's JSON metadata, which
+// does not record linking.
+func UnlinkedKegs(pkgs []Package) []UnlinkedKeg {
+	var kegs []UnlinkedKeg
+	for _, pkg := range pkgs {
+		if pkg.Manager != Brew || pkg.LinkedKeg != "" || pkg.KegOnly || len(pkg.InstalledVersions) == 0 || pkg.Location == "" {
+			continue
+		}
+
+		binaries := kegBinaries(filepath.Join(pkg.Location, pkg.Version))
+		if len(binaries) == 0 {
+			continue
+		}
+		kegs = append(kegs, UnlinkedKeg{Name: pkg.Name, Version: pkg.Version, Binaries: binaries})
+	}
+	return kegs
+}
+
+// BrewOrphans returns the formulae `brew autoremove` would remove, as
+// `brew autoremove --dry-run` lists them, so the report matches what the
+// suggested command does. When brew cannot be run, they are worked out from
+// pkgs instead (see OrphanedFormulae).
+func BrewOrphans(ctx context.Context, pkgs []Package) []string {
+	hasBrew := false
+	for _, pkg := range pkgs {
+		if pkg.Manager == Brew {
+			hasBrew = true
+			break
+		}
+	}
+	if !hasBrew {
+		return nil
+	}
+
+	output, err := exec.CommandContext(ctx, "brew", "autoremove", "--dry-run").Output()
+	if err != nil {
+		return OrphanedFormulae(pkgs)
+	}
+	return parseAutoremoveDryRun(string(output))
+}
+
+// parseAutoremoveDryRun returns the formulae listed under brew's "Would
+// autoremove N unneeded formulae:" heading, one per line
+func parseAutoremoveDryRun(output string) []string {
+	var orphans []string
+	listing := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "==>") {
+			listing = strings.Contains(line, "Would autoremove")
+			continue
+		}
+		if listing && line != "" {
+			for _, name := range strings.Fields(line) {
+				orphans = append(orphans, brewShortName(name))
+			}
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+// OrphanedFormulae finds brew formulae that were installed only as a
+// dependency and are no longer needed by any formula installed on request
+// or by any installed cask, directly or transitively. These are what `brew
+// autoremove` would remove. Packages detected without brew's JSON metadata
+// carry no dependency data, so nothing is reported for them.
+func OrphanedFormulae(pkgs []Package) []string {
+	formulae := make(map[string]Package)
+	var casks []Package
+	for _, pkg := range pkgs {
+		if pkg.Manager != Brew {
+			continue
+		}
+		// Only formulae record their installed versions
+		if len(pkg.InstalledVersions) > 0 {
+			formulae[pkg.Name] = pkg
+		} else {
+			casks = append(casks, pkg)
+		}
+	}
+
+	// Walk the dependency graph from every formula the user asked for, and
+	// from the formulae casks depend on
+	needed := make(map[string]bool)
+	var visit func(name string)
+	visit = func(name string) {
+		if needed[name] {
+			return
+		}
+		needed[name] = true
+		for _, dep := range formulae[name].Dependencies {
+			visit(brewShortName(dep))
+		}
+	}
+	for name, pkg := range formulae {
+		if !pkg.InstalledAsDependency {
+			visit(name)
+		}
+	}
+	for _, cask := range casks {
+		for _, dep := range cask.Dependencies {
+			visit(brewShortName(dep))
+		}
+	}
+
+	var orphans []string
+	for name := range formulae {
+		if !needed[name] {
+			orphans = append(orphans, name)
+		}
+	}
+	sort.Strings(orphans)
+	return orphans
+}
+
+// brewShortName strips the tap from a full formula name
+// (homebrew/core/openssl@3 -> openssl@3)
+func brewShortName(fullName string) string {
+	return filepath.Base(fullName)
+}
+
+// brewCellar returns the Homebrew Cellar directory, or "" when none exists
+func brewCellar() string {
+	var prefixes []string
+	if prefix := os.Getenv("HOMEBREW_PREFIX"); prefix != "" {
+		prefixes = append(prefixes, prefix)
+	}
+	prefixes = append(prefixes, "/opt/homebrew", "/usr/local", "/home/linuxbrew/.linuxbrew")
+
+	for _, prefix := range prefixes {
+		cellar := filepath.Join(prefix, "Cellar")
+		if info, err := os.Stat(cellar); err == nil && info.IsDir() {
+			return cellar
+		}
+	}
+	return ""
+}
+
+// kegBinaries lists the executables in a keg's bin and sbin directories
+func kegBinaries(keg string) []string {
+	var binaries []string
+	for _, sub := range []string{"bin", "sbin"} {
+		entries, err := os.ReadDir(filepath.Join(keg, sub))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			info, err := os.Stat(filepath.Join(keg, sub, entry.Name()))
+			if err == nil && !info.IsDir() && info.Mode()&0111 != 0 {
+				binaries = append(binaries, entry.Name())
+			}
+		}
+	}
+	return binaries
+}
+
+// brewKeg returns the formula and keg a path lies in when it is under a
+// Cellar, as in <cellar>/<formula>/<version>/bin/<tool>
+func brewKeg(cellar, path string) (formula, keg string, ok bool) {
+	rel, err := filepath.Rel(cellar, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", "", false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 3 {
+		return "", "", false
+	}
+	return parts[0], filepath.Join(cellar, parts[0], parts[1]), true
+}
+
+// brewReceiptFormula returns the formula a keg was installed from, as its
+// INSTALL_RECEIPT.json records it: the file name of source.path, such as
+// python@3.12.rb. It differs from the keg's directory when the formula was
+// renamed since, or its keg was installed under an alias.
+func brewReceiptFormula(keg string) string {
+	data, err := os.ReadFile(filepath.Join(keg, "INSTALL_RECEIPT.json"))
+	if err != nil {
+		return ""
+	}
+	var receipt struct {
+		Source struct {
+			Path string `json:"path"`
+		} `json:"source"`
+	}
+	if err := json.Unmarshal(data, &receipt); err != nil || receipt.Source.Path == "" {
+		return ""
+	}
+	return strings.TrimSuffix(filepath.Base(filepath.FromSlash(receipt.Source.Path)), ".rb")
+}
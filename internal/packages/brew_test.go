@@ -0,0 +1,42 @@
+package packages
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAutoremoveDryRun(t *testing.T) {
+	output := "==> Would autoremove 3 unneeded formulae:\n" +
+		"libyaml\n" +
+		"homebrew/core/readline\n" +
+		"sqlite\n"
+	want := []string{"libyaml", "readline", "sqlite"}
+	if got := parseAutoremoveDryRun(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseAutoremoveDryRun = %v, want %v", got, want)
+	}
+	if got := parseAutoremoveDryRun(""); len(got) != 0 {
+		t.Errorf("parseAutoremoveDryRun of no output = %v, want none", got)
+	}
+}
+
+func TestOrphanedFormulae(t *testing.T) {
+	formula := func(name string, asDependency bool, deps ...string) Package {
+		return Package{Name: name, Manager: Brew, InstalledVersions: []string{"1.0"}, InstalledAsDependency: asDependency, Dependencies: deps}
+	}
+	pkgs := []Package{
+		formula("git", false, "homebrew/core/pcre2", "gettext"),
+		formula("pcre2", true),
+		formula("gettext", true),
+		// Left behind by a removed formula
+		formula("libyaml", true),
+		formula("readline", true),
+		// Needed only by an installed cask
+		formula("ffmpeg", true, "x264"),
+		formula("x264", true),
+		{Name: "some-app", Manager: Brew, Version: "2.0", Dependencies: []string{"ffmpeg"}},
+	}
+	want := []string{"libyaml", "readline"}
+	if got := OrphanedFormulae(pkgs); !reflect.DeepEqual(got, want) {
+		t.Errorf("OrphanedFormulae = %v, want %v", got, want)
+	}
+}
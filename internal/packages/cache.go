@@ -0,0 +1,226 @@
+package packages
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/atomicfile"
+)
+
+// DefaultCacheTTL is how long detected packages are reused when the
+// manager's metadata directories have not changed
+const DefaultCacheTTL = time.Hour
+
+// cacheEntry is one manager's detected packages, with the environment they
+// were detected in
+type cacheEntry struct {
+	SavedAt int64 `json:"saved_at"`
+	// Path is $PATH at detection time, since it decides which npm, pip, and
+	// gem are run
+	Path string `json:"path"`
+	// Stamps are the modification times of the manager's bin and metadata
+	// directories that existed at detection time
+	Stamps   map[string]int64 `json:"stamps"`
+	Packages []Package        `json:"packages"`
+	// Environments are the pip environments of the interpreters on PATH,
+	// stored instead of packages (see DetectPipEnvironments)
+	Environments []PipEnvironment `json:"environments,omitempty"`
+}
+
+// packageCache stores detected packages per manager. An entry is used while
+// it is younger than the TTL, PATH is unchanged, and none of the manager's
+// bin or metadata directories has appeared, disappeared, or been modified,
+// which installing, upgrading, or removing a package does.
+type packageCache struct {
+	path    string
+	ttl     time.Duration
+	entries map[PackageManager]cacheEntry
+	dirty   bool
+}
+
+// packageCachePath returns the cache file location under the user cache
+// directory
+func packageCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cli", "packages.json")
+}
+
+// loadPackageCache reads the cache file. A missing or unreadable cache
+// starts empty.
+func loadPackageCache(path string, ttl time.Duration) *packageCache {
+	cache := &packageCache{path: path, ttl: ttl, entries: make(map[PackageManager]cacheEntry)}
+	if path == "" {
+		return cache
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &cache.entries)
+	}
+	return cache
+}
+
+func (c *packageCache) get(manager PackageManager, stamps map[string]int64) ([]Package, bool) {
+	entry, ok := c.entry(manager, stamps)
+	return entry.Packages, ok
+}
+
+// entry returns the entry stored under key if it is still valid
+func (c *packageCache) entry(key PackageManager, stamps map[string]int64) (cacheEntry, bool) {
+	entry, ok := c.entries[key]
+	if !ok || time.Since(time.Unix(entry.SavedAt, 0)) > c.ttl || entry.Path != os.Getenv("PATH") {
+		return cacheEntry{}, false
+	}
+	if len(entry.Stamps) != len(stamps) {
+		return cacheEntry{}, false
+	}
+	for dir, mtime := range stamps {
+		if entry.Stamps[dir] != mtime {
+			return cacheEntry{}, false
+		}
+	}
+	return entry, true
+}
+
+func (c *packageCache) put(manager PackageManager, stamps map[string]int64, pkgs []Package) {
+	c.entries[manager] = cacheEntry{
+		SavedAt:  time.Now().Unix(),
+		Path:     os.Getenv("PATH"),
+		Stamps:   stamps,
+		Packages: pkgs,
+	}
+	c.dirty = true
+}
+
+func (c *packageCache) putEnvironments(key PackageManager, stamps map[string]int64, envs []PipEnvironment) {
+	c.entries[key] = cacheEntry{
+		SavedAt:      time.Now().Unix(),
+		Path:         os.Getenv("PATH"),
+		Stamps:       stamps,
+		Environments: envs,
+	}
+	c.dirty = true
+}
+
+// save writes the cache if an entry was stored since it was loaded. Runs
+// detecting packages at the same time each replace the whole file, and the
+// last one wins.
+func (c *packageCache) save() error {
+	if !c.dirty || c.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := atomicfile.Write(c.path, data, 0644); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// metadataStamps returns the modification times of the existing directories
+// (and files) a manager changes when packages are installed, upgraded, or
+// removed: its bin directories plus where it records what is installed
+func metadataStamps(manager PackageManager, binDirs map[PackageManager][]string) map[string]int64 {
+	home, _ := os.UserHomeDir()
+	patterns := append([]string{}, binDirs[manager]...)
+
+	switch manager {
+	case NPM:
+		for _, bin := range binDirs[NPM] {
+			patterns = append(patterns, filepath.Join(filepath.Dir(bin), "lib", "node_modules"))
+		}
+		patterns = append(patterns, "/usr/local/lib/node_modules", "/usr/lib/node_modules", "/opt/homebrew/lib/node_modules")
+	case Pip:
+		for _, prefix := range []string{filepath.Join(home, ".local"), "/usr/local", "/usr", "/opt/homebrew"} {
+			patterns = append(patterns,
+				filepath.Join(prefix, "lib", "python3*", "site-packages"),
+				filepath.Join(prefix, "lib", "python3*", "dist-packages"))
+		}
+		patterns = append(patterns, filepath.Join(home, "Library", "Python", "*", "lib", "python", "site-packages"))
+	case Pipx:
+		pipxHome := os.Getenv("PIPX_HOME")
+		if pipxHome == "" {
+			pipxHome = filepath.Join(home, ".local", "pipx")
+			if _, err := os.Stat(pipxHome); err != nil {
+				pipxHome = filepath.Join(home, ".local", "share", "pipx")
+			}
+		}
+		patterns = append(patterns, filepath.Join(pipxHome, "venvs"), pipxBinDir())
+	case Uv:
+		// Each tool's receipt is rewritten when it is upgraded
+		patterns = append(patterns, uvToolDir(), filepath.Join(uvToolDir(), "*", "uv-receipt.toml"))
+	case Poetry:
+		patterns = append(patterns, sitePackagesDirs(filepath.Join(poetryHome(), "venv"))...)
+	case Brew:
+		for _, bin := range binDirs[Brew] {
+			prefix := filepath.Dir(bin)
+			patterns = append(patterns,
+				filepath.Join(prefix, "Cellar"),
+				filepath.Join(prefix, "Caskroom"),
+				filepath.Join(prefix, "var", "homebrew", "linked"))
+		}
+	case Cargo:
+		patterns = append(patterns, filepath.Join(cargoInstallRoot(), ".crates2.json"))
+	case Gem:
+		if gemHome := os.Getenv("GEM_HOME"); gemHome != "" {
+			patterns = append(patterns, filepath.Join(gemHome, "specifications"))
+		}
+		patterns = append(patterns,
+			filepath.Join(home, ".gem", "ruby", "*", "specifications"),
+			filepath.Join(home, ".local", "share", "gem", "ruby", "*", "specifications"),
+			"/var/lib/gems/*/specifications",
+			"/usr/local/lib/ruby/gems/*/specifications",
+			"/opt/homebrew/lib/ruby/gems/*/specifications")
+	case MacPorts:
+		patterns = append(patterns, filepath.Join(macPortsPrefix, "var", "macports", "registry"))
+	case Nix:
+		patterns = append(patterns, nixProfileDirs()...)
+	case Deno:
+		patterns = append(patterns, denoBinDirs()...)
+	case Dotnet:
+		// One directory per tool package, with one entry per version
+		patterns = append(patterns, filepath.Join(dotnetToolsDir(), ".store", "*"))
+	case Composer:
+		for _, home := range composerHomes() {
+			patterns = append(patterns, filepath.Join(home, "vendor", "composer", "installed.json"))
+		}
+	case Snap:
+		// Each snap has a directory with one entry per revision installed
+		patterns = append(patterns, "/snap/*", "/var/lib/snapd/snap/*")
+	case Flatpak:
+		for _, root := range flatpakRoots() {
+			patterns = append(patterns, filepath.Join(root, "app", "*", "current"))
+		}
+	case Scoop:
+		for _, root := range []string{scoopRoot(), scoopGlobalRoot()} {
+			if root != "" {
+				patterns = append(patterns, filepath.Join(root, "apps"))
+			}
+		}
+	case Chocolatey:
+		if root := chocolateyRoot(); root != "" {
+			patterns = append(patterns, filepath.Join(root, "lib"))
+		}
+	case Winget:
+		if links := wingetLinks(); links != "" {
+			patterns = append(patterns, filepath.Join(filepath.Dir(links), "Packages"))
+		}
+	}
+
+	stamps := make(map[string]int64)
+	for _, pattern := range patterns {
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil {
+				stamps[match] = info.ModTime().UnixNano()
+			}
+		}
+	}
+	return stamps
+}
@@ -0,0 +1,85 @@
+package packages
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// cargoInstallRoot returns where `cargo install` puts crates:
+// $CARGO_INSTALL_ROOT, $CARGO_HOME, or ~/.cargo. Binaries go in its bin
+// directory and the record of installed crates next to it.
+func cargoInstallRoot() string {
+	if root := os.Getenv("CARGO_INSTALL_ROOT"); root != "" {
+		return root
+	}
+	if cargoHome := os.Getenv("CARGO_HOME"); cargoHome != "" {
+		return cargoHome
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cargo")
+}
+
+// detectCargo detects crates installed with `cargo install` from the record
+// cargo keeps of them, .crates2.json, which lists the binaries of each
+// crate by name even when they are not named after it (ripgrep installs rg)
+func (d *Detector) detectCargo() ([]Package, error) {
+	root := cargoInstallRoot()
+	data, err := os.ReadFile(filepath.Join(root, ".crates2.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	return parseCratesJSON(data, filepath.Join(root, "bin"))
+}
+
+// parseCratesJSON parses .crates2.json. Each installed crate is keyed by its
+// package ID, the crate's name, version, and source:
+//
+//	{"installs": {
+//	  "ripgrep 14.1.0 (registry+https://github.com/rust-lang/crates.io-index)": {"bins": ["rg"], ...},
+//	  "cargo-edit 0.12.2 (git+https://github.com/killercup/cargo-edit#abc123)": {"bins": ["cargo-add", "cargo-rm"], ...}
+//	}}
+//
+// binDir is where the crates' binaries were installed, recorded as their
+// Location.
+func parseCratesJSON(data []byte, binDir string) ([]Package, error) {
+	var record struct {
+		Installs map[string]struct {
+			Bins []string `json:"bins"`
+		} `json:"installs"`
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+
+	var packages []Package
+	for id, install := range record.Installs {
+		fields := strings.Fields(id)
+		if len(fields) < 2 {
+			continue
+		}
+		pkg := Package{
+			Name:     fields[0],
+			Version:  strings.TrimPrefix(fields[1], "v"),
+			Manager:  Cargo,
+			Location: binDir,
+			Global:   true,
+		}
+		if len(fields) > 2 {
+			pkg.Source = strings.TrimSuffix(strings.TrimPrefix(strings.Join(fields[2:], " "), "("), ")")
+		}
+		for _, bin := range install.Bins {
+			// Windows binaries are recorded with their extension
+			pkg.Binaries = append(pkg.Binaries, strings.TrimSuffix(bin, ".exe"))
+		}
+		packages = append(packages, pkg)
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
@@ -0,0 +1,68 @@
+package packages
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseCratesJSON(t *testing.T) {
+	binDir := filepath.Join("home", ".cargo", "bin")
+	tests := []struct {
+		name    string
+		data    string
+		want    []Package
+		wantErr bool
+	}{
+		{
+			name: "several binaries",
+			data: `{"installs": {
+				"cargo-edit 0.12.2 (registry+https://github.com/rust-lang/crates.io-index)": {"bins": ["cargo-add", "cargo-rm", "cargo-set-version", "cargo-upgrade"]},
+				"ripgrep 14.1.0 (registry+https://github.com/rust-lang/crates.io-index)": {"bins": ["rg.exe"]}
+			}}`,
+			want: []Package{
+				{Name: "cargo-edit", Version: "0.12.2", Manager: Cargo, Location: binDir, Global: true,
+					Source:   "registry+https://github.com/rust-lang/crates.io-index",
+					Binaries: []string{"cargo-add", "cargo-rm", "cargo-set-version", "cargo-upgrade"}},
+				{Name: "ripgrep", Version: "14.1.0", Manager: Cargo, Location: binDir, Global: true,
+					Source:   "registry+https://github.com/rust-lang/crates.io-index",
+					Binaries: []string{"rg"}},
+			},
+		},
+		{
+			name: "git and path sources",
+			data: `{"installs": {
+				"helix-term 23.10.0 (git+https://github.com/helix-editor/helix#f6021dd0)": {"bins": ["hx"]},
+				"mytool 0.1.0 (path+file:///home/me/src/my tool)": {"bins": ["mytool"]}
+			}}`,
+			want: []Package{
+				{Name: "helix-term", Version: "23.10.0", Manager: Cargo, Location: binDir, Global: true,
+					Source:   "git+https://github.com/helix-editor/helix#f6021dd0",
+					Binaries: []string{"hx"}},
+				{Name: "mytool", Version: "0.1.0", Manager: Cargo, Location: binDir, Global: true,
+					Source:   "path+file:///home/me/src/my tool",
+					Binaries: []string{"mytool"}},
+			},
+		},
+		{
+			name: "no installs",
+			data: `{"installs": {}}`,
+		},
+		{
+			name:    "malformed",
+			data:    `{"installs": {"ripgrep 14.1.0": `,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCratesJSON([]byte(tt.data), binDir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package packages
+
+// UninstallCommand returns the command that removes a package with its
+// manager, as an argument list. It returns nil for managers without an
+// uninstall command, such as go, whose binaries are plain files to delete,
+// and deno, whose `deno uninstall` takes one script's name rather than the
+// module.
+func UninstallCommand(manager PackageManager, name string) []string {
+	switch manager {
+	case Brew:
+		return []string{"brew", "uninstall", name}
+	case NPM:
+		return []string{"npm", "uninstall", "-g", name}
+	case Pip:
+		return []string{"python3", "-m", "pip", "uninstall", "-y", name}
+	case Pipx:
+		return []string{"pipx", "uninstall", name}
+	case Uv:
+		return []string{"uv", "tool", "uninstall", name}
+	case Poetry:
+		// poetry itself is removed with its installer's --uninstall
+		if name != "poetry" {
+			return []string{"poetry", "self", "remove", name}
+		}
+	case Cargo:
+		return []string{"cargo", "uninstall", name}
+	case Gem:
+		return []string{"gem", "uninstall", "-x", name}
+	case MacPorts:
+		return []string{"sudo", "port", "uninstall", name}
+	case Nix:
+		if nixEnvProfile() {
+			return []string{"nix-env", "--uninstall", name}
+		}
+		return []string{"nix", "profile", "remove", name}
+	case Snap:
+		return []string{"sudo", "snap", "remove", name}
+	case Flatpak:
+		return []string{"flatpak", "uninstall", "-y", name}
+	case Dotnet:
+		return []string{"dotnet", "tool", "uninstall", "--global", name}
+	case Composer:
+		return []string{"composer", "global", "remove", name}
+	case Scoop:
+		return []string{"scoop", "uninstall", name}
+	case Chocolatey:
+		return []string{"choco", "uninstall", "-y", name}
+	case Winget:
+		return []string{"winget", "uninstall", "--id", name, "--exact"}
+	}
+	return nil
+}
+
+// UpgradeCommand returns the command that upgrades a package to its latest
+// version with its manager, as an argument list. For go, name must be the
+// main package's import path, which `go install` rebuilds at @latest. It
+// returns nil for managers it cannot upgrade with.
+func UpgradeCommand(manager PackageManager, name string) []string {
+	switch manager {
+	case Brew:
+		return []string{"brew", "upgrade", name}
+	case NPM:
+		return []string{"npm", "update", "-g", name}
+	case Pip:
+		return []string{"python3", "-m", "pip", "install", "--upgrade", name}
+	case Pipx:
+		return []string{"pipx", "upgrade", name}
+	case Uv:
+		return []string{"uv", "tool", "upgrade", name}
+	case Poetry:
+		if name == "poetry" {
+			return []string{"poetry", "self", "update"}
+		}
+		return []string{"poetry", "self", "add", name + "@latest"}
+	case Cargo:
+		// cargo install replaces an installed crate when a newer version exists
+		return []string{"cargo", "install", name}
+	case Go:
+		return []string{"go", "install", name + "@latest"}
+	case Gem:
+		return []string{"gem", "update", name}
+	case MacPorts:
+		return []string{"sudo", "port", "upgrade", name}
+	case Nix:
+		if nixEnvProfile() {
+			return []string{"nix-env", "--upgrade", name}
+		}
+		return []string{"nix", "profile", "upgrade", name}
+	case Snap:
+		return []string{"sudo", "snap", "refresh", name}
+	case Flatpak:
+		return []string{"flatpak", "update", "-y", name}
+	case Dotnet:
+		return []string{"dotnet", "tool", "update", "--global", name}
+	case Composer:
+		return []string{"composer", "global", "update", name}
+	case Scoop:
+		return []string{"scoop", "update", name}
+	case Chocolatey:
+		return []string{"choco", "upgrade", "-y", name}
+	case Winget:
+		return []string{"winget", "upgrade", "--id", name, "--exact"}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package packages
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// composerHomes returns the directories composer may keep its global
+// project in: $COMPOSER_HOME, or ~/.composer and the XDG config directory
+// (~/.config/composer) on Unix and %APPDATA%\Composer on Windows
+func composerHomes() []string {
+	if home := os.Getenv("COMPOSER_HOME"); home != "" {
+		return []string{home}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{filepath.Join(os.Getenv("APPDATA"), "Composer")}
+	}
+	home, _ := os.UserHomeDir()
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		configHome = filepath.Join(home, ".config")
+	}
+	return []string{filepath.Join(home, ".composer"), filepath.Join(configHome, "composer")}
+}
+
+// composerBinDirs returns the directories composer links the binaries of
+// global packages into
+func composerBinDirs() []string {
+	var dirs []string
+	for _, home := range composerHomes() {
+		dirs = append(dirs, filepath.Join(home, "vendor", "bin"))
+	}
+	return dirs
+}
+
+// detectComposer detects packages installed with `composer global require`.
+// `composer global show` lists them without their binaries, which are read
+// from the installed.json composer keeps in the global project's vendor
+// directory.
+func (d *Detector) detectComposer() ([]Package, error) {
+	output, err := d.command("composer", "global", "show", "--format=json", "--no-ansi").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var shown struct {
+		Installed []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"installed"`
+	}
+	if err := json.Unmarshal(output, &shown); err != nil {
+		return nil, err
+	}
+
+	binaries, location := composerInstalledBinaries()
+	var packages []Package
+	for _, p := range shown.Installed {
+		packages = append(packages, Package{
+			Name:     p.Name,
+			Version:  p.Version,
+			Manager:  Composer,
+			Binaries: binaries[p.Name],
+			Location: location,
+			Global:   true,
+		})
+	}
+	return packages, nil
+}
+
+// composerInstalledBinaries reads the binaries of each package from the
+// first global project with a vendor/composer/installed.json, and returns
+// them with the project's bin directory. The file is a list of packages in
+// composer 1 and has them under "packages" since composer 2; their "bin"
+// paths are relative to the package.
+func composerInstalledBinaries() (map[string][]string, string) {
+	type installedPackage struct {
+		Name string   `json:"name"`
+		Bin  []string `json:"bin"`
+	}
+
+	for _, home := range composerHomes() {
+		data, err := os.ReadFile(filepath.Join(home, "vendor", "composer", "installed.json"))
+		if err != nil {
+			continue
+		}
+		var installed struct {
+			Packages []installedPackage `json:"packages"`
+		}
+		if err := json.Unmarshal(data, &installed); err != nil {
+			if err := json.Unmarshal(data, &installed.Packages); err != nil {
+				continue
+			}
+		}
+
+		binaries := make(map[string][]string)
+		for _, p := range installed.Packages {
+			for _, bin := range p.Bin {
+				binaries[p.Name] = append(binaries[p.Name], filepath.Base(bin))
+			}
+		}
+		return binaries, filepath.Join(home, "vendor", "bin")
+	}
+	return nil, ""
+}
+
+// composerCommand returns the name a composer binary is run by, without the
+// .bat extension of the proxies composer writes on Windows
+func composerCommand(file string) string {
+	return strings.TrimSuffix(file, ".bat")
+}
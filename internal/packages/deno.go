@@ -0,0 +1,174 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxDenoScript bounds the size of the files read as `deno install`
+// scripts, so the deno executable that shares their directory is skipped
+const maxDenoScript = 64 * 1024
+
+// denoBinDirs returns where `deno install` puts the scripts it generates:
+// $DENO_INSTALL_ROOT/bin, or ~/.deno/bin by default
+func denoBinDirs() []string {
+	var dirs []string
+	if root := os.Getenv("DENO_INSTALL_ROOT"); root != "" {
+		dirs = append(dirs, filepath.Join(root, "bin"))
+	}
+	home, _ := os.UserHomeDir()
+	return append(dirs, filepath.Join(home, ".deno", "bin"))
+}
+
+// detectDeno detects CLIs installed with `deno install`. Each is a script
+// that runs `deno run` on the module it was installed from, so the module
+// is read back from the script. Scripts installed from the same module (a
+// versioned deno.land/std, say) are grouped into one package named by the
+// module without its version.
+func (d *Detector) detectDeno() ([]Package, error) {
+	var packages []Package
+	index := make(map[string]int)
+	found := false
+
+	for _, dir := range denoBinDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		found = true
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || entry.IsDir() || info.Size() > maxDenoScript {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				continue
+			}
+			specifier := parseDenoScript(string(data))
+			if specifier == "" {
+				continue
+			}
+
+			name, version := splitDenoSpecifier(specifier)
+			command := denoCommand(entry.Name())
+			key := dir + "\x00" + name + "\x00" + version
+			if i, ok := index[key]; ok {
+				packages[i].Binaries = appendUnique(packages[i].Binaries, command)
+				continue
+			}
+			index[key] = len(packages)
+			packages = append(packages, Package{
+				Name:     name,
+				Version:  version,
+				Manager:  Deno,
+				Binaries: []string{command},
+				Location: dir,
+				Global:   true,
+			})
+		}
+	}
+	if !found {
+		return nil, os.ErrNotExist
+	}
+
+	sort.SliceStable(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
+
+// parseDenoScript returns the module a `deno install` script runs: the
+// last argument of its `deno run` line, before the arguments passed
+// through. The script is a shell script,
+//
+//	#!/bin/sh
+//	# generated by deno install
+//	exec deno run --allow-net 'https://deno.land/std@0.200.0/http/file_server.ts' "$@"
+//
+// or a .cmd file on Windows ending in %*. It returns "" for other files.
+func parseDenoScript(script string) string {
+	for _, line := range strings.Split(script, "\n") {
+		args := shellFields(strings.TrimSpace(line))
+		for i := 0; i+1 < len(args); i++ {
+			// cmd files start the line with "@" to keep it from echoing
+			if commandName(strings.TrimPrefix(args[i], "@")) != "deno" || args[i+1] != "run" {
+				continue
+			}
+			rest := args[i+2:]
+			for len(rest) > 0 && (rest[len(rest)-1] == "$@" || rest[len(rest)-1] == "%*") {
+				rest = rest[:len(rest)-1]
+			}
+			if len(rest) > 0 && !strings.HasPrefix(rest[len(rest)-1], "-") {
+				return rest[len(rest)-1]
+			}
+		}
+	}
+	return ""
+}
+
+// splitDenoSpecifier splits a module specifier into the module and its
+// version: "jsr:@std/http@1.0.0/file-server" is jsr:@std/http 1.0.0,
+// "npm:cowsay@1.5.0" is npm:cowsay 1.5.0, and
+// "https://deno.land/x/denon@2.5.0/denon.ts" is https://deno.land/x/denon
+// 2.5.0. A specifier without a version is returned whole.
+func splitDenoSpecifier(specifier string) (string, string) {
+	for i := 1; i < len(specifier); i++ {
+		// A version follows an "@" inside a path segment; an "@" that starts
+		// one is a scope
+		if specifier[i] != '@' || specifier[i-1] == '/' || specifier[i-1] == ':' {
+			continue
+		}
+		version := specifier[i+1:]
+		if end := strings.Index(version, "/"); end >= 0 {
+			version = version[:end]
+		}
+		if version != "" {
+			return specifier[:i], version
+		}
+	}
+	return specifier, ""
+}
+
+// denoCommand returns the name a deno install script is run by, without
+// the .cmd extension of Windows scripts
+func denoCommand(file string) string {
+	return strings.TrimSuffix(file, ".cmd")
+}
+
+// shellFields splits a command line into words, honouring single and
+// double quotes as sh and cmd.exe do for the lines deno writes
+func shellFields(line string) []string {
+	var fields []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\r':
+			if inWord {
+				fields = append(fields, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		fields = append(fields, word.String())
+	}
+	return fields
+}
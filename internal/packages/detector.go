@@ -1,59 +1,204 @@
 package packages
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"os/exec"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 )
 
+// macPortsPrefix is the default MacPorts installation prefix
+const macPortsPrefix = "/opt/local"
+
 // PackageManager represents different package managers
 type PackageManager string
 
 const (
-	NPM    PackageManager = "npm"
-	Pip    PackageManager = "pip"
-	Brew   PackageManager = "brew"
-	Cargo  PackageManager = "cargo"
-	Go     PackageManager = "go"
-	Gem    PackageManager = "gem"
+	NPM      PackageManager = "npm"
+	Pip      PackageManager = "pip"
+	Pipx     PackageManager = "pipx"
+	Uv       PackageManager = "uv"
+	Poetry   PackageManager = "poetry"
+	Brew     PackageManager = "brew"
+	Cargo    PackageManager = "cargo"
+	Go       PackageManager = "go"
+	Gem      PackageManager = "gem"
+	MacPorts PackageManager = "macports"
+	Nix      PackageManager = "nix"
+	Snap     PackageManager = "snap"
+	Flatpak  PackageManager = "flatpak"
+	Deno     PackageManager = "deno"
+	Dotnet   PackageManager = "dotnet"
+	Composer PackageManager = "composer"
+
+	// Windows package managers
+	Scoop      PackageManager = "scoop"
+	Chocolatey PackageManager = "choco"
+	Winget     PackageManager = "winget"
 )
 
+// allManagers are the package managers detected by default, in the order
+// their packages are merged
+var allManagers = []PackageManager{NPM, Pip, Pipx, Uv, Poetry, Brew, Cargo, Go, Gem, MacPorts, Nix, Snap, Flatpak, Deno, Dotnet, Composer, Scoop, Chocolatey, Winget}
+
+// AllManagers returns the package managers detected by default, in the
+// fixed order their packages are merged
+func AllManagers() []PackageManager {
+	return append([]PackageManager(nil), allManagers...)
+}
+
 // Package represents a package that provides CLI tools
 type Package struct {
-	Name           string         `json:"name"`
-	Version        string         `json:"version"`
-	Manager        PackageManager `json:"manager"`
-	Binaries       []string       `json:"binaries,omitempty"`
-	Location       string         `json:"location,omitempty"`
-	Global         bool           `json:"global"`
+	Name     string         `json:"name"`
+	Version  string         `json:"version"`
+	Manager  PackageManager `json:"manager"`
+	Binaries []string       `json:"binaries,omitempty"`
+	Location string         `json:"location,omitempty"`
+	Global   bool           `json:"global"`
+	// Source is where the package was installed from, such as the registry
+	// or git repository of a cargo crate
+	Source string `json:"source,omitempty"`
+
+	// Homebrew keg state, populated from `brew info --json`
+	InstalledVersions []string `json:"installed_versions,omitempty"`
+	LinkedKeg         string   `json:"linked_keg,omitempty"`
+	KegOnly           bool     `json:"keg_only,omitempty"`
+	// InstalledAsDependency is true when brew installed the formula only
+	// to satisfy another formula, not because it was asked for
+	InstalledAsDependency bool     `json:"installed_as_dependency,omitempty"`
+	Dependencies          []string `json:"dependencies,omitempty"`
 }
 
 // Detector finds packages from various package managers
 type Detector struct {
 	enabledManagers []PackageManager
+	brewInfo        *brewInfo
+	// ctx kills running package manager commands when cancelled
+	ctx context.Context
+	// cacheTTL enables the on-disk package cache when positive
+	cacheTTL     time.Duration
+	refreshCache bool
 }
 
 // NewDetector creates a new package detector
 func NewDetector() *Detector {
 	return &Detector{
-		enabledManagers: []PackageManager{NPM, Pip, Brew, Cargo, Go, Gem},
+		enabledManagers: allManagers,
+		ctx:             context.Background(),
 	}
 }
 
-// DetectAll detects packages from all enabled package managers
+// SetContext makes detection stop when ctx is cancelled: running package
+// manager commands are killed and managers not yet queried are skipped
+func (d *Detector) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+// ParseManager returns the package manager with the given name, as used in
+// --manager flags and the config file
+func ParseManager(name string) (PackageManager, error) {
+	for _, manager := range allManagers {
+		if string(manager) == name {
+			return manager, nil
+		}
+	}
+	return "", fmt.Errorf("unknown package manager %q", name)
+}
+
+// SetCache makes DetectAll reuse each manager's packages from the on-disk
+// cache for up to ttl, as long as PATH and the manager's bin and metadata
+// directories are unchanged, and save what it detects afresh. With refresh,
+// every manager is queried again and the cache is rewritten. The cache is
+// off until SetCache is called with a positive ttl.
+func (d *Detector) SetCache(ttl time.Duration, refresh bool) {
+	d.cacheTTL = ttl
+	d.refreshCache = refresh
+}
+
+// command prepares a package manager command bound to the detector's context
+func (d *Detector) command(name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(d.ctx, name, args...)
+}
+
+// detectWorkers bounds how many package managers are queried at once. Each
+// query runs the manager's own CLI, several of which are slow interpreters.
+const detectWorkers = 4
+
+// DetectAll detects packages from all enabled package managers. Managers are
+// queried concurrently, but their packages are merged in the order the
+// managers are enabled, so results are the same from run to run. Managers
+// whose cached packages are still valid are not queried (see SetCache). If
+// the detector's context is cancelled, managers not yet queried are skipped
+// and the packages found so far are returned with the context's error.
 func (d *Detector) DetectAll() ([]Package, error) {
-	var packages []Package
+	// Each manager's packages go into its own slot so the merge below does
+	// not depend on completion order
+	results := make([][]Package, len(d.enabledManagers))
+	fresh := make([]bool, len(d.enabledManagers))
+
+	// Stamps are taken before detection, so a package installed while it
+	// runs invalidates the entry next time
+	var cache *packageCache
+	var stamps []map[string]int64
+	if d.cacheTTL > 0 {
+		cache = loadPackageCache(packageCachePath(), d.cacheTTL)
+		binDirs := BinDirs()
+		for _, manager := range d.enabledManagers {
+			stamps = append(stamps, metadataStamps(manager, binDirs))
+		}
+	}
 
-	for _, manager := range d.enabledManagers {
-		pkgs, err := d.detectByManager(manager)
-		if err != nil {
-			// Skip managers that fail (not installed, etc.)
-			continue
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < detectWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if d.ctx.Err() != nil {
+					continue
+				}
+				if cache != nil && !d.refreshCache {
+					if pkgs, ok := cache.get(d.enabledManagers[index], stamps[index]); ok {
+						results[index] = pkgs
+						continue
+					}
+				}
+				// Skip managers that fail (not installed, etc.)
+				if pkgs, err := d.detectByManager(d.enabledManagers[index]); err == nil {
+					results[index] = pkgs
+					fresh[index] = true
+				}
+			}
+		}()
+	}
+	for index := range d.enabledManagers {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+
+	// A cancelled detection may have cut commands short, so nothing from it
+	// is cached
+	if cache != nil && d.ctx.Err() == nil {
+		for index, manager := range d.enabledManagers {
+			if fresh[index] {
+				cache.put(manager, stamps[index], results[index])
+			}
 		}
-		packages = append(packages, pkgs...)
+		// The cache only saves time; failing to write it is not an error
+		_ = cache.save()
 	}
 
-	return packages, nil
+	var packages []Package
+	for _, pkgs := range results {
+		packages = append(packages, pkgs...)
+	}
+	return packages, d.ctx.Err()
 }
 
 // detectByManager detects packages for a specific manager
@@ -63,6 +208,12 @@ func (d *Detector) detectByManager(manager PackageManager) ([]Package, error) {
 		return d.detectNPM()
 	case Pip:
 		return d.detectPip()
+	case Pipx:
+		return d.detectPipx()
+	case Uv:
+		return d.detectUv()
+	case Poetry:
+		return d.detectPoetry()
 	case Brew:
 		return d.detectBrew()
 	case Cargo:
@@ -71,14 +222,35 @@ func (d *Detector) detectByManager(manager PackageManager) ([]Package, error) {
 		return d.detectGo()
 	case Gem:
 		return d.detectGem()
+	case MacPorts:
+		return d.detectMacPorts()
+	case Nix:
+		return d.detectNix()
+	case Snap:
+		return d.detectSnap()
+	case Flatpak:
+		return d.detectFlatpak()
+	case Deno:
+		return d.detectDeno()
+	case Dotnet:
+		return d.detectDotnet()
+	case Composer:
+		return d.detectComposer()
+	case Scoop:
+		return d.detectScoop()
+	case Chocolatey:
+		return d.detectChocolatey()
+	case Winget:
+		return d.detectWinget()
 	default:
 		return nil, nil
 	}
 }
 
-// detectNPM detects globally installed npm packages
+// detectNPM detects globally installed npm packages, with the commands each
+// declares in the bin field of its package.json
 func (d *Detector) detectNPM() ([]Package, error) {
-	cmd := exec.Command("npm", "list", "-g", "--json", "--depth=0")
+	cmd := d.command("npm", "list", "-g", "--json", "--depth=0")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -87,6 +259,8 @@ func (d *Detector) detectNPM() ([]Package, error) {
 	var result struct {
 		Dependencies map[string]struct {
 			Version string `json:"version"`
+			// Resolved is the tarball URL, or file: for npm link
+			Resolved string `json:"resolved"`
 		} `json:"dependencies"`
 	}
 
@@ -94,26 +268,42 @@ func (d *Detector) detectNPM() ([]Package, error) {
 		return nil, err
 	}
 
+	// Without the global node_modules directory, packages are still
+	// listed, just without their binaries
+	root := ""
+	if output, err := d.command("npm", "root", "-g").Output(); err == nil {
+		root = strings.TrimSpace(string(output))
+	}
+
 	var packages []Package
 	for name, info := range result.Dependencies {
-		packages = append(packages, Package{
+		pkg := Package{
 			Name:    name,
 			Version: info.Version,
 			Manager: NPM,
 			Global:  true,
-		})
+			Source:  info.Resolved,
+		}
+		if root != "" {
+			pkg.Binaries = readNpmBinaries(root, name)
+			pkg.Location = npmBinDir(root)
+		}
+		packages = append(packages, pkg)
 	}
 
 	return packages, nil
 }
 
-// detectPip detects installed pip packages
+// detectPip detects installed pip packages. Their commands come from each
+// distribution's metadata in site-packages: the console and GUI scripts in
+// entry_points.txt, and the scripts RECORD lists in the bin directory.
 func (d *Detector) detectPip() ([]Package, error) {
-	cmd := exec.Command("pip", "list", "--format=json")
+	// --verbose adds the site-packages directory of each package
+	cmd := d.command("pip", "list", "--format=json", "--verbose", "--disable-pip-version-check")
 	output, err := cmd.Output()
 	if err != nil {
 		// Try pip3
-		cmd = exec.Command("pip3", "list", "--format=json")
+		cmd = d.command("pip3", "list", "--format=json", "--verbose", "--disable-pip-version-check")
 		output, err = cmd.Output()
 		if err != nil {
 			return nil, err
@@ -121,30 +311,58 @@ func (d *Detector) detectPip() ([]Package, error) {
 	}
 
 	var result []struct {
-		Name    string `json:"name"`
-		Version string `json:"version"`
+		Name     string `json:"name"`
+		Version  string `json:"version"`
+		Location string `json:"location"`
 	}
 
 	if err := json.Unmarshal(output, &result); err != nil {
 		return nil, err
 	}
 
+	// Distributions by site-packages directory and normalized name
+	dists := make(map[string]map[string]pythonDist)
 	var packages []Package
 	for _, item := range result {
-		packages = append(packages, Package{
+		pkg := Package{
 			Name:    item.Name,
 			Version: item.Version,
 			Manager: Pip,
 			Global:  false,
-		})
+		}
+		if item.Location != "" {
+			if dists[item.Location] == nil {
+				dists[item.Location] = make(map[string]pythonDist)
+				for _, dist := range readPythonDists(item.Location) {
+					dists[item.Location][normalizePipName(dist.Name)] = dist
+				}
+			}
+			if dist, ok := dists[item.Location][normalizePipName(item.Name)]; ok {
+				pkg.Binaries = dist.Commands()
+				pkg.Location = dist.ScriptDir
+			}
+		}
+		packages = append(packages, pkg)
 	}
 
 	return packages, nil
 }
 
-// detectBrew detects installed homebrew packages
+// detectBrew detects installed homebrew packages. Each formula's Location
+// is its directory in the Cellar, which holds one keg per installed version.
 func (d *Detector) detectBrew() ([]Package, error) {
-	cmd := exec.Command("brew", "list", "--versions")
+	cellar := brewCellar()
+	if output, err := d.command("brew", "--prefix").Output(); err == nil {
+		cellar = filepath.Join(strings.TrimSpace(string(output)), "Cellar")
+	}
+
+	// Prefer brew's JSON metadata, which reports the linked keg and every
+	// installed version rather than whatever order `brew list` prints
+	if info, err := d.loadBrewInfo(); err == nil {
+		return brewPackages(info, cellar), nil
+	}
+
+	cmd := d.command("brew", "list", "--versions")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -161,21 +379,26 @@ func (d *Detector) detectBrew() ([]Package, error) {
 
 		parts := strings.Fields(line)
 		if len(parts) >= 2 {
-			packages = append(packages, Package{
+			pkg := Package{
 				Name:    parts[0],
 				Version: parts[1],
 				Manager: Brew,
 				Global:  true,
-			})
+			}
+			if cellar != "" {
+				pkg.Location = filepath.Join(cellar, parts[0])
+			}
+			packages = append(packages, pkg)
 		}
 	}
 
 	return packages, nil
 }
 
-// detectCargo detects installed cargo packages
-func (d *Detector) detectCargo() ([]Package, error) {
-	cmd := exec.Command("cargo", "install", "--list")
+// detectGem detects installed ruby gems, with the executables each one's
+// specification lists
+func (d *Detector) detectGem() ([]Package, error) {
+	cmd := d.command("gem", "list", "--local")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
@@ -186,63 +409,114 @@ func (d *Detector) detectCargo() ([]Package, error) {
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, " ") {
+		if line == "" {
 			continue
 		}
 
-		parts := strings.Fields(line)
-		if len(parts) >= 2 {
+		// Format: "package_name (version1, version2)"
+		parts := strings.Split(line, " (")
+		if len(parts) == 2 {
 			name := parts[0]
-			version := strings.Trim(parts[1], "v:")
+			version := strings.TrimSuffix(parts[1], ")")
+			// Take first version if multiple
+			version = strings.Split(version, ",")[0]
+			// Default gems, which ship with Ruby, are listed as "default: 2.4.10"
+			version = strings.TrimPrefix(version, "default: ")
 			packages = append(packages, Package{
 				Name:    name,
-				Version: version,
-				Manager: Cargo,
-				Global:  true,
+				Version: strings.TrimSpace(version),
+				Manager: Gem,
+				Global:  false,
 			})
 		}
 	}
 
-	return packages, nil
-}
+	// Without the gem paths, gems are still listed, just without their
+	// executables
+	output, err = d.command("gem", "environment").Output()
+	if err != nil {
+		return packages, nil
+	}
+	env := parseGemEnvironment(string(output))
+	executables := make([]map[string][]string, len(env.Paths))
+	for i, dir := range env.Paths {
+		executables[i] = readGemExecutables(dir)
+	}
+	for i := range packages {
+		// The first gem path holding the version wins, as it does for Ruby
+		for j, dir := range env.Paths {
+			if bins, ok := executables[j][packages[i].Name+"-"+packages[i].Version]; ok {
+				packages[i].Binaries = bins
+				packages[i].Location = env.binDir(dir)
+				break
+			}
+		}
+	}
 
-// detectGo detects installed go packages
-func (d *Detector) detectGo() ([]Package, error) {
-	// Go doesn't have a built-in list command, so this is limited
-	// We could scan $GOPATH/bin but that requires more work
-	return nil, nil
+	return packages, nil
 }
 
-// detectGem detects installed ruby gems
-func (d *Detector) detectGem() ([]Package, error) {
-	cmd := exec.Command("gem", "list", "--local")
+// detectMacPorts detects active MacPorts ports and the binaries they install
+func (d *Detector) detectMacPorts() ([]Package, error) {
+	cmd := d.command("port", "-q", "installed", "active")
 	output, err := cmd.Output()
 	if err != nil {
 		return nil, err
 	}
 
-	lines := strings.Split(string(output), "\n")
 	var packages []Package
+	index := make(map[string]int)
+	for _, line := range strings.Split(string(output), "\n") {
+		// Format: "git @2.43.0_0+credential_osxkeychain+doc (active)"
+		parts := strings.Fields(line)
+		if len(parts) < 2 || !strings.HasPrefix(parts[1], "@") {
+			continue
+		}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
+		version := strings.TrimPrefix(parts[1], "@")
+		// Drop variants (+doc) and the port revision (_0)
+		version = strings.SplitN(version, "+", 2)[0]
+		if i := strings.LastIndex(version, "_"); i > 0 {
+			version = version[:i]
+		}
+
+		index[parts[0]] = len(packages)
+		packages = append(packages, Package{
+			Name:     parts[0],
+			Version:  version,
+			Manager:  MacPorts,
+			Location: macPortsPrefix,
+			Global:   true,
+		})
+	}
+
+	// Port names rarely match their binaries (python311 -> python3.11), so
+	// read the installed files of every active port in one call
+	cmd = d.command("port", "-q", "contents", "active")
+	output, err = cmd.Output()
+	if err != nil {
+		return packages, nil
+	}
+
+	current := -1
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.HasPrefix(line, "Port ") && strings.HasSuffix(line, " contains:") {
+			name := strings.TrimSuffix(strings.TrimPrefix(line, "Port "), " contains:")
+			if i, ok := index[name]; ok {
+				current = i
+			} else {
+				current = -1
+			}
 			continue
 		}
 
-		// Format: "package_name (version1, version2)"
-		parts := strings.Split(line, " (")
-		if len(parts) == 2 {
-			name := parts[0]
-			version := strings.TrimSuffix(parts[1], ")")
-			// Take first version if multiple
-			version = strings.Split(version, ",")[0]
-			packages = append(packages, Package{
-				Name:    name,
-				Version: strings.TrimSpace(version),
-				Manager: Gem,
-				Global:  false,
-			})
+		path := strings.TrimSpace(line)
+		if current < 0 || path == "" {
+			continue
+		}
+		dir := filepath.Dir(path)
+		if dir == macPortsPrefix+"/bin" || dir == macPortsPrefix+"/sbin" {
+			packages[current].Binaries = append(packages[current].Binaries, filepath.Base(path))
 		}
 	}
 
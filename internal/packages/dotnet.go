@@ -0,0 +1,72 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// dotnetToolsDir returns where `dotnet tool install --global` puts the
+// commands of global tools: .dotnet/tools under $DOTNET_CLI_HOME, or under
+// the home directory by default
+func dotnetToolsDir() string {
+	home := os.Getenv("DOTNET_CLI_HOME")
+	if home == "" {
+		home, _ = os.UserHomeDir()
+	}
+	return filepath.Join(home, ".dotnet", "tools")
+}
+
+// detectDotnet detects .NET global tools. Their packages are kept in the
+// .store directory of the tools directory, and each command is an
+// executable next to it.
+func (d *Detector) detectDotnet() ([]Package, error) {
+	output, err := d.command("dotnet", "tool", "list", "--global").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	packages := parseDotnetToolList(string(output))
+	for i := range packages {
+		packages[i].Location = dotnetToolsDir()
+	}
+	return packages, nil
+}
+
+// parseDotnetToolList parses `dotnet tool list --global`, a table with one
+// tool per line below its header, the commands of a tool separated by
+// commas:
+//
+//	Package Id      Version      Commands
+//	-------------------------------------------
+//	dotnet-ef       8.0.1        dotnet-ef
+//	powershell      7.4.1        pwsh
+func parseDotnetToolList(output string) []Package {
+	var packages []Package
+	inTable := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "---") {
+			inTable = true
+			continue
+		}
+		fields := strings.Fields(line)
+		if !inTable || len(fields) < 2 {
+			continue
+		}
+
+		pkg := Package{
+			Name:    fields[0],
+			Version: fields[1],
+			Manager: Dotnet,
+			Global:  true,
+		}
+		for _, command := range strings.Split(strings.Join(fields[2:], ""), ",") {
+			if command != "" {
+				pkg.Binaries = append(pkg.Binaries, command)
+			}
+		}
+		packages = append(packages, pkg)
+	}
+	return packages
+}
@@ -0,0 +1,77 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// flatpakRoots returns the system and user flatpak installations
+func flatpakRoots() []string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, _ := os.UserHomeDir()
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return []string{"/var/lib/flatpak", filepath.Join(dataHome, "flatpak")}
+}
+
+// detectFlatpak detects installed flatpak applications. Flatpak exports
+// each application as a command named by its ID (org.gimp.GIMP) in the
+// exports/bin directory of its installation.
+func (d *Detector) detectFlatpak() ([]Package, error) {
+	output, err := d.command("flatpak", "list", "--app", "--columns=application,version").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	packages := parseFlatpakList(string(output))
+	for i := range packages {
+		for _, root := range flatpakRoots() {
+			dir := filepath.Join(root, "exports", "bin")
+			if _, err := os.Stat(filepath.Join(dir, packages[i].Name)); err == nil {
+				packages[i].Binaries = []string{packages[i].Name}
+				packages[i].Location = dir
+				break
+			}
+		}
+	}
+	return packages, nil
+}
+
+// parseFlatpakList parses `flatpak list --app
+// --columns=application,version`: one tab-separated "id version" line per
+// application, the version empty when the application sets none. An
+// application installed both system-wide and for the user is listed once.
+func parseFlatpakList(output string) []Package {
+	var packages []Package
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		id := strings.TrimSpace(fields[0])
+		if id == "" || seen[id] || !strings.Contains(id, ".") {
+			continue
+		}
+		seen[id] = true
+		pkg := Package{Name: id, Manager: Flatpak, Global: true}
+		if len(fields) > 1 {
+			pkg.Version = strings.TrimSpace(fields[1])
+		}
+		packages = append(packages, pkg)
+	}
+	return packages
+}
+
+// flatpakApp returns the application ID of a flatpak export, as in
+// /var/lib/flatpak/exports/bin/<id> or the file it links to,
+// /var/lib/flatpak/app/<id>/current/active/export/bin/<id>
+func flatpakApp(path string) (string, bool) {
+	slashed := filepath.ToSlash(path)
+	if strings.Contains(slashed, "/flatpak/exports/bin/") {
+		return filepath.Base(slashed), true
+	}
+	if i := strings.Index(slashed, "/flatpak/app/"); i >= 0 {
+		return strings.Split(slashed[i+len("/flatpak/app/"):], "/")[0], true
+	}
+	return "", false
+}
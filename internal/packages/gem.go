@@ -0,0 +1,136 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gemEnvironment is where RubyGems keeps gems, from `gem environment`
+type gemEnvironment struct {
+	// InstallDir is where `gem install` puts gems, and ExecutableDir where
+	// it writes their executables' wrappers
+	InstallDir    string
+	ExecutableDir string
+	// Paths are the directories gems are loaded from, InstallDir among them
+	Paths []string
+}
+
+// binDir returns the directory the executables of the gems in gem path
+// dir are written to: the executable directory for the installation
+// directory, which may be a system bin directory, and dir/bin for the
+// others, such as the user installation directory of --user-install
+func (env gemEnvironment) binDir(dir string) string {
+	if dir == env.InstallDir && env.ExecutableDir != "" {
+		return env.ExecutableDir
+	}
+	return filepath.Join(dir, "bin")
+}
+
+// parseGemEnvironment parses `gem environment`:
+//
+//	RubyGems Environment:
+//	  - INSTALLATION DIRECTORY: /var/lib/gems/3.1.0
+//	  - EXECUTABLE DIRECTORY: /usr/local/bin
+//	  - GEM PATHS:
+//	     - /var/lib/gems/3.1.0
+//	     - /root/.local/share/gem/ruby/3.1.0
+//	  - GEM CONFIGURATION:
+func parseGemEnvironment(output string) gemEnvironment {
+	var env gemEnvironment
+	inPaths := false
+	for _, line := range strings.Split(output, "\n") {
+		item, ok := strings.CutPrefix(strings.TrimSpace(line), "- ")
+		if !ok {
+			continue
+		}
+		// Gem paths are indented further than the settings
+		if inPaths && strings.HasPrefix(line, "     ") {
+			env.Paths = append(env.Paths, strings.TrimSpace(item))
+			continue
+		}
+		inPaths = false
+		key, value, _ := strings.Cut(item, ":")
+		switch key {
+		case "INSTALLATION DIRECTORY":
+			env.InstallDir = strings.TrimSpace(value)
+		case "EXECUTABLE DIRECTORY":
+			env.ExecutableDir = strings.TrimSpace(value)
+		case "GEM PATHS":
+			inPaths = true
+		}
+	}
+	return env
+}
+
+// readGemExecutables reads the executables of the gems installed in a gem
+// path from their specifications, keyed by "name-version". Installed
+// specifications are Ruby, but RubyGems writes them in a fixed form with
+// one attribute per line:
+//
+//	s.name = "rubocop".freeze
+//	s.version = "1.60.2"
+//	s.executables = ["rubocop".freeze]
+func readGemExecutables(dir string) map[string][]string {
+	executables := make(map[string][]string)
+	for _, pattern := range []string{"*.gemspec", filepath.Join("default", "*.gemspec")} {
+		specs, _ := filepath.Glob(filepath.Join(dir, "specifications", pattern))
+		for _, spec := range specs {
+			data, err := os.ReadFile(spec)
+			if err != nil {
+				continue
+			}
+			var name, version string
+			var bins []string
+			for _, line := range strings.Split(string(data), "\n") {
+				attribute, value, ok := strings.Cut(strings.TrimSpace(line), " = ")
+				if !ok {
+					continue
+				}
+				switch attribute {
+				case "s.name":
+					name = firstQuoted(value)
+				case "s.version":
+					version = firstQuoted(value)
+				case "s.executables":
+					bins = quotedStrings(value)
+				}
+			}
+			if name != "" && version != "" {
+				executables[name+"-"+version] = bins
+			}
+		}
+	}
+	return executables
+}
+
+// quotedStrings returns the double-quoted strings in a line of Ruby
+func quotedStrings(line string) []string {
+	var values []string
+	for {
+		start := strings.Index(line, `"`)
+		if start < 0 {
+			return values
+		}
+		end := strings.Index(line[start+1:], `"`)
+		if end < 0 {
+			return values
+		}
+		values = append(values, line[start+1:start+1+end])
+		line = line[start+end+2:]
+	}
+}
+
+// firstQuoted returns the first double-quoted string in a line of Ruby
+func firstQuoted(line string) string {
+	if values := quotedStrings(line); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// gemCommand returns the name a gem executable is run by, without the .bat
+// extension of the wrappers RubyGems writes on Windows
+func gemCommand(file string) string {
+	return strings.TrimSuffix(file, ".bat")
+}
@@ -0,0 +1,61 @@
+package packages
+
+import (
+	"debug/buildinfo"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// detectGo detects CLIs installed with `go install` by reading the build
+// information embedded in each executable in $GOBIN and $GOPATH/bin, the
+// same data `go version -m` prints. Binaries built from one module (several
+// commands of golang.org/x/tools, say) are grouped into one package named by
+// the module path. Executables that are not Go binaries are skipped.
+func (d *Detector) detectGo() ([]Package, error) {
+	var packages []Package
+	index := make(map[string]int)
+
+	for _, dir := range BinDirs()[Go] {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if err := d.ctx.Err(); err != nil {
+				return packages, err
+			}
+			if entry.IsDir() {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			info, err := buildinfo.ReadFile(path)
+			if err != nil || info.Main.Path == "" {
+				continue
+			}
+
+			name := strings.TrimSuffix(entry.Name(), ".exe")
+			key := dir + "\x00" + info.Main.Path
+			if i, ok := index[key]; ok {
+				packages[i].Binaries = append(packages[i].Binaries, name)
+				continue
+			}
+			index[key] = len(packages)
+			packages = append(packages, Package{
+				Name:     info.Main.Path,
+				Version:  strings.TrimPrefix(info.Main.Version, "v"),
+				Manager:  Go,
+				Binaries: []string{name},
+				Location: dir,
+				Global:   true,
+			})
+		}
+	}
+
+	sort.SliceStable(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
@@ -1,24 +1,123 @@
 package packages
 
 import (
+	"fmt"
 	"path/filepath"
 	"strings"
 
 	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/shims"
 )
 
+// systemDirs are directories owned by the operating system rather than a
+// package manager this tool detects
+var systemDirs = []string{"/usr/bin", "/bin", "/usr/sbin", "/sbin"}
+
 // Linker links CLI tools to their source packages
 type Linker struct {
 	packages map[string]Package
+	// byManager indexes packages per manager so the same name from two
+	// managers (brew git, MacPorts git) stays distinguishable
+	byManager map[PackageManager]map[string]Package
+	// binaries maps manager -> binary name -> package for detectors that
+	// know exactly which executables a package installs
+	binaries map[PackageManager]map[string]Package
+	// explain records which strategy linked each tool (or why none did)
+	explain bool
+	// shims resolves tools that are version manager shims
+	shims *shims.Resolver
+	// nixPaths maps store paths to the nix packages installed from them
+	nixPaths map[string]Package
+	// brewCellars are the Cellar directories brew formulae are installed in
+	brewCellars []string
 }
 
 // NewLinker creates a new package linker
 func NewLinker(packages []Package) *Linker {
 	pkgMap := make(map[string]Package)
+	byManager := make(map[PackageManager]map[string]Package)
+	binaries := make(map[PackageManager]map[string]Package)
+	nixPaths := make(map[string]Package)
+	var brewCellars []string
 	for _, pkg := range packages {
 		pkgMap[pkg.Name] = pkg
+		if pkg.Manager == Nix {
+			nixPaths[pkg.Location] = pkg
+		}
+		if pkg.Manager == Brew && pkg.Location != "" {
+			brewCellars = appendUnique(brewCellars, filepath.Dir(pkg.Location))
+		}
+
+		if byManager[pkg.Manager] == nil {
+			byManager[pkg.Manager] = make(map[string]Package)
+			binaries[pkg.Manager] = make(map[string]Package)
+		}
+		byManager[pkg.Manager][pkg.Name] = pkg
+		for _, bin := range pkg.Binaries {
+			binaries[pkg.Manager][bin] = pkg
+		}
+	}
+	return &Linker{packages: pkgMap, byManager: byManager, binaries: binaries, nixPaths: nixPaths, brewCellars: brewCellars}
+}
+
+// SetExplain enables recording of the matching strategy on each linked tool
+func (l *Linker) SetExplain(explain bool) {
+	l.explain = explain
+}
+
+// SetShimResolver makes LinkTools attribute version manager shims to the
+// plugin and version they run, with the manager as their package manager
+func (l *Linker) SetShimResolver(resolver *shims.Resolver) {
+	l.shims = resolver
+}
+
+// link attributes a tool to pkg and, in explain mode, records how it matched
+func (l *Linker) link(tool *models.Tool, pkg Package, strategy, reason string) {
+	tool.PackageName = pkg.Name
+	tool.PackageManager = string(pkg.Manager)
+	tool.PackageVersion = pkg.Version
+	if l.explain {
+		tool.LinkStrategy = strategy
+		tool.LinkReason = reason
+	}
+}
+
+// lookup finds the package of a given manager that provides toolName,
+// preferring exact binary data over a package name match
+func (l *Linker) lookup(manager PackageManager, toolName string) (Package, bool) {
+	if pkg, ok := l.binaries[manager][toolName]; ok {
+		return pkg, true
 	}
-	return &Linker{packages: pkgMap}
+	pkg, ok := l.byManager[manager][toolName]
+	return pkg, ok
+}
+
+// IsSystemPath reports whether path is in a directory owned by the operating system
+func IsSystemPath(path string) bool {
+	return IsSystemDir(filepath.Dir(path))
+}
+
+// IsSystemDir reports whether dir is a directory owned by the operating system
+func IsSystemDir(dir string) bool {
+	dir = filepath.Clean(dir)
+	for _, sysDir := range systemDirs {
+		if dir == sysDir {
+			return true
+		}
+	}
+	return false
+}
+
+// Provenance describes where a tool installation comes from: its package
+// manager, "system" for OS-provided binaries, or "unmanaged"
+func Provenance(tool models.Tool) string {
+	if tool.PackageManager != "" {
+		return tool.PackageManager
+	}
+	if IsSystemPath(tool.Path) {
+		return "system"
+	}
+	return "unmanaged"
 }
 
 // LinkTools links tools to their source packages using various heuristics
@@ -35,20 +134,65 @@ func (l *Linker) LinkTools(tools []models.Tool) []models.Tool {
 
 // linkTool attempts to link a single tool to its package
 func (l *Linker) linkTool(tool *models.Tool) {
-	// Strategy 1: Direct name match (e.g., "vercel" package -> "vercel" cli)
-	if pkg, ok := l.packages[tool.Name]; ok {
-		tool.PackageName = pkg.Name
-		tool.PackageManager = string(pkg.Manager)
-		tool.PackageVersion = pkg.Version
-		return
+	// A shim runs whatever version its manager selects, even when it is a
+	// symlink to the manager itself, as mise's are
+	if l.shims != nil {
+		if info := l.shims.Resolve(tool.Name, tool.Path); info != nil {
+			l.linkShim(tool, info)
+			return
+		}
 	}
 
-	// Strategy 2: Path-based detection
+	// Strategy 1: Path-based detection. This runs first because the path
+	// tells us which manager installed this copy when several provide it
 	l.detectFromPath(tool)
+	if tool.PackageName != "" {
+		return
+	}
+
+	// Binaries in OS directories belong to the system, not to a package
+	// manager that happens to ship a tool of the same name
+	if IsSystemPath(tool.Path) {
+		l.unlinked(tool, "in an operating system directory")
+		return
+	}
+
+	// Strategy 2: Direct name match (e.g., "vercel" package -> "vercel" cli)
+	if pkg, ok := l.packages[tool.Name]; ok {
+		l.link(tool, pkg, "name", "tool name matches package name")
+		return
+	}
 
 	// Strategy 3: Common patterns (e.g., @supabase/cli -> supabase)
+	l.detectFromPatterns(tool)
 	if tool.PackageName == "" {
-		l.detectFromPatterns(tool)
+		l.unlinked(tool, "no package matched its path, name, or naming patterns")
+	}
+}
+
+// linkShim attributes a shim to its manager and the plugin it runs
+func (l *Linker) linkShim(tool *models.Tool, info *models.ShimInfo) {
+	tool.Shim = info
+	tool.PackageManager = info.Manager
+	tool.PackageName = info.Plugin
+	tool.PackageVersion = info.Version
+	if l.explain {
+		tool.LinkStrategy = "shim"
+		tool.LinkReason = fmt.Sprintf("in the %s shims directory", info.Manager)
+		if info.Manager == string(shims.Nvm) {
+			tool.LinkReason = "in the bin directory of a node version nvm installed"
+		}
+		if info.Target != "" {
+			tool.LinkReason += "; runs " + info.Target
+		}
+	}
+}
+
+// unlinked records why a tool could not be linked in explain mode
+func (l *Linker) unlinked(tool *models.Tool, reason string) {
+	if l.explain {
+		tool.LinkStrategy = "none"
+		tool.LinkReason = reason
 	}
 }
 
@@ -60,6 +204,16 @@ func (l *Linker) detectFromPath(tool *models.Tool) {
 		paths = append(paths, tool.SymlinkTo)
 	}
 
+	// Nix profiles are chains of symlinks that end in the store, and brew's
+	// links (and opt directories) lead into a keg in the Cellar
+	if resolved, err := filepath.EvalSymlinks(tool.Path); err == nil && resolved != tool.Path {
+		if _, ok := nixStorePath(resolved); ok {
+			paths = append(paths, resolved)
+		} else if _, _, ok := l.brewKeg(resolved); ok {
+			paths = append(paths, resolved)
+		}
+	}
+
 	for _, path := range paths {
 		if l.checkPath(tool, path) {
 			return
@@ -69,6 +223,13 @@ func (l *Linker) detectFromPath(tool *models.Tool) {
 
 // checkPath checks a single path for package information
 func (l *Linker) checkPath(tool *models.Tool, path string) bool {
+	// npm global packages: the commands their package.json declares, linked
+	// into npm's global bin directory
+	if pkg, ok := l.binaries[NPM][npmCommand(filepath.Base(path))]; ok && filepath.Clean(filepath.Dir(path)) == filepath.Clean(pkg.Location) {
+		l.link(tool, pkg, "path", "npm package bin in "+path)
+		return true
+	}
+
 	// NPM global modules (.nvm, node_modules)
 	if strings.Contains(path, "node_modules") {
 		parts := strings.Split(path, "node_modules")
@@ -83,64 +244,187 @@ func (l *Linker) checkPath(tool *models.Tool, path string) bool {
 					pkgName = pkgName + "/" + pkgParts[1]
 				}
 				if pkg, ok := l.packages[pkgName]; ok {
-					tool.PackageName = pkg.Name
-					tool.PackageManager = string(pkg.Manager)
-					tool.PackageVersion = pkg.Version
+					l.link(tool, pkg, "path", "node_modules segment in "+path)
 					return true
 				}
 			}
 		}
 	}
 
-	// Homebrew packages
-	if strings.Contains(path, "/opt/homebrew/") || strings.Contains(path, "/usr/local/Cellar/") || strings.Contains(path, "Cellar/") {
-		// Extract from /opt/homebrew/Cellar/package/version/bin/tool or ../Cellar/package/version/bin/tool
-		if strings.Contains(path, "Cellar/") {
-			parts := strings.Split(path, "Cellar/")
-			if len(parts) > 1 {
-				remaining := parts[1]
-				pkgName := strings.Split(remaining, "/")[0]
-				if pkg, ok := l.packages[pkgName]; ok {
-					tool.PackageName = pkg.Name
-					tool.PackageManager = string(pkg.Manager)
-					tool.PackageVersion = pkg.Version
-					return true
-				}
-			}
+	// Homebrew kegs: <cellar>/<formula>/<version>/..., attributed to the
+	// formula the keg's directory is named after or, failing that, the one
+	// its install receipt records. Only the formula is taken from the path;
+	// the version comes from brew's metadata, which knows the linked keg.
+	if formula, keg, ok := l.brewKeg(path); ok {
+		if pkg, found := l.lookup(Brew, formula); found {
+			l.link(tool, pkg, "path", "Homebrew keg "+keg)
+			return true
+		}
+		if pkg, found := l.lookup(Brew, brewReceiptFormula(keg)); found {
+			l.link(tool, pkg, "path", "Homebrew install receipt in "+keg)
+			return true
 		}
+	}
 
-		// Try extracting from /opt/homebrew/opt/package
-		if strings.Contains(path, "/opt/") {
-			parts := strings.Split(path, "/opt/")
-			if len(parts) > 1 {
-				remaining := parts[1]
-				pkgName := strings.Split(remaining, "/")[0]
-				if pkg, ok := l.packages[pkgName]; ok {
-					tool.PackageName = pkg.Name
-					tool.PackageManager = string(pkg.Manager)
-					tool.PackageVersion = pkg.Version
-					return true
-				}
+	// MacPorts packages (/opt/local/bin, /opt/local/sbin)
+	if strings.HasPrefix(path, macPortsPrefix+"/") {
+		if pkg, ok := l.lookup(MacPorts, filepath.Base(path)); ok {
+			l.link(tool, pkg, "path", "MacPorts prefix in "+path)
+			return true
+		}
+	}
+
+	// Nix packages (/nix/store/<hash>-<name>-<version>/bin/tool)
+	if storePath, ok := nixStorePath(path); ok {
+		if pkg, ok := l.nixPaths[storePath]; ok {
+			l.link(tool, pkg, "path", "nix store path "+storePath)
+			return true
+		}
+	}
+
+	// Snap commands (/snap/bin/<snap>[.<app>] and aliases)
+	for _, dir := range snapBinDirs {
+		if filepath.Dir(path) == dir {
+			if pkg, ok := l.lookup(Snap, filepath.Base(path)); ok {
+				l.link(tool, pkg, "path", "snap bin directory in "+path)
+				return true
 			}
 		}
 	}
 
-	// Python packages (.pyenv, site-packages)
-	if strings.Contains(path, "site-packages") || strings.Contains(path, ".pyenv") {
-		// Python CLIs are harder to detect, skip for now
-		return false
+	// Flatpak exports, named by application ID
+	if app, ok := flatpakApp(path); ok {
+		if pkg, ok := l.lookup(Flatpak, app); ok {
+			l.link(tool, pkg, "path", "flatpak export in "+path)
+			return true
+		}
+	}
+
+	// pipx apps: a symlink into (or, on Windows, a copy from) the app's
+	// venv, exposed in the pipx bin directory
+	if pkg, ok := l.binaries[Pipx][filepath.Base(path)]; ok {
+		if filepath.Clean(filepath.Dir(path)) == filepath.Clean(pkg.Location) || strings.Contains(filepath.ToSlash(path), "pipx/venvs/") {
+			l.link(tool, pkg, "path", "pipx app in "+path)
+			return true
+		}
+	}
+
+	// uv tools: like pipx apps, exposed in uv's bin directory from the
+	// tool's environment
+	if name, ok := uvTool(path); ok {
+		if pkg, ok := l.lookup(Uv, name); ok {
+			l.link(tool, pkg, "path", "uv tool environment in "+path)
+			return true
+		}
+	}
+	if pkg, ok := l.binaries[Uv][filepath.Base(path)]; ok && filepath.Clean(filepath.Dir(path)) == pkg.Location {
+		l.link(tool, pkg, "path", "uv tool executable in "+path)
+		return true
+	}
+
+	// poetry and its plugins' commands, in poetry's environment or linked
+	// from it into poetry's bin directory
+	if pkg, ok := l.binaries[Poetry][filepath.Base(path)]; ok {
+		if dir := filepath.Clean(filepath.Dir(path)); dir == filepath.Clean(pkg.Location) || dir == filepath.Clean(poetryBinDir()) {
+			l.link(tool, pkg, "path", "poetry environment command "+path)
+			return true
+		}
+	}
+
+	// pip packages: console scripts and scripts a distribution installed
+	// into the bin directory of its environment, as its metadata records
+	if pkg, ok := l.binaries[Pip][strings.TrimSuffix(filepath.Base(path), ".exe")]; ok && filepath.Clean(filepath.Dir(path)) == filepath.Clean(pkg.Location) {
+		l.link(tool, pkg, "path", "pip entry point in "+path)
+		return true
+	}
+
+	// Windows package managers. Paths are compared case-insensitively with
+	// forward slashes so the checks read the same on every platform.
+	slashed := strings.ToLower(filepath.ToSlash(path))
+	command := strings.TrimSuffix(filepath.Base(slashed), filepath.Ext(slashed))
+
+	// scoop: shims named after the manifest's bin entries, or an app's own
+	// directory (scoop/apps/<app>/...)
+	if strings.Contains(slashed, "/scoop/shims/") {
+		if pkg, ok := l.lookup(Scoop, command); ok {
+			l.link(tool, pkg, "path", "scoop shim in "+path)
+			return true
+		}
+	}
+	if i := strings.Index(slashed, "/scoop/apps/"); i >= 0 {
+		app := strings.Split(slashed[i+len("/scoop/apps/"):], "/")[0]
+		if pkg, ok := l.lookup(Scoop, app); ok {
+			l.link(tool, pkg, "path", "scoop app directory in "+path)
+			return true
+		}
 	}
 
-	// Cargo packages (.cargo/bin)
-	if strings.Contains(path, ".cargo/bin") {
-		toolName := filepath.Base(path)
-		if pkg, ok := l.packages[toolName]; ok && pkg.Manager == Cargo {
-			tool.PackageName = pkg.Name
-			tool.PackageManager = string(pkg.Manager)
-			tool.PackageVersion = pkg.Version
+	// Chocolatey: shims in chocolatey/bin named after the package or one of
+	// its executables, or files under chocolatey/lib/<package>/
+	if strings.Contains(slashed, "/chocolatey/bin/") {
+		if pkg, ok := l.lookup(Chocolatey, command); ok {
+			l.link(tool, pkg, "path", "Chocolatey shim in "+path)
 			return true
 		}
 	}
+	if i := strings.Index(slashed, "/chocolatey/lib/"); i >= 0 {
+		name := strings.Split(slashed[i+len("/chocolatey/lib/"):], "/")[0]
+		if pkg, ok := l.lookup(Chocolatey, name); ok {
+			l.link(tool, pkg, "path", "Chocolatey lib directory in "+path)
+			return true
+		}
+	}
+
+	// winget: portable packages live in WinGet/Packages/<Id>_<source>/ and
+	// are linked from WinGet/Links
+	segments := strings.Split(filepath.ToSlash(path), "/")
+	for i := 0; i+2 < len(segments); i++ {
+		if strings.EqualFold(segments[i], "WinGet") && strings.EqualFold(segments[i+1], "Packages") {
+			id, _, _ := strings.Cut(segments[i+2], "_")
+			if pkg, ok := l.lookup(Winget, id); ok {
+				l.link(tool, pkg, "path", "winget package directory in "+path)
+				return true
+			}
+		}
+	}
+
+	// Go binaries ($GOBIN, $GOPATH/bin), identified by their build info
+	if pkg, ok := l.binaries[Go][strings.TrimSuffix(filepath.Base(path), ".exe")]; ok && pkg.Location == filepath.Dir(path) {
+		l.link(tool, pkg, "path", "Go build info of "+path)
+		return true
+	}
+
+	// deno install scripts, identified by the module they run
+	if pkg, ok := l.binaries[Deno][denoCommand(filepath.Base(path))]; ok && pkg.Location == filepath.Dir(path) {
+		l.link(tool, pkg, "path", "deno install script in "+path)
+		return true
+	}
+
+	// .NET global tools (~/.dotnet/tools)
+	if pkg, ok := l.binaries[Dotnet][strings.TrimSuffix(filepath.Base(path), ".exe")]; ok && pkg.Location == filepath.Dir(path) {
+		l.link(tool, pkg, "path", ".NET global tool in "+path)
+		return true
+	}
+
+	// composer global packages (~/.composer/vendor/bin)
+	if pkg, ok := l.binaries[Composer][composerCommand(filepath.Base(path))]; ok && pkg.Location == filepath.Dir(path) {
+		l.link(tool, pkg, "path", "composer global binary in "+path)
+		return true
+	}
+
+	// Gems: the executables their specifications list, wrapped in the bin
+	// directory of the gem path they are installed in
+	if pkg, ok := l.binaries[Gem][gemCommand(filepath.Base(path))]; ok && pkg.Location == filepath.Dir(path) {
+		l.link(tool, pkg, "path", "gem executable in "+path)
+		return true
+	}
+
+	// Cargo crates: the binaries .crates2.json records for each crate, in
+	// the install root's bin directory
+	if pkg, ok := l.binaries[Cargo][strings.TrimSuffix(filepath.Base(path), ".exe")]; ok && pkg.Location == filepath.Dir(path) {
+		l.link(tool, pkg, "path", "cargo crate binary in "+path)
+		return true
+	}
 
 	return false
 }
@@ -164,9 +448,7 @@ func (l *Linker) detectFromPatterns(tool *models.Tool) {
 	for _, pattern := range patterns {
 		if pattern != name {
 			if pkg, ok := l.packages[pattern]; ok {
-				tool.PackageName = pkg.Name
-				tool.PackageManager = string(pkg.Manager)
-				tool.PackageVersion = pkg.Version
+				l.link(tool, pkg, "pattern", fmt.Sprintf("name pattern %q matches package", pattern))
 				return
 			}
 		}
@@ -178,9 +460,7 @@ func (l *Linker) detectFromPatterns(tool *models.Tool) {
 		if len(parts) == 2 {
 			// Try @scope/package
 			if pkg, ok := l.packages[name]; ok {
-				tool.PackageName = pkg.Name
-				tool.PackageManager = string(pkg.Manager)
-				tool.PackageVersion = pkg.Version
+				l.link(tool, pkg, "pattern", "scoped package name matches package")
 				return
 			}
 		}
@@ -208,9 +488,21 @@ func GetPackagesWithBinaries(packages []Package, tools []models.Tool) []models.P
 				Binaries: binaries,
 				Location: pkg.Location,
 				Global:   pkg.Global,
+				Source:   pkg.Source,
 			})
 		}
 	}
 
 	return result
 }
+
+// brewKeg returns the formula and keg path lies in, when it is in one of the
+// Cellars brew formulae were detected in
+func (l *Linker) brewKeg(path string) (formula, keg string, ok bool) {
+	for _, cellar := range l.brewCellars {
+		if formula, keg, ok := brewKeg(cellar, path); ok {
+			return formula, keg, true
+		}
+	}
+	return "", "", false
+}
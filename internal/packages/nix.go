@@ -0,0 +1,270 @@
+package packages
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// nixStore is where nix keeps every package it builds or downloads
+const nixStore = "/nix/store"
+
+// nixOutputs are the output names nix appends to a store path, as in
+// git-2.43.0-doc, which are not part of the version
+var nixOutputs = []string{"bin", "out", "dev", "lib", "man", "doc", "info"}
+
+// nixProfileBins returns the bin directories of the nix profiles on this
+// machine: the user's (nix-env and nix profile), home-manager's, the
+// multi-user default, and NixOS's system profile. ~/.nix-profile usually
+// links to the profile under ~/.local/state, so paths to the same
+// directory are listed once.
+func nixProfileBins() []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, dir := range nixProfileCandidates() {
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err != nil {
+			resolved = dir
+		}
+		if !seen[resolved] {
+			seen[resolved] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+func nixProfileCandidates() []string {
+	home, _ := os.UserHomeDir()
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	dirs := []string{
+		filepath.Join(home, ".nix-profile", "bin"),
+		filepath.Join(stateHome, "nix", "profile", "bin"),
+	}
+	if user := os.Getenv("USER"); user != "" {
+		dirs = append(dirs, filepath.Join("/etc/profiles/per-user", user, "bin"))
+	}
+	return append(dirs, "/nix/var/nix/profiles/default/bin", "/run/current-system/sw/bin")
+}
+
+// nixProfileDirs returns the directories nix adds a generation link to
+// whenever a profile changes. Store paths all have the same modification
+// time, so these are what tell an install apart.
+func nixProfileDirs() []string {
+	home, _ := os.UserHomeDir()
+	stateHome := os.Getenv("XDG_STATE_HOME")
+	if stateHome == "" {
+		stateHome = filepath.Join(home, ".local", "state")
+	}
+	return []string{
+		filepath.Join(stateHome, "nix", "profiles"),
+		filepath.Join("/nix/var/nix/profiles/per-user", os.Getenv("USER")),
+		"/nix/var/nix/profiles",
+	}
+}
+
+// detectNix detects packages installed in nix profiles. The user profile
+// is listed with `nix profile list`, or `nix-env -q` when it is an older
+// nix-env profile, and the executables in every profile's bin directory are
+// followed into the store, which also finds packages the system
+// configuration or home-manager installed. A package's name and version
+// come from its store path, /nix/store/<hash>-<name>-<version>.
+func (d *Detector) detectNix() ([]Package, error) {
+	storePaths, err := d.nixProfileStorePaths()
+
+	var packages []Package
+	index := make(map[string]int)
+	add := func(storePath string) int {
+		if i, ok := index[storePath]; ok {
+			return i
+		}
+		_, drvName, _ := strings.Cut(filepath.Base(storePath), "-")
+		name, version := parseNixName(drvName)
+		index[storePath] = len(packages)
+		packages = append(packages, Package{
+			Name:     name,
+			Version:  version,
+			Manager:  Nix,
+			Location: storePath,
+			Global:   true,
+		})
+		return index[storePath]
+	}
+
+	for _, path := range storePaths {
+		storePath, ok := nixStorePath(path)
+		if !ok {
+			continue
+		}
+		i := add(storePath)
+		names, _ := os.ReadDir(filepath.Join(storePath, "bin"))
+		for _, entry := range names {
+			if !entry.IsDir() {
+				packages[i].Binaries = appendUnique(packages[i].Binaries, entry.Name())
+			}
+		}
+	}
+
+	for _, dir := range nixProfileBins() {
+		entries, readErr := os.ReadDir(dir)
+		if readErr != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if d.ctx.Err() != nil {
+				return packages, d.ctx.Err()
+			}
+			resolved, evalErr := filepath.EvalSymlinks(filepath.Join(dir, entry.Name()))
+			if evalErr != nil {
+				continue
+			}
+			storePath, ok := nixStorePath(resolved)
+			if !ok {
+				continue
+			}
+			i := add(storePath)
+			packages[i].Binaries = appendUnique(packages[i].Binaries, entry.Name())
+		}
+	}
+
+	if len(packages) == 0 && err != nil {
+		return nil, err
+	}
+	sort.SliceStable(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
+
+// nixProfileStorePaths lists the store paths installed in the user profile
+func (d *Detector) nixProfileStorePaths() ([]string, error) {
+	output, err := d.command("nix", "profile", "list", "--json").Output()
+	if err == nil {
+		if paths, parseErr := parseNixProfileList(output); parseErr == nil {
+			return paths, nil
+		}
+	}
+
+	// nix profile refuses to read a profile nix-env manages
+	output, envErr := d.command("nix-env", "-q", "--json", "--out-path").Output()
+	if envErr != nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, envErr
+	}
+	return parseNixEnvQuery(output)
+}
+
+// parseNixProfileList reads the store paths from `nix profile list --json`,
+// whose elements are a list before nix 2.20 and keyed by name since
+func parseNixProfileList(output []byte) ([]string, error) {
+	var profile struct {
+		Elements json.RawMessage `json:"elements"`
+	}
+	if err := json.Unmarshal(output, &profile); err != nil {
+		return nil, err
+	}
+
+	type element struct {
+		Active     *bool    `json:"active"`
+		StorePaths []string `json:"storePaths"`
+	}
+	var elements []element
+	var byName map[string]element
+	if err := json.Unmarshal(profile.Elements, &byName); err == nil {
+		names := make([]string, 0, len(byName))
+		for name := range byName {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			elements = append(elements, byName[name])
+		}
+	} else if err := json.Unmarshal(profile.Elements, &elements); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, e := range elements {
+		if e.Active != nil && !*e.Active {
+			continue
+		}
+		paths = append(paths, e.StorePaths...)
+	}
+	return paths, nil
+}
+
+// parseNixEnvQuery reads the store paths from `nix-env -q --json
+// --out-path`, which maps each installed derivation to its outputs
+func parseNixEnvQuery(output []byte) ([]string, error) {
+	var installed map[string]struct {
+		Outputs map[string]*string `json:"outputs"`
+	}
+	if err := json.Unmarshal(output, &installed); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, drv := range installed {
+		for _, path := range drv.Outputs {
+			if path != nil && *path != "" {
+				paths = append(paths, *path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// nixStorePath returns the store path holding path, /nix/store/<hash>-<name>
+func nixStorePath(path string) (string, bool) {
+	rest := strings.TrimPrefix(filepath.ToSlash(path), nixStore+"/")
+	if rest == filepath.ToSlash(path) {
+		return "", false
+	}
+	entry := strings.Split(rest, "/")[0]
+	if !strings.Contains(entry, "-") {
+		return "", false
+	}
+	return nixStore + "/" + entry, true
+}
+
+// parseNixName splits a store path name into a package name and version
+// the way nix does: the version starts at the first dash followed by
+// something other than a letter, so "python3-3.12.1" is python3 3.12.1 and
+// "nix-index-0.1.7" is nix-index 0.1.7. A trailing output name is dropped.
+func parseNixName(name string) (string, string) {
+	for _, output := range nixOutputs {
+		name = strings.TrimSuffix(name, "-"+output)
+	}
+	for i := 0; i+1 < len(name); i++ {
+		if name[i] != '-' {
+			continue
+		}
+		if next := name[i+1]; !('a' <= next && next <= 'z' || 'A' <= next && next <= 'Z') {
+			return name[:i], name[i+1:]
+		}
+	}
+	return name, ""
+}
+
+// nixEnvProfile reports whether the user profile is managed by nix-env,
+// which keeps a manifest.nix in it, rather than by nix profile
+func nixEnvProfile() bool {
+	home, _ := os.UserHomeDir()
+	_, err := os.Stat(filepath.Join(home, ".nix-profile", "manifest.nix"))
+	return err == nil
+}
+
+func appendUnique(list []string, value string) []string {
+	if containsString(list, value) {
+		return list
+	}
+	return append(list, value)
+}
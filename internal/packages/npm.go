@@ -0,0 +1,60 @@
+package packages
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// npmBinDir returns the directory npm links global packages' binaries into,
+// given the global node_modules directory from `npm root -g`:
+// <prefix>/lib/node_modules links into <prefix>/bin, and on Windows
+// <prefix>\node_modules into <prefix> itself
+func npmBinDir(root string) string {
+	prefix := filepath.Dir(root)
+	if runtime.GOOS == "windows" {
+		return prefix
+	}
+	return filepath.Join(filepath.Dir(prefix), "bin")
+}
+
+// readNpmBinaries returns the commands a global package installs, from the
+// bin field of its package.json in root. bin is either a map of command
+// names to scripts or, for a package with one command, a single script run
+// by the package's name without its scope.
+func readNpmBinaries(root, name string) []string {
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name), "package.json"))
+	if err != nil {
+		return nil
+	}
+	var manifest struct {
+		Bin json.RawMessage `json:"bin"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil || len(manifest.Bin) == 0 {
+		return nil
+	}
+
+	var script string
+	if err := json.Unmarshal(manifest.Bin, &script); err == nil {
+		return []string{name[strings.LastIndex(name, "/")+1:]}
+	}
+	var scripts map[string]string
+	if err := json.Unmarshal(manifest.Bin, &scripts); err != nil {
+		return nil
+	}
+	var binaries []string
+	for command := range scripts {
+		binaries = append(binaries, command)
+	}
+	sort.Strings(binaries)
+	return binaries
+}
+
+// npmCommand returns the name an npm binary is run by, without the .cmd and
+// .ps1 extensions of the wrappers npm writes on Windows
+func npmCommand(file string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(file, ".cmd"), ".ps1")
+}
@@ -0,0 +1,247 @@
+package packages
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// pythonName matches interpreter names: python, python3, python3.12, and
+// their .exe forms on Windows
+var pythonName = regexp.MustCompile(`^python(\d+(\.\d+)?)?(\.exe)?$`)
+
+// pipTimeout bounds each interpreter probe; `pip list` can be slow on large
+// environments but should never hang an audit
+const pipTimeout = 30 * time.Second
+
+// PipEnvironment is the set of pip packages installed for one Python
+// interpreter
+type PipEnvironment struct {
+	// Interpreter is the first PATH entry found for this environment
+	Interpreter string `json:"interpreter"`
+	// Prefix is the environment's sys.prefix, which identifies it
+	Prefix   string    `json:"prefix"`
+	Packages []Package `json:"packages"`
+}
+
+// PipInstall is one interpreter's copy of a package
+type PipInstall struct {
+	Interpreter string `json:"interpreter"`
+	Version     string `json:"version"`
+}
+
+// PipSkew is a package installed at different versions under different
+// Python interpreters
+type PipSkew struct {
+	Name     string       `json:"name"`
+	Installs []PipInstall `json:"installs"`
+}
+
+// pipEnvironmentsKey is the package cache entry holding the environments
+// DetectPipEnvironments found
+const pipEnvironmentsKey PackageManager = "pip-environments"
+
+// DetectPipEnvironments lists the pip packages of every distinct Python
+// interpreter on PATH. Interpreters that share an environment (python and
+// python3 in the same venv, or a symlink to another interpreter) are probed
+// once; interpreters without pip are skipped. Probing stops when the
+// detector's context is cancelled.
+//
+// Running pip for each interpreter takes seconds, so with the package cache
+// on (see SetCache), the environments are reused for as long as PATH, the
+// interpreters, and their site-packages directories are unchanged.
+func (d *Detector) DetectPipEnvironments(paths []string) []PipEnvironment {
+	pythons := findPythons(paths)
+
+	var cache *packageCache
+	var stamps map[string]int64
+	if d.cacheTTL > 0 {
+		cache = loadPackageCache(packageCachePath(), d.cacheTTL)
+		stamps = pipEnvironmentStamps(pythons)
+		if !d.refreshCache {
+			if entry, ok := cache.entry(pipEnvironmentsKey, stamps); ok {
+				return entry.Environments
+			}
+		}
+	}
+
+	var envs []PipEnvironment
+	seenPrefix := make(map[string]bool)
+	for _, interpreter := range pythons {
+		if d.ctx.Err() != nil {
+			break
+		}
+		prefix, err := pythonPrefix(d.ctx, interpreter)
+		if err != nil || seenPrefix[prefix] {
+			continue
+		}
+		seenPrefix[prefix] = true
+
+		pkgs, err := pipList(d.ctx, interpreter)
+		if err != nil {
+			continue
+		}
+		envs = append(envs, PipEnvironment{Interpreter: interpreter, Prefix: prefix, Packages: pkgs})
+	}
+
+	// A cancelled probe may have skipped interpreters, so it is not cached
+	if cache != nil && d.ctx.Err() == nil {
+		cache.putEnvironments(pipEnvironmentsKey, stamps, envs)
+		_ = cache.save()
+	}
+	return envs
+}
+
+// pipEnvironmentStamps returns the modification times of the interpreters
+// and of the site-packages directories pip installs into for them, which
+// installing, upgrading, or removing a package changes. An interpreter's
+// prefix is taken to be the directory above its bin directory, or its own
+// directory on Windows, both before and after resolving symlinks.
+func pipEnvironmentStamps(pythons []string) map[string]int64 {
+	stamps := metadataStamps(Pip, nil)
+	for _, interpreter := range pythons {
+		files := []string{interpreter}
+		if resolved, err := filepath.EvalSymlinks(interpreter); err == nil && resolved != interpreter {
+			files = append(files, resolved)
+		}
+		for _, file := range files {
+			if info, err := os.Stat(file); err == nil {
+				stamps[file] = info.ModTime().UnixNano()
+			}
+			prefixes := []string{filepath.Dir(filepath.Dir(file)), filepath.Dir(file)}
+			for _, prefix := range prefixes {
+				dirs := sitePackagesDirs(prefix)
+				distPackages, _ := filepath.Glob(filepath.Join(prefix, "lib", "python*", "dist-packages"))
+				for _, dir := range append(dirs, distPackages...) {
+					if info, err := os.Stat(dir); err == nil {
+						stamps[dir] = info.ModTime().UnixNano()
+					}
+				}
+			}
+		}
+	}
+	return stamps
+}
+
+// findPythons returns the Python interpreters in PATH order, one per
+// resolved file
+func findPythons(paths []string) []string {
+	var pythons []string
+	seen := make(map[string]bool)
+	for _, dir := range paths {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !pythonName.MatchString(entry.Name()) {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			key := path
+			if resolved, err := filepath.EvalSymlinks(path); err == nil {
+				key = resolved
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			pythons = append(pythons, path)
+		}
+	}
+	return pythons
+}
+
+// pythonPrefix returns the interpreter's sys.prefix
+func pythonPrefix(ctx context.Context, interpreter string) (string, error) {
+	output, err := runPython(ctx, interpreter, "-c", "import sys; print(sys.prefix)")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// pipList runs `python -m pip list` for one interpreter
+func pipList(ctx context.Context, interpreter string) ([]Package, error) {
+	output, err := runPython(ctx, interpreter, "-m", "pip", "list", "--format=json", "--disable-pip-version-check")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, err
+	}
+
+	var pkgs []Package
+	for _, item := range result {
+		pkgs = append(pkgs, Package{
+			Name:     item.Name,
+			Version:  item.Version,
+			Manager:  Pip,
+			Location: interpreter,
+		})
+	}
+	return pkgs, nil
+}
+
+func runPython(ctx context.Context, interpreter string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, pipTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, interpreter, args...).Output()
+}
+
+// pipBootstrap are packages every interpreter carries its own copy of, so
+// differing versions are expected rather than a hazard
+var pipBootstrap = map[string]bool{"pip": true, "setuptools": true, "wheel": true}
+
+// PipVersionSkew finds packages installed under more than one interpreter at
+// different versions. Packages at the same version everywhere are not
+// reported, nor are pip, setuptools, and wheel. Names are compared the way
+// pip does, ignoring case and treating -, _, and . alike.
+func PipVersionSkew(envs []PipEnvironment) []PipSkew {
+	installs := make(map[string][]PipInstall)
+	names := make(map[string]string)
+	for _, env := range envs {
+		for _, pkg := range env.Packages {
+			key := normalizePipName(pkg.Name)
+			if pipBootstrap[key] {
+				continue
+			}
+			if _, ok := names[key]; !ok {
+				names[key] = pkg.Name
+			}
+			installs[key] = append(installs[key], PipInstall{Interpreter: env.Interpreter, Version: pkg.Version})
+		}
+	}
+
+	var skews []PipSkew
+	for key, list := range installs {
+		versions := make(map[string]bool)
+		for _, install := range list {
+			versions[install.Version] = true
+		}
+		if len(versions) > 1 {
+			skews = append(skews, PipSkew{Name: names[key], Installs: list})
+		}
+	}
+	sort.Slice(skews, func(i, j int) bool {
+		return strings.ToLower(skews[i].Name) < strings.ToLower(skews[j].Name)
+	})
+	return skews
+}
+
+// normalizePipName applies PEP 503 name normalization
+func normalizePipName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer("_", "-", ".", "-").Replace(name)
+}
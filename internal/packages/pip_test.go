@@ -0,0 +1,57 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestDetectPipEnvironmentsCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake interpreter is a shell script")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	// An interpreter that fails every probe, so only the cache can list
+	// its packages
+	dir := t.TempDir()
+	python := filepath.Join(dir, "python3")
+	if err := os.WriteFile(python, []byte("#!/bin/sh\nexit 1\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	cached := []PipEnvironment{{
+		Interpreter: python,
+		Prefix:      dir,
+		Packages:    []Package{{Name: "requests", Version: "2.31.0", Manager: Pip, Location: python}},
+	}}
+	cache := loadPackageCache(packageCachePath(), time.Hour)
+	cache.putEnvironments(pipEnvironmentsKey, pipEnvironmentStamps(findPythons([]string{dir})), cached)
+	if err := cache.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	d := NewDetector()
+	d.SetCache(time.Hour, false)
+	if envs := d.DetectPipEnvironments([]string{dir}); len(envs) != 1 || envs[0].Packages[0].Name != "requests" {
+		t.Errorf("with the cache on, got %+v, want the cached environment", envs)
+	}
+
+	d.SetCache(time.Hour, true)
+	if envs := d.DetectPipEnvironments([]string{dir}); len(envs) != 0 {
+		t.Errorf("with refresh, got %+v, want the interpreter probed again", envs)
+	}
+
+	// Changing an interpreter invalidates the entry
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(python, later, later); err != nil {
+		t.Fatal(err)
+	}
+	cache = loadPackageCache(packageCachePath(), time.Hour)
+	if _, ok := cache.entry(pipEnvironmentsKey, pipEnvironmentStamps(findPythons([]string{dir}))); ok {
+		t.Error("cache entry still valid after the interpreter changed")
+	}
+}
@@ -0,0 +1,89 @@
+package packages
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// pipxList is the subset of `pipx list --json` we use. Each venv holds one
+// main package and exposes its apps in the pipx bin directory.
+type pipxList struct {
+	Venvs map[string]struct {
+		Metadata struct {
+			MainPackage pipxPackage            `json:"main_package"`
+			Injected    map[string]pipxPackage `json:"injected_packages"`
+		} `json:"metadata"`
+	} `json:"venvs"`
+}
+
+type pipxPackage struct {
+	Package        string   `json:"package"`
+	PackageVersion string   `json:"package_version"`
+	Apps           []string `json:"apps"`
+	// AppsOfDependencies are exposed only for packages installed with
+	// --include-deps
+	AppsOfDependencies  []string `json:"apps_of_dependencies"`
+	IncludeDependencies bool     `json:"include_dependencies"`
+	// IncludeApps is set on injected packages installed with --include-apps
+	IncludeApps bool `json:"include_apps"`
+}
+
+// detectPipx detects applications installed with pipx. Each venv's main
+// package is reported with the version installed in its venv and the apps
+// it exposes; apps of injected packages installed with --include-apps are
+// attributed to the venv's main package, since that is what pipx manages.
+func (d *Detector) detectPipx() ([]Package, error) {
+	output, err := d.command("pipx", "list", "--json").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parsePipxList(output, pipxBinDir())
+}
+
+// parsePipxList parses `pipx list --json` output, locating apps in binDir
+func parsePipxList(output []byte, binDir string) ([]Package, error) {
+	var list pipxList
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, err
+	}
+
+	var packages []Package
+	for _, venv := range list.Venvs {
+		main := venv.Metadata.MainPackage
+		binaries := append([]string{}, main.Apps...)
+		if main.IncludeDependencies {
+			binaries = append(binaries, main.AppsOfDependencies...)
+		}
+		for _, injected := range venv.Metadata.Injected {
+			if injected.IncludeApps {
+				binaries = append(binaries, injected.Apps...)
+			}
+		}
+
+		packages = append(packages, Package{
+			Name:     main.Package,
+			Version:  main.PackageVersion,
+			Manager:  Pipx,
+			Binaries: binaries,
+			Location: binDir,
+			Global:   true,
+		})
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
+
+// pipxBinDir returns the directory pipx exposes apps in: $PIPX_BIN_DIR, or
+// ~/.local/bin by default
+func pipxBinDir() string {
+	if dir := os.Getenv("PIPX_BIN_DIR"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "bin")
+}
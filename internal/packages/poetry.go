@@ -0,0 +1,99 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+)
+
+// poetryPluginGroups are the entry point groups poetry loads plugins from
+var poetryPluginGroups = []string{"poetry.application.plugin", "poetry.plugin"}
+
+// poetryHome returns where poetry's official installer puts poetry:
+// $POETRY_HOME, or pypoetry in the user data directory
+func poetryHome() string {
+	if home := os.Getenv("POETRY_HOME"); home != "" {
+		return home
+	}
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		return filepath.Join(os.Getenv("APPDATA"), "pypoetry")
+	case "darwin":
+		return filepath.Join(home, "Library", "Application Support", "pypoetry")
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "pypoetry")
+}
+
+// poetryVenvBin returns the bin directory of the virtual environment
+// poetry and its plugins are installed in
+func poetryVenvBin() string {
+	venv := filepath.Join(poetryHome(), "venv")
+	if runtime.GOOS == "windows" {
+		return filepath.Join(venv, "Scripts")
+	}
+	return filepath.Join(venv, "bin")
+}
+
+// poetryBinDir returns the directory the installer links the poetry
+// command into: $POETRY_HOME/bin, or ~/.local/bin (%APPDATA%\Python\Scripts
+// on Windows)
+func poetryBinDir() string {
+	if home := os.Getenv("POETRY_HOME"); home != "" {
+		return filepath.Join(home, "bin")
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "Python", "Scripts")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "bin")
+}
+
+// detectPoetry detects poetry installed with its official installer, and
+// the plugins added to it with `poetry self add`. Both live in poetry's own
+// virtual environment, so they are read from its site-packages rather than
+// by running poetry.
+func (d *Detector) detectPoetry() ([]Package, error) {
+	venv := filepath.Join(poetryHome(), "venv")
+	dirs := sitePackagesDirs(venv)
+	if len(dirs) == 0 {
+		return nil, os.ErrNotExist
+	}
+
+	var packages []Package
+	for _, dir := range dirs {
+		for _, dist := range readPythonDists(dir) {
+			if dist.Name != "poetry" && !isPoetryPlugin(dist) {
+				continue
+			}
+			packages = append(packages, Package{
+				Name:     dist.Name,
+				Version:  dist.Version,
+				Manager:  Poetry,
+				Binaries: dist.EntryPoints["console_scripts"],
+				Location: poetryVenvBin(),
+				Global:   true,
+			})
+		}
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
+
+// isPoetryPlugin reports whether a distribution registers a poetry plugin
+func isPoetryPlugin(dist pythonDist) bool {
+	for _, group := range poetryPluginGroups {
+		if len(dist.EntryPoints[group]) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package packages
+
+import (
+	"sort"
+	"time"
+)
+
+// Timing is how long one package manager's detection took
+type Timing struct {
+	Manager  PackageManager `json:"manager"`
+	Duration time.Duration  `json:"-"`
+	// DurationMS is Duration in milliseconds, for JSON output
+	DurationMS int64 `json:"duration_ms"`
+	Packages   int   `json:"packages"`
+	// Error is why detection failed, usually because the manager is not
+	// installed
+	Error string `json:"error,omitempty"`
+}
+
+// Profile runs each enabled manager's detection in turn and times it. The
+// results are sorted slowest first, so the bottleneck leads.
+func (d *Detector) Profile() []Timing {
+	var timings []Timing
+	for _, manager := range d.enabledManagers {
+		if d.ctx.Err() != nil {
+			break
+		}
+		start := time.Now()
+		pkgs, err := d.detectByManager(manager)
+		elapsed := time.Since(start)
+
+		timing := Timing{
+			Manager:    manager,
+			Duration:   elapsed,
+			DurationMS: elapsed.Milliseconds(),
+			Packages:   len(pkgs),
+		}
+		if err != nil {
+			timing.Error = err.Error()
+		}
+		timings = append(timings, timing)
+	}
+
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Duration > timings[j].Duration
+	})
+	return timings
+}
+
+// SetManagers limits detection to the given managers
+func (d *Detector) SetManagers(managers []PackageManager) {
+	d.enabledManagers = managers
+}
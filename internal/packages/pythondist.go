@@ -0,0 +1,170 @@
+package packages
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pythonDist is a Python distribution installed in a site-packages
+// directory, read from its .dist-info directory
+type pythonDist struct {
+	Name    string
+	Version string
+	// EntryPoints maps each entry point group of the distribution, such as
+	// console_scripts, to the names in it
+	EntryPoints map[string][]string
+	// Scripts are the files its RECORD installs into a bin (or Scripts)
+	// directory, and ScriptDir that directory
+	Scripts   []string
+	ScriptDir string
+}
+
+// Commands returns the commands a distribution installs: its console and
+// GUI entry points and the scripts it ships, without the .exe of Windows
+// launchers
+func (dist pythonDist) Commands() []string {
+	var commands []string
+	names := append(append([]string{}, dist.EntryPoints["console_scripts"]...), dist.EntryPoints["gui_scripts"]...)
+	for _, name := range append(names, dist.Scripts...) {
+		commands = appendUnique(commands, strings.TrimSuffix(name, ".exe"))
+	}
+	return commands
+}
+
+// sitePackagesDirs returns the site-packages directories of a virtual
+// environment or Python prefix: lib/pythonX.Y/site-packages on Unix and
+// Lib/site-packages on Windows
+func sitePackagesDirs(prefix string) []string {
+	dirs, _ := filepath.Glob(filepath.Join(prefix, "lib", "python*", "site-packages"))
+	if windows := filepath.Join(prefix, "Lib", "site-packages"); isDir(windows) {
+		dirs = append(dirs, windows)
+	}
+	return dirs
+}
+
+// readPythonDists reads the distributions installed in a site-packages
+// directory
+func readPythonDists(sitePackages string) []pythonDist {
+	infos, _ := filepath.Glob(filepath.Join(sitePackages, "*.dist-info"))
+	var dists []pythonDist
+	for _, info := range infos {
+		dist, ok := readDistInfo(info)
+		if ok {
+			dists = append(dists, dist)
+		}
+	}
+	return dists
+}
+
+// readDistInfo reads a distribution's name and version from the METADATA
+// headers of its .dist-info directory, its entry points from
+// entry_points.txt, and its scripts from RECORD
+func readDistInfo(dir string) (pythonDist, bool) {
+	file, err := os.Open(filepath.Join(dir, "METADATA"))
+	if err != nil {
+		return pythonDist{}, false
+	}
+	defer file.Close()
+
+	var dist pythonDist
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		// The headers end at the first blank line, before the description
+		if line == "" {
+			break
+		}
+		if value, ok := strings.CutPrefix(line, "Name: "); ok {
+			dist.Name = strings.TrimSpace(value)
+		} else if value, ok := strings.CutPrefix(line, "Version: "); ok {
+			dist.Version = strings.TrimSpace(value)
+		}
+	}
+	if dist.Name == "" {
+		return pythonDist{}, false
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "entry_points.txt")); err == nil {
+		dist.EntryPoints = parseEntryPoints(string(data))
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "RECORD")); err == nil {
+		dist.Scripts, dist.ScriptDir = recordScripts(string(data), filepath.Dir(dir))
+	}
+	if dist.ScriptDir == "" {
+		dist.ScriptDir = defaultScriptDir(filepath.Dir(dir))
+	}
+	return dist, true
+}
+
+// recordScripts returns the files a RECORD lists in a bin or Scripts
+// directory outside site-packages, and that directory. RECORD is a CSV file
+// of installed paths, relative to site-packages, with their hashes and
+// sizes:
+//
+//	black/__init__.py,sha256=...,1234
+//	../../../bin/black,sha256=...,250
+func recordScripts(record, sitePackages string) ([]string, string) {
+	var scripts []string
+	dir := ""
+	for _, line := range strings.Split(record, "\n") {
+		path := strings.TrimSpace(line)
+		if strings.HasPrefix(path, `"`) {
+			path, _, _ = strings.Cut(path[1:], `"`)
+		} else {
+			path, _, _ = strings.Cut(path, ",")
+		}
+		if !strings.HasPrefix(filepath.ToSlash(path), "../") {
+			continue
+		}
+		installed := filepath.Clean(filepath.Join(sitePackages, path))
+		if base := filepath.Base(filepath.Dir(installed)); base != "bin" && base != "Scripts" {
+			continue
+		}
+		scripts = append(scripts, filepath.Base(installed))
+		dir = filepath.Dir(installed)
+	}
+	return scripts, dir
+}
+
+// defaultScriptDir returns where pip puts the commands of the distributions
+// in a site-packages directory: the bin directory of its prefix, as in
+// <prefix>/lib/python3.12/site-packages, or the Scripts directory of
+// <prefix>\Lib\site-packages on Windows
+func defaultScriptDir(sitePackages string) string {
+	lib := filepath.Dir(sitePackages)
+	if filepath.Base(lib) == "Lib" {
+		return filepath.Join(filepath.Dir(lib), "Scripts")
+	}
+	return filepath.Join(filepath.Dir(filepath.Dir(lib)), "bin")
+}
+
+// parseEntryPoints parses an entry_points.txt, an INI file with one section
+// per group and one "name = module:attr" line per entry point:
+//
+//	[console_scripts]
+//	black = black:patched_main
+//	blackd = blackd:patched_main [d]
+func parseEntryPoints(data string) map[string][]string {
+	groups := make(map[string][]string)
+	group := ""
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			group = strings.TrimSpace(line[1 : len(line)-1])
+		case group != "":
+			if name, _, ok := strings.Cut(line, "="); ok {
+				groups[group] = append(groups[group], strings.TrimSpace(name))
+			}
+		}
+	}
+	return groups
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,77 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// snapBinDirs are where snapd exposes the commands of installed snaps: /snap
+// on Ubuntu and Debian, /var/lib/snapd/snap on Fedora and Arch
+var snapBinDirs = []string{"/snap/bin", "/var/lib/snapd/snap/bin"}
+
+// detectSnap detects installed snaps. A snap's commands are the entries of
+// the snap bin directory named after it ("lxd", or "lxd.lxc" for its other
+// apps), which link to /usr/bin/snap, and aliases such as "lxc", which link
+// to one of those.
+func (d *Detector) detectSnap() ([]Package, error) {
+	output, err := d.command("snap", "list").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	packages := parseSnapList(string(output))
+	index := make(map[string]int)
+	for i, pkg := range packages {
+		index[pkg.Name] = i
+	}
+	for _, dir := range snapBinDirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			i, ok := index[snapOwner(dir, entry.Name())]
+			if !ok {
+				continue
+			}
+			packages[i].Binaries = append(packages[i].Binaries, entry.Name())
+			packages[i].Location = dir
+		}
+	}
+	return packages, nil
+}
+
+// parseSnapList parses `snap list`, a table with one snap per line under
+// its header:
+//
+//	Name    Version   Rev    Tracking       Publisher   Notes
+//	core22  20240111  1122   latest/stable  canonical✓  base
+//	lxd     5.19      26200  latest/stable  canonical✓  -
+func parseSnapList(output string) []Package {
+	var packages []Package
+	for i, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) < 2 {
+			continue
+		}
+		packages = append(packages, Package{
+			Name:    fields[0],
+			Version: fields[1],
+			Manager: Snap,
+			Global:  true,
+		})
+	}
+	return packages
+}
+
+// snapOwner returns the snap providing the command called name in a snap
+// bin directory: the part of the name before the first dot, or of the
+// command an alias links to
+func snapOwner(dir, name string) string {
+	if target, err := os.Readlink(filepath.Join(dir, name)); err == nil && filepath.Base(target) != "snap" {
+		name = filepath.Base(target)
+	}
+	owner, _, _ := strings.Cut(name, ".")
+	return owner
+}
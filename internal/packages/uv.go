@@ -0,0 +1,114 @@
+package packages
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// uvToolDir returns where `uv tool install` creates each tool's virtual
+// environment: $UV_TOOL_DIR, or uv/tools under the user data directory
+// ($XDG_DATA_HOME or ~/.local/share, %APPDATA%\uv\data on Windows)
+func uvToolDir() string {
+	if dir := os.Getenv("UV_TOOL_DIR"); dir != "" {
+		return dir
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "uv", "data", "tools")
+	}
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, _ := os.UserHomeDir()
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "uv", "tools")
+}
+
+// uvToolBinDir returns the directory uv exposes tool executables in:
+// $UV_TOOL_BIN_DIR, $XDG_BIN_HOME, the bin directory next to
+// $XDG_DATA_HOME, or ~/.local/bin by default, which it shares with pipx
+func uvToolBinDir() string {
+	if dir := os.Getenv("UV_TOOL_BIN_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_BIN_HOME"); dir != "" {
+		return dir
+	}
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "..", "bin")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "bin")
+}
+
+// detectUv detects tools installed with `uv tool install`
+func (d *Detector) detectUv() ([]Package, error) {
+	output, err := d.command("uv", "tool", "list").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	packages := parseUvToolList(string(output))
+	for i := range packages {
+		packages[i].Location = filepath.Clean(uvToolBinDir())
+	}
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+	return packages, nil
+}
+
+// parseUvToolList parses `uv tool list`: each tool on a line with its
+// version, and the executables it exposes on the lines below:
+//
+//	black v24.2.0
+//	- black
+//	- blackd
+//	ruff v0.3.0 [required: >=0.3]
+//	- ruff
+func parseUvToolList(output string) []Package {
+	var packages []Package
+	current := -1
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if executable, ok := strings.CutPrefix(line, "- "); ok {
+			if current >= 0 {
+				// --show-paths adds the executable's path in parentheses
+				executable, _, _ = strings.Cut(executable, " (")
+				packages[current].Binaries = append(packages[current].Binaries, strings.TrimSpace(executable))
+			}
+			continue
+		}
+
+		// Other lines, such as "No tools installed", have no version
+		fields := strings.Fields(line)
+		current = -1
+		if len(fields) < 2 || !strings.HasPrefix(fields[1], "v") {
+			continue
+		}
+		current = len(packages)
+		packages = append(packages, Package{
+			Name:    fields[0],
+			Version: strings.TrimPrefix(fields[1], "v"),
+			Manager: Uv,
+			Global:  true,
+		})
+	}
+	return packages
+}
+
+// uvTool returns the tool whose environment holds path, as in
+// ~/.local/share/uv/tools/<tool>/bin/<executable>
+func uvTool(path string) (string, bool) {
+	slashed := filepath.ToSlash(path)
+	prefix := filepath.ToSlash(uvToolDir()) + "/"
+	if !strings.HasPrefix(slashed, prefix) {
+		return "", false
+	}
+	return strings.Split(strings.TrimPrefix(slashed, prefix), "/")[0], true
+}
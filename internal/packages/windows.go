@@ -0,0 +1,303 @@
+package packages
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// scoopRoot returns scoop's user installation directory: $SCOOP, or
+// ~/scoop by default
+func scoopRoot() string {
+	if root := os.Getenv("SCOOP"); root != "" {
+		return root
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "scoop")
+}
+
+// scoopGlobalRoot returns scoop's directory for apps installed with
+// --global: $SCOOP_GLOBAL, or %ProgramData%\scoop by default
+func scoopGlobalRoot() string {
+	if root := os.Getenv("SCOOP_GLOBAL"); root != "" {
+		return root
+	}
+	if programData := os.Getenv("ProgramData"); programData != "" {
+		return filepath.Join(programData, "scoop")
+	}
+	return ""
+}
+
+// chocolateyRoot returns Chocolatey's installation directory:
+// $ChocolateyInstall, or %ProgramData%\chocolatey by default
+func chocolateyRoot() string {
+	if root := os.Getenv("ChocolateyInstall"); root != "" {
+		return root
+	}
+	if programData := os.Getenv("ProgramData"); programData != "" {
+		return filepath.Join(programData, "chocolatey")
+	}
+	return ""
+}
+
+// wingetLinks returns the directory winget links portable packages'
+// executables into
+func wingetLinks() string {
+	if localAppData := os.Getenv("LOCALAPPDATA"); localAppData != "" {
+		return filepath.Join(localAppData, "Microsoft", "WinGet", "Links")
+	}
+	return ""
+}
+
+// detectScoop detects apps installed with scoop. Each app's executables
+// come from the "bin" entries of its installed manifest, since scoop exposes
+// them as shims whose names can differ from the app's.
+func (d *Detector) detectScoop() ([]Package, error) {
+	output, err := d.command("scoop", "list").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	packages := parseScoopList(string(output))
+	for i := range packages {
+		for _, root := range []string{scoopRoot(), scoopGlobalRoot()} {
+			if root == "" {
+				continue
+			}
+			manifest := filepath.Join(root, "apps", packages[i].Name, "current", "manifest.json")
+			if bins := scoopManifestBins(manifest); bins != nil {
+				packages[i].Binaries = bins
+				packages[i].Location = filepath.Join(root, "shims")
+				break
+			}
+		}
+	}
+	return packages, nil
+}
+
+// parseScoopList parses `scoop list` output: a table with a dashed
+// separator under its header in current scoop,
+//
+//	Name Version Source Updated             Info
+//	---- ------- ------ -------             ----
+//	git  2.43.0  main   2024-01-10 09:12:44
+//
+// or "name version [bucket]" lines in older versions.
+func parseScoopList(output string) []Package {
+	var packages []Package
+	inTable := false
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "----") {
+			inTable = true
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch {
+		case inTable && len(fields) >= 2:
+		case !inTable && len(fields) >= 3 && strings.HasPrefix(fields[2], "["):
+		default:
+			continue
+		}
+		packages = append(packages, Package{
+			Name:    fields[0],
+			Version: fields[1],
+			Manager: Scoop,
+			Global:  true,
+		})
+	}
+	return packages
+}
+
+// scoopManifestBins returns the command names a scoop manifest exposes. A
+// "bin" entry is a path, or an array of a path and the alias it is exposed
+// as. nil means the manifest could not be read.
+func scoopManifestBins(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var manifest struct {
+		Bin json.RawMessage `json:"bin"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil
+	}
+
+	var entries []json.RawMessage
+	var single string
+	if json.Unmarshal(manifest.Bin, &single) == nil {
+		entries = []json.RawMessage{manifest.Bin}
+	} else if json.Unmarshal(manifest.Bin, &entries) != nil {
+		return []string{}
+	}
+
+	bins := []string{}
+	for _, entry := range entries {
+		var path string
+		var pair []string
+		switch {
+		case json.Unmarshal(entry, &path) == nil:
+			bins = append(bins, commandName(path))
+		case json.Unmarshal(entry, &pair) == nil && len(pair) >= 2:
+			bins = append(bins, pair[1])
+		case len(pair) == 1:
+			bins = append(bins, commandName(pair[0]))
+		}
+	}
+	return bins
+}
+
+// commandName returns the name an executable path is run by: its base name
+// without extension (bin\git.exe runs as git)
+func commandName(path string) string {
+	base := filepath.Base(strings.ReplaceAll(path, `\`, "/"))
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// detectChocolatey detects packages installed with Chocolatey. Chocolatey
+// 2 lists local packages by default and dropped --local-only, so it is
+// retried without the flag.
+func (d *Detector) detectChocolatey() ([]Package, error) {
+	output, err := d.command("choco", "list", "--local-only", "--limit-output").Output()
+	if err != nil {
+		output, err = d.command("choco", "list", "--limit-output").Output()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	location := ""
+	if root := chocolateyRoot(); root != "" {
+		location = filepath.Join(root, "bin")
+	}
+	packages := parseChocoList(string(output))
+	for i := range packages {
+		packages[i].Location = location
+	}
+	return packages, nil
+}
+
+// parseChocoList parses `choco list --limit-output`: one "name|version"
+// line per package
+func parseChocoList(output string) []Package {
+	var packages []Package
+	for _, line := range strings.Split(output, "\n") {
+		name, version, ok := strings.Cut(strings.TrimSpace(line), "|")
+		if !ok || name == "" {
+			continue
+		}
+		packages = append(packages, Package{
+			Name:    name,
+			Version: version,
+			Manager: Chocolatey,
+			Global:  true,
+		})
+	}
+	return packages
+}
+
+// detectWinget detects packages installed from the winget source. Packages
+// are named by their winget ID (Git.Git), which is what winget commands
+// take.
+func (d *Detector) detectWinget() ([]Package, error) {
+	output, err := d.command("winget", "list", "--source", "winget", "--accept-source-agreements", "--disable-interactivity").Output()
+	if err != nil {
+		return nil, err
+	}
+	return parseWingetList(string(output), wingetLinks()), nil
+}
+
+// parseWingetList parses the table `winget list` prints. Columns are
+// aligned under the header, and names can contain spaces, so fields are cut
+// at the header's column offsets rather than split on whitespace. The
+// header is printed in the system language, so its columns are told apart
+// by position, not by name (see wingetColumns):
+//
+//	Name      Id           Version  Available Source
+//	-------------------------------------------------
+//	Git       Git.Git      2.43.0   2.44.0    winget
+func parseWingetList(output, links string) []Package {
+	var packages []Package
+	var header string
+	var idCol, versionCol, availableCol int
+	inTable := false
+
+	for _, line := range strings.Split(output, "\n") {
+		// Progress spinners are redrawn in place with carriage returns
+		line = strings.TrimRight(line, "\r")
+		if i := strings.LastIndex(line, "\r"); i >= 0 {
+			line = line[i+1:]
+		}
+		line = strings.TrimRight(line, " ")
+
+		if !inTable {
+			// The header is the line above the dashed separator
+			if strings.HasPrefix(line, "---") && strings.Trim(line, "-") == "" && header != "" {
+				idCol, versionCol, availableCol, inTable = wingetColumns(header)
+				continue
+			}
+			header = line
+			continue
+		}
+
+		id := strings.TrimSpace(column(line, idCol, versionCol))
+		version := strings.TrimSpace(column(line, versionCol, availableCol))
+		if id == "" {
+			continue
+		}
+		packages = append(packages, Package{
+			Name:     id,
+			Version:  version,
+			Manager:  Winget,
+			Location: links,
+			Global:   true,
+		})
+	}
+	return packages
+}
+
+// wingetColumns returns the terminal cells at which the Id, Version, and
+// following column of a `winget list` header start. The columns are always
+// Name, Id, Version, Available (only when an upgrade is known), and Source,
+// so they are the second, third, and fourth words of the header. Offsets
+// are in cells, not runes, since winget pads to the display width and
+// headings such as 名前 are two cells a character. A header with some other
+// number of words is not understood.
+func wingetColumns(header string) (idCol, versionCol, nextCol int, ok bool) {
+	var starts []int
+	cell, prev := 0, ' '
+	for _, r := range header {
+		if r != ' ' && prev == ' ' {
+			starts = append(starts, cell)
+		}
+		cell += runewidth.RuneWidth(r)
+		prev = r
+	}
+	if len(starts) != 4 && len(starts) != 5 {
+		return 0, 0, 0, false
+	}
+	return starts[1], starts[2], starts[3], true
+}
+
+// column returns the runes of line from cell start up to cell end; end <=
+// start means to the end of the line
+func column(line string, start, end int) string {
+	var sb strings.Builder
+	cell := 0
+	for _, r := range line {
+		if cell >= start && (end <= start || cell < end) {
+			sb.WriteRune(r)
+		}
+		cell += runewidth.RuneWidth(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,51 @@
+package packages
+
+import "testing"
+
+func TestParseWingetList(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{"english", "" +
+			"Name            Id              Version  Available Source\n" +
+			"---------------------------------------------------------\n" +
+			"Git             Git.Git         2.43.0   2.44.0    winget\n" +
+			"Microsoft Teams Microsoft.Teams 1.6.0             winget\n"},
+		{"german", "" +
+			"Name            ID              Version  Verfügbar Quelle\n" +
+			"---------------------------------------------------------\n" +
+			"Git             Git.Git         2.43.0   2.44.0    winget\n" +
+			"Microsoft Teams Microsoft.Teams 1.6.0             winget\n"},
+		{"japanese without upgrades", "" +
+			"名前            ID              バージョン ソース\n" +
+			"---------------------------------------------------------\n" +
+			"Git             Git.Git         2.43.0     winget\n" +
+			"Microsoft Teams Microsoft.Teams 1.6.0      winget\n"},
+		{"progress spinner", "" +
+			"\r - \r \\ \rName            Id              Version  Source\r\n" +
+			"---------------------------------------------------------\r\n" +
+			"Git             Git.Git         2.43.0   winget\r\n" +
+			"Microsoft Teams Microsoft.Teams 1.6.0    winget\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			links := `C:\Users\me\AppData\Local\Microsoft\WinGet\Links`
+			got := parseWingetList(tt.output, links)
+			if len(got) != 2 {
+				t.Fatalf("got %d packages, want 2: %+v", len(got), got)
+			}
+			if got[0].Name != "Git.Git" || got[0].Version != "2.43.0" {
+				t.Errorf("first package = %s %s, want Git.Git 2.43.0", got[0].Name, got[0].Version)
+			}
+			if got[1].Name != "Microsoft.Teams" || got[1].Version != "1.6.0" {
+				t.Errorf("second package = %s %s, want Microsoft.Teams 1.6.0", got[1].Name, got[1].Version)
+			}
+			for _, pkg := range got {
+				if pkg.Location != links {
+					t.Errorf("%s location = %q, want %q", pkg.Name, pkg.Location, links)
+				}
+			}
+		})
+	}
+}
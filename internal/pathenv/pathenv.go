@@ -0,0 +1,220 @@
+package pathenv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/scanner"
+	"github.com/cli-ai-org/cli/internal/shims"
+)
+
+// Dir describes one PATH entry and what it contributes to command resolution
+type Dir struct {
+	Index       int      `json:"index"`
+	Path        string   `json:"path"`
+	Exists      bool     `json:"exists"`
+	DuplicateOf int      `json:"duplicate_of"`
+	Executables []string `json:"-"`
+	// Reachable lists the executables that resolve to this directory,
+	// i.e. that no earlier PATH entry provides
+	Reachable []string `json:"-"`
+	// Counts of the above, for JSON output
+	ExecutableCount int `json:"executable_count"`
+	ReachableCount  int `json:"reachable_count"`
+	// Owner is the package or version manager that owns the directory, or
+	// "system", "user", or "unknown"
+	Owner string `json:"owner,omitempty"`
+	// Claimants lists every manager that installs into the directory when
+	// several do (brew and npm both using /usr/local/bin), Owner first
+	Claimants []string `json:"claimants,omitempty"`
+}
+
+// Removal is a PATH entry that can be dropped without changing which binary
+// any command resolves to
+type Removal struct {
+	Dir    Dir    `json:"dir"`
+	Reason string `json:"reason"`
+	// Relative is set for entries such as "." or "bin" that resolve
+	// against the working directory. They are left out of the suggested
+	// PATH as a finding to fix, not because they are redundant: which
+	// commands they provide, and shadow, changes with where you run them.
+	Relative bool `json:"relative,omitempty"`
+}
+
+// Analyze inspects each PATH entry in order, recording duplicates and which
+// executables each directory actually provides
+func Analyze(paths []string) []Dir {
+	dirs := make([]Dir, 0, len(paths))
+	seen := make(map[string]int)
+	resolved := make(map[string]bool)
+
+	for i, path := range paths {
+		dir := Dir{Index: i, Path: path, DuplicateOf: -1}
+
+		key := canonical(path)
+		if first, ok := seen[key]; ok {
+			dir.DuplicateOf = first
+			dir.Exists = dirs[first].Exists
+			dirs = append(dirs, dir)
+			continue
+		}
+		seen[key] = i
+
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			dir.Exists = true
+			dir.Executables, _ = scanner.ListExecutables(path)
+			for _, name := range dir.Executables {
+				if !resolved[scanner.NameKey(name)] {
+					resolved[scanner.NameKey(name)] = true
+					dir.Reachable = append(dir.Reachable, name)
+				}
+			}
+			dir.ExecutableCount = len(dir.Executables)
+			dir.ReachableCount = len(dir.Reachable)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	return dirs
+}
+
+// Trim proposes a minimal PATH. It drops entries that are missing,
+// duplicated, empty, or fully shadowed by earlier entries, so every command
+// keeps resolving to the same binary. Relative entries ("", ".", "bin")
+// are dropped too and marked Relative: their contents depend on the working
+// directory, so no PATH that keeps them resolves commands the same way
+// everywhere.
+func Trim(dirs []Dir) ([]string, []Removal) {
+	var keep []string
+	var removed []Removal
+
+	for _, dir := range dirs {
+		reason := ""
+		switch {
+		case !filepath.IsAbs(dir.Path):
+			name := dir.Path
+			if name == "" {
+				name = "an empty entry, which means the current directory"
+			}
+			removed = append(removed, Removal{
+				Dir:      dir,
+				Reason:   fmt.Sprintf("relative: %s resolves against the working directory, so the commands it provides change with where you run them; use an absolute path instead", name),
+				Relative: true,
+			})
+			continue
+		case dir.DuplicateOf >= 0:
+			reason = fmt.Sprintf("duplicate of PATH entry #%d (%s)", dir.DuplicateOf+1, dirs[dir.DuplicateOf].Path)
+		case !dir.Exists:
+			reason = "directory does not exist"
+		case len(dir.Executables) == 0:
+			reason = "contains no executables"
+		case len(dir.Reachable) == 0:
+			reason = fmt.Sprintf("all %d executables are shadowed by earlier PATH entries", len(dir.Executables))
+		}
+
+		if reason != "" {
+			removed = append(removed, Removal{Dir: dir, Reason: reason})
+			continue
+		}
+		keep = append(keep, dir.Path)
+	}
+
+	return keep, removed
+}
+
+// AssignOwners labels each directory with the manager that owns it, using the
+// managers' bin directories and version-manager shim directories. When
+// several claim a directory, shim directories come first and then package
+// managers in the fixed order of packages.AllManagers, so the owner is the
+// same on every run; all of them are recorded in Claimants.
+func AssignOwners(dirs []Dir, binDirs map[packages.PackageManager][]string, shimDirs []shims.ShimDir) {
+	claimants := make(map[string][]string)
+	claim := func(path, manager string) {
+		key := canonical(path)
+		for _, existing := range claimants[key] {
+			if existing == manager {
+				return
+			}
+		}
+		claimants[key] = append(claimants[key], manager)
+	}
+
+	for _, shimDir := range shimDirs {
+		claim(shimDir.ShimsPath, string(shimDir.Manager))
+	}
+	managers := packages.AllManagers()
+	known := make(map[packages.PackageManager]bool)
+	for _, manager := range managers {
+		known[manager] = true
+	}
+	var others []packages.PackageManager
+	for manager := range binDirs {
+		if !known[manager] {
+			others = append(others, manager)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool { return others[i] < others[j] })
+	for _, manager := range append(managers, others...) {
+		for _, path := range binDirs[manager] {
+			claim(path, string(manager))
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+	for i := range dirs {
+		dirs[i].Owner = ownerOf(dirs[i].Path, claimants, home)
+		if claimed := claimants[canonical(dirs[i].Path)]; len(claimed) > 1 {
+			dirs[i].Claimants = claimed
+		}
+	}
+}
+
+// ownerOf returns the owner of a single PATH directory given the managers
+// claiming known directories, first claimant first
+func ownerOf(path string, claimants map[string][]string, home string) string {
+	if claimed := claimants[canonical(path)]; len(claimed) > 0 {
+		return claimed[0]
+	}
+	if packages.IsSystemDir(path) {
+		return "system"
+	}
+	if home != "" && strings.HasPrefix(filepath.Clean(path), home+string(filepath.Separator)) {
+		return "user"
+	}
+	return "unknown"
+}
+
+// Contains reports whether dir is one of paths, treating trailing slashes
+// and symlinked aliases of the same directory as equal
+func Contains(paths []string, dir string) bool {
+	return Index(paths, dir) >= 0
+}
+
+// Index returns the position of dir in paths, compared like Contains, or -1
+func Index(paths []string, dir string) int {
+	key := canonical(dir)
+	for index, path := range paths {
+		if canonical(path) == key {
+			return index
+		}
+	}
+	return -1
+}
+
+// Join builds a PATH value from directories
+func Join(paths []string) string {
+	return strings.Join(paths, string(os.PathListSeparator))
+}
+
+// canonical returns a comparison key for a PATH entry that treats
+// /usr/bin, /usr/bin/, and symlinked aliases of the same directory as equal
+func canonical(path string) string {
+	if path == "" {
+		return "."
+	}
+	return scanner.DirKey(path)
+}
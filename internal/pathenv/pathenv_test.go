@@ -0,0 +1,67 @@
+package pathenv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/shims"
+)
+
+func TestAssignOwnersIsDeterministic(t *testing.T) {
+	root := t.TempDir()
+	usrLocal := filepath.Join(root, "usr", "local", "bin")
+	localBin := filepath.Join(root, "home", ".local", "bin")
+	shimsDir := filepath.Join(root, "home", ".asdf", "shims")
+	binDirs := map[packages.PackageManager][]string{
+		packages.Brew: {usrLocal},
+		packages.NPM:  {usrLocal},
+		packages.Go:   {localBin},
+		packages.Pipx: {localBin},
+		packages.Uv:   {localBin},
+		packages.Pip:  {shimsDir},
+	}
+	shimDirs := []shims.ShimDir{{Manager: "asdf", ShimsPath: shimsDir}}
+
+	for run := 0; run < 50; run++ {
+		dirs := []Dir{{Path: usrLocal}, {Path: localBin}, {Path: shimsDir}}
+		AssignOwners(dirs, binDirs, shimDirs)
+
+		want := []struct {
+			owner     string
+			claimants int
+		}{
+			{string(packages.NPM), 2},
+			{string(packages.Pipx), 3},
+			{"asdf", 2},
+		}
+		for i, dir := range dirs {
+			if dir.Owner != want[i].owner || len(dir.Claimants) != want[i].claimants {
+				t.Fatalf("run %d: %s owned by %s, claimed by %v; want %s with %d claimants",
+					run, dir.Path, dir.Owner, dir.Claimants, want[i].owner, want[i].claimants)
+			}
+		}
+	}
+}
+
+func TestTrimRelativeEntries(t *testing.T) {
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "tool"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	keep, removed := Trim(Analyze([]string{".", bin, "", "node_modules/.bin"}))
+	if len(keep) != 1 || keep[0] != bin {
+		t.Errorf("kept %q, want only %s", keep, bin)
+	}
+	var relative []string
+	for _, r := range removed {
+		if r.Relative {
+			relative = append(relative, r.Dir.Path)
+		}
+	}
+	if len(relative) != 3 {
+		t.Errorf("relative findings %q, want ., \"\", and node_modules/.bin", relative)
+	}
+}
@@ -0,0 +1,94 @@
+package pathenv
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+)
+
+const (
+	// largeLength and largeEntries mark a PATH as unusually large. Every
+	// process spawn copies the environment and every command lookup walks
+	// the entries, so bloat has a cost long before any hard limit.
+	largeLength  = 4096
+	largeEntries = 50
+
+	// nearLimitPercent is how close to the platform limit a PATH must be
+	// before it is reported as approaching it
+	nearLimitPercent = 75
+)
+
+// Usage summarizes how large PATH is and who contributes to it
+type Usage struct {
+	Length  int `json:"length"`
+	Entries int `json:"entries"`
+	// Limit is the platform's maximum size for a single environment
+	// variable, in characters
+	Limit        int           `json:"limit"`
+	Warning      string        `json:"warning,omitempty"`
+	Contributors []Contributor `json:"contributors"`
+}
+
+// Contributor is the share of PATH added by one owner
+type Contributor struct {
+	Owner   string `json:"owner"`
+	Entries int    `json:"entries"`
+	Length  int    `json:"length"`
+}
+
+// Limit returns the maximum length of a single environment variable on this
+// platform. Windows caps variables at 32767 characters; Linux caps each
+// environment string at MAX_ARG_STRLEN (128 KiB), and macOS's ARG_MAX is
+// larger than that for the whole environment.
+func Limit() int {
+	if runtime.GOOS == "windows" {
+		return 32767
+	}
+	return 131072
+}
+
+// Measure reports the size of the raw PATH value and groups its entries by
+// owner, largest first. dirs should already have owners assigned.
+func Measure(raw string, dirs []Dir) Usage {
+	usage := Usage{
+		Length:  len(raw),
+		Entries: len(dirs),
+		Limit:   Limit(),
+	}
+
+	byOwner := make(map[string]*Contributor)
+	for _, dir := range dirs {
+		owner := dir.Owner
+		if owner == "" {
+			owner = "unknown"
+		}
+		c, ok := byOwner[owner]
+		if !ok {
+			c = &Contributor{Owner: owner}
+			byOwner[owner] = c
+		}
+		c.Entries++
+		// Count the list separator along with the entry
+		c.Length += len(dir.Path) + 1
+	}
+	for _, c := range byOwner {
+		usage.Contributors = append(usage.Contributors, *c)
+	}
+	sort.Slice(usage.Contributors, func(i, j int) bool {
+		if usage.Contributors[i].Length != usage.Contributors[j].Length {
+			return usage.Contributors[i].Length > usage.Contributors[j].Length
+		}
+		return usage.Contributors[i].Owner < usage.Contributors[j].Owner
+	})
+
+	switch {
+	case usage.Length*100 >= usage.Limit*nearLimitPercent:
+		usage.Warning = fmt.Sprintf("PATH is %d characters, approaching the %d-character limit; commands may fail to start",
+			usage.Length, usage.Limit)
+	case usage.Length > largeLength || usage.Entries > largeEntries:
+		usage.Warning = fmt.Sprintf("PATH is unusually large (%d characters, %d entries); every process spawn copies it and every command lookup searches it",
+			usage.Length, usage.Entries)
+	}
+
+	return usage
+}
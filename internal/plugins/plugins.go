@@ -0,0 +1,288 @@
+package plugins
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/scanner"
+	"gopkg.in/yaml.v3"
+)
+
+// How plugins are installed
+const (
+	SourceKrew        = "krew"
+	SourceGhExtension = "gh extension"
+	SourcePath        = "path"
+)
+
+// commandTimeout bounds `gh extension list`
+const commandTimeout = 10 * time.Second
+
+// Hosts are the tools whose plugins are discovered
+var Hosts = []string{"kubectl", "gh", "cargo", "git"}
+
+// prefixHosts are the tools that run an executable named <host>-<name>
+// found in PATH when `<host> <name>` is typed
+var prefixHosts = []string{"kubectl", "cargo", "git"}
+
+// gitCommands are the git-* executables git itself installs in PATH, for
+// other programs to run rather than as subcommands
+var gitCommands = map[string]bool{
+	"receive-pack":   true,
+	"upload-pack":    true,
+	"upload-archive": true,
+	"shell":          true,
+	"cvsserver":      true,
+}
+
+// Discoverer finds the plugins of kubectl, gh, cargo, and git
+type Discoverer struct {
+	// ctx kills `gh extension list` when cancelled
+	ctx    context.Context
+	noExec bool
+}
+
+// NewDiscoverer creates a discoverer
+func NewDiscoverer() *Discoverer {
+	return &Discoverer{ctx: context.Background()}
+}
+
+// SetContext makes running gh stop when ctx is cancelled
+func (d *Discoverer) SetContext(ctx context.Context) {
+	d.ctx = ctx
+}
+
+// SetNoExec keeps the discoverer from running gh; extensions are then read
+// from gh's extensions directory, without their versions
+func (d *Discoverer) SetNoExec(noExec bool) {
+	d.noExec = noExec
+}
+
+// Discover returns the plugins among the installations in tools that run,
+// and the installed gh extensions, sorted by host and name. kubectl, cargo,
+// and git plugins are executables in PATH named after their host; those in
+// krew's bin directory are attributed to krew, with the version from its
+// receipt. gh extensions live outside PATH and are listed with `gh
+// extension list`.
+func (d *Discoverer) Discover(tools []models.Tool) []models.PluginInfo {
+	var plugins []models.PluginInfo
+	krewBin := scanner.PathKey(filepath.Join(krewRoot(), "bin"))
+	var krewVersions map[string]string
+
+	for _, tool := range tools {
+		if tool.Shadowed {
+			continue
+		}
+		plugin, ok := pathPlugin(tool.Name)
+		if !ok {
+			continue
+		}
+		plugin.Path = tool.Path
+		if plugin.Host == "kubectl" && scanner.PathKey(filepath.Dir(tool.Path)) == krewBin {
+			if krewVersions == nil {
+				krewVersions = krewReceipts()
+			}
+			plugin.Source = SourceKrew
+			plugin.Version = krewVersions[scanner.NameKey(tool.Name)]
+		}
+		plugins = append(plugins, plugin)
+	}
+	plugins = append(plugins, d.ghExtensions()...)
+
+	sort.SliceStable(plugins, func(i, j int) bool {
+		if plugins[i].Host != plugins[j].Host {
+			return plugins[i].Host < plugins[j].Host
+		}
+		return plugins[i].Name < plugins[j].Name
+	})
+	return plugins
+}
+
+// Annotate records plugins in tools: each plugin executable gets its
+// Plugin, and each host installation that runs gets the plugins installed
+// for it, so they are presented as subcommands of their host rather than
+// as tools of their own
+func Annotate(tools []models.Tool, plugins []models.PluginInfo) {
+	byPath := make(map[string]models.PluginInfo)
+	byHost := make(map[string][]models.PluginInfo)
+	for _, plugin := range plugins {
+		if plugin.Source != SourceGhExtension {
+			byPath[plugin.Path] = plugin
+		}
+		byHost[plugin.Host] = append(byHost[plugin.Host], plugin)
+	}
+
+	for i := range tools {
+		if plugin, ok := byPath[tools[i].Path]; ok {
+			plugin := plugin
+			tools[i].Plugin = &plugin
+		}
+		if !tools[i].Shadowed {
+			tools[i].Plugins = byHost[scanner.NameKey(tools[i].Name)]
+		}
+	}
+}
+
+// pathPlugin returns the plugin an executable named <host>-<name> in PATH
+// adds to its host. kubectl reads further dashes as nested subcommands and
+// underscores as dashes, so kubectl-view_secret is `kubectl view-secret`.
+func pathPlugin(toolName string) (models.PluginInfo, bool) {
+	key := scanner.NameKey(toolName)
+	for _, host := range prefixHosts {
+		name := strings.TrimPrefix(key, host+"-")
+		if name == key || name == "" {
+			continue
+		}
+		if host == "git" && gitCommands[name] {
+			return models.PluginInfo{}, false
+		}
+		if host == "kubectl" {
+			name = strings.ReplaceAll(strings.ReplaceAll(name, "-", " "), "_", "-")
+		}
+		return models.PluginInfo{
+			Host:    host,
+			Name:    name,
+			Command: host + " " + name,
+			Source:  SourcePath,
+		}, true
+	}
+	return models.PluginInfo{}, false
+}
+
+// krewRoot returns where krew keeps its plugins: $KREW_ROOT, or ~/.krew
+func krewRoot() string {
+	if root := os.Getenv("KREW_ROOT"); root != "" {
+		return root
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".krew")
+}
+
+// krewReceipts reads the version of each installed krew plugin from its
+// receipt, receipts/<name>.yaml, keyed by the executable krew links into
+// its bin directory: kubectl-<name>, with dashes in the name as
+// underscores
+func krewReceipts() map[string]string {
+	versions := make(map[string]string)
+	paths, _ := filepath.Glob(filepath.Join(krewRoot(), "receipts", "*.yaml"))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var receipt struct {
+			Spec struct {
+				Version string `yaml:"version"`
+			} `yaml:"spec"`
+		}
+		if err := yaml.Unmarshal(data, &receipt); err != nil {
+			continue
+		}
+		name := strings.TrimSuffix(filepath.Base(path), ".yaml")
+		versions["kubectl-"+strings.ReplaceAll(name, "-", "_")] = receipt.Spec.Version
+	}
+	return versions
+}
+
+// ghExtensionsDir returns where gh installs extensions, under its data
+// directory: $GH_DATA_DIR, $XDG_DATA_HOME/gh, %LOCALAPPDATA%\GitHub CLI on
+// Windows, or ~/.local/share/gh
+func ghExtensionsDir() string {
+	dataDir := os.Getenv("GH_DATA_DIR")
+	switch {
+	case dataDir != "":
+	case os.Getenv("XDG_DATA_HOME") != "":
+		dataDir = filepath.Join(os.Getenv("XDG_DATA_HOME"), "gh")
+	case runtime.GOOS == "windows" && os.Getenv("LOCALAPPDATA") != "":
+		dataDir = filepath.Join(os.Getenv("LOCALAPPDATA"), "GitHub CLI")
+	default:
+		home, _ := os.UserHomeDir()
+		dataDir = filepath.Join(home, ".local", "share", "gh")
+	}
+	return filepath.Join(dataDir, "extensions")
+}
+
+// ghExtensions lists the installed gh extensions with `gh extension list`,
+// or from gh's extensions directory when gh cannot be run. Each extension
+// is a directory gh-<name> holding an executable of the same name.
+func (d *Discoverer) ghExtensions() []models.PluginInfo {
+	dir := ghExtensionsDir()
+	if !d.noExec {
+		if output, err := d.run("gh", "extension", "list"); err == nil {
+			extensions := parseGhExtensionList(string(output))
+			for i := range extensions {
+				extensions[i].Path = filepath.Join(dir, "gh-"+extensions[i].Name, "gh-"+extensions[i].Name)
+			}
+			return extensions
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var extensions []models.PluginInfo
+	for _, entry := range entries {
+		name := strings.TrimPrefix(entry.Name(), "gh-")
+		if name == entry.Name() || name == "" {
+			continue
+		}
+		extensions = append(extensions, models.PluginInfo{
+			Host:    "gh",
+			Name:    name,
+			Command: "gh " + name,
+			Path:    filepath.Join(dir, entry.Name(), entry.Name()),
+			Source:  SourceGhExtension,
+		})
+	}
+	return extensions
+}
+
+// parseGhExtensionList parses `gh extension list` when piped: one
+// tab-separated line per extension with its command, repository, and
+// version (a release tag, or a commit for extensions installed from a
+// repository without releases)
+//
+//	gh dash	dlvhdr/gh-dash	v4.0.0
+func parseGhExtensionList(output string) []models.PluginInfo {
+	var extensions []models.PluginInfo
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		name := strings.TrimSpace(strings.TrimPrefix(fields[0], "gh "))
+		if name == "" || name == fields[0] {
+			continue
+		}
+		extension := models.PluginInfo{
+			Host:    "gh",
+			Name:    name,
+			Command: "gh " + name,
+			Source:  SourceGhExtension,
+		}
+		if len(fields) > 1 {
+			extension.Repository = strings.TrimSpace(fields[1])
+		}
+		if len(fields) > 2 {
+			extension.Version = strings.TrimSpace(fields[2])
+		}
+		extensions = append(extensions, extension)
+	}
+	return extensions
+}
+
+// run runs a host tool with args
+func (d *Discoverer) run(name string, args ...string) ([]byte, error) {
+	command, err := exec.LookPath(name)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(d.ctx, commandTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, command, args...).Output()
+}
@@ -0,0 +1,416 @@
+// Package policy builds the policy of which tools an agent may run, from
+// the categories and risks of the tools in a catalog and the user's rules,
+// and checks commands against it for wrappers that enforce it.
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/categories"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/safety"
+	"gopkg.in/yaml.v3"
+)
+
+// Decisions a policy makes about running a tool
+const (
+	// Allow lets the agent run the tool
+	Allow = "allow"
+	// Confirm asks the user before the agent runs the tool
+	Confirm = "confirm"
+	// Deny never lets the agent run the tool
+	Deny = "deny"
+)
+
+// Decisions lists every decision, from the least to the most restrictive
+var Decisions = []string{Allow, Confirm, Deny}
+
+// Version is the version of the policy file format written
+const Version = 1
+
+// DefaultFileName is the policy file looked up in the user's home directory
+const DefaultFileName = ".cli-policy.yaml"
+
+// NoRisk and NoCategory name the groups of tools without a risk or a
+// category
+const (
+	NoRisk     = "none"
+	NoCategory = categories.Other
+)
+
+// riskOrder ranks tool risks from the most to the least severe, which is
+// the order groups are listed in within a category
+var riskOrder = []string{safety.Privileged, safety.Destructive, safety.NetworkWrite, NoRisk}
+
+// Policy lists the tools an agent may run, grouped by category and risk
+type Policy struct {
+	Version     int    `json:"version" yaml:"version"`
+	GeneratedAt string `json:"generated_at" yaml:"generated_at"`
+	// Default is the decision for tools the policy does not list
+	Default string  `json:"default" yaml:"default"`
+	Groups  []Group `json:"groups" yaml:"groups"`
+
+	// byName indexes the entries of Groups for Check
+	byName map[string]*Entry
+}
+
+// Group holds the tools of one category whose most severe risk is Risk
+type Group struct {
+	Category string  `json:"category" yaml:"category"`
+	Risk     string  `json:"risk" yaml:"risk"`
+	Tools    []Entry `json:"tools" yaml:"tools"`
+}
+
+// Entry is the decision about one tool
+type Entry struct {
+	Name     string   `json:"name" yaml:"name"`
+	Path     string   `json:"path,omitempty" yaml:"path,omitempty"`
+	Decision string   `json:"decision" yaml:"decision"`
+	Risks    []string `json:"risks,omitempty" yaml:"risks,omitempty"`
+	// Reason says which rule or risk made the decision
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+	// Subcommands are the subcommands decided differently from the tool
+	Subcommands []SubcommandEntry `json:"subcommands,omitempty" yaml:"subcommands,omitempty"`
+}
+
+// SubcommandEntry is the decision about one subcommand of a tool; its name
+// may span levels ("system prune")
+type SubcommandEntry struct {
+	Name     string   `json:"name" yaml:"name"`
+	Decision string   `json:"decision" yaml:"decision"`
+	Risks    []string `json:"risks,omitempty" yaml:"risks,omitempty"`
+	Reason   string   `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// Verdict is the decision about one command line
+type Verdict struct {
+	Decision   string `json:"decision"`
+	Tool       string `json:"tool"`
+	Subcommand string `json:"subcommand,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+// DefaultPath returns the default policy file location
+// ($HOME/.cli-policy.yaml)
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, DefaultFileName)
+}
+
+// CheckDecision returns an error naming the valid decisions if decision is
+// not one of them
+func CheckDecision(decision string) error {
+	if rank(decision) < 0 {
+		return fmt.Errorf("unknown decision %q (valid: %s)", decision, strings.Join(Decisions, ", "))
+	}
+	return nil
+}
+
+// rank orders decisions by how restrictive they are, -1 for an unknown one
+func rank(decision string) int {
+	for i, d := range Decisions {
+		if d == decision {
+			return i
+		}
+	}
+	return -1
+}
+
+// Generate builds the policy for tools, which should be tagged with their
+// categories and annotated with their risks. Each tool is decided by the
+// first of: a tools rule naming it, the most restrictive decision among
+// the rules for its risks and categories, and allow. Its risky subcommands
+// are decided by the rules for their risks, and "tool subcommand" tools
+// rules; those decided differently from the tool are listed under it.
+// Shadowed installations are left out, since the name runs the first one.
+func Generate(tools []models.Tool, rules Rules) *Policy {
+	p := &Policy{
+		Version:     Version,
+		GeneratedAt: time.Now().UTC().Format(time.RFC3339),
+		Default:     rules.Default,
+	}
+
+	groups := make(map[[2]string]*Group)
+	seen := make(map[string]bool)
+	for _, tool := range tools {
+		if tool.Shadowed || seen[tool.Name] {
+			continue
+		}
+		seen[tool.Name] = true
+
+		entry := decide(tool, rules)
+		category := NoCategory
+		if len(tool.Tags) > 0 {
+			category = tool.Tags[0]
+		}
+		key := [2]string{category, severest(entry.Risks)}
+		group, ok := groups[key]
+		if !ok {
+			group = &Group{Category: key[0], Risk: key[1]}
+			groups[key] = group
+		}
+		group.Tools = append(group.Tools, entry)
+	}
+
+	for _, group := range groups {
+		sort.Slice(group.Tools, func(i, j int) bool {
+			return group.Tools[i].Name < group.Tools[j].Name
+		})
+		p.Groups = append(p.Groups, *group)
+	}
+	sort.Slice(p.Groups, func(i, j int) bool {
+		a, b := p.Groups[i], p.Groups[j]
+		// Keep uncategorized tools last
+		if (a.Category == NoCategory) != (b.Category == NoCategory) {
+			return b.Category == NoCategory
+		}
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return riskIndex(a.Risk) < riskIndex(b.Risk)
+	})
+	return p
+}
+
+// decide makes the decision about a tool and its subcommands
+func decide(tool models.Tool, rules Rules) Entry {
+	entry := Entry{Name: tool.Name, Path: tool.Path, Decision: Allow}
+	var subcommands []models.SubcommandRisk
+	if tool.Safety != nil {
+		entry.Risks = tool.Safety.Risks
+		subcommands = tool.Safety.Subcommands
+	}
+
+	if decision, pattern, ok := rules.tool(tool.Name); ok {
+		entry.Decision = decision
+		entry.Reason = "rule tools." + pattern
+	} else {
+		for _, risk := range entry.Risks {
+			if decision, ok := rules.Risks[risk]; ok && (entry.Reason == "" || rank(decision) > rank(entry.Decision)) {
+				entry.Decision = decision
+				entry.Reason = "risk " + risk
+				if tool.Safety.Reason != "" {
+					entry.Reason += ": " + tool.Safety.Reason
+				}
+			}
+		}
+		for _, tag := range tool.Tags {
+			if decision, ok := rules.Categories[tag]; ok && (entry.Reason == "" || rank(decision) > rank(entry.Decision)) {
+				entry.Decision = decision
+				entry.Reason = "category " + tag
+			}
+		}
+	}
+
+	decided := make(map[string]bool)
+	for _, sub := range subcommands {
+		decision, reason := entry.Decision, ""
+		for _, risk := range sub.Risks {
+			if d, ok := rules.Risks[risk]; ok && rank(d) > rank(decision) {
+				decision = d
+				reason = "risk " + risk
+				if sub.Reason != "" {
+					reason += ": " + sub.Reason
+				}
+			}
+		}
+		if d, pattern, ok := rules.tool(tool.Name + " " + sub.Name); ok {
+			decision, reason = d, "rule tools."+pattern
+		}
+		decided[sub.Name] = true
+		if decision != entry.Decision {
+			entry.Subcommands = append(entry.Subcommands, SubcommandEntry{Name: sub.Name, Decision: decision, Risks: sub.Risks, Reason: reason})
+		}
+	}
+	// Rules may name subcommands no risk was found for
+	for _, sub := range rules.subcommands(tool.Name) {
+		if decided[sub] {
+			continue
+		}
+		decision, pattern, _ := rules.tool(tool.Name + " " + sub)
+		if decision != entry.Decision {
+			entry.Subcommands = append(entry.Subcommands, SubcommandEntry{Name: sub, Decision: decision, Reason: "rule tools." + pattern})
+		}
+	}
+	return entry
+}
+
+// severest returns the most severe of risks, or NoRisk
+func severest(risks []string) string {
+	best := NoRisk
+	for _, risk := range risks {
+		if riskIndex(risk) < riskIndex(best) {
+			best = risk
+		}
+	}
+	return best
+}
+
+// riskIndex returns the position of a risk in riskOrder
+func riskIndex(risk string) int {
+	for i, r := range riskOrder {
+		if r == risk {
+			return i
+		}
+	}
+	return len(riskOrder)
+}
+
+// Load reads a policy file, in YAML or JSON
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("policy file %s does not exist; create it with cli policy generate -o %s", path, path)
+		}
+		return nil, fmt.Errorf("reading policy file: %w", err)
+	}
+	p := &Policy{}
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("parsing policy file %s: %w", path, err)
+	}
+	if p.Version > Version {
+		return nil, fmt.Errorf("policy file %s has version %d; this cli reads up to version %d", path, p.Version, Version)
+	}
+	if p.Default == "" {
+		p.Default = Deny
+	}
+	if err := CheckDecision(p.Default); err != nil {
+		return nil, fmt.Errorf("policy file %s: default: %w", path, err)
+	}
+
+	p.byName = make(map[string]*Entry)
+	for i := range p.Groups {
+		for j := range p.Groups[i].Tools {
+			entry := &p.Groups[i].Tools[j]
+			if err := CheckDecision(entry.Decision); err != nil {
+				return nil, fmt.Errorf("policy file %s: %s: %w", path, entry.Name, err)
+			}
+			for _, sub := range entry.Subcommands {
+				if err := CheckDecision(sub.Decision); err != nil {
+					return nil, fmt.Errorf("policy file %s: %s %s: %w", path, entry.Name, sub.Name, err)
+				}
+			}
+			p.byName[entry.Name] = entry
+		}
+	}
+	return p, nil
+}
+
+// Check decides whether a command line may run. The tool is looked up by
+// name, without its directory or a .exe extension; tools the policy does
+// not list get its default decision, and so does a tool given as a path
+// that is not the one the policy lists, after resolving symlinks.
+//
+// The subcommand is the longest listed one whose words begin the arguments
+// that do not start with "-". A word after a flag may be that flag's value
+// or the subcommand, so when flags come first each word up to the first
+// one that cannot be a flag value may begin it, and the most restrictive
+// decision among those readings is taken.
+func (p *Policy) Check(tool string, args []string) Verdict {
+	name := strings.TrimSuffix(filepath.Base(tool), ".exe")
+	entry, ok := p.byName[name]
+	if !ok {
+		return Verdict{Decision: p.Default, Tool: name, Reason: "not in the policy"}
+	}
+	if entry.Path != "" && strings.ContainsAny(tool, `/\`) && !samePath(tool, entry.Path) {
+		return Verdict{Decision: p.Default, Tool: name, Reason: fmt.Sprintf("%s is not %s, the one in the policy", tool, entry.Path)}
+	}
+
+	// maybeValue marks the words that follow a flag and so may be its value
+	var words []string
+	var maybeValue []bool
+	afterFlag, endOfFlags := false, false
+	for _, arg := range args {
+		if !endOfFlags && strings.HasPrefix(arg, "-") && arg != "-" {
+			endOfFlags = arg == "--"
+			afterFlag = !endOfFlags && !strings.Contains(arg, "=")
+			continue
+		}
+		words = append(words, arg)
+		maybeValue = append(maybeValue, afterFlag)
+		afterFlag = false
+	}
+
+	// starts are the word positions the subcommand may begin at; if every
+	// word may be a flag value, there may be no subcommand at all
+	var starts []int
+	for i := range words {
+		starts = append(starts, i)
+		if !maybeValue[i] {
+			break
+		}
+	}
+	noSubcommand := len(starts) == 0 || maybeValue[starts[len(starts)-1]]
+
+	base := Verdict{Decision: entry.Decision, Tool: name, Reason: entry.Reason}
+	var verdict Verdict
+	found := false
+	// The last start is the likeliest reading, so it wins ties
+	for i := len(starts) - 1; i >= 0; i-- {
+		v := matchSubcommand(entry, base, words[starts[i]:])
+		if !found || rank(v.Decision) > rank(verdict.Decision) {
+			verdict, found = v, true
+		}
+	}
+	if !found || (noSubcommand && rank(base.Decision) > rank(verdict.Decision)) {
+		verdict = base
+	}
+	if verdict.Reason == "" {
+		verdict.Reason = "no risk found"
+	}
+	return verdict
+}
+
+// matchSubcommand returns the verdict for the longest subcommand of entry that
+// words begin with, or base if none does
+func matchSubcommand(entry *Entry, base Verdict, words []string) Verdict {
+	verdict := base
+	longest := 0
+	for _, sub := range entry.Subcommands {
+		subWords := strings.Fields(sub.Name)
+		if len(subWords) <= longest || len(subWords) > len(words) {
+			continue
+		}
+		match := true
+		for i, word := range subWords {
+			if words[i] != word {
+				match = false
+				break
+			}
+		}
+		if match {
+			longest = len(subWords)
+			verdict.Decision = sub.Decision
+			verdict.Subcommand = sub.Name
+			verdict.Reason = sub.Reason
+		}
+	}
+	return verdict
+}
+
+// samePath reports whether two paths name the same file once symlinks are
+// resolved; a path that cannot be resolved is compared as written
+func samePath(a, b string) bool {
+	return resolve(a) == resolve(b)
+}
+
+// resolve returns path made absolute with its symlinks resolved
+func resolve(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	if real, err := filepath.EvalSymlinks(path); err == nil {
+		path = real
+	}
+	return filepath.Clean(path)
+}
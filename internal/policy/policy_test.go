@@ -0,0 +1,123 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPolicy = `version: 1
+default: deny
+groups:
+  - category: vcs
+    risk: network-write
+    tools:
+      - name: git
+        path: %GIT%
+        decision: allow
+        subcommands:
+          - name: push
+            decision: confirm
+          - name: clean
+            decision: deny
+  - category: cloud
+    risk: destructive
+    tools:
+      - name: kubectl
+        decision: allow
+        subcommands:
+          - name: delete
+            decision: confirm
+      - name: docker
+        decision: allow
+        subcommands:
+          - name: system prune
+            decision: deny
+      - name: rm
+        path: %RM%
+        decision: confirm
+`
+
+// loadTestPolicy writes testPolicy with git and rm installed in a temporary
+// directory and loads it
+func loadTestPolicy(t *testing.T) (*Policy, string) {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"git", "rm"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := testPolicy
+	data = strings.ReplaceAll(data, "%GIT%", filepath.Join(dir, "git"))
+	data = strings.ReplaceAll(data, "%RM%", filepath.Join(dir, "rm"))
+	path := filepath.Join(dir, "policy.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, dir
+}
+
+func TestCheckSubcommands(t *testing.T) {
+	p, _ := loadTestPolicy(t)
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"git", "status"}, Allow},
+		{[]string{"git", "push", "--force"}, Confirm},
+		{[]string{"git", "-C", "/repo", "push", "--force"}, Confirm},
+		{[]string{"git", "-C", "/repo", "-c", "user.name=x", "clean", "-fdx"}, Deny},
+		{[]string{"git", "--no-pager", "push"}, Confirm},
+		{[]string{"git", "-C=/repo", "push"}, Confirm},
+		{[]string{"git", "log", "--", "push"}, Allow},
+		{[]string{"kubectl", "-n", "prod", "delete", "pod", "web"}, Confirm},
+		{[]string{"kubectl", "get", "pods", "-n", "delete"}, Allow},
+		{[]string{"docker", "--context", "x", "system", "prune", "-a"}, Deny},
+		{[]string{"docker", "system", "df"}, Allow},
+		{[]string{"terraform", "plan"}, Deny},
+	}
+	for _, tt := range tests {
+		got := p.Check(tt.args[0], tt.args[1:])
+		if got.Decision != tt.want {
+			t.Errorf("Check(%q) = %s (%s), want %s", tt.args, got.Decision, got.Reason, tt.want)
+		}
+	}
+}
+
+func TestCheckPaths(t *testing.T) {
+	p, dir := loadTestPolicy(t)
+	link := filepath.Join(t.TempDir(), "git")
+	if err := os.Symlink(filepath.Join(dir, "git"), link); err != nil {
+		t.Fatal(err)
+	}
+	evil := t.TempDir()
+	for _, name := range []string{"git", "rm"} {
+		if err := os.WriteFile(filepath.Join(evil, name), []byte("#!/bin/sh\n"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		tool string
+		want string
+	}{
+		{"git", Allow},
+		{filepath.Join(dir, "git"), Allow},
+		{link, Allow},
+		{filepath.Join(evil, "git"), Deny},
+		{filepath.Join(evil, "rm"), Deny},
+		{"rm", Confirm},
+	}
+	for _, tt := range tests {
+		got := p.Check(tt.tool, []string{"status"})
+		if got.Decision != tt.want {
+			t.Errorf("Check(%s) = %s (%s), want %s", tt.tool, got.Decision, got.Reason, tt.want)
+		}
+	}
+}
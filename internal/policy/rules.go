@@ -0,0 +1,155 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/categories"
+	"github.com/cli-ai-org/cli/internal/safety"
+	"gopkg.in/yaml.v3"
+)
+
+// Rules are what a policy is generated from
+type Rules struct {
+	// Default is the decision for tools the policy does not list (default
+	// deny)
+	Default string `yaml:"default"`
+	// Risks decide tools and subcommands by their risks (default: confirm
+	// destructive and network-write, deny privileged)
+	Risks map[string]string `yaml:"risks"`
+	// Categories decide tools by their tags
+	Categories map[string]string `yaml:"categories"`
+	// Tools decide tools by name or shell glob, and subcommands as
+	// "tool subcommand", overriding their risks and categories
+	Tools map[string]string `yaml:"tools"`
+}
+
+// DefaultRules returns the rules used where the rules file says nothing:
+// anything risky needs confirmation, and privileged tools are denied
+func DefaultRules() Rules {
+	return Rules{
+		Default: Deny,
+		Risks: map[string]string{
+			safety.Destructive:  Confirm,
+			safety.NetworkWrite: Confirm,
+			safety.Privileged:   Deny,
+		},
+		Categories: map[string]string{},
+		Tools:      map[string]string{},
+	}
+}
+
+// LoadRules reads a rules file over the default rules: its default
+// replaces theirs, and its risks, categories, and tools are added to
+// theirs, replacing the same keys
+func LoadRules(path string) (Rules, error) {
+	rules := DefaultRules()
+	if path == "" {
+		return rules, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return rules, fmt.Errorf("reading rules file: %w", err)
+	}
+	var file Rules
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return rules, fmt.Errorf("parsing rules file %s: %w", path, err)
+	}
+	if file.Default != "" {
+		rules.Default = file.Default
+	}
+	for risk, decision := range file.Risks {
+		rules.Risks[risk] = decision
+	}
+	for category, decision := range file.Categories {
+		rules.Categories[category] = decision
+	}
+	for pattern, decision := range file.Tools {
+		rules.Tools[pattern] = decision
+	}
+	if err := rules.Validate(); err != nil {
+		return rules, fmt.Errorf("rules file %s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// Validate checks every decision, risk, category, and pattern of the rules
+func (r Rules) Validate() error {
+	if err := CheckDecision(r.Default); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for risk, decision := range r.Risks {
+		valid := false
+		for _, known := range safety.Risks {
+			valid = valid || known == risk
+		}
+		if !valid {
+			return fmt.Errorf("risks: unknown risk %q (valid: %s)", risk, strings.Join(safety.Risks, ", "))
+		}
+		if err := CheckDecision(decision); err != nil {
+			return fmt.Errorf("risks.%s: %w", risk, err)
+		}
+	}
+	for category, decision := range r.Categories {
+		if err := categories.CheckTags([]string{category}); err != nil {
+			return fmt.Errorf("categories: %w", err)
+		}
+		if err := CheckDecision(decision); err != nil {
+			return fmt.Errorf("categories.%s: %w", category, err)
+		}
+	}
+	for pattern, decision := range r.Tools {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("tools: invalid pattern %q: %w", pattern, err)
+		}
+		if err := CheckDecision(decision); err != nil {
+			return fmt.Errorf("tools.%s: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// tool returns the decision of the tools rule for a tool name, or a
+// "tool subcommand" command: the rule naming it exactly, else the one with
+// the longest matching glob. Globs of tool names never match subcommands,
+// so allowing "kube*" does not allow "kubectl delete".
+func (r Rules) tool(command string) (decision, pattern string, ok bool) {
+	if decision, ok := r.Tools[command]; ok {
+		return decision, command, true
+	}
+	var matches []string
+	for p := range r.Tools {
+		if strings.Contains(p, " ") != strings.Contains(command, " ") {
+			continue
+		}
+		if matched, _ := filepath.Match(p, command); matched {
+			matches = append(matches, p)
+		}
+	}
+	if len(matches) == 0 {
+		return "", "", false
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if len(matches[i]) != len(matches[j]) {
+			return len(matches[i]) > len(matches[j])
+		}
+		return matches[i] < matches[j]
+	})
+	return r.Tools[matches[0]], matches[0], true
+}
+
+// subcommands returns the subcommands the tools rules name for a tool
+// ("terraform destroy" names destroy), alphabetically
+func (r Rules) subcommands(tool string) []string {
+	var subs []string
+	for pattern := range r.Tools {
+		if sub := strings.TrimPrefix(pattern, tool+" "); sub != pattern && sub != "" {
+			subs = append(subs, sub)
+		}
+	}
+	sort.Strings(subs)
+	return subs
+}
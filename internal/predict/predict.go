@@ -0,0 +1,189 @@
+package predict
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"sort"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/pathenv"
+)
+
+// Sources of a package's binary list
+const (
+	// SourceRegistry means the binaries came from the package registry
+	SourceRegistry = "registry"
+	// SourceUser means the binaries were given on the command line
+	SourceUser = "user"
+	// SourceAssumed means the registry could not say, so the package is
+	// assumed to provide a command of its own name
+	SourceAssumed = "assumed"
+)
+
+// Clash describes a command that installing a package would add alongside
+// an existing installation of the same name
+type Clash struct {
+	Binary string `json:"binary"`
+	// Existing are the current installations, in PATH order
+	Existing []models.Tool `json:"existing"`
+	// WouldShadow is true when the new install's bin directory comes before
+	// the active installation in PATH, so the new copy would take over
+	WouldShadow bool `json:"would_shadow"`
+	// SameDir is true when an existing installation lives in the bin
+	// directory the new copy would be installed to, so installing would
+	// overwrite it or, for Homebrew, fail to link
+	SameDir bool `json:"same_dir,omitempty"`
+	// BinDir is where the manager would install the command, if known
+	BinDir string `json:"bin_dir,omitempty"`
+}
+
+// Resolver looks up which commands a package provides
+type Resolver struct {
+	timeout time.Duration
+	client  *http.Client
+}
+
+// NewResolver creates a new binary resolver
+func NewResolver() *Resolver {
+	return &Resolver{
+		timeout: 30 * time.Second,
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Binaries returns the commands a package would install and where that list
+// came from. Registries that do not publish executables (Homebrew, PyPI)
+// fall back to assuming the package provides a command of its own name.
+func (r *Resolver) Binaries(manager packages.PackageManager, name string) ([]string, string) {
+	var bins []string
+	var err error
+	switch manager {
+	case packages.NPM:
+		bins, err = r.npmBinaries(name)
+	case packages.Cargo:
+		bins, err = r.cargoBinaries(name)
+	default:
+		err = fmt.Errorf("%s registry does not list executables", manager)
+	}
+	if err != nil || len(bins) == 0 {
+		return []string{name}, SourceAssumed
+	}
+	sort.Strings(bins)
+	return bins, SourceRegistry
+}
+
+// npmBinaries reads the bin field from `npm view`, which is either a map of
+// command names or a single path installed under the package's name
+func (r *Resolver) npmBinaries(name string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, "npm", "view", name, "bin", "--json").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var bin interface{}
+	if err := json.Unmarshal(output, &bin); err != nil {
+		return nil, err
+	}
+	switch v := bin.(type) {
+	case map[string]interface{}:
+		var bins []string
+		for command := range v {
+			bins = append(bins, command)
+		}
+		return bins, nil
+	case string:
+		return []string{name}, nil
+	}
+	return nil, nil
+}
+
+// cargoBinaries reads bin_names for the latest version from crates.io
+func (r *Resolver) cargoBinaries(name string) ([]string, error) {
+	// The name is user input; escaped, it cannot reach another endpoint
+	req, err := http.NewRequest("GET", "https://crates.io/api/v1/crates/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, err
+	}
+	// crates.io rejects requests without a user agent
+	req.Header.Set("User-Agent", "cli-ai-org/cli")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("crates.io returned %s", resp.Status)
+	}
+
+	var info struct {
+		Versions []struct {
+			BinNames []string `json:"bin_names"`
+		} `json:"versions"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	if len(info.Versions) == 0 {
+		return nil, nil
+	}
+	return info.Versions[0].BinNames, nil
+}
+
+// Clashes compares the commands a package would install against the
+// current installations. existing maps each binary to its installations in
+// PATH order, already linked to packages. Installations that belong to the
+// package being installed are a reinstall, not a clash. binDirs are the
+// manager's bin directories and paths is PATH, used to predict whether the
+// new copy would win.
+func Clashes(manager packages.PackageManager, name string, binaries []string, existing map[string][]models.Tool, binDirs, paths []string) []Clash {
+	binDir, binIndex := firstOnPath(binDirs, paths)
+
+	var clashes []Clash
+	for _, binary := range binaries {
+		var others []models.Tool
+		for _, tool := range existing[binary] {
+			if tool.PackageManager == string(manager) && tool.PackageName == name {
+				continue
+			}
+			others = append(others, tool)
+		}
+		if len(others) == 0 {
+			continue
+		}
+
+		clash := Clash{Binary: binary, Existing: others, BinDir: binDir}
+		if binIndex >= 0 && binIndex < others[0].PathIndex {
+			clash.WouldShadow = true
+		}
+		for _, tool := range others {
+			if binIndex >= 0 && tool.PathIndex == binIndex {
+				clash.SameDir = true
+			}
+		}
+		clashes = append(clashes, clash)
+	}
+	return clashes
+}
+
+// firstOnPath returns the bin directory that appears earliest in PATH and
+// its position, or the first bin directory and -1 if none is on PATH
+func firstOnPath(binDirs, paths []string) (string, int) {
+	best, bestIndex := "", -1
+	for _, binDir := range binDirs {
+		if index := pathenv.Index(paths, binDir); index >= 0 && (bestIndex < 0 || index < bestIndex) {
+			best, bestIndex = binDir, index
+		}
+	}
+	if bestIndex < 0 && len(binDirs) > 0 {
+		best = binDirs[0]
+	}
+	return best, bestIndex
+}
@@ -0,0 +1,289 @@
+package safety
+
+import (
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// rule is the built-in annotation of a tool
+type rule struct {
+	risks       []string
+	reason      string
+	subcommands []models.SubcommandRisk
+}
+
+// sub builds a risky subcommand
+func sub(name, reason string, risks ...string) models.SubcommandRisk {
+	return models.SubcommandRisk{Name: name, Risks: risks, Reason: reason}
+}
+
+// Reasons shared by many entries
+const (
+	deletesFiles    = "deletes files"
+	overwritesDisks = "overwrites disks and partitions"
+	stopsProcesses  = "stops processes"
+	stopsMachine    = "shuts down or restarts the machine"
+	elevates        = "runs commands as another user, usually root"
+	transfers       = "transfers files to or from other machines"
+	downloads       = "downloads and uploads data; often piped into a shell to install software"
+	cloudResources  = "creates, changes, and deletes cloud resources"
+	installs        = "installs software from the network"
+	uninstalls      = "removes installed software"
+)
+
+// builtin maps well-known tools to their risks
+var builtin = map[string]rule{
+	// Files and disks
+	"rm":       {risks: []string{Destructive}, reason: deletesFiles},
+	"rmdir":    {risks: []string{Destructive}, reason: "deletes directories"},
+	"shred":    {risks: []string{Destructive}, reason: "overwrites files so they cannot be recovered"},
+	"srm":      {risks: []string{Destructive}, reason: deletesFiles},
+	"truncate": {risks: []string{Destructive}, reason: "cuts files short"},
+	"dd":       {risks: []string{Destructive}, reason: "copies raw data, overwriting files and disks"},
+	"fdisk":    {risks: []string{Destructive, Privileged}, reason: overwritesDisks},
+	"sfdisk":   {risks: []string{Destructive, Privileged}, reason: overwritesDisks},
+	"gdisk":    {risks: []string{Destructive, Privileged}, reason: overwritesDisks},
+	"parted":   {risks: []string{Destructive, Privileged}, reason: overwritesDisks},
+	"wipefs":   {risks: []string{Destructive, Privileged}, reason: "erases filesystem signatures"},
+	"mkswap":   {risks: []string{Destructive, Privileged}, reason: "formats a partition as swap"},
+	"diskutil": {subcommands: []models.SubcommandRisk{
+		sub("eraseDisk", overwritesDisks, Destructive),
+		sub("eraseVolume", overwritesDisks, Destructive),
+		sub("partitionDisk", overwritesDisks, Destructive),
+		sub("zeroDisk", overwritesDisks, Destructive),
+	}},
+	"format": {risks: []string{Destructive}, reason: "formats a disk"},
+
+	// Processes and the machine
+	"kill":     {risks: []string{Destructive}, reason: stopsProcesses},
+	"killall":  {risks: []string{Destructive}, reason: stopsProcesses},
+	"pkill":    {risks: []string{Destructive}, reason: stopsProcesses},
+	"taskkill": {risks: []string{Destructive}, reason: stopsProcesses},
+	"shutdown": {risks: []string{Destructive, Privileged}, reason: stopsMachine},
+	"reboot":   {risks: []string{Destructive, Privileged}, reason: stopsMachine},
+	"halt":     {risks: []string{Destructive, Privileged}, reason: stopsMachine},
+	"poweroff": {risks: []string{Destructive, Privileged}, reason: stopsMachine},
+	"systemctl": {subcommands: []models.SubcommandRisk{
+		sub("stop", "stops services", Destructive, Privileged),
+		sub("restart", "restarts services", Destructive, Privileged),
+		sub("kill", "stops services", Destructive, Privileged),
+		sub("disable", "keeps services from starting", Privileged),
+		sub("mask", "keeps services from starting", Privileged),
+		sub("poweroff", stopsMachine, Destructive, Privileged),
+		sub("reboot", stopsMachine, Destructive, Privileged),
+	}},
+	"launchctl": {subcommands: []models.SubcommandRisk{
+		sub("bootout", "stops services", Destructive),
+		sub("unload", "stops services", Destructive),
+		sub("kill", "stops services", Destructive),
+	}},
+
+	// Privileges and accounts
+	"sudo":     {risks: []string{Privileged}, reason: elevates},
+	"su":       {risks: []string{Privileged}, reason: elevates},
+	"doas":     {risks: []string{Privileged}, reason: elevates},
+	"pkexec":   {risks: []string{Privileged}, reason: elevates},
+	"runas":    {risks: []string{Privileged}, reason: elevates},
+	"chroot":   {risks: []string{Privileged}, reason: "runs commands in another root directory"},
+	"visudo":   {risks: []string{Privileged}, reason: "edits who may run commands as root"},
+	"passwd":   {risks: []string{Privileged}, reason: "changes passwords"},
+	"useradd":  {risks: []string{Privileged}, reason: "creates user accounts"},
+	"usermod":  {risks: []string{Privileged}, reason: "changes user accounts"},
+	"userdel":  {risks: []string{Destructive, Privileged}, reason: "deletes user accounts"},
+	"chown":    {risks: []string{Privileged}, reason: "changes file ownership"},
+	"mount":    {risks: []string{Privileged}, reason: "mounts filesystems"},
+	"umount":   {risks: []string{Privileged}, reason: "unmounts filesystems"},
+	"iptables": {risks: []string{Privileged}, reason: "changes firewall rules"},
+	"nft":      {risks: []string{Privileged}, reason: "changes firewall rules"},
+	"ufw":      {risks: []string{Privileged}, reason: "changes firewall rules"},
+
+	// Network transfers
+	"curl":   {risks: []string{NetworkWrite}, reason: downloads},
+	"wget":   {risks: []string{NetworkWrite}, reason: downloads},
+	"aria2c": {risks: []string{NetworkWrite}, reason: downloads},
+	"http":   {risks: []string{NetworkWrite}, reason: "sends HTTP requests"},
+	"xh":     {risks: []string{NetworkWrite}, reason: "sends HTTP requests"},
+	"scp":    {risks: []string{NetworkWrite}, reason: transfers},
+	"sftp":   {risks: []string{NetworkWrite}, reason: transfers},
+	"ftp":    {risks: []string{NetworkWrite}, reason: transfers},
+	"rsync":  {risks: []string{NetworkWrite, Destructive}, reason: "transfers files to or from other machines, deleting files with --delete"},
+	"ssh":    {risks: []string{NetworkWrite}, reason: "runs commands on other machines"},
+	"nc":     {risks: []string{NetworkWrite}, reason: "sends raw data over the network"},
+	"ncat":   {risks: []string{NetworkWrite}, reason: "sends raw data over the network"},
+	"socat":  {risks: []string{NetworkWrite}, reason: "sends raw data over the network"},
+
+	// Cloud and infrastructure
+	"aws":     {risks: []string{NetworkWrite}, reason: cloudResources},
+	"gcloud":  {risks: []string{NetworkWrite}, reason: cloudResources},
+	"az":      {risks: []string{NetworkWrite}, reason: cloudResources},
+	"doctl":   {risks: []string{NetworkWrite}, reason: cloudResources},
+	"flyctl":  {risks: []string{NetworkWrite}, reason: cloudResources},
+	"heroku":  {risks: []string{NetworkWrite}, reason: cloudResources},
+	"vercel":  {risks: []string{NetworkWrite}, reason: cloudResources},
+	"netlify": {risks: []string{NetworkWrite}, reason: cloudResources},
+	"terraform": {subcommands: []models.SubcommandRisk{
+		sub("destroy", "deletes the infrastructure a configuration manages", Destructive, NetworkWrite),
+		sub("apply", "changes infrastructure", NetworkWrite),
+		sub("import", "changes the recorded state", NetworkWrite),
+		sub("state rm", "drops resources from the recorded state", Destructive),
+	}},
+	"tofu": {subcommands: []models.SubcommandRisk{
+		sub("destroy", "deletes the infrastructure a configuration manages", Destructive, NetworkWrite),
+		sub("apply", "changes infrastructure", NetworkWrite),
+		sub("import", "changes the recorded state", NetworkWrite),
+		sub("state rm", "drops resources from the recorded state", Destructive),
+	}},
+	"pulumi": {subcommands: []models.SubcommandRisk{
+		sub("destroy", "deletes the resources of a stack", Destructive, NetworkWrite),
+		sub("up", "changes infrastructure", NetworkWrite),
+		sub("stack rm", "deletes a stack", Destructive),
+	}},
+	"kubectl": {subcommands: []models.SubcommandRisk{
+		sub("delete", "deletes cluster resources", Destructive, NetworkWrite),
+		sub("drain", "evicts the pods of a node", Destructive, NetworkWrite),
+		sub("apply", "changes cluster resources", NetworkWrite),
+		sub("create", "creates cluster resources", NetworkWrite),
+		sub("replace", "changes cluster resources", NetworkWrite),
+		sub("patch", "changes cluster resources", NetworkWrite),
+		sub("scale", "changes how many replicas run", NetworkWrite),
+		sub("exec", "runs commands in containers", NetworkWrite),
+	}},
+	"helm": {subcommands: []models.SubcommandRisk{
+		sub("uninstall", "deletes a release", Destructive, NetworkWrite),
+		sub("install", "deploys a release", NetworkWrite),
+		sub("upgrade", "changes a release", NetworkWrite),
+		sub("rollback", "changes a release", NetworkWrite),
+	}},
+
+	// Containers
+	"docker": {subcommands: []models.SubcommandRisk{
+		sub("rm", "deletes containers", Destructive),
+		sub("rmi", "deletes images", Destructive),
+		sub("kill", "stops containers", Destructive),
+		sub("system prune", "deletes unused containers, images, and volumes", Destructive),
+		sub("volume rm", "deletes volumes and their data", Destructive),
+		sub("push", "uploads images to a registry", NetworkWrite),
+	}},
+	"podman": {subcommands: []models.SubcommandRisk{
+		sub("rm", "deletes containers", Destructive),
+		sub("rmi", "deletes images", Destructive),
+		sub("kill", "stops containers", Destructive),
+		sub("system prune", "deletes unused containers, images, and volumes", Destructive),
+		sub("volume rm", "deletes volumes and their data", Destructive),
+		sub("push", "uploads images to a registry", NetworkWrite),
+	}},
+
+	// Version control
+	"git": {subcommands: []models.SubcommandRisk{
+		sub("push", "changes remote repositories", NetworkWrite),
+		sub("clean", "deletes untracked files", Destructive),
+		sub("reset", "discards commits and changes with --hard", Destructive),
+		sub("filter-branch", "rewrites history", Destructive),
+	}},
+	"gh": {subcommands: []models.SubcommandRisk{
+		sub("repo delete", "deletes a repository", Destructive, NetworkWrite),
+		sub("release delete", "deletes a release", Destructive, NetworkWrite),
+		sub("pr merge", "merges a pull request", NetworkWrite),
+		sub("api", "sends requests to the GitHub API", NetworkWrite),
+	}},
+
+	// Package managers
+	"npm": {subcommands: []models.SubcommandRisk{
+		sub("install", installs, NetworkWrite),
+		sub("uninstall", uninstalls, Destructive),
+		sub("publish", "uploads a package to the registry", NetworkWrite),
+		sub("unpublish", "deletes a package from the registry", Destructive, NetworkWrite),
+	}},
+	"npx":  {risks: []string{NetworkWrite}, reason: "downloads and runs packages"},
+	"bunx": {risks: []string{NetworkWrite}, reason: "downloads and runs packages"},
+	"uvx":  {risks: []string{NetworkWrite}, reason: "downloads and runs packages"},
+	"pip": {subcommands: []models.SubcommandRisk{
+		sub("install", installs, NetworkWrite),
+		sub("uninstall", uninstalls, Destructive),
+	}},
+	"pip3": {subcommands: []models.SubcommandRisk{
+		sub("install", installs, NetworkWrite),
+		sub("uninstall", uninstalls, Destructive),
+	}},
+	"brew": {subcommands: []models.SubcommandRisk{
+		sub("install", installs, NetworkWrite),
+		sub("upgrade", installs, NetworkWrite),
+		sub("uninstall", uninstalls, Destructive),
+		sub("cleanup", "deletes old versions and downloads", Destructive),
+	}},
+	"apt": {subcommands: []models.SubcommandRisk{
+		sub("install", installs, NetworkWrite, Privileged),
+		sub("upgrade", installs, NetworkWrite, Privileged),
+		sub("remove", uninstalls, Destructive, Privileged),
+		sub("purge", uninstalls, Destructive, Privileged),
+		sub("autoremove", uninstalls, Destructive, Privileged),
+	}},
+	"apt-get": {subcommands: []models.SubcommandRisk{
+		sub("install", installs, NetworkWrite, Privileged),
+		sub("upgrade", installs, NetworkWrite, Privileged),
+		sub("remove", uninstalls, Destructive, Privileged),
+		sub("purge", uninstalls, Destructive, Privileged),
+		sub("autoremove", uninstalls, Destructive, Privileged),
+	}},
+	"cargo": {subcommands: []models.SubcommandRisk{
+		sub("install", installs, NetworkWrite),
+		sub("uninstall", uninstalls, Destructive),
+		sub("publish", "uploads a crate to the registry", NetworkWrite),
+	}},
+	"gem": {subcommands: []models.SubcommandRisk{
+		sub("install", installs, NetworkWrite),
+		sub("uninstall", uninstalls, Destructive),
+		sub("push", "uploads a gem to the registry", NetworkWrite),
+	}},
+}
+
+// prefixes give the risks of families of tools that share a name prefix
+var prefixes = []struct {
+	prefix string
+	rule   rule
+}{
+	{"mkfs", rule{risks: []string{Destructive, Privileged}, reason: "formats a partition, erasing it"}},
+}
+
+// lookup returns the built-in rule for a tool name, matched exactly, then
+// by prefix
+func lookup(name string) (rule, bool) {
+	if r, ok := builtin[name]; ok {
+		return r, true
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(name, p.prefix) {
+			return p.rule, true
+		}
+	}
+	return rule{}, false
+}
+
+// subcommandRisk is the risk a subcommand name suggests
+type subcommandRisk struct {
+	risk   string
+	reason string
+}
+
+// subcommandWords are subcommand names that suggest a risk, for tools the
+// built-in list does not know
+var subcommandWords = map[string]subcommandRisk{
+	"delete":    {Destructive, "deletes data"},
+	"destroy":   {Destructive, "deletes data"},
+	"remove":    {Destructive, "deletes data"},
+	"rm":        {Destructive, "deletes data"},
+	"purge":     {Destructive, "deletes data"},
+	"prune":     {Destructive, "deletes data"},
+	"drop":      {Destructive, "deletes data"},
+	"wipe":      {Destructive, "deletes data"},
+	"erase":     {Destructive, "deletes data"},
+	"uninstall": {Destructive, uninstalls},
+	"kill":      {Destructive, stopsProcesses},
+	"terminate": {Destructive, stopsProcesses},
+	"push":      {NetworkWrite, "uploads data"},
+	"publish":   {NetworkWrite, "uploads data"},
+	"upload":    {NetworkWrite, "uploads data"},
+	"deploy":    {NetworkWrite, "changes a deployment"},
+	"install":   {NetworkWrite, installs},
+}
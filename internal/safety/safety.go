@@ -0,0 +1,233 @@
+// Package safety annotates tools with the risks of running them, from a
+// built-in list of dangerous tools and subcommands, the subcommands their
+// help lists, and the user's overrides, so agent policies can ask for
+// confirmation before running them.
+package safety
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Risks a tool or subcommand can carry
+const (
+	// Destructive deletes or overwrites data, or stops processes or the
+	// machine
+	Destructive = "destructive"
+	// NetworkWrite changes remote state, uploads data, or downloads code
+	// to run
+	NetworkWrite = "network-write"
+	// Privileged runs commands with, or changes, elevated privileges
+	Privileged = "privileged"
+)
+
+// Risks lists every risk, in the order they are reported
+var Risks = []string{Destructive, NetworkWrite, Privileged}
+
+// Sources of an annotation
+const (
+	SourceBuiltin   = "builtin"
+	SourceHeuristic = "heuristic"
+	SourceOverride  = "override"
+)
+
+// DefaultFileName is the override file looked up in the user's home
+// directory
+const DefaultFileName = ".cli-safety.yaml"
+
+// Override replaces the annotation of the tools its name matches. An
+// override with no risks and no subcommands marks a tool as having none.
+type Override struct {
+	Risks       []string                `yaml:"risks"`
+	Reason      string                  `yaml:"reason"`
+	Subcommands []models.SubcommandRisk `yaml:"subcommands"`
+}
+
+// Overrides are the user's annotations, keyed by tool name or shell glob
+type Overrides struct {
+	Tools map[string]Override `yaml:"tools"`
+}
+
+// DefaultPath returns the default override file location
+// ($HOME/.cli-safety.yaml)
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, DefaultFileName)
+}
+
+// Load reads the override file at path, or the default location when path
+// is empty. A missing default file yields no overrides; a missing file that
+// was asked for explicitly is an error.
+func Load(path string) (*Overrides, error) {
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+
+	overrides := &Overrides{}
+	if path == "" {
+		return overrides, nil
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+	case !explicit && errors.Is(err, os.ErrNotExist):
+		return overrides, nil
+	default:
+		return nil, fmt.Errorf("reading safety file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, overrides); err != nil {
+		return nil, fmt.Errorf("parsing safety file %s: %w", path, err)
+	}
+	if err := overrides.validate(); err != nil {
+		return nil, fmt.Errorf("safety file %s: %w", path, err)
+	}
+	return overrides, nil
+}
+
+// validate checks the patterns and risks of every override
+func (o *Overrides) validate() error {
+	for pattern, override := range o.Tools {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("tools: invalid pattern %q: %w", pattern, err)
+		}
+		if err := checkRisks(override.Risks); err != nil {
+			return fmt.Errorf("tools.%s: %w", pattern, err)
+		}
+		for _, sub := range override.Subcommands {
+			if sub.Name == "" {
+				return fmt.Errorf("tools.%s: subcommand without a name", pattern)
+			}
+			if err := checkRisks(sub.Risks); err != nil {
+				return fmt.Errorf("tools.%s: subcommand %s: %w", pattern, sub.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkRisks returns an error naming the valid risks if any risk is not one
+func checkRisks(risks []string) error {
+	for _, risk := range risks {
+		valid := false
+		for _, r := range Risks {
+			if r == risk {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown risk %q (valid: %s)", risk, strings.Join(Risks, ", "))
+		}
+	}
+	return nil
+}
+
+// match returns the override for a tool name: the one naming it exactly,
+// else the longest matching glob
+func (o *Overrides) match(name string) (Override, bool) {
+	if o == nil {
+		return Override{}, false
+	}
+	if override, ok := o.Tools[name]; ok {
+		return override, true
+	}
+	var patterns []string
+	for pattern := range o.Tools {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			patterns = append(patterns, pattern)
+		}
+	}
+	if len(patterns) == 0 {
+		return Override{}, false
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+	return o.Tools[patterns[0]], true
+}
+
+// Annotate sets the safety annotation of each tool, leaving it nil for
+// tools with no known risk
+func Annotate(tools []models.Tool, overrides *Overrides) {
+	for i := range tools {
+		tools[i].Safety = Assess(tools[i], overrides)
+	}
+}
+
+// Assess returns the safety annotation of a tool, or nil when it has no
+// known risk. An override replaces everything else. Otherwise the built-in
+// list gives the risks of well-known tools and subcommands, and the
+// subcommands the tool's help lists are judged by their names (delete,
+// destroy, push, deploy, ...).
+func Assess(tool models.Tool, overrides *Overrides) *models.SafetyInfo {
+	if override, ok := overrides.match(tool.Name); ok {
+		if len(override.Risks) == 0 && len(override.Subcommands) == 0 {
+			return nil
+		}
+		return &models.SafetyInfo{
+			Risks:       override.Risks,
+			Reason:      override.Reason,
+			Subcommands: override.Subcommands,
+			Source:      SourceOverride,
+		}
+	}
+
+	info := &models.SafetyInfo{}
+	if r, ok := lookup(tool.Name); ok {
+		info.Risks = r.risks
+		info.Reason = r.reason
+		info.Subcommands = append(info.Subcommands, r.subcommands...)
+		info.Source = SourceBuiltin
+	}
+
+	if tool.Help != nil {
+		known := make(map[string]bool)
+		for _, sub := range info.Subcommands {
+			known[sub.Name] = true
+		}
+		for _, sub := range riskySubcommands(tool.Help.Subcommands, "") {
+			if !known[sub.Name] {
+				info.Subcommands = append(info.Subcommands, sub)
+				if info.Source == "" {
+					info.Source = SourceHeuristic
+				}
+			}
+		}
+	}
+
+	if info.Source == "" {
+		return nil
+	}
+	return info
+}
+
+// riskySubcommands judges subcommands, and the subcommands of walked
+// subcommands, by their names
+func riskySubcommands(subcommands []models.Subcommand, parent string) []models.SubcommandRisk {
+	var risky []models.SubcommandRisk
+	for _, sub := range subcommands {
+		name := sub.Name
+		if parent != "" {
+			name = parent + " " + name
+		}
+		if risk, ok := subcommandWords[strings.ToLower(sub.Name)]; ok {
+			risky = append(risky, models.SubcommandRisk{Name: name, Risks: []string{risk.risk}, Reason: risk.reason})
+		}
+		risky = append(risky, riskySubcommands(sub.Subcommands, name)...)
+	}
+	return risky
+}
@@ -0,0 +1,122 @@
+package sbom
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// cycloneDXSpecVersion is the CycloneDX specification version written
+const cycloneDXSpecVersion = "1.5"
+
+// CycloneDXBOM is a CycloneDX bill of materials in its JSON form
+type CycloneDXBOM struct {
+	BOMFormat    string               `json:"bomFormat"`
+	SpecVersion  string               `json:"specVersion"`
+	SerialNumber string               `json:"serialNumber"`
+	Version      int                  `json:"version"`
+	Metadata     CycloneDXMetadata    `json:"metadata"`
+	Components   []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXMetadata says when and by what the bill was written, and for
+// which machine
+type CycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp"`
+	Tools     CycloneDXTools     `json:"tools"`
+	Component CycloneDXComponent `json:"component"`
+}
+
+// CycloneDXTools lists the programs that wrote the bill
+type CycloneDXTools struct {
+	Components []CycloneDXComponent `json:"components"`
+}
+
+// CycloneDXComponent is a package, a standalone tool, or the machine
+type CycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Hashes     []CycloneDXHash     `json:"hashes,omitempty"`
+	Properties []CycloneDXProperty `json:"properties,omitempty"`
+}
+
+// CycloneDXHash is a digest of a component's file
+type CycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+// CycloneDXProperty is a name-value pair CycloneDX has no field for
+type CycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CycloneDX builds a CycloneDX bill of materials for the catalog: one
+// application component per package, with the tools it provides and its
+// manager as properties, and one per tool no package owns, with its path
+// and, if hashed, its SHA-256. host names the machine the catalog describes.
+func CycloneDX(catalog *models.ToolCatalog, host string, creator Creator) CycloneDXBOM {
+	bom := CycloneDXBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  cycloneDXSpecVersion,
+		SerialNumber: "urn:uuid:" + newUUID(),
+		Version:      1,
+		Metadata: CycloneDXMetadata{
+			Timestamp: timestamp(catalog),
+			Tools: CycloneDXTools{Components: []CycloneDXComponent{{
+				Type:    "application",
+				Name:    creator.Name,
+				Version: creator.Version,
+			}}},
+			Component: CycloneDXComponent{Type: "device", Name: host},
+		},
+		Components: []CycloneDXComponent{},
+	}
+
+	refs := make(map[string]int)
+	for _, comp := range components(catalog) {
+		// bom-refs must be unique, but pip and pipx can install the same
+		// package at the same version
+		ref := comp.PURL
+		if refs[comp.PURL]++; refs[comp.PURL] > 1 {
+			ref += "#" + strconv.Itoa(refs[comp.PURL])
+		}
+		entry := CycloneDXComponent{
+			Type:    "application",
+			BOMRef:  ref,
+			Name:    comp.Name,
+			Version: comp.Version,
+			PURL:    comp.PURL,
+		}
+		if comp.Manager != "" {
+			entry.Properties = append(entry.Properties, CycloneDXProperty{Name: "cli:package_manager", Value: comp.Manager})
+		}
+		if len(comp.Binaries) > 0 {
+			entry.Properties = append(entry.Properties, CycloneDXProperty{Name: "cli:binaries", Value: strings.Join(comp.Binaries, ",")})
+		}
+		if comp.Path != "" {
+			entry.Properties = append(entry.Properties, CycloneDXProperty{Name: "cli:path", Value: comp.Path})
+		}
+		if comp.SHA256 != "" {
+			entry.Hashes = []CycloneDXHash{{Alg: "SHA-256", Content: comp.SHA256}}
+		}
+		bom.Components = append(bom.Components, entry)
+	}
+	return bom
+}
+
+// timestamp returns when the catalog was generated, in UTC as SBOM formats
+// expect, falling back to now
+func timestamp(catalog *models.ToolCatalog) string {
+	generated, err := time.Parse(time.RFC3339, catalog.GeneratedAt)
+	if err != nil {
+		generated = time.Now()
+	}
+	return generated.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,157 @@
+// Package sbom renders a tool catalog as a software bill of materials, in
+// the CycloneDX and SPDX JSON formats, so machine tool inventories can be
+// fed into existing SBOM pipelines.
+package sbom
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/semver"
+)
+
+// Creator names the program that wrote a bill of materials
+type Creator struct {
+	Name    string
+	Version string
+}
+
+// purlTypes maps package managers to package URL types. brew, macports,
+// nix, snap, flatpak, deno, scoop, chocolatey, and winget have no
+// registered type, so they are named after the manager, as other SBOM
+// generators do.
+var purlTypes = map[string]string{
+	"npm":      "npm",
+	"pip":      "pypi",
+	"pipx":     "pypi",
+	"uv":       "pypi",
+	"poetry":   "pypi",
+	"brew":     "brew",
+	"cargo":    "cargo",
+	"go":       "golang",
+	"gem":      "gem",
+	"macports": "macports",
+	"nix":      "nix",
+	"snap":     "snap",
+	"flatpak":  "flatpak",
+	"deno":     "deno",
+	"dotnet":   "nuget",
+	"composer": "composer",
+	"scoop":    "scoop",
+	"choco":    "chocolatey",
+	"winget":   "winget",
+}
+
+// component is one entry of a bill of materials: a package and the tools
+// it provides, or a tool no package manager owns
+type component struct {
+	Name     string
+	Version  string
+	Manager  string
+	PURL     string
+	Binaries []string
+	// Path and SHA256 are only set for standalone tools
+	Path   string
+	SHA256 string
+}
+
+// PURL returns the package URL of a package, such as pkg:npm/%40vercel/cli@1.0.0
+// or pkg:golang/github.com/junegunn/fzf@v0.44.1. Managers without a purl
+// type get one named after the manager.
+func PURL(manager, name, version string) string {
+	purlType, ok := purlTypes[manager]
+	if !ok {
+		purlType = strings.ToLower(manager)
+	}
+
+	var namespace string
+	switch purlType {
+	case "pypi":
+		// PyPI names are case-insensitive and treat _ as -
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	case "npm":
+		if scope, rest, ok := strings.Cut(name, "/"); ok && strings.HasPrefix(scope, "@") {
+			namespace, name = scope, rest
+		}
+	case "golang":
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			namespace, name = name[:i], name[i+1:]
+		}
+	case "composer":
+		// composer packages are named vendor/package
+		if vendor, rest, ok := strings.Cut(name, "/"); ok {
+			namespace, name = vendor, rest
+		}
+	}
+	return purl(purlType, namespace, name, version)
+}
+
+// standalonePURL returns the package URL of a tool no package manager owns
+func standalonePURL(name, version string) string {
+	return purl("generic", "", name, version)
+}
+
+func purl(purlType, namespace, name, version string) string {
+	var sb strings.Builder
+	sb.WriteString("pkg:" + purlType + "/")
+	if namespace != "" {
+		for _, segment := range strings.Split(namespace, "/") {
+			sb.WriteString(escape(segment) + "/")
+		}
+	}
+	sb.WriteString(escape(name))
+	if version != "" {
+		sb.WriteString("@" + escape(version))
+	}
+	return sb.String()
+}
+
+// escape percent-encodes a purl segment, including the '@' that would
+// otherwise start the version and the '+' of build metadata
+func escape(segment string) string {
+	return strings.NewReplacer("@", "%40", "+", "%2B").Replace(url.PathEscape(segment))
+}
+
+// components lists a catalog's packages, then the active tools no package
+// owns. Shadowed installations never run, so they are left out.
+func components(catalog *models.ToolCatalog) []component {
+	var list []component
+	for _, pkg := range catalog.Packages {
+		list = append(list, component{
+			Name:     pkg.Name,
+			Version:  pkg.Version,
+			Manager:  pkg.Manager,
+			PURL:     PURL(pkg.Manager, pkg.Name, pkg.Version),
+			Binaries: pkg.Binaries,
+		})
+	}
+	for _, tool := range catalog.Tools {
+		if tool.PackageName != "" || tool.Shadowed {
+			continue
+		}
+		version := semver.Extract(tool.Version)
+		list = append(list, component{
+			Name:     tool.Name,
+			Version:  version,
+			PURL:     standalonePURL(tool.Name, version),
+			Binaries: []string{tool.Name},
+			Path:     tool.Path,
+			SHA256:   tool.SHA256,
+		})
+	}
+	return list
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,128 @@
+package sbom
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// spdxVersion is the SPDX specification version written
+const spdxVersion = "SPDX-2.3"
+
+// spdxNamespaceBase prefixes each document's unique namespace URI
+const spdxNamespaceBase = "https://cli-ai.org/spdx/"
+
+// noAssertion is SPDX's value for information that was not looked for
+const noAssertion = "NOASSERTION"
+
+// SPDXDocument is an SPDX document in its JSON form
+type SPDXDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo   `json:"creationInfo"`
+	Packages          []SPDXPackage      `json:"packages"`
+	Relationships     []SPDXRelationship `json:"relationships"`
+}
+
+// SPDXCreationInfo says when and by what the document was written
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+// SPDXPackage is a package or a standalone tool
+type SPDXPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Comment          string            `json:"comment,omitempty"`
+	Checksums        []SPDXChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []SPDXExternalRef `json:"externalRefs,omitempty"`
+}
+
+// SPDXChecksum is a digest of a package's file
+type SPDXChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+// SPDXExternalRef identifies a package outside the document, here by purl
+type SPDXExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+// SPDXRelationship relates two elements of the document
+type SPDXRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// SPDX builds an SPDX document for the catalog, describing one package per
+// package and per tool no package owns, each with its purl. Licenses and
+// download locations are not known, so they are NOASSERTION. The tools a
+// package provides, and the path of a standalone tool, go in its comment.
+// host names the machine the catalog describes.
+func SPDX(catalog *models.ToolCatalog, host string, creator Creator) SPDXDocument {
+	name := "cli-tools-" + host
+	doc := SPDXDocument{
+		SPDXVersion:       spdxVersion,
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              name,
+		DocumentNamespace: spdxNamespaceBase + escape(name) + "-" + newUUID(),
+		CreationInfo: SPDXCreationInfo{
+			Created:  timestamp(catalog),
+			Creators: []string{"Tool: " + creator.Name + "-" + creator.Version},
+		},
+		Packages:      []SPDXPackage{},
+		Relationships: []SPDXRelationship{},
+	}
+
+	for i, comp := range components(catalog) {
+		id := "SPDXRef-Package-" + strconv.Itoa(i+1)
+		pkg := SPDXPackage{
+			Name:             comp.Name,
+			SPDXID:           id,
+			VersionInfo:      comp.Version,
+			DownloadLocation: noAssertion,
+			LicenseConcluded: noAssertion,
+			LicenseDeclared:  noAssertion,
+			CopyrightText:    noAssertion,
+			ExternalRefs: []SPDXExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  comp.PURL,
+			}},
+		}
+		switch {
+		case comp.Path != "":
+			pkg.Comment = "Standalone tool at " + comp.Path
+		case len(comp.Binaries) > 0:
+			pkg.Comment = "Installed with " + comp.Manager + "; provides " + strings.Join(comp.Binaries, ", ")
+		default:
+			pkg.Comment = "Installed with " + comp.Manager
+		}
+		if comp.SHA256 != "" {
+			pkg.Checksums = []SPDXChecksum{{Algorithm: "SHA256", ChecksumValue: comp.SHA256}}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, SPDXRelationship{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: id,
+		})
+	}
+	return doc
+}
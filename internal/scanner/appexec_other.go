@@ -0,0 +1,13 @@
+//go:build !windows
+
+package scanner
+
+// isAppExecAlias always reports false: App Execution Aliases only exist on Windows
+func isAppExecAlias(path string) bool {
+	return false
+}
+
+// isShortcut always reports false: .lnk shortcuts only run on Windows
+func isShortcut(path string) bool {
+	return false
+}
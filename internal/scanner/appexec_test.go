@@ -0,0 +1,20 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+func TestWithoutLaunchers(t *testing.T) {
+	tools := []models.Tool{
+		{Name: "git"},
+		{Name: "python", AppExecAlias: true},
+		{Name: "editor", Shortcut: true},
+		{Name: "jq"},
+	}
+	got := WithoutLaunchers(tools)
+	if len(got) != 2 || got[0].Name != "git" || got[1].Name != "jq" {
+		t.Errorf("WithoutLaunchers = %+v, want git and jq", got)
+	}
+}
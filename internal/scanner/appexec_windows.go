@@ -0,0 +1,40 @@
+//go:build windows
+
+package scanner
+
+import (
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// ioReparseTagAppExecLink is the reparse tag of App Execution Aliases
+const ioReparseTagAppExecLink = 0x8000001B
+
+// isAppExecAlias reports whether path is an App Execution Alias: a zero-byte
+// reparse-point stub (e.g. WindowsApps\python.exe) that opens the Microsoft
+// Store or launches a packaged app instead of being a real executable
+func isAppExecAlias(path string) bool {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return false
+	}
+
+	// For reparse points, FindFirstFile reports the reparse tag in Reserved0
+	var data syscall.Win32finddata
+	handle, err := syscall.FindFirstFile(name, &data)
+	if err != nil {
+		return false
+	}
+	syscall.FindClose(handle)
+
+	return data.FileAttributes&syscall.FILE_ATTRIBUTE_REPARSE_POINT != 0 &&
+		data.Reserved0 == ioReparseTagAppExecLink
+}
+
+// isShortcut reports whether path is a .lnk shortcut. cmd.exe runs one when
+// .LNK is in PATHEXT by handing it to the shell, but CreateProcess, and so
+// every program that execs tools, cannot start it.
+func isShortcut(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".lnk")
+}
@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// scanWorkers bounds how many PATH directories are read at once. Reading
+// directories is I/O bound, so this is independent of the CPU count.
+const scanWorkers = 8
+
+// ScanAllOccurrences scans every PATH directory and returns every
+// occurrence of every tool, not just the first. Directories are read
+// concurrently, but results are merged back in exact PATH order, so for any
+// name the first occurrence is the one the shell runs and the rest are
+// marked Shadowed, each with its PathRank among that name's occurrences.
+// Repeated PATH entries, including a directory reached through a symlinked
+// alias such as /bin for /usr/bin, are scanned once, at their first
+// position. If the scanner's context is
+// cancelled, directories not yet read are skipped and the occurrences found
+// so far are returned with the context's error.
+func (s *Scanner) ScanAllOccurrences() ([]models.Tool, error) {
+	// Each directory's results go into its own slot, indexed by PATH
+	// position, so the merge below does not depend on completion order
+	results := make([][]models.Tool, len(s.paths))
+
+	seen := make(map[string]bool)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < scanWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if s.ctx.Err() != nil {
+					continue
+				}
+				results[index] = s.scanDir(index, s.paths[index])
+			}
+		}()
+	}
+	for index, dir := range s.paths {
+		key := DirKey(dir)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+
+	var tools []models.Tool
+	for _, dirTools := range results {
+		tools = append(tools, dirTools...)
+	}
+	rankOccurrences(tools)
+	return tools, s.ctx.Err()
+}
+
+// rankOccurrences sets PathRank and Shadowed on tools in PATH order
+func rankOccurrences(tools []models.Tool) {
+	ranks := make(map[string]int)
+	for i := range tools {
+		key := NameKey(tools[i].Name)
+		tools[i].PathRank = ranks[key]
+		tools[i].Shadowed = ranks[key] > 0
+		ranks[key]++
+	}
+}
+
+// scanDir returns the CLI tools in one PATH directory, in directory order.
+// index is the directory's position in PATH.
+func (s *Scanner) scanDir(index int, dir string) []models.Tool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// Skip directories we can't read
+		return nil
+	}
+
+	var tools []models.Tool
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		if !s.includeTool(toolName(entry.Name())) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || !isExecutable(info) {
+			continue
+		}
+
+		tools = append(tools, newTool(dir, entry.Name(), index, info))
+	}
+	return tools
+}
+
+// newTool builds the Tool for the executable file found in a PATH directory.
+// info is the Lstat result, so symlinks are detected and recorded.
+func newTool(dir, file string, index int, info os.FileInfo) models.Tool {
+	fullPath := filepath.Join(dir, file)
+	tool := models.Tool{
+		Name:      toolName(file),
+		Path:      fullPath,
+		Size:      info.Size(),
+		PathIndex: index,
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		tool.IsSymlink = true
+		if target, err := os.Readlink(fullPath); err == nil {
+			tool.SymlinkTo = target
+		}
+	}
+
+	checkInstall(&tool)
+	return tool
+}
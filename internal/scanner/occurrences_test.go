@@ -0,0 +1,113 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// pathDirs creates n directories, each holding its own tool and a shared
+// one every directory has, the way PATH often holds several pythons
+func pathDirs(tb testing.TB, n, perDir int) []string {
+	tb.Helper()
+	if runtime.GOOS == "windows" {
+		tb.Skip("fixtures are executables by mode, not by extension")
+	}
+	root := tb.TempDir()
+	dirs := make([]string, n)
+	for i := range dirs {
+		dirs[i] = filepath.Join(root, fmt.Sprintf("bin%02d", i))
+		if err := os.Mkdir(dirs[i], 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		names := []string{"shared"}
+		for j := 0; j < perDir; j++ {
+			names = append(names, fmt.Sprintf("tool%02d-%03d", i, j))
+		}
+		for _, name := range names {
+			if err := os.WriteFile(filepath.Join(dirs[i], name), []byte("#!/bin/sh\n"), 0o755); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	return dirs
+}
+
+func TestScanAllOccurrencesOrder(t *testing.T) {
+	dirs := pathDirs(t, 50, 3)
+	// A repeated entry is scanned once, at its first position
+	paths := append(dirs, dirs[0])
+
+	tools, err := NewWithPaths(paths).ScanAllOccurrences()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 50 * 4; len(tools) != want {
+		t.Fatalf("got %d tools, want %d", len(tools), want)
+	}
+
+	lastIndex, shared := -1, 0
+	for _, tool := range tools {
+		if tool.PathIndex < lastIndex {
+			t.Fatalf("%s (PATH index %d) after index %d: results are not in PATH order", tool.Path, tool.PathIndex, lastIndex)
+		}
+		lastIndex = tool.PathIndex
+		if filepath.Dir(tool.Path) != dirs[tool.PathIndex] {
+			t.Errorf("%s has PATH index %d, want the index of its directory", tool.Path, tool.PathIndex)
+		}
+
+		if tool.Name != "shared" {
+			if tool.PathRank != 0 || tool.Shadowed {
+				t.Errorf("%s: PathRank %d, Shadowed %v; want 0, false", tool.Path, tool.PathRank, tool.Shadowed)
+			}
+			continue
+		}
+		if tool.PathRank != shared || tool.Shadowed != (shared > 0) || tool.PathIndex != shared {
+			t.Errorf("%s: PathRank %d, Shadowed %v, PathIndex %d; want %d, %v, %d",
+				tool.Path, tool.PathRank, tool.Shadowed, tool.PathIndex, shared, shared > 0, shared)
+		}
+		shared++
+	}
+	if shared != 50 {
+		t.Errorf("found shared %d times, want 50", shared)
+	}
+}
+
+func BenchmarkScanAllOccurrences(b *testing.B) {
+	s := NewWithPaths(pathDirs(b, 50, 40))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.ScanAllOccurrences(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestScanAllOccurrencesSymlinkedDir(t *testing.T) {
+	dirs := pathDirs(t, 1, 2)
+	// A merged-/usr layout: /bin is a symlink to /usr/bin
+	alias := filepath.Join(t.TempDir(), "bin")
+	if err := os.Symlink(dirs[0], alias); err != nil {
+		t.Fatal(err)
+	}
+	s := NewWithPaths([]string{dirs[0], alias})
+
+	tools, err := s.ScanAllOccurrences()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) != 3 {
+		t.Fatalf("got %d tools, want 3: the aliased directory is scanned once", len(tools))
+	}
+	for _, tool := range tools {
+		if tool.Shadowed {
+			t.Errorf("%s is shadowed by itself through the symlinked directory", tool.Path)
+		}
+	}
+
+	if found := s.FindAll("shared"); len(found) != 1 {
+		t.Errorf("FindAll found %d installations of shared, want 1", len(found))
+	}
+}
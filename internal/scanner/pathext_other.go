@@ -0,0 +1,38 @@
+//go:build !windows
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// isExecutable checks if a file has executable permissions
+func isExecutable(info os.FileInfo) bool {
+	mode := info.Mode()
+	return mode&0111 != 0
+}
+
+// toolName returns the command name a file in a PATH directory is run by,
+// which on Unix is the file name itself
+func toolName(file string) string {
+	return file
+}
+
+// candidates returns the file names a typed command resolves to within one
+// directory, in the order the shell tries them
+func candidates(name string) []string {
+	return []string{name}
+}
+
+// NameKey returns the key under which command names are compared. Unix
+// file names are case-sensitive, so the name is its own key.
+func NameKey(name string) string {
+	return name
+}
+
+// PathKey returns the key under which PATH directories are compared: the
+// cleaned path, since Unix paths are case-sensitive
+func PathKey(path string) string {
+	return filepath.Clean(path)
+}
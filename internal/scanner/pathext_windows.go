@@ -0,0 +1,98 @@
+//go:build windows
+
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultPathExt is cmd.exe's PATHEXT when the variable is unset
+const defaultPathExt = ".COM;.EXE;.BAT;.CMD"
+
+// pathExts returns the lower-cased extensions that make a file runnable by
+// name, in PATHEXT order. .ps1 is always included after them, since
+// PowerShell runs scripts from PATH regardless of PATHEXT.
+func pathExts() []string {
+	pathExt := os.Getenv("PATHEXT")
+	if pathExt == "" {
+		pathExt = defaultPathExt
+	}
+
+	var exts []string
+	for _, ext := range strings.Split(pathExt, ";") {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts = append(exts, ext)
+	}
+	if !containsExt(exts, ".ps1") {
+		exts = append(exts, ".ps1")
+	}
+	return exts
+}
+
+func containsExt(exts []string, ext string) bool {
+	for _, e := range exts {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// executableExt returns the PATHEXT extension of file, or "" if it has none.
+// Windows has no execute bit: the extension alone decides.
+func executableExt(file string) string {
+	ext := strings.ToLower(filepath.Ext(file))
+	if ext != "" && containsExt(pathExts(), ext) {
+		return ext
+	}
+	return ""
+}
+
+// isExecutable reports whether a file's extension is listed in PATHEXT
+func isExecutable(info os.FileInfo) bool {
+	return executableExt(info.Name()) != ""
+}
+
+// toolName returns the command name a file in a PATH directory is run by:
+// the file name without its PATHEXT extension (git.exe runs as git)
+func toolName(file string) string {
+	if ext := executableExt(file); ext != "" {
+		return file[:len(file)-len(ext)]
+	}
+	return file
+}
+
+// candidates returns the file names a typed command resolves to within one
+// directory, in the order cmd.exe tries them. A name that already carries
+// a PATHEXT extension is used as is.
+func candidates(name string) []string {
+	if executableExt(name) != "" {
+		return []string{name}
+	}
+	var names []string
+	for _, ext := range pathExts() {
+		names = append(names, name+ext)
+	}
+	return names
+}
+
+// NameKey returns the key under which command names are compared: the name
+// without its PATHEXT extension, lower-cased, since git, Git, and git.exe
+// all run the same file
+func NameKey(name string) string {
+	return strings.ToLower(toolName(name))
+}
+
+// PathKey returns the key under which PATH directories are compared: the
+// cleaned, lower-cased path, since Windows paths are case-insensitive
+func PathKey(path string) string {
+	return strings.ToLower(filepath.Clean(path))
+}
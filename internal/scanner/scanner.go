@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,13 +12,61 @@ import (
 // Scanner handles the discovery of CLI tools on the system
 type Scanner struct {
 	paths []string
+	// alwaysInclude names tools that bypass the CLI tool filters
+	alwaysInclude map[string]bool
+	// exclude holds shell globs of tool names that are never included
+	exclude []string
+	// ctx stops a scan early when cancelled
+	ctx context.Context
 }
 
 // New creates a new Scanner instance
 func New() *Scanner {
+	return NewWithPaths(getPathDirectories())
+}
+
+// NewWithPaths creates a Scanner that searches the given directories, in
+// order, instead of PATH. It makes scans independent of the environment,
+// for auditing one directory or testing against fixture directories.
+func NewWithPaths(paths []string) *Scanner {
 	return &Scanner{
-		paths: getPathDirectories(),
+		paths: paths,
+		ctx:   context.Background(),
+	}
+}
+
+// SetContext makes scans stop when ctx is cancelled, returning the tools
+// found so far along with ctx's error
+func (s *Scanner) SetContext(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// SetAlwaysInclude sets tool names that are always included, even when the
+// built-in filters would treat them as tests, daemons, or system internals
+func (s *Scanner) SetAlwaysInclude(names []string) {
+	s.alwaysInclude = make(map[string]bool)
+	for _, name := range names {
+		s.alwaysInclude[name] = true
+	}
+}
+
+// SetExclude sets shell globs of tool names to leave out of scan results.
+// Tools set with SetAlwaysInclude are still included.
+func (s *Scanner) SetExclude(patterns []string) {
+	s.exclude = patterns
+}
+
+// includeTool reports whether a tool should be included in scan results
+func (s *Scanner) includeTool(name string) bool {
+	if s.alwaysInclude[name] {
+		return true
+	}
+	for _, pattern := range s.exclude {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return false
+		}
 	}
+	return shouldIncludeTool(name)
 }
 
 // getPathDirectories returns all directories in the system PATH
@@ -46,10 +95,10 @@ func (s *Scanner) ScanAll() ([]string, error) {
 				continue
 			}
 
-			name := entry.Name()
+			name := toolName(entry.Name())
 
 			// Filter out non-CLI tools
-			if !shouldIncludeTool(name) {
+			if !s.includeTool(name) {
 				continue
 			}
 
@@ -60,8 +109,8 @@ func (s *Scanner) ScanAll() ([]string, error) {
 			}
 
 			if isExecutable(info) {
-				if !seen[name] {
-					seen[name] = true
+				if !seen[NameKey(name)] {
+					seen[NameKey(name)] = true
 					tools = append(tools, name)
 				}
 			}
@@ -71,10 +120,29 @@ func (s *Scanner) ScanAll() ([]string, error) {
 	return tools, nil
 }
 
-// isExecutable checks if a file has executable permissions
-func isExecutable(info os.FileInfo) bool {
-	mode := info.Mode()
-	return mode&0111 != 0
+// ListExecutables returns the names of all executable files in dir, without
+// applying the CLI tool filters
+func ListExecutables(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		// Stat follows symlinks so linked binaries count as executables
+		info, err := os.Stat(filepath.Join(dir, entry.Name()))
+		if err != nil || info.IsDir() {
+			continue
+		}
+		if isExecutable(info) {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
 }
 
 // shouldIncludeTool filters out system daemons, test utilities, and internal tools
@@ -168,78 +236,74 @@ func (s *Scanner) GetPaths() []string {
 	return s.paths
 }
 
-// ScanAllDetailed scans all PATH directories and returns detailed Tool information
+// ScanAllDetailed scans all PATH directories and returns detailed Tool
+// information for the installation of each tool that the shell runs. Use
+// ScanAllOccurrences to also get the installations it shadows. If the
+// scanner's context is cancelled, the tools found so far are returned with
+// the context's error.
 func (s *Scanner) ScanAllDetailed() ([]models.Tool, error) {
-	var tools []models.Tool
-	seen := make(map[string]bool)
+	occurrences, err := s.ScanAllOccurrences()
 
-	for _, dir := range s.paths {
-		entries, err := os.ReadDir(dir)
-		if err != nil {
-			// Skip directories we can't read
-			continue
+	var tools []models.Tool
+	for _, tool := range occurrences {
+		if !tool.Shadowed {
+			tools = append(tools, tool)
 		}
+	}
 
-		for _, entry := range entries {
-			if entry.IsDir() {
-				continue
-			}
+	return tools, err
+}
 
-			name := entry.Name()
+// FindAll returns every installation of a tool in PATH order. The first
+// result is the one the shell runs; the rest are shadowed. On Windows, name
+// may omit its PATHEXT extension, as when typed.
+func (s *Scanner) FindAll(name string) []models.Tool {
+	var tools []models.Tool
+	seen := make(map[string]bool)
 
-			// Filter out non-CLI tools
-			if !shouldIncludeTool(name) {
-				continue
-			}
+	for index, dir := range s.paths {
+		key := DirKey(dir)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
 
-			// Check if file is executable
-			info, err := entry.Info()
-			if err != nil {
-				continue
-			}
+		fullPath, info := findInDir(dir, name)
+		if info == nil {
+			continue
+		}
 
-			if isExecutable(info) {
-				if !seen[name] {
-					seen[name] = true
-					fullPath := filepath.Join(dir, name)
-
-					tool := models.Tool{
-						Name: name,
-						Path: fullPath,
-						Size: info.Size(),
-					}
-
-					// Check if symlink
-					if info.Mode()&os.ModeSymlink != 0 {
-						tool.IsSymlink = true
-						if target, err := os.Readlink(fullPath); err == nil {
-							tool.SymlinkTo = target
-						}
-					}
-
-					tools = append(tools, tool)
-				}
+		tool := models.Tool{
+			Name:      name,
+			Path:      fullPath,
+			Size:      info.Size(),
+			PathIndex: index,
+		}
+		if linkInfo, err := os.Lstat(fullPath); err == nil && linkInfo.Mode()&os.ModeSymlink != 0 {
+			tool.IsSymlink = true
+			if target, err := os.Readlink(fullPath); err == nil {
+				tool.SymlinkTo = target
 			}
 		}
+		checkInstall(&tool)
+
+		tools = append(tools, tool)
 	}
 
-	return tools, nil
+	rankOccurrences(tools)
+	return tools
 }
 
 // FindTool finds a specific tool by name and returns detailed information
 func (s *Scanner) FindTool(name string) (*models.Tool, error) {
-	for _, dir := range s.paths {
-		fullPath := filepath.Join(dir, name)
-		info, err := os.Stat(fullPath)
-		if err != nil {
-			continue
-		}
-
-		if isExecutable(info) {
+	for index, dir := range s.paths {
+		fullPath, info := findInDir(dir, name)
+		if info != nil {
 			tool := &models.Tool{
-				Name: name,
-				Path: fullPath,
-				Size: info.Size(),
+				Name:      name,
+				Path:      fullPath,
+				Size:      info.Size(),
+				PathIndex: index,
 			}
 
 			// Check if symlink
@@ -251,9 +315,81 @@ func (s *Scanner) FindTool(name string) (*models.Tool, error) {
 				}
 			}
 
+			checkInstall(tool)
+
 			return tool, nil
 		}
 	}
 
 	return nil, os.ErrNotExist
 }
+
+// findInDir returns the path and info of the executable a typed name runs
+// in dir, or a nil info when dir has none
+func findInDir(dir, name string) (string, os.FileInfo) {
+	for _, file := range candidates(name) {
+		fullPath := filepath.Join(dir, file)
+		info, err := os.Stat(fullPath)
+		if err == nil && !info.IsDir() && isExecutable(info) {
+			return fullPath, info
+		}
+	}
+	return "", nil
+}
+
+// checkInstall flags executables that are not real installs: Windows App
+// Execution Aliases and .lnk shortcuts, and zero-byte files left behind by failed downloads or
+// interrupted installs, which fail with "cannot execute binary file". It
+// only looks at file metadata; scripts whose interpreter is missing are
+// found by CheckInterpreters, which reads them.
+func checkInstall(tool *models.Tool) {
+	// Aliases are zero-byte by design, so check them before the size
+	if isAppExecAlias(tool.Path) {
+		tool.AppExecAlias = true
+		return
+	}
+	if isShortcut(tool.Path) {
+		tool.Shortcut = true
+		return
+	}
+
+	size := tool.Size
+	if tool.IsSymlink {
+		// The scanned size of a symlink is the link itself, not its target
+		info, err := os.Stat(tool.Path)
+		if err != nil {
+			return
+		}
+		size = info.Size()
+	}
+
+	if size == 0 {
+		tool.Broken = true
+		tool.BrokenReason = "zero-byte executable"
+	}
+}
+
+// WithoutLaunchers returns tools minus the App Execution Aliases and .lnk
+// shortcuts, which stand in for a program rather than being one. The
+// slice is filtered in place.
+func WithoutLaunchers(tools []models.Tool) []models.Tool {
+	kept := tools[:0]
+	for _, tool := range tools {
+		if !tool.AppExecAlias && !tool.Shortcut {
+			kept = append(kept, tool)
+		}
+	}
+	return kept
+}
+
+// DirKey returns the key under which PATH directories are compared when
+// deduplicating them: the PathKey of the directory with symlinks resolved,
+// so /bin and /usr/bin on a merged-/usr system, or /usr/bin and
+// /usr/bin/, are one directory scanned once
+func DirKey(dir string) string {
+	cleaned := filepath.Clean(dir)
+	if resolved, err := filepath.EvalSymlinks(cleaned); err == nil {
+		return PathKey(resolved)
+	}
+	return PathKey(cleaned)
+}
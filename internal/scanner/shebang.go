@@ -0,0 +1,121 @@
+package scanner
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// shebangBufSize matches the kernel's limit on how much of a script's first
+// line it reads to find the interpreter (BINPRM_BUF_SIZE on Linux)
+const shebangBufSize = 256
+
+// Shebang returns the interpreter a script's #! line names, resolving the
+// `/usr/bin/env <interp>` form to the command env would look up. It returns
+// an empty string for files without a shebang.
+func Shebang(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	buf := make([]byte, shebangBufSize)
+	n, _ := file.Read(buf)
+	buf = buf[:n]
+	if !bytes.HasPrefix(buf, []byte("#!")) {
+		return ""
+	}
+	if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+		buf = buf[:i]
+	}
+
+	fields := strings.Fields(strings.TrimSuffix(string(buf[2:]), "\r"))
+	if len(fields) == 0 {
+		return ""
+	}
+	if filepath.Base(fields[0]) != "env" {
+		return fields[0]
+	}
+
+	// Skip env's own options (-S, -i, -u NAME) and VAR=value assignments
+	for i := 1; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case field == "-u" || field == "--unset":
+			i++
+		case strings.HasPrefix(field, "-"), strings.Contains(field, "="):
+		default:
+			return field
+		}
+	}
+	return fields[0]
+}
+
+// checkShebang verifies that a script's interpreter exists and is
+// executable. A script whose interpreter was removed (an old python2 or
+// ruby) fails with a confusing "bad interpreter" error. It returns the
+// reason the script is broken, or an empty string.
+func checkShebang(path string) string {
+	// Windows does not execute scripts through #! lines
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+
+	interp := Shebang(path)
+	if interp == "" {
+		return ""
+	}
+
+	if !strings.Contains(interp, "/") {
+		// Bare names come from `/usr/bin/env <interp>` and resolve on PATH
+		if _, err := exec.LookPath(interp); err != nil {
+			return fmt.Sprintf("interpreter %s not found on PATH", interp)
+		}
+		return ""
+	}
+
+	info, err := os.Stat(interp)
+	if err != nil {
+		return fmt.Sprintf("interpreter %s not found", interp)
+	}
+	if info.IsDir() || !isExecutable(info) {
+		return fmt.Sprintf("interpreter %s is not executable", interp)
+	}
+	return ""
+}
+
+// CheckInterpreters marks the scripts among tools whose #! interpreter is
+// missing or not executable as broken. It opens every tool, so scans leave
+// it to the commands that report broken installations (audit, info, which,
+// and debug) rather than paying for it on every list or search.
+func CheckInterpreters(tools []models.Tool) {
+	jobs := make(chan *models.Tool)
+	var wg sync.WaitGroup
+	for w := 0; w < scanWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tool := range jobs {
+				if reason := checkShebang(tool.Path); reason != "" {
+					tool.Broken = true
+					tool.BrokenReason = reason
+				}
+			}
+		}()
+	}
+	for i := range tools {
+		if !tools[i].Broken && !tools[i].AppExecAlias && !tools[i].Shortcut {
+			jobs <- &tools[i]
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
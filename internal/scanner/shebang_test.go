@@ -0,0 +1,51 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+func TestCheckInterpreters(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows does not run scripts through #! lines")
+	}
+	dir := t.TempDir()
+	scripts := map[string]string{
+		"ok":      "#!/bin/sh\n",
+		"gone":    "#!/nonexistent/python2\n",
+		"env-ok":  "#!/usr/bin/env sh\n",
+		"env-bad": "#!/usr/bin/env no-such-interpreter-here\n",
+		"binary":  "\x7fELF",
+	}
+	var tools []models.Tool
+	for name, content := range scripts {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		tools = append(tools, models.Tool{Name: name, Path: path})
+	}
+
+	// A scan alone does not read the scripts
+	scanned, err := NewWithPaths([]string{dir}).ScanAllOccurrences()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range scanned {
+		if tool.Broken {
+			t.Errorf("scan marked %s broken: %s", tool.Name, tool.BrokenReason)
+		}
+	}
+
+	CheckInterpreters(tools)
+	for _, tool := range tools {
+		want := tool.Name == "gone" || tool.Name == "env-bad"
+		if tool.Broken != want {
+			t.Errorf("%s: Broken = %v (%s), want %v", tool.Name, tool.Broken, tool.BrokenReason, want)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// Candidate is a tool ranked against a capability query
+type Candidate struct {
+	Name           string  `json:"name"`
+	Path           string  `json:"path"`
+	PackageName    string  `json:"package_name,omitempty"`
+	PackageManager string  `json:"package_manager,omitempty"`
+	Description    string  `json:"description,omitempty"`
+	Score          float64 `json:"score"`
+	// Reasons say where the tool matched the query, best evidence first
+	Reasons []string `json:"reasons"`
+}
+
+// Where a term can be found in a tool, with how much a match there counts
+const (
+	weightName        = 6.0
+	weightPackage     = 3.0
+	weightDescription = 3.0
+	weightSubcommand  = 2.0
+	weightFlag        = 1.0
+	weightHelp        = 1.0
+)
+
+// synonymWeight is how much a synonym of a query word counts compared to
+// the word itself
+const synonymWeight = 0.7
+
+// stopWords are query words too common to say anything about a tool
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "to": true, "of": true, "for": true,
+	"and": true, "or": true, "in": true, "on": true, "with": true, "from": true,
+	"into": true, "my": true, "me": true, "i": true, "how": true, "do": true,
+	"what": true, "which": true, "that": true, "is": true, "are": true, "be": true,
+	"can": true, "some": true, "this": true, "it": true, "use": true, "using": true,
+	"tool": true, "command": true, "cli": true,
+}
+
+// synonyms are other words tools use for a query word, by stem
+var synonyms = map[string][]string{
+	"convert":   {"transcode", "encode", "transform"},
+	"video":     {"mp4", "mkv", "movie", "media"},
+	"audio":     {"mp3", "sound", "media"},
+	"image":     {"picture", "photo", "png", "jpeg"},
+	"compress":  {"zip", "archive", "gzip"},
+	"archive":   {"tar", "zip", "compress"},
+	"download":  {"fetch", "http", "transfer"},
+	"search":    {"grep", "find", "match"},
+	"delete":    {"remove"},
+	"remove":    {"delete"},
+	"yaml":      {"yml"},
+	"yml":       {"yaml"},
+	"compare":   {"diff"},
+	"diff":      {"compare"},
+	"edit":      {"editor"},
+	"container": {"docker", "image", "pod"},
+	"kubernete": {"kubectl", "k8s", "cluster"},
+	"format":    {"prettify", "lint"},
+	"pdf":       {"document"},
+}
+
+// capabilityDoc is a tool's text, split into the fields a term can match in
+type capabilityDoc struct {
+	tool        models.Tool
+	description string
+	fields      []docField
+}
+
+// docField is one field of a tool's text, with the stems of its words
+type docField struct {
+	weight float64
+	// label describes the field in a reason ("flag --vcodec")
+	label string
+	text  string
+	terms map[string]bool
+}
+
+// RankCapabilities ranks tools against a free-text description of what the
+// user wants to do, returning at most limit candidates with the best score
+// first. Each query word counts by where it appears in a tool (its name,
+// package, description, subcommands, flags, or the rest of its help) and by
+// how rare it is across all tools, so "video" outweighs "files"; synonyms
+// of a word count for less. Tools matching more of the query rank higher.
+// A tool's description is its man page summary from descriptions, keyed by
+// path, or else the one its help text starts with.
+func RankCapabilities(tools []models.Tool, descriptions map[string]string, query string, limit int) ([]Candidate, error) {
+	words := queryTerms(query)
+	if len(words) == 0 {
+		return nil, fmt.Errorf("query %q has no words to match", query)
+	}
+
+	docs := make([]capabilityDoc, len(tools))
+	df := make(map[string]int)
+	for i, tool := range tools {
+		docs[i] = newCapabilityDoc(tool, descriptions[tool.Path])
+		seen := make(map[string]bool)
+		for _, field := range docs[i].fields {
+			for term := range field.terms {
+				if !seen[term] {
+					seen[term] = true
+					df[term]++
+				}
+			}
+		}
+	}
+	idf := func(term string) float64 {
+		return math.Log(1 + float64(len(docs))/float64(1+df[term]))
+	}
+
+	candidates := []Candidate{}
+	for _, doc := range docs {
+		score := 0.0
+		matched := 0
+		var reasons []string
+		for _, word := range words {
+			best, reason := 0.0, ""
+			alternatives := append([]string{word}, synonyms[word]...)
+			for i, term := range alternatives {
+				term = stem(term)
+				factor := 1.0
+				if i > 0 {
+					factor = synonymWeight
+				}
+				for _, field := range doc.fields {
+					if !field.terms[term] {
+						continue
+					}
+					if value := field.weight * factor * idf(term); value > best {
+						best = value
+						reason = fieldReason(field, alternatives[i], i > 0, word)
+					}
+				}
+			}
+			if best > 0 {
+				score += best
+				matched++
+				reasons = append(reasons, reason)
+			}
+		}
+		if matched == 0 {
+			continue
+		}
+		// A tool matching every word beats one matching a single rare word
+		score *= float64(matched) / float64(len(words))
+		candidates = append(candidates, Candidate{
+			Name:           doc.tool.Name,
+			Path:           doc.tool.Path,
+			PackageName:    doc.tool.PackageName,
+			PackageManager: doc.tool.PackageManager,
+			Description:    doc.description,
+			Score:          math.Round(score*100) / 100,
+			Reasons:        reasons,
+		})
+	}
+
+	sortCandidates(candidates)
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// sortCandidates orders candidates by score, best first, then by name
+func sortCandidates(candidates []Candidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+}
+
+// newCapabilityDoc splits a tool's name, package, description, and help
+// into weighted fields
+func newCapabilityDoc(tool models.Tool, description string) capabilityDoc {
+	if description == "" && tool.Help != nil {
+		description = tool.Help.Description
+	}
+	doc := capabilityDoc{tool: tool, description: description}
+	add := func(weight float64, label, text string) {
+		if text = strings.TrimSpace(text); text != "" {
+			doc.fields = append(doc.fields, docField{weight: weight, label: label, text: text, terms: termSet(text)})
+		}
+	}
+
+	add(weightName, "name", tool.Name)
+	add(weightPackage, "package", tool.PackageName)
+	add(weightDescription, "description", description)
+	if tool.Help != nil {
+		for _, sub := range tool.Help.Subcommands {
+			add(weightSubcommand, "subcommand "+sub.Name, sub.Name+" "+sub.Description)
+		}
+		for _, flag := range tool.Help.CommonFlags {
+			add(weightFlag, "flag "+flag.Name, strings.TrimLeft(flag.Name, "-")+" "+flag.Description)
+		}
+	}
+	add(weightHelp, "help text", tool.HelpText)
+	return doc
+}
+
+// fieldReason explains a match of term in field. Short fields are quoted
+// whole; the help text is too long to quote, so only the term is named.
+func fieldReason(field docField, term string, synonym bool, word string) string {
+	found := fmt.Sprintf("%q", term)
+	if synonym {
+		found = fmt.Sprintf("%q (for %q)", term, word)
+	}
+	switch {
+	case field.label == "name" || field.label == "package":
+		return fmt.Sprintf("%s %q matches %s", field.label, field.text, found)
+	case field.label == "help text":
+		return "help text mentions " + found
+	}
+	text := strings.Join(strings.Fields(field.text), " ")
+	if runes := []rune(text); len(runes) > maxSnippet {
+		text = string(runes[:maxSnippet-3]) + "..."
+	}
+	return fmt.Sprintf("%s mentions %s: %s", field.label, found, text)
+}
+
+// queryTerms returns the stems of a query's words, without stop words or
+// repeats
+func queryTerms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, word := range words(query) {
+		if stopWords[word] {
+			continue
+		}
+		if term := stem(word); !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// termSet returns the stems of the words in text. A hyphenated or dotted
+// name counts both whole and as its parts, so "ffmpeg-normalize" matches
+// "normalize".
+func termSet(text string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, word := range words(text) {
+		terms[stem(word)] = true
+	}
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		if word = strings.Trim(word, ".,;:()[]<>\"'`"); strings.ContainsAny(word, "-_.") {
+			terms[word] = true
+		}
+	}
+	return terms
+}
+
+// words splits text into lowercase words of letters and digits
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// stem strips common English suffixes so "converts", "converting", and
+// "converted" match "convert". It is crude, but applied to the query and
+// the tools alike.
+func stem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 5 && strings.HasSuffix(word, "ing"):
+		return word[:len(word)-3]
+	case len(word) > 4 && strings.HasSuffix(word, "ed"):
+		return word[:len(word)-2]
+	case len(word) > 4 && (strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes") || strings.HasSuffix(word, "xes") || strings.HasSuffix(word, "sses")):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	}
+	return word
+}
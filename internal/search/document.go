@@ -0,0 +1,82 @@
+package search
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/brief"
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// maxDocumentExamples is how many parsed examples a tool's text lists
+// beyond those already in its trimmed help
+const maxDocumentExamples = 5
+
+// Document is one tool's record for an embedding pipeline: its normalized
+// text, with an ID that stays the same between runs so a vector index can
+// be updated in place, and a hash of the text so unchanged records need not
+// be embedded again
+type Document struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Path           string `json:"path"`
+	PackageName    string `json:"package_name,omitempty"`
+	PackageManager string `json:"package_manager,omitempty"`
+	Version        string `json:"version,omitempty"`
+	Text           string `json:"text"`
+	TextSHA256     string `json:"text_sha256"`
+	Tokens         int    `json:"tokens"`
+}
+
+// NewDocument builds the embedding record of a tool, described by its man
+// page summary if it has one
+func NewDocument(tool models.Tool, description string) Document {
+	text := EmbeddingText(tool, description)
+	sum := sha256.Sum256([]byte(text))
+	return Document{
+		ID:             DocumentID(tool.Path),
+		Name:           tool.Name,
+		Path:           tool.Path,
+		PackageName:    tool.PackageName,
+		PackageManager: tool.PackageManager,
+		Version:        tool.Version,
+		Text:           text,
+		TextSHA256:     hex.EncodeToString(sum[:]),
+		Tokens:         brief.EstimateTokens(text),
+	}
+}
+
+// DocumentID identifies a tool by its path, which unlike its name is unique
+// on a machine and unlike its content survives upgrades
+func DocumentID(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return "tool-" + hex.EncodeToString(sum[:8])
+}
+
+// normalizeText collapses runs of spaces and tabs within each line and
+// drops blank lines, which carry no meaning for an embedding model but
+// would count against its input limit
+func normalizeText(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimText shortens text to at most max runes, cutting at the last line
+// break that fits so no line is left half-finished
+func trimText(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	trimmed := string(runes[:max])
+	if i := strings.LastIndexByte(trimmed, '\n'); i > 0 {
+		trimmed = trimmed[:i]
+	}
+	return trimmed
+}
@@ -0,0 +1,301 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/atomicfile"
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// embedBatch is how many texts are sent to the embeddings endpoint at once
+const embedBatch = 64
+
+// maxEmbeddingHelp is how much of a tool's help text its embedding text
+// keeps: enough for the synopsis and main options, within the input limit
+// of common embedding models
+const maxEmbeddingHelp = 1500
+
+// Embedder computes text embeddings with an OpenAI-compatible embeddings
+// endpoint (POST {"model": ..., "input": [...]}), such as OpenAI's or a
+// local Ollama or llama.cpp server. Embeddings are cached on disk by model
+// and text, so tools are only embedded again when their text changes.
+type Embedder struct {
+	url    string
+	model  string
+	apiKey string
+	client *http.Client
+	cache  *embeddingCache
+}
+
+// NewEmbedder creates an embedder for the endpoint at url. apiKey, if set,
+// is sent as a bearer token.
+func NewEmbedder(url, model, apiKey string) *Embedder {
+	return &Embedder{
+		url:    url,
+		model:  model,
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 60 * time.Second},
+		cache:  loadEmbeddingCache(embeddingCachePath()),
+	}
+}
+
+// Embed returns the embedding of each text, in order
+func (e *Embedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	var missing []int
+	for i, text := range texts {
+		if vector, ok := e.cache.get(e.key(text)); ok {
+			vectors[i] = vector
+		} else {
+			missing = append(missing, i)
+		}
+	}
+
+	for start := 0; start < len(missing); start += embedBatch {
+		end := start + embedBatch
+		if end > len(missing) {
+			end = len(missing)
+		}
+		batch := make([]string, 0, end-start)
+		for _, i := range missing[start:end] {
+			batch = append(batch, texts[i])
+		}
+		embedded, err := e.request(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range missing[start:end] {
+			vectors[i] = embedded[j]
+			e.cache.put(e.key(texts[i]), embedded[j])
+		}
+	}
+	return vectors, nil
+}
+
+// SaveCache writes the embeddings used since the embedder was created,
+// dropping the ones no longer used so the cache does not grow forever
+func (e *Embedder) SaveCache() error {
+	return e.cache.save()
+}
+
+// key identifies a text's embedding by model, so changing models does not
+// mix vectors of different spaces
+func (e *Embedder) key(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return e.model + ":" + hex.EncodeToString(sum[:])
+}
+
+// request embeds one batch of texts
+func (e *Embedder) request(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(map[string]interface{}{"model": e.model, "input": texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embeddings endpoint returned %s", resp.Status)
+	}
+
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding embeddings: %w", err)
+	}
+	if len(result.Data) != len(texts) {
+		return nil, fmt.Errorf("embeddings endpoint returned %d embeddings for %d inputs", len(result.Data), len(texts))
+	}
+	vectors := make([][]float32, len(texts))
+	for _, item := range result.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("embeddings endpoint returned index %d for %d inputs", item.Index, len(texts))
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	return vectors, nil
+}
+
+// EmbeddingText is the text a tool is embedded by: its name, package,
+// description, the start of its help text with whitespace normalized, and
+// the examples parsed from its help that the trimmed text no longer holds
+func EmbeddingText(tool models.Tool, description string) string {
+	if description == "" && tool.Help != nil {
+		description = tool.Help.Description
+	}
+	parts := []string{tool.Name}
+	if tool.PackageName != "" && tool.PackageName != tool.Name {
+		parts = append(parts, "package "+tool.PackageName)
+	}
+	if description = normalizeText(description); description != "" {
+		parts = append(parts, description)
+	}
+	help := trimText(normalizeText(tool.HelpText), maxEmbeddingHelp)
+	if help != "" {
+		parts = append(parts, help)
+	}
+	if tool.Help != nil {
+		var examples []string
+		for _, example := range tool.Help.Examples {
+			example = normalizeText(example)
+			if example == "" || strings.Contains(help, example) {
+				continue
+			}
+			examples = append(examples, example)
+			if len(examples) == maxDocumentExamples {
+				break
+			}
+		}
+		if len(examples) > 0 {
+			parts = append(parts, "Examples:\n"+strings.Join(examples, "\n"))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// RankSemantic ranks tools by how similar their embedding text is to the
+// query's, returning at most limit candidates with the best first. Each
+// candidate's reasons give its similarity, then where the query's words
+// appear in it, as RankCapabilities finds them.
+func RankSemantic(ctx context.Context, e *Embedder, tools []models.Tool, descriptions map[string]string, query string, limit int) ([]Candidate, error) {
+	texts := []string{query}
+	for _, tool := range tools {
+		texts = append(texts, EmbeddingText(tool, descriptions[tool.Path]))
+	}
+	vectors, err := e.Embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	// A query of stop words has no keyword evidence, which is fine here
+	keywords, _ := RankCapabilities(tools, descriptions, query, 0)
+	reasons := make(map[string][]string)
+	for _, candidate := range keywords {
+		reasons[candidate.Path] = candidate.Reasons
+	}
+
+	candidates := []Candidate{}
+	for i, tool := range tools {
+		similarity := cosine(vectors[0], vectors[i+1])
+		description := descriptions[tool.Path]
+		if description == "" && tool.Help != nil {
+			description = tool.Help.Description
+		}
+		candidates = append(candidates, Candidate{
+			Name:           tool.Name,
+			Path:           tool.Path,
+			PackageName:    tool.PackageName,
+			PackageManager: tool.PackageManager,
+			Description:    description,
+			Score:          math.Round(similarity*1000) / 1000,
+			Reasons:        append([]string{fmt.Sprintf("semantic similarity %.3f", similarity)}, reasons[tool.Path]...),
+		})
+	}
+
+	sortCandidates(candidates)
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates, nil
+}
+
+// cosine returns the cosine similarity of two vectors, 0 when either is
+// empty or they differ in length
+func cosine(a, b []float32) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
+
+// embeddingCache stores embeddings keyed by model and text hash
+type embeddingCache struct {
+	path    string
+	entries map[string][]float32
+	// used are the entries read or added since loading, the only ones saved
+	used map[string][]float32
+}
+
+// embeddingCachePath returns the cache file location under the user cache
+// directory
+func embeddingCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cli", "embeddings.json")
+}
+
+// loadEmbeddingCache reads the cache file. A missing or unreadable cache
+// starts empty.
+func loadEmbeddingCache(path string) *embeddingCache {
+	cache := &embeddingCache{path: path, entries: make(map[string][]float32), used: make(map[string][]float32)}
+	if path == "" {
+		return cache
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &cache.entries)
+	}
+	return cache
+}
+
+func (c *embeddingCache) get(key string) ([]float32, bool) {
+	vector, ok := c.entries[key]
+	if ok {
+		c.used[key] = vector
+	}
+	return vector, ok
+}
+
+func (c *embeddingCache) put(key string, vector []float32) {
+	c.entries[key] = vector
+	c.used[key] = vector
+}
+
+// save writes the entries used in this run, dropping those of tools no
+// longer in PATH
+func (c *embeddingCache) save() error {
+	if c.path == "" || len(c.used) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(c.used)
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(c.path, data, 0644)
+}
@@ -0,0 +1,160 @@
+// Package search finds the tools whose name, package, description, or help
+// text match a query, for `cli search`.
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// Fields a query is matched against, in the order results are ranked by: a
+// tool matching on its name comes before one matching only in its help
+const (
+	FieldName        = "name"
+	FieldPackage     = "package"
+	FieldDescription = "description"
+	FieldHelp        = "help"
+)
+
+// Fields are the fields a query can be matched against
+var Fields = []string{FieldName, FieldPackage, FieldDescription, FieldHelp}
+
+// maxSnippet is how many characters of a matching help line are kept
+const maxSnippet = 120
+
+// Result is a tool that matched a query
+type Result struct {
+	Name           string `json:"name"`
+	Path           string `json:"path"`
+	PackageName    string `json:"package_name,omitempty"`
+	PackageManager string `json:"package_manager,omitempty"`
+	Description    string `json:"description,omitempty"`
+	// Matched are the fields the query matched, in ranking order
+	Matched []string `json:"matched"`
+	// Snippet is the first help line the query matched, when it matched
+	// the help text
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// Query matches text against a search term: a case-insensitive substring,
+// or a regular expression
+type Query struct {
+	match func(text string) bool
+}
+
+// NewQuery creates a query for term, as a regular expression when regex is
+// set and otherwise as a substring, both matched regardless of case
+func NewQuery(term string, regex bool) (*Query, error) {
+	if !regex {
+		lower := strings.ToLower(term)
+		return &Query{match: func(text string) bool {
+			return strings.Contains(strings.ToLower(text), lower)
+		}}, nil
+	}
+	re, err := regexp.Compile("(?i)" + term)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	return &Query{match: re.MatchString}, nil
+}
+
+// CheckFields returns an error naming the first of fields that is not one
+// of Fields
+func CheckFields(fields []string) error {
+	for _, field := range fields {
+		known := false
+		for _, name := range Fields {
+			if field == name {
+				known = true
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown field %q (valid: %s)", field, strings.Join(Fields, ", "))
+		}
+	}
+	return nil
+}
+
+// Search returns the tools that match q in any of fields, ranked by the
+// first field they match in and then by name. A tool's description is its
+// man page summary from descriptions, keyed by path, or else the one its
+// help text starts with.
+func Search(tools []models.Tool, descriptions map[string]string, q *Query, fields []string) []Result {
+	in := make(map[string]bool)
+	for _, field := range fields {
+		in[field] = true
+	}
+
+	results := []Result{}
+	rank := make(map[string]int)
+	for _, tool := range tools {
+		description := descriptions[tool.Path]
+		if description == "" && tool.Help != nil {
+			description = tool.Help.Description
+		}
+		result := Result{
+			Name:           tool.Name,
+			Path:           tool.Path,
+			PackageName:    tool.PackageName,
+			PackageManager: tool.PackageManager,
+			Description:    description,
+		}
+
+		if in[FieldName] && q.match(tool.Name) {
+			result.Matched = append(result.Matched, FieldName)
+		}
+		if in[FieldPackage] && tool.PackageName != "" && q.match(tool.PackageName) {
+			result.Matched = append(result.Matched, FieldPackage)
+		}
+		if in[FieldDescription] && description != "" && q.match(description) {
+			result.Matched = append(result.Matched, FieldDescription)
+		}
+		if in[FieldHelp] {
+			if line, ok := matchingLine(q, tool.HelpText); ok {
+				result.Matched = append(result.Matched, FieldHelp)
+				result.Snippet = line
+			}
+		}
+		if len(result.Matched) == 0 {
+			continue
+		}
+
+		for i, field := range Fields {
+			if field == result.Matched[0] {
+				rank[tool.Path] = i
+			}
+		}
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if rank[results[i].Path] != rank[results[j].Path] {
+			return rank[results[i].Path] < rank[results[j].Path]
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// matchingLine returns the first line of text that q matches, with its
+// whitespace collapsed and shortened to maxSnippet characters
+func matchingLine(q *Query, text string) (string, bool) {
+	if text == "" {
+		return "", false
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if !q.match(line) {
+			continue
+		}
+		line = strings.Join(strings.Fields(line), " ")
+		if runes := []rune(line); len(runes) > maxSnippet {
+			line = string(runes[:maxSnippet-3]) + "..."
+		}
+		return line, true
+	}
+	return "", false
+}
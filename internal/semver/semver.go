@@ -0,0 +1,92 @@
+package semver
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionToken matches a dotted version with an optional pre-release or
+// build suffix inside free text
+var versionToken = regexp.MustCompile(`\bv?\d+(?:\.\d+)+(?:[-+_][0-9A-Za-z.]+)?`)
+
+// Compare compares two loosely formatted version strings and returns -1, 0,
+// or 1. It understands the forms package managers actually report: an
+// optional "v" prefix, any number of dot-separated numeric segments, and a
+// pre-release or build suffix ("1.2.0-rc1", "3.12.1_1", "2.0.0b3").
+// Pre-release versions sort before their release.
+func Compare(a, b string) int {
+	aNums, aPre := split(a)
+	bNums, bPre := split(b)
+
+	for i := 0; i < len(aNums) || i < len(bNums); i++ {
+		var x, y int
+		if i < len(aNums) {
+			x = aNums[i]
+		}
+		if i < len(bNums) {
+			y = bNums[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// Newer reports whether candidate is a strictly newer version than current
+func Newer(candidate, current string) bool {
+	return Compare(candidate, current) > 0
+}
+
+// Extract returns the first dotted version number in text such as a tool's
+// --version output ("git version 2.39.2" yields "2.39.2"), or "" if there
+// is none
+func Extract(text string) string {
+	return versionToken.FindString(text)
+}
+
+// split separates a version into its numeric segments and the remaining
+// pre-release suffix. Revision suffixes like brew's "_1" are dropped.
+func split(v string) ([]int, string) {
+	v = strings.TrimSpace(v)
+	v = strings.TrimPrefix(strings.TrimPrefix(v, "v"), "V")
+	if i := strings.IndexAny(v, "_+"); i >= 0 {
+		v = v[:i]
+	}
+
+	var nums []int
+	for v != "" {
+		end := 0
+		for end < len(v) && v[end] >= '0' && v[end] <= '9' {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		n, _ := strconv.Atoi(v[:end])
+		nums = append(nums, n)
+		v = v[end:]
+		if !strings.HasPrefix(v, ".") {
+			break
+		}
+		v = v[1:]
+	}
+
+	return nums, strings.TrimLeft(v, "-.")
+}
@@ -0,0 +1,367 @@
+package shellrc
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Kinds of definitions found in shell startup files
+const (
+	Alias    = "alias"
+	Function = "function"
+)
+
+// maxSourceDepth bounds how deeply `source`d files are followed
+const maxSourceDepth = 3
+
+// Definition is an alias or function defined in a shell startup file
+type Definition struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Shell string `json:"shell"`
+	// Value is what an alias expands to; empty for functions
+	Value string `json:"value,omitempty"`
+	File  string `json:"file"`
+	Line  int    `json:"line"`
+	// Removed marks an unalias, unset -f, unfunction, or functions -e,
+	// which undoes the earlier definitions of Name; an empty Name
+	// (unalias -a) undoes every alias
+	Removed bool `json:"removed,omitempty"`
+}
+
+var (
+	// name() {, function name {, function name() {
+	shFunction = regexp.MustCompile(`^(?:function\s+([^\s(){}]+)\s*(?:\(\s*\))?|([A-Za-z0-9_.:+-]+)\s*\(\s*\))\s*(?:\{|\(|$)`)
+	// function name [options]
+	fishFunction = regexp.MustCompile(`^function\s+([^\s;]+)`)
+)
+
+// CurrentShell returns the name of the user's login shell from $SHELL
+// (bash, zsh, fish, ...), or "" when it is not set
+func CurrentShell() string {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		return ""
+	}
+	return filepath.Base(shell)
+}
+
+// Shells returns the shells whose startup files are read for shell, which
+// is all supported shells when shell is unknown
+func Shells(shell string) []string {
+	switch shell {
+	case "bash", "zsh", "fish":
+		return []string{shell}
+	}
+	return []string{"bash", "zsh", "fish"}
+}
+
+// Files returns the startup files a shell reads, in the order it reads them
+func Files(shell string) []string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+
+	switch shell {
+	case "bash":
+		return []string{
+			filepath.Join(home, ".bash_profile"),
+			filepath.Join(home, ".bash_login"),
+			filepath.Join(home, ".profile"),
+			filepath.Join(home, ".bashrc"),
+			filepath.Join(home, ".bash_aliases"),
+		}
+	case "zsh":
+		dir := home
+		if zdotdir := os.Getenv("ZDOTDIR"); zdotdir != "" {
+			dir = zdotdir
+		}
+		return []string{
+			filepath.Join(dir, ".zshenv"),
+			filepath.Join(dir, ".zprofile"),
+			filepath.Join(dir, ".zshrc"),
+			filepath.Join(dir, ".zlogin"),
+		}
+	case "fish":
+		config := filepath.Join(home, ".config", "fish")
+		if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+			config = filepath.Join(xdg, "fish")
+		}
+		files, _ := filepath.Glob(filepath.Join(config, "conf.d", "*.fish"))
+		files = append(files, filepath.Join(config, "config.fish"))
+		functions, _ := filepath.Glob(filepath.Join(config, "functions", "*.fish"))
+		return append(files, functions...)
+	}
+	return nil
+}
+
+// Parse returns the aliases and functions defined in the startup files of
+// the given shells, following `source` and `.` of literal paths, and the
+// removals of earlier ones. Later definitions of a name come after earlier
+// ones, as the shell would apply them; Lookup resolves which are in effect.
+func Parse(shells []string) []Definition {
+	var defs []Definition
+	for _, shell := range shells {
+		visited := make(map[string]bool)
+		for _, file := range Files(shell) {
+			defs = append(defs, parseFile(shell, file, visited, 0)...)
+		}
+	}
+	return defs
+}
+
+// Lookup returns the definitions of name in effect once the startup files
+// have run: the last alias and the last function of the name in each shell,
+// unless it was removed afterwards. They are grouped by shell in the order
+// shells first appear in defs, each shell's alias first, since an alias is
+// expanded before a function of the same name is looked up.
+func Lookup(defs []Definition, name string) []Definition {
+	type key struct{ shell, kind string }
+	last := make(map[key]Definition)
+	var shells []string
+	for _, def := range defs {
+		if def.Name != name && !(def.Removed && def.Name == "" && def.Kind == Alias) {
+			continue
+		}
+		if !contains(shells, def.Shell) {
+			shells = append(shells, def.Shell)
+		}
+		last[key{def.Shell, def.Kind}] = def
+	}
+
+	var found []Definition
+	for _, shell := range shells {
+		for _, kind := range []string{Alias, Function} {
+			if def, ok := last[key{shell, kind}]; ok && !def.Removed {
+				found = append(found, def)
+			}
+		}
+	}
+	return found
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func parseFile(shell, path string, visited map[string]bool, depth int) []Definition {
+	if visited[path] || depth > maxSourceDepth {
+		return nil
+	}
+	visited[path] = true
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var defs []Definition
+	lineNo := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		words := splitWords(line)
+		if len(words) == 0 {
+			continue
+		}
+
+		switch words[0] {
+		case "alias":
+			for _, def := range parseAlias(shell, words[1:]) {
+				def.File, def.Line = path, lineNo
+				defs = append(defs, def)
+			}
+			continue
+		case "unalias", "unset", "unfunction", "functions":
+			for _, def := range parseRemoval(shell, words) {
+				def.File, def.Line = path, lineNo
+				defs = append(defs, def)
+			}
+			continue
+		case "source", ".":
+			if len(words) > 1 {
+				if sourced := expandPath(words[1], filepath.Dir(path)); sourced != "" {
+					defs = append(defs, parseFile(shell, sourced, visited, depth+1)...)
+				}
+			}
+			continue
+		}
+
+		if name := functionName(shell, line); name != "" {
+			defs = append(defs, Definition{Name: name, Kind: Function, Shell: shell, File: path, Line: lineNo})
+		}
+	}
+	return defs
+}
+
+// parseAlias parses the arguments of an alias command: name=value pairs in
+// POSIX shells, or "name value" in fish
+func parseAlias(shell string, args []string) []Definition {
+	// Skip options such as zsh's -g and -s
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		args = args[1:]
+	}
+
+	var defs []Definition
+	if shell == "fish" && len(args) >= 2 && !strings.Contains(args[0], "=") {
+		return append(defs, Definition{Name: args[0], Kind: Alias, Shell: shell, Value: strings.Join(args[1:], " ")})
+	}
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			// `alias name` alone prints the alias rather than defining it
+			continue
+		}
+		defs = append(defs, Definition{Name: name, Kind: Alias, Shell: shell, Value: value})
+	}
+	return defs
+}
+
+// parseRemoval parses a command that removes aliases or functions: unalias,
+// unset -f, and zsh's unfunction, or fish's functions -e, which also
+// removes aliases since fish defines them as functions
+func parseRemoval(shell string, words []string) []Definition {
+	kind := Alias
+	switch words[0] {
+	case "unset", "unfunction":
+		kind = Function
+	}
+
+	var names []string
+	all, isRemoval := false, words[0] == "unalias" || words[0] == "unfunction"
+	for _, word := range words[1:] {
+		if !strings.HasPrefix(word, "-") || word == "-" {
+			names = append(names, word)
+			continue
+		}
+		switch {
+		case words[0] == "unalias" && word == "-a":
+			all = true
+		case words[0] == "unset" && word == "-f":
+			isRemoval = true
+		case words[0] == "unset" && word == "-v":
+			isRemoval = false
+		case words[0] == "functions" && (word == "-e" || word == "--erase"):
+			isRemoval = true
+		}
+	}
+	if !isRemoval {
+		return nil
+	}
+
+	var defs []Definition
+	if all {
+		defs = append(defs, Definition{Kind: Alias, Shell: shell, Removed: true})
+	}
+	for _, name := range names {
+		if words[0] == "functions" {
+			defs = append(defs,
+				Definition{Name: name, Kind: Alias, Shell: shell, Removed: true},
+				Definition{Name: name, Kind: Function, Shell: shell, Removed: true})
+			continue
+		}
+		defs = append(defs, Definition{Name: name, Kind: kind, Shell: shell, Removed: true})
+	}
+	return defs
+}
+
+// functionName returns the name of the function a line starts defining
+func functionName(shell, line string) string {
+	if shell == "fish" {
+		if m := fishFunction.FindStringSubmatch(line); m != nil {
+			return m[1]
+		}
+		return ""
+	}
+	if m := shFunction.FindStringSubmatch(line); m != nil {
+		if m[1] != "" {
+			return m[1]
+		}
+		return m[2]
+	}
+	return ""
+}
+
+// splitWords splits a line into shell words, honoring single and double
+// quotes and backslash escapes, and stopping at an unquoted comment or
+// command separator
+func splitWords(line string) []string {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' {
+				escaped = true
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case (r == '#' && !inWord) || r == ';' || r == '&' || r == '|':
+			if inWord {
+				words = append(words, word.String())
+			}
+			return words
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// expandPath resolves a sourced file path with ~ and $HOME expanded,
+// relative to dir. Paths with other variables or substitutions cannot be
+// resolved statically and yield "".
+func expandPath(path, dir string) string {
+	home, _ := os.UserHomeDir()
+	switch {
+	case path == "~" || strings.HasPrefix(path, "~/"):
+		path = home + path[1:]
+	case strings.HasPrefix(path, "$HOME/"), strings.HasPrefix(path, "${HOME}/"):
+		path = home + path[strings.Index(path, "/"):]
+	}
+	if strings.ContainsAny(path, "$`*?") {
+		return ""
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path
+}
@@ -0,0 +1,102 @@
+package shellrc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeHome writes startup files into a temporary home directory
+func writeHome(t *testing.T, files map[string]string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("ZDOTDIR", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	for name, content := range files {
+		path := filepath.Join(home, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLookupLastDefinitionWins(t *testing.T) {
+	writeHome(t, map[string]string{
+		".bashrc":       "alias ll='ls -l'\nll() { ls -la; }\n",
+		".bash_aliases": "alias ll='eza -l'\n",
+	})
+	defs := Lookup(Parse([]string{"bash"}), "ll")
+	if len(defs) != 2 {
+		t.Fatalf("got %d definitions, want the alias and the function: %+v", len(defs), defs)
+	}
+	if defs[0].Kind != Alias || defs[0].Value != "eza -l" || filepath.Base(defs[0].File) != ".bash_aliases" {
+		t.Errorf("alias = %+v, want the one in .bash_aliases", defs[0])
+	}
+	if defs[1].Kind != Function {
+		t.Errorf("second definition = %+v, want the function", defs[1])
+	}
+}
+
+func TestLookupRemovals(t *testing.T) {
+	writeHome(t, map[string]string{
+		".bashrc": "alias ls='ls --color'\nalias gs='git status'\nunalias ls\n" +
+			"mkcd() { mkdir -p \"$1\"; }\nunset -f mkcd\n" +
+			"alias vi=vim\nunset vi\n",
+		".zshrc":                   "alias gs='git status -sb'\nunalias -a\nalias ll='ls -l'\n",
+		".config/fish/config.fish": "alias gs 'git status'\nfunctions -e gs\n",
+	})
+	defs := Parse([]string{"bash", "zsh", "fish"})
+
+	tests := []struct {
+		name  string
+		kinds []string
+	}{
+		{"ls", nil},
+		{"mkcd", nil},
+		// unset without -f removes a variable, not the alias
+		{"vi", []string{"bash " + Alias}},
+		{"gs", []string{"bash " + Alias}},
+		{"ll", []string{"zsh " + Alias}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, def := range Lookup(defs, tt.name) {
+			got = append(got, def.Shell+" "+def.Kind)
+		}
+		if len(got) != len(tt.kinds) {
+			t.Errorf("Lookup(%s) = %v, want %v", tt.name, got, tt.kinds)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.kinds[i] {
+				t.Errorf("Lookup(%s) = %v, want %v", tt.name, got, tt.kinds)
+				break
+			}
+		}
+	}
+}
+
+func TestLookupGroupsByShell(t *testing.T) {
+	writeHome(t, map[string]string{
+		".bashrc": "g() { git \"$@\"; }\n",
+		".zshrc":  "g() { git \"$@\"; }\nalias g=git\n",
+	})
+	var got []string
+	for _, def := range Lookup(Parse([]string{"bash", "zsh"}), "g") {
+		got = append(got, def.Shell+" "+def.Kind)
+	}
+	want := []string{"bash function", "zsh alias", "zsh function"}
+	if len(got) != len(want) {
+		t.Fatalf("Lookup = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Lookup = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,405 @@
+package shims
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/semver"
+)
+
+// commandTimeout bounds each version manager command the resolver runs
+const commandTimeout = 10 * time.Second
+
+// Resolver finds the binaries that version manager shims run. It is not
+// safe for concurrent use.
+type Resolver struct {
+	dirs []ShimDir
+	// ctx kills running version manager commands when cancelled
+	ctx context.Context
+	// runtimes maps each shims directory to the runtimes providing each
+	// of its shims
+	runtimes map[string]map[string][]string
+	// selections caches the version selected for each runtime
+	selections map[string]selection
+}
+
+// selection is the versions of a runtime a manager selects, in the order
+// shims look for a binary in them, and the file or variable that selected
+// them
+type selection struct {
+	versions []string
+	source   string
+}
+
+// NewResolver creates a resolver for the shims in dirs
+func NewResolver(dirs []ShimDir) *Resolver {
+	return &Resolver{
+		dirs:       dirs,
+		ctx:        context.Background(),
+		runtimes:   make(map[string]map[string][]string),
+		selections: make(map[string]selection),
+	}
+}
+
+// SetContext makes running version manager commands stop when ctx is
+// cancelled
+func (r *Resolver) SetContext(ctx context.Context) {
+	r.ctx = ctx
+}
+
+// Resolve returns what the shim called name at path runs, or nil when path
+// is not in a version manager's shims directory. The plugin and version
+// come from the manager (`asdf current`, `mise current`, `pyenv
+// version-name`, `rbenv version-name`) or its version files, and the binary
+// from the plugin's install directory, or from the manager's `which` when
+// it is elsewhere. Fields that cannot be resolved are left empty.
+//
+// nvm has no shims, so node, npm, npx, and corepack in the bin directory of
+// any node version nvm installed are attributed to that version instead.
+func (r *Resolver) Resolve(name, path string) *models.ShimInfo {
+	dir, ok := r.dirOf(path)
+	if !ok {
+		return nil
+	}
+	switch dir.Manager {
+	case Nvm:
+		return r.resolveNvm(dir, name, path)
+	case Volta:
+		return r.resolveVolta(dir, name)
+	}
+
+	info := &models.ShimInfo{Manager: string(dir.Manager)}
+	candidates := r.shimRuntimes(dir)[name]
+	for _, runtime := range candidates {
+		selected := r.selected(dir, runtime)
+		for _, version := range selected.versions {
+			if target := installedBinary(dir, runtime, version, name); target != "" {
+				info.Plugin, info.Version, info.Target, info.Source = runtime, version, target, selected.source
+				return info
+			}
+		}
+		if info.Plugin == "" && len(selected.versions) > 0 {
+			info.Plugin, info.Version, info.Source = runtime, selected.versions[0], selected.source
+		}
+	}
+
+	// The manager knows plugins whose binaries live outside bin/, and
+	// versions set in legacy files such as .nvmrc
+	if target := r.which(dir, name); target != "" {
+		info.Target = target
+		if runtime, version, ok := installOf(dir, target); ok && (runtime != info.Plugin || version != info.Version) {
+			info.Plugin, info.Version = runtime, version
+			info.Source = ""
+			if selected := r.selected(dir, runtime); contains(selected.versions, version) {
+				info.Source = selected.source
+			}
+		}
+	}
+	if info.Plugin == "" && len(candidates) > 0 {
+		info.Plugin = candidates[0]
+	}
+	return info
+}
+
+// resolveNvm attributes node and the tools it ships in the bin directory
+// of an nvm install. Packages installed there with npm -g are left to npm.
+func (r *Resolver) resolveNvm(dir ShimDir, name, path string) *models.ShimInfo {
+	if !contains(nodeBinaries, name) {
+		return nil
+	}
+	version := filepath.Base(filepath.Dir(filepath.Dir(path)))
+	info := &models.ShimInfo{Manager: string(Nvm), Plugin: "node", Version: version, Target: path}
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		info.Target = target
+	}
+	if selected := r.selected(dir, "node"); contains(selected.versions, version) {
+		info.Source = selected.source
+	}
+	return info
+}
+
+// resolveVolta attributes a volta shim. node, npm, yarn, and pnpm run the
+// version pinned in the nearest package.json or the default platform; npm
+// is node's own unless a version of it is pinned. Other shims run the
+// package installed with volta install.
+func (r *Resolver) resolveVolta(dir ShimDir, name string) *models.ShimInfo {
+	info := &models.ShimInfo{Manager: string(Volta)}
+	if tool, ok := voltaTools[name]; ok {
+		selected := r.selected(dir, tool)
+		if tool == "npm" && len(selected.versions) == 0 {
+			tool = "node"
+			selected = r.selected(dir, tool)
+		}
+		info.Plugin = tool
+		if len(selected.versions) > 0 {
+			info.Version, info.Source = selected.versions[0], selected.source
+			info.Target = installedBinary(dir, tool, info.Version, name)
+		}
+	} else if bin, ok := readVoltaBin(dir, name); ok {
+		info.Plugin, info.Version, info.Source = bin.Package, bin.Version, bin.path
+		info.Target = voltaPackageBinary(dir, bin.Package, bin.Version, name)
+	}
+	if info.Target == "" {
+		info.Target = r.which(dir, name)
+	}
+	return info
+}
+
+// UninstallCommand returns the command that removes one installed version
+// of a runtime
+func UninstallCommand(manager Manager, runtime, version string) string {
+	switch manager {
+	case Pyenv, Rbenv, Nvm:
+		return fmt.Sprintf("%s uninstall %s", manager, version)
+	case Mise:
+		return fmt.Sprintf("mise uninstall %s@%s", runtime, version)
+	case Volta:
+		if _, ok := voltaTools[runtime]; ok {
+			// volta uninstall only removes packages
+			return "rm -rf " + filepath.Join(envOr("VOLTA_HOME", homeJoin(".volta")), "tools", "image", runtime, version)
+		}
+		return fmt.Sprintf("volta uninstall %s", runtime)
+	default:
+		return fmt.Sprintf("%s uninstall %s %s", manager, runtime, version)
+	}
+}
+
+// SelectCommands returns the commands that choose which installed version
+// of a runtime a shim runs, everywhere and in the current directory
+func SelectCommands(manager Manager, runtime string) (global, local string) {
+	switch manager {
+	case Pyenv, Rbenv:
+		return fmt.Sprintf("%s global <version>", manager), fmt.Sprintf("%s local <version>", manager)
+	case Mise:
+		return fmt.Sprintf("mise use --global %s@<version>", runtime), fmt.Sprintf("mise use %s@<version>", runtime)
+	case Nvm:
+		return "nvm alias default <version>", "nvm use <version>"
+	case Volta:
+		return fmt.Sprintf("volta install %s@<version>", runtime), fmt.Sprintf("volta pin %s@<version>", runtime)
+	default:
+		return fmt.Sprintf("%s global %s <version>", manager, runtime), fmt.Sprintf("%s local %s <version>", manager, runtime)
+	}
+}
+
+// dirOf returns the shims directory path is in, or for nvm the manager
+// whose install holds it
+func (r *Resolver) dirOf(path string) (ShimDir, bool) {
+	parent := filepath.Clean(filepath.Dir(path))
+	for _, dir := range r.dirs {
+		if dir.Manager == Nvm {
+			if filepath.Base(parent) == "bin" && filepath.Dir(filepath.Dir(parent)) == filepath.Clean(dir.InstallsPath) {
+				return dir, true
+			}
+			continue
+		}
+		if filepath.Clean(dir.ShimsPath) == parent {
+			return dir, true
+		}
+	}
+	return ShimDir{}, false
+}
+
+// shimRuntimes maps each of dir's shims to the runtimes that provide it
+func (r *Resolver) shimRuntimes(dir ShimDir) map[string][]string {
+	if byShim, ok := r.runtimes[dir.ShimsPath]; ok {
+		return byShim
+	}
+	byShim := make(map[string][]string)
+	for runtime, names := range runtimesFor(dir, listShims(dir.ShimsPath)) {
+		for _, name := range names {
+			byShim[name] = append(byShim[name], runtime)
+		}
+	}
+	for _, runtimes := range byShim {
+		sort.Strings(runtimes)
+	}
+	r.runtimes[dir.ShimsPath] = byShim
+	return byShim
+}
+
+// selected returns the installed versions of runtime the manager selects,
+// asking the manager and falling back to reading its version files
+func (r *Resolver) selected(dir ShimDir, runtime string) selection {
+	key := string(dir.Manager) + "/" + runtime
+	if s, ok := r.selections[key]; ok {
+		return s
+	}
+
+	var s selection
+	switch dir.Manager {
+	case Asdf:
+		if output, err := r.run(dir, "current", runtime); err == nil {
+			s = parseAsdfCurrent(string(output), runtime)
+		}
+	case Mise:
+		if output, err := r.run(dir, "current", runtime); err == nil {
+			s.versions = strings.Fields(string(output))
+		}
+	case Pyenv, Rbenv:
+		// Prints the selected versions joined by ":"
+		if output, err := r.run(dir, "version-name"); err == nil {
+			s.versions = strings.FieldsFunc(string(output), isVersionSeparator)
+		}
+	}
+	if len(s.versions) == 0 || s.source == "" {
+		if versions, source := ConfiguredVersions(dir.Manager, runtime); len(versions) > 0 {
+			if len(s.versions) == 0 {
+				s.versions = versions
+			}
+			if s.versions[0] == versions[0] {
+				s.source = source
+			}
+		}
+	}
+	for i, version := range s.versions {
+		s.versions[i] = installedVersion(dir, runtime, version)
+	}
+	r.selections[key] = s
+	return s
+}
+
+// which asks the manager for the binary the shim called name runs
+func (r *Resolver) which(dir ShimDir, name string) string {
+	output, err := r.run(dir, "which", name)
+	if err != nil {
+		return ""
+	}
+	target, _, _ := strings.Cut(strings.TrimSpace(string(output)), "\n")
+	target = strings.TrimSpace(target)
+	if !filepath.IsAbs(target) {
+		return ""
+	}
+	if _, err := os.Stat(target); err != nil {
+		return ""
+	}
+	return target
+}
+
+// run runs the manager with args
+func (r *Resolver) run(dir ShimDir, args ...string) ([]byte, error) {
+	if dir.Manager == Nvm {
+		// nvm is a shell function, not a command
+		return nil, exec.ErrNotFound
+	}
+	name := string(dir.Manager)
+	command, err := exec.LookPath(name)
+	if err != nil {
+		// asdf installed from git is often only sourced into the shell
+		command = filepath.Join(dir.Root, "bin", name)
+		if _, statErr := os.Stat(command); statErr != nil {
+			return nil, err
+		}
+	}
+	ctx, cancel := context.WithTimeout(r.ctx, commandTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, command, args...).Output()
+}
+
+// parseAsdfCurrent reads runtime's version and source from `asdf current`,
+// which prints "nodejs 20.11.0 /home/me/.tool-versions", under a header row
+// since asdf 0.16, and "______" for a runtime with no version set
+func parseAsdfCurrent(output, runtime string) selection {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != runtime || fields[1] == "______" {
+			continue
+		}
+		s := selection{versions: []string{fields[1]}}
+		if len(fields) > 2 && (filepath.IsAbs(fields[2]) || strings.HasPrefix(fields[2], "ASDF_")) {
+			s.source = fields[2]
+		}
+		return s
+	}
+	return selection{}
+}
+
+// installedVersion returns the installed version a selected version names:
+// itself, or the newest installed version it is a prefix of, as "20"
+// selects 20.11.0
+func installedVersion(dir ShimDir, runtime, version string) string {
+	if !isResolvable(version) {
+		return version
+	}
+	var matches []string
+	for _, installed := range InstalledVersions(dir, runtime) {
+		if installed == version {
+			return version
+		}
+		if strings.HasPrefix(installed, version+".") {
+			matches = append(matches, installed)
+		}
+	}
+	if len(matches) == 0 {
+		return version
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return semver.Compare(matches[i], matches[j]) > 0
+	})
+	return matches[0]
+}
+
+// installedBinary returns the binary called name in the install of a
+// runtime version, or ""
+func installedBinary(dir ShimDir, runtime, version, name string) string {
+	if !isResolvable(version) {
+		return ""
+	}
+	root := filepath.Join(dir.InstallsPath, runtime, version)
+	if dir.Manager == Pyenv || dir.Manager == Rbenv || dir.Manager == Nvm {
+		root = filepath.Join(dir.InstallsPath, version)
+	}
+	for _, path := range []string{filepath.Join(root, "bin", name), filepath.Join(root, name)} {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// voltaPackageBinary returns the executable called name of a package
+// installed with volta install, or ""
+func voltaPackageBinary(dir ShimDir, pkg, version, name string) string {
+	// Releases before 1.1 kept one directory per package version
+	root := filepath.Join(dir.InstallsPath, "packages", filepath.FromSlash(pkg))
+	for _, path := range []string{filepath.Join(root, "bin", name), filepath.Join(root, version, "bin", name)} {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// installOf returns the runtime and version whose install directory holds
+// path
+func installOf(dir ShimDir, path string) (string, string, bool) {
+	rel, err := filepath.Rel(dir.InstallsPath, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return "", "", false
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if dir.Manager == Pyenv || dir.Manager == Rbenv {
+		if len(parts) < 2 {
+			return "", "", false
+		}
+		return runtimeOf(dir.Manager), parts[0], true
+	}
+	if len(parts) < 3 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// runtimeOf returns the one runtime pyenv or rbenv manages
+func runtimeOf(manager Manager) string {
+	if manager == Rbenv {
+		return "ruby"
+	}
+	return "python"
+}
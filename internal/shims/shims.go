@@ -0,0 +1,719 @@
+package shims
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/semver"
+)
+
+// Manager identifies a runtime version manager that installs shims
+type Manager string
+
+const (
+	Pyenv Manager = "pyenv"
+	Rbenv Manager = "rbenv"
+	Asdf  Manager = "asdf"
+	Mise  Manager = "mise"
+	Nvm   Manager = "nvm"
+	Volta Manager = "volta"
+)
+
+// nodeBinaries are the executables a node install ships
+var nodeBinaries = []string{"node", "npm", "npx", "corepack"}
+
+// voltaTools maps the shims volta provides for the tools it manages itself
+// to the tool; other volta shims run packages installed with volta install
+var voltaTools = map[string]string{
+	"node":     "node",
+	"corepack": "node",
+	"npm":      "npm",
+	"npx":      "npm",
+	"yarn":     "yarn",
+	"yarnpkg":  "yarn",
+	"pnpm":     "pnpm",
+	"pnpx":     "pnpm",
+}
+
+// ShimDir describes a version manager installation on this machine. nvm has
+// no shims; its ShimsPath is the bin directory of the node version in use.
+type ShimDir struct {
+	Manager      Manager `json:"manager"`
+	Root         string  `json:"root"`
+	ShimsPath    string  `json:"shims_path"`
+	InstallsPath string  `json:"installs_path"`
+}
+
+// StaleShim is a runtime whose configured version is not installed, so its
+// shims fail with "version not installed" errors when run
+type StaleShim struct {
+	Manager Manager  `json:"manager"`
+	Runtime string   `json:"runtime"`
+	Version string   `json:"version"`
+	Source  string   `json:"source,omitempty"`
+	Shims   []string `json:"shims,omitempty"`
+}
+
+// RuntimeVersions summarizes the versions of one runtime installed side by
+// side by a version manager (e.g. every python under ~/.pyenv/versions)
+type RuntimeVersions struct {
+	Manager  Manager  `json:"manager"`
+	Runtime  string   `json:"runtime"`
+	Versions []string `json:"versions"`
+	Active   string   `json:"active,omitempty"`
+	Shims    []string `json:"shims,omitempty"`
+}
+
+// Summary describes the runtime's versions, e.g.
+// "python: 5 versions installed via pyenv (active: 3.12.1)"
+func (r RuntimeVersions) Summary() string {
+	noun := "versions"
+	if len(r.Versions) == 1 {
+		noun = "version"
+	}
+	summary := fmt.Sprintf("%s: %d %s installed via %s", r.Runtime, len(r.Versions), noun, r.Manager)
+	if r.Active != "" {
+		summary += fmt.Sprintf(" (active: %s)", r.Active)
+	}
+	return summary
+}
+
+// InstallCommand returns the command that installs the missing version
+func (s StaleShim) InstallCommand() string {
+	switch s.Manager {
+	case Pyenv, Rbenv:
+		return fmt.Sprintf("%s install %s", s.Manager, s.Version)
+	case Mise:
+		return fmt.Sprintf("mise install %s@%s", s.Runtime, s.Version)
+	case Nvm:
+		return fmt.Sprintf("nvm install %s", s.Version)
+	case Volta:
+		return fmt.Sprintf("volta install %s@%s", s.Runtime, s.Version)
+	default:
+		return fmt.Sprintf("%s install %s %s", s.Manager, s.Runtime, s.Version)
+	}
+}
+
+// DetectDirs finds the shim directories of installed version managers
+func DetectDirs() []ShimDir {
+	home, _ := os.UserHomeDir()
+
+	candidates := []ShimDir{
+		newShimDir(Pyenv, envOr("PYENV_ROOT", filepath.Join(home, ".pyenv")), "versions"),
+		newShimDir(Rbenv, envOr("RBENV_ROOT", filepath.Join(home, ".rbenv")), "versions"),
+		newShimDir(Asdf, envOr("ASDF_DATA_DIR", filepath.Join(home, ".asdf")), "installs"),
+		newShimDir(Mise, envOr("MISE_DATA_DIR", filepath.Join(home, ".local", "share", "mise")), "installs"),
+		nvmDir(envOr("NVM_DIR", filepath.Join(home, ".nvm"))),
+		voltaDir(envOr("VOLTA_HOME", filepath.Join(home, ".volta"))),
+	}
+
+	var dirs []ShimDir
+	for _, dir := range candidates {
+		if dir.ShimsPath == "" {
+			continue
+		}
+		if info, err := os.Stat(dir.ShimsPath); err == nil && info.IsDir() {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+func newShimDir(manager Manager, root, installs string) ShimDir {
+	return ShimDir{
+		Manager:      manager,
+		Root:         root,
+		ShimsPath:    filepath.Join(root, "shims"),
+		InstallsPath: filepath.Join(root, installs),
+	}
+}
+
+func nvmDir(root string) ShimDir {
+	dir := ShimDir{
+		Manager:      Nvm,
+		Root:         root,
+		InstallsPath: filepath.Join(root, "versions", "node"),
+	}
+	// nvm.sh puts the default version on PATH until `nvm use` picks another
+	if bin := os.Getenv("NVM_BIN"); bin != "" {
+		dir.ShimsPath = bin
+	} else if version := installedVersion(dir, "node", resolveNvmAlias(dir, "default")); isResolvable(version) {
+		dir.ShimsPath = filepath.Join(dir.InstallsPath, version, "bin")
+	}
+	return dir
+}
+
+func voltaDir(root string) ShimDir {
+	return ShimDir{
+		Manager:      Volta,
+		Root:         root,
+		ShimsPath:    filepath.Join(root, "bin"),
+		InstallsPath: filepath.Join(root, "tools", "image"),
+	}
+}
+
+// FindStale resolves each shim's configured version against the installed
+// versions and returns the runtimes that point at missing versions
+func FindStale(dirs []ShimDir) []StaleShim {
+	var stale []StaleShim
+	for _, dir := range dirs {
+		shims := listShims(dir.ShimsPath)
+		for runtime, names := range runtimesFor(dir, shims) {
+			versions, source := ConfiguredVersions(dir.Manager, runtime)
+			installed := InstalledVersions(dir, runtime)
+			for _, version := range versions {
+				if !isResolvable(version) || versionInstalled(version, installed) {
+					continue
+				}
+				sort.Strings(names)
+				stale = append(stale, StaleShim{
+					Manager: dir.Manager,
+					Runtime: runtime,
+					Version: version,
+					Source:  source,
+					Shims:   names,
+				})
+			}
+		}
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].Manager != stale[j].Manager {
+			return stale[i].Manager < stale[j].Manager
+		}
+		return stale[i].Runtime < stale[j].Runtime
+	})
+	return stale
+}
+
+// ListRuntimes groups the version-specific installs of each runtime under
+// the version manager that owns them
+func ListRuntimes(dirs []ShimDir) []RuntimeVersions {
+	var runtimes []RuntimeVersions
+	for _, dir := range dirs {
+		shimsByRuntime := runtimesFor(dir, listShims(dir.ShimsPath))
+
+		names := []string{}
+		switch dir.Manager {
+		case Pyenv:
+			names = append(names, "python")
+		case Rbenv:
+			names = append(names, "ruby")
+		case Nvm:
+			names = append(names, "node")
+		case Volta:
+			// The rest of the image directory holds installed packages
+			names = append(names, "node", "npm", "pnpm", "yarn")
+		default:
+			entries, _ := os.ReadDir(dir.InstallsPath)
+			for _, entry := range entries {
+				if entry.IsDir() {
+					names = append(names, entry.Name())
+				}
+			}
+		}
+
+		for _, runtime := range names {
+			versions := InstalledVersions(dir, runtime)
+			if len(versions) == 0 {
+				continue
+			}
+			sort.Slice(versions, func(i, j int) bool {
+				return semver.Compare(versions[i], versions[j]) < 0
+			})
+
+			rv := RuntimeVersions{
+				Manager:  dir.Manager,
+				Runtime:  runtime,
+				Versions: versions,
+				Shims:    shimsByRuntime[runtime],
+			}
+			if configured, _ := ConfiguredVersions(dir.Manager, runtime); len(configured) > 0 {
+				rv.Active = configured[0]
+			}
+			sort.Strings(rv.Shims)
+			runtimes = append(runtimes, rv)
+		}
+	}
+	return runtimes
+}
+
+// InstalledVersions lists the versions of a runtime installed by the manager
+func InstalledVersions(dir ShimDir, runtime string) []string {
+	path := dir.InstallsPath
+	if dir.Manager == Asdf || dir.Manager == Mise || dir.Manager == Volta {
+		path = filepath.Join(path, runtime)
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Type()&os.ModeSymlink != 0 {
+			versions = append(versions, entry.Name())
+		}
+	}
+	return versions
+}
+
+// ConfiguredVersions returns the versions selected for a runtime and the
+// file or variable that selected them, following each manager's lookup order
+func ConfiguredVersions(manager Manager, runtime string) ([]string, string) {
+	switch manager {
+	case Pyenv:
+		return lookupVersionFile("PYENV_VERSION", ".python-version",
+			filepath.Join(envOr("PYENV_ROOT", homeJoin(".pyenv")), "version"))
+	case Rbenv:
+		return lookupVersionFile("RBENV_VERSION", ".ruby-version",
+			filepath.Join(envOr("RBENV_ROOT", homeJoin(".rbenv")), "version"))
+	case Asdf, Mise:
+		prefix := "ASDF_"
+		if manager == Mise {
+			prefix = "MISE_"
+		}
+		envName := prefix + strings.ToUpper(strings.ReplaceAll(runtime, "-", "_")) + "_VERSION"
+		if v := os.Getenv(envName); v != "" {
+			return strings.Fields(v), envName
+		}
+		for _, path := range searchUpward(".tool-versions") {
+			if versions := readToolVersions(path, runtime); len(versions) > 0 {
+				return versions, path
+			}
+		}
+		if manager == Mise {
+			for _, path := range miseConfigFiles() {
+				if version := readMiseConfig(path, runtime); version != "" {
+					return []string{version}, path
+				}
+			}
+		}
+	case Nvm:
+		return nvmVersion(nvmDir(envOr("NVM_DIR", homeJoin(".nvm"))))
+	case Volta:
+		return voltaVersion(envOr("VOLTA_HOME", homeJoin(".volta")), runtime)
+	}
+	return nil, ""
+}
+
+// nvmVersion returns the node version nvm selects: the one `nvm use` put
+// on PATH, else the nearest .nvmrc, else the default alias
+func nvmVersion(dir ShimDir) ([]string, string) {
+	if bin := os.Getenv("NVM_BIN"); bin != "" {
+		return []string{filepath.Base(filepath.Dir(bin))}, "NVM_BIN"
+	}
+	sources := append(searchUpward(".nvmrc"), filepath.Join(dir.Root, "alias", "default"))
+	for _, path := range sources {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if fields := strings.Fields(string(data)); len(fields) > 0 {
+			return []string{resolveNvmAlias(dir, fields[0])}, path
+		}
+	}
+	return nil, ""
+}
+
+// resolveNvmAlias follows nvm aliases such as "default" or "lts/iron" to
+// the version they name, with a "v" prefix as nvm's directories have.
+// "node" and "stable" are the newest installed version.
+func resolveNvmAlias(dir ShimDir, name string) string {
+	for hops := 0; hops < 10; hops++ {
+		data, err := os.ReadFile(filepath.Join(dir.Root, "alias", filepath.FromSlash(name)))
+		if err != nil {
+			break
+		}
+		fields := strings.Fields(string(data))
+		if len(fields) == 0 {
+			break
+		}
+		name = fields[0]
+	}
+
+	switch {
+	case name == "node" || name == "stable":
+		installed := InstalledVersions(dir, "node")
+		sort.Slice(installed, func(i, j int) bool {
+			return semver.Compare(installed[i], installed[j]) > 0
+		})
+		if len(installed) > 0 {
+			return installed[0]
+		}
+	case name != "" && name[0] >= '0' && name[0] <= '9':
+		return "v" + name
+	}
+	return name
+}
+
+// voltaVersion returns the version of a tool volta selects: the one pinned
+// in the nearest package.json, else the default platform. npm is left
+// empty when it is the copy bundled with node.
+func voltaVersion(root, tool string) ([]string, string) {
+	if projects := searchUpward("package.json"); len(projects) > 0 {
+		if version, path := readVoltaPin(projects[0], tool); version != "" {
+			return []string{version}, path
+		}
+	}
+	path := filepath.Join(root, "tools", "user", "platform.json")
+	if version := readVoltaPlatform(path, tool); version != "" {
+		return []string{version}, path
+	}
+	return nil, ""
+}
+
+// readVoltaPin reads the version of tool in the "volta" section of a
+// package.json, following "extends" to the file it inherits from
+func readVoltaPin(path, tool string) (string, string) {
+	for hops := 0; hops < 10; hops++ {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", ""
+		}
+		var manifest struct {
+			Volta map[string]string `json:"volta"`
+		}
+		if json.Unmarshal(data, &manifest) != nil || manifest.Volta == nil {
+			return "", ""
+		}
+		if version := manifest.Volta[tool]; version != "" {
+			return version, path
+		}
+		extends := manifest.Volta["extends"]
+		if extends == "" {
+			return "", ""
+		}
+		if !filepath.IsAbs(extends) {
+			extends = filepath.Join(filepath.Dir(path), extends)
+		}
+		path = extends
+	}
+	return "", ""
+}
+
+// readVoltaPlatform reads the default version of tool from volta's
+// platform.json, which holds node and its npm as
+// {"node": {"runtime": "20.11.0", "npm": null}, "yarn": "1.22.19"}
+func readVoltaPlatform(path, tool string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var platform map[string]json.RawMessage
+	if json.Unmarshal(data, &platform) != nil {
+		return ""
+	}
+	if tool == "node" || tool == "npm" {
+		var node struct {
+			Runtime string `json:"runtime"`
+			Npm     string `json:"npm"`
+		}
+		if json.Unmarshal(platform["node"], &node) != nil {
+			return ""
+		}
+		if tool == "npm" {
+			return node.Npm
+		}
+		return node.Runtime
+	}
+	var version string
+	if json.Unmarshal(platform[tool], &version) != nil {
+		// Older volta releases keep package managers as objects too
+		var versioned struct {
+			Version string `json:"version"`
+		}
+		if json.Unmarshal(platform[tool], &versioned) == nil {
+			return versioned.Version
+		}
+	}
+	return version
+}
+
+// runtimesFor maps each runtime managed by dir to the shims it provides
+func runtimesFor(dir ShimDir, shims []string) map[string][]string {
+	result := make(map[string][]string)
+	switch dir.Manager {
+	case Pyenv:
+		if len(shims) > 0 {
+			result["python"] = shims
+		}
+	case Rbenv:
+		if len(shims) > 0 {
+			result["ruby"] = shims
+		}
+	case Asdf:
+		// asdf shims are scripts annotated with "# asdf-plugin: <plugin> <version>"
+		for _, name := range shims {
+			for _, plugin := range asdfShimPlugins(filepath.Join(dir.ShimsPath, name)) {
+				result[plugin] = append(result[plugin], name)
+			}
+		}
+	case Nvm:
+		// Packages installed with npm -g share the bin directory, and are
+		// left to npm
+		for _, name := range shims {
+			if contains(nodeBinaries, name) {
+				result["node"] = append(result["node"], name)
+			}
+		}
+	case Volta:
+		for _, name := range shims {
+			if tool, ok := voltaTools[name]; ok {
+				result[tool] = append(result[tool], name)
+			} else if bin, ok := readVoltaBin(dir, name); ok {
+				result[bin.Package] = append(result[bin.Package], name)
+			}
+		}
+	case Mise:
+		// mise shims are symlinks to mise itself, so attribute them by
+		// finding which installed tool ships a binary with the same name
+		shimSet := make(map[string]bool)
+		for _, name := range shims {
+			shimSet[name] = true
+		}
+		tools, _ := os.ReadDir(dir.InstallsPath)
+		for _, tool := range tools {
+			bins, _ := filepath.Glob(filepath.Join(dir.InstallsPath, tool.Name(), "*", "bin", "*"))
+			seen := make(map[string]bool)
+			for _, bin := range bins {
+				name := filepath.Base(bin)
+				if shimSet[name] && !seen[name] {
+					seen[name] = true
+					result[tool.Name()] = append(result[tool.Name()], name)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// voltaBin is volta's record of an executable provided by a package
+// installed with volta install
+type voltaBin struct {
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	Version string `json:"version"`
+	// path is the record's file
+	path string
+}
+
+// readVoltaBin reads the record of the package providing the volta shim
+// called name
+func readVoltaBin(dir ShimDir, name string) (voltaBin, bool) {
+	path := filepath.Join(dir.Root, "tools", "user", "bins", name+".json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return voltaBin{}, false
+	}
+	var bin voltaBin
+	if json.Unmarshal(data, &bin) != nil || bin.Package == "" {
+		return voltaBin{}, false
+	}
+	bin.path = path
+	return bin, true
+}
+
+// listShims returns the names of the shims in a shims directory
+func listShims(path string) []string {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
+// asdfShimPlugins reads the plugin annotations from an asdf shim script
+func asdfShimPlugins(path string) []string {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	seen := make(map[string]bool)
+	var plugins []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "# asdf-plugin:") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "# asdf-plugin:"))
+		if len(fields) > 0 && !seen[fields[0]] {
+			seen[fields[0]] = true
+			plugins = append(plugins, fields[0])
+		}
+	}
+	return plugins
+}
+
+// lookupVersionFile implements the pyenv/rbenv lookup: environment variable,
+// then a version file in the current directory or a parent, then the global file
+func lookupVersionFile(envName, fileName, globalFile string) ([]string, string) {
+	if v := os.Getenv(envName); v != "" {
+		return strings.FieldsFunc(v, isVersionSeparator), envName
+	}
+	for _, path := range append(searchUpward(fileName), globalFile) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var versions []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if i := strings.Index(line, "#"); i >= 0 {
+				line = line[:i]
+			}
+			versions = append(versions, strings.FieldsFunc(line, isVersionSeparator)...)
+		}
+		if len(versions) > 0 {
+			return versions, path
+		}
+	}
+	return nil, ""
+}
+
+// readToolVersions returns the versions listed for runtime in a .tool-versions file
+func readToolVersions(path, runtime string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) > 1 && fields[0] == runtime {
+			return fields[1:]
+		}
+	}
+	return nil
+}
+
+// readMiseConfig extracts `runtime = "version"` from the [tools] table of a mise config
+func readMiseConfig(path, runtime string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	inTools := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inTools = line == "[tools]"
+			continue
+		}
+		if !inTools {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || strings.Trim(strings.TrimSpace(key), `"`) != runtime {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, `'`) {
+			return strings.Trim(value, `"'`)
+		}
+		// Arrays and inline tables are left to mise itself
+		return ""
+	}
+	return ""
+}
+
+// miseConfigFiles returns mise config files from the current directory upward,
+// followed by the global config
+func miseConfigFiles() []string {
+	var files []string
+	for _, name := range []string{"mise.toml", ".mise.toml"} {
+		files = append(files, searchUpward(name)...)
+	}
+	configHome := envOr("XDG_CONFIG_HOME", homeJoin(".config"))
+	return append(files, filepath.Join(configHome, "mise", "config.toml"))
+}
+
+// searchUpward returns existing files named fileName in the current directory
+// and its parents, nearest first
+func searchUpward(fileName string) []string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	var found []string
+	for {
+		path := filepath.Join(dir, fileName)
+		if _, err := os.Stat(path); err == nil {
+			found = append(found, path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		path := filepath.Join(home, fileName)
+		if _, err := os.Stat(path); err == nil && !contains(found, path) {
+			found = append(found, path)
+		}
+	}
+	return found
+}
+
+// isResolvable reports whether a configured version names a concrete install
+// (as opposed to "system", "latest", or a path/ref specifier)
+func isResolvable(version string) bool {
+	switch version {
+	case "", "system", "latest", "lts", "node", "stable":
+		return false
+	}
+	return !strings.HasPrefix(version, "path:") && !strings.HasPrefix(version, "ref:") &&
+		!strings.HasPrefix(version, "prefix:") && !strings.HasPrefix(version, "lts/")
+}
+
+// versionInstalled reports whether version, or a fuzzy prefix of it, is installed
+func versionInstalled(version string, installed []string) bool {
+	for _, v := range installed {
+		if v == version || strings.HasPrefix(v, version+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func isVersionSeparator(r rune) bool {
+	return r == ':' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func homeJoin(elem ...string) string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(append([]string{home}, elem...)...)
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
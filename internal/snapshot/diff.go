@@ -0,0 +1,224 @@
+package snapshot
+
+import (
+	"sort"
+
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/semver"
+)
+
+// Kinds of change to a tool present in both catalogs
+const (
+	Upgraded   = "upgraded"
+	Downgraded = "downgraded"
+	// Reowned is a tool now provided by a different package or manager
+	Reowned = "reowned"
+	// Moved is a tool that now runs from a different path
+	Moved = "moved"
+	// Modified is a tool whose file changed size without a version change,
+	// such as a rebuild or an unmanaged binary replaced in place
+	Modified = "modified"
+)
+
+// ToolState is what a diff records about one tool in one catalog
+type ToolState struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Version string `json:"version,omitempty"`
+	Package string `json:"package,omitempty"`
+	Manager string `json:"manager,omitempty"`
+	Size    int64  `json:"size,omitempty"`
+}
+
+// ToolChange is a tool present in both catalogs that changed
+type ToolChange struct {
+	Name   string    `json:"name"`
+	Kind   string    `json:"kind"`
+	Before ToolState `json:"before"`
+	After  ToolState `json:"after"`
+}
+
+// PackageChange is a package whose version changed
+type PackageChange struct {
+	Name    string `json:"name"`
+	Manager string `json:"manager"`
+	Kind    string `json:"kind"`
+	Before  string `json:"before"`
+	After   string `json:"after"`
+}
+
+// Diff is what changed between two catalogs
+type Diff struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// PackagesCompared is false when either catalog lacks package
+	// information, so ownership and packages were not compared
+	PackagesCompared bool `json:"packages_compared"`
+
+	AddedTools   []ToolState  `json:"added_tools"`
+	RemovedTools []ToolState  `json:"removed_tools"`
+	ChangedTools []ToolChange `json:"changed_tools"`
+
+	AddedPackages   []models.PackageInfo `json:"added_packages"`
+	RemovedPackages []models.PackageInfo `json:"removed_packages"`
+	ChangedPackages []PackageChange      `json:"changed_packages"`
+}
+
+// Empty reports whether nothing changed
+func (d Diff) Empty() bool {
+	return len(d.AddedTools) == 0 && len(d.RemovedTools) == 0 && len(d.ChangedTools) == 0 &&
+		len(d.AddedPackages) == 0 && len(d.RemovedPackages) == 0 && len(d.ChangedPackages) == 0
+}
+
+// Compare reports what changed from one catalog to another. Tools are
+// compared by name, using the installation that runs; packages by manager
+// and name. Ownership and packages are only compared when both catalogs
+// record them, so a plain export is not reported as losing every package.
+func Compare(from, to *models.ToolCatalog) Diff {
+	diff := Diff{
+		AddedTools:      []ToolState{},
+		RemovedTools:    []ToolState{},
+		ChangedTools:    []ToolChange{},
+		AddedPackages:   []models.PackageInfo{},
+		RemovedPackages: []models.PackageInfo{},
+		ChangedPackages: []PackageChange{},
+	}
+
+	linked := hasPackages(from) && hasPackages(to)
+	diff.PackagesCompared = linked
+	before, after := activeTools(from, linked), activeTools(to, linked)
+	for _, name := range toolNames(after) {
+		now := after[name]
+		was, ok := before[name]
+		if !ok {
+			diff.AddedTools = append(diff.AddedTools, now)
+			continue
+		}
+		if kind := toolChange(was, now); kind != "" {
+			diff.ChangedTools = append(diff.ChangedTools, ToolChange{Name: name, Kind: kind, Before: was, After: now})
+		}
+	}
+	for _, name := range toolNames(before) {
+		if _, ok := after[name]; !ok {
+			diff.RemovedTools = append(diff.RemovedTools, before[name])
+		}
+	}
+
+	if !linked {
+		return diff
+	}
+	beforePkgs, afterPkgs := packagesByKey(from), packagesByKey(to)
+	for _, key := range packageKeys(afterPkgs) {
+		now := afterPkgs[key]
+		was, ok := beforePkgs[key]
+		switch {
+		case !ok:
+			diff.AddedPackages = append(diff.AddedPackages, now)
+		case was.Version != now.Version:
+			diff.ChangedPackages = append(diff.ChangedPackages, PackageChange{
+				Name:    now.Name,
+				Manager: now.Manager,
+				Kind:    versionChange(was.Version, now.Version),
+				Before:  was.Version,
+				After:   now.Version,
+			})
+		}
+	}
+	for _, key := range packageKeys(beforePkgs) {
+		if _, ok := afterPkgs[key]; !ok {
+			diff.RemovedPackages = append(diff.RemovedPackages, beforePkgs[key])
+		}
+	}
+	return diff
+}
+
+// hasPackages reports whether a catalog records packages, as an export with
+// --with-packages or a snapshot does
+func hasPackages(catalog *models.ToolCatalog) bool {
+	if len(catalog.Packages) > 0 {
+		return true
+	}
+	for _, tool := range catalog.Tools {
+		if tool.PackageManager != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// activeTools indexes a catalog's tools by name, keeping the first
+// unshadowed installation of each, and their owners if linked
+func activeTools(catalog *models.ToolCatalog, linked bool) map[string]ToolState {
+	tools := make(map[string]ToolState)
+	for _, tool := range catalog.Tools {
+		if _, seen := tools[tool.Name]; seen || tool.Shadowed {
+			continue
+		}
+		version := tool.Version
+		if version == "" {
+			version = tool.PackageVersion
+		}
+		state := ToolState{
+			Name:    tool.Name,
+			Path:    tool.Path,
+			Version: version,
+			Size:    tool.Size,
+		}
+		if linked {
+			state.Package, state.Manager = tool.PackageName, tool.PackageManager
+		}
+		tools[tool.Name] = state
+	}
+	return tools
+}
+
+func packagesByKey(catalog *models.ToolCatalog) map[string]models.PackageInfo {
+	pkgs := make(map[string]models.PackageInfo)
+	for _, pkg := range catalog.Packages {
+		pkgs[pkg.Manager+":"+pkg.Name] = pkg
+	}
+	return pkgs
+}
+
+// toolChange classifies how a tool changed, or returns "" if it did not.
+// A version change says the most, so it wins over a move or a new owner. A
+// version only one side knows, as when one catalog was exported with
+// --with-meta, is not evidence of a change by itself.
+func toolChange(was, now ToolState) string {
+	switch {
+	case was.Version != now.Version && was.Version != "" && now.Version != "":
+		return versionChange(was.Version, now.Version)
+	case was.Manager != now.Manager || was.Package != now.Package:
+		return Reowned
+	case was.Path != now.Path:
+		return Moved
+	case was.Size != now.Size && was.Size != 0 && now.Size != 0:
+		return Modified
+	}
+	return ""
+}
+
+func versionChange(was, now string) string {
+	if semver.Compare(now, was) < 0 {
+		return Downgraded
+	}
+	return Upgraded
+}
+
+func toolNames(tools map[string]ToolState) []string {
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func packageKeys(pkgs map[string]models.PackageInfo) []string {
+	keys := make([]string, 0, len(pkgs))
+	for key := range pkgs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,208 @@
+// Package snapshot stores timestamped catalogs of the environment and
+// compares catalogs, to answer "what changed on this machine since then?".
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/atomicfile"
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// idLayout is the timestamp a snapshot's ID starts with. It sorts in time
+// order and is safe in file names on every platform.
+const idLayout = "20060102-150405"
+
+// labelPattern is what a snapshot label may contain, so it can go in the
+// file name
+var labelPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Entry describes one saved snapshot
+type Entry struct {
+	// ID is the file name without .json: the time it was taken, then the
+	// label if it has one (20261016-093000-before-upgrade)
+	ID       string    `json:"id"`
+	Label    string    `json:"label,omitempty"`
+	Path     string    `json:"path"`
+	TakenAt  time.Time `json:"taken_at"`
+	Tools    int       `json:"tools"`
+	Packages int       `json:"packages"`
+}
+
+// Dir returns the directory snapshots are kept in:
+// $XDG_DATA_HOME/cli-ai/snapshots, by default ~/.local/share/cli-ai/snapshots
+func Dir() (string, error) {
+	if data := os.Getenv("XDG_DATA_HOME"); data != "" {
+		return filepath.Join(data, "cli-ai", "snapshots"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "cli-ai", "snapshots"), nil
+}
+
+// CheckLabel reports whether label can name a snapshot
+func CheckLabel(label string) error {
+	if label != "" && !labelPattern.MatchString(label) {
+		return fmt.Errorf("invalid label %q: use letters, digits, '.', '_', and '-'", label)
+	}
+	return nil
+}
+
+// Save writes the catalog to dir as a new snapshot. Snapshots are plain
+// catalogs, so `cli validate` and `cli merge` read them too.
+func Save(dir string, catalog *models.ToolCatalog, label string, takenAt time.Time) (Entry, error) {
+	if err := CheckLabel(label); err != nil {
+		return Entry{}, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return Entry{}, err
+	}
+
+	id := takenAt.UTC().Format(idLayout)
+	if label != "" {
+		id += "-" + label
+	}
+	path := filepath.Join(dir, id+".json")
+	if _, err := os.Stat(path); err == nil {
+		return Entry{}, fmt.Errorf("snapshot %s already exists", id)
+	}
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return Entry{}, err
+	}
+	// Replace the file whole so List never sees a partial snapshot
+	if err := atomicfile.Write(path, append(data, '\n'), 0644); err != nil {
+		return Entry{}, err
+	}
+	return Entry{
+		ID:       id,
+		Label:    label,
+		Path:     path,
+		TakenAt:  takenAt.UTC().Truncate(time.Second),
+		Tools:    len(catalog.Tools),
+		Packages: len(catalog.Packages),
+	}, nil
+}
+
+// List returns the snapshots in dir, oldest first. A missing directory
+// holds no snapshots.
+func List(dir string) ([]Entry, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	for _, file := range files {
+		id, ok := strings.CutSuffix(file.Name(), ".json")
+		if !ok || file.IsDir() {
+			continue
+		}
+		entry, ok := parseID(id)
+		if !ok {
+			continue
+		}
+		entry.Path = filepath.Join(dir, file.Name())
+		if catalog, err := Load(entry.Path); err == nil {
+			entry.Tools = len(catalog.Tools)
+			entry.Packages = len(catalog.Packages)
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ID < entries[j].ID
+	})
+	return entries, nil
+}
+
+// parseID splits a snapshot ID into its time and label
+func parseID(id string) (Entry, bool) {
+	if len(id) < len(idLayout) {
+		return Entry{}, false
+	}
+	takenAt, err := time.Parse(idLayout, id[:len(idLayout)])
+	if err != nil {
+		return Entry{}, false
+	}
+	entry := Entry{ID: id, TakenAt: takenAt}
+	if rest := id[len(idLayout):]; rest != "" {
+		label, ok := strings.CutPrefix(rest, "-")
+		if !ok {
+			return Entry{}, false
+		}
+		entry.Label = label
+	}
+	return entry, true
+}
+
+// Find resolves ref to a saved snapshot: "latest", "latest~N" for the Nth
+// before the latest, a full ID, a label, or a prefix of an ID that only
+// one snapshot has
+func Find(entries []Entry, ref string) (Entry, error) {
+	if len(entries) == 0 {
+		return Entry{}, errors.New("no snapshots saved yet (run `cli snapshot save`)")
+	}
+
+	if ref == "latest" || strings.HasPrefix(ref, "latest~") {
+		back := 0
+		if n, ok := strings.CutPrefix(ref, "latest~"); ok {
+			var err error
+			if back, err = strconv.Atoi(n); err != nil || back < 0 {
+				return Entry{}, fmt.Errorf("invalid snapshot reference %q", ref)
+			}
+		}
+		if back >= len(entries) {
+			return Entry{}, fmt.Errorf("%s: only %d snapshots saved", ref, len(entries))
+		}
+		return entries[len(entries)-1-back], nil
+	}
+
+	var matches []Entry
+	for _, entry := range entries {
+		if entry.ID == ref {
+			return entry, nil
+		}
+		if entry.Label == ref || strings.HasPrefix(entry.ID, ref) {
+			matches = append(matches, entry)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return Entry{}, fmt.Errorf("no snapshot matches %q (see `cli snapshot list`)", ref)
+	case 1:
+		return matches[0], nil
+	}
+	var ids []string
+	for _, entry := range matches {
+		ids = append(ids, entry.ID)
+	}
+	return Entry{}, fmt.Errorf("%q matches several snapshots: %s", ref, strings.Join(ids, ", "))
+}
+
+// Load reads a snapshot, or any exported catalog, from path
+func Load(path string) (*models.ToolCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	catalog, err := models.DecodeCatalog(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return catalog, nil
+}
@@ -0,0 +1,286 @@
+// Package toolschema describes CLI tools as the function-calling tool
+// definitions of LLM APIs, built from the usage, flags, and subcommands
+// parsed from their help, so an agent framework can register local tools
+// as callable functions.
+package toolschema
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// maxNameLength is the longest function name OpenAI and Anthropic accept
+const maxNameLength = 64
+
+// maxDescriptionLength keeps descriptions short enough that a few dozen
+// tools fit in a request without crowding out the conversation
+const maxDescriptionLength = 1024
+
+// ArgsProperty is the parameter holding a call's positional arguments
+const ArgsProperty = "args"
+
+// SubcommandProperty is the parameter naming the subcommand to run, for
+// tools whose help lists subcommands
+const SubcommandProperty = "subcommand"
+
+var (
+	// invalidName matches the characters function names may not contain
+	invalidName = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+	// invalidProperty matches the characters parameter names may not contain
+	invalidProperty = regexp.MustCompile(`[^a-zA-Z0-9_.-]+`)
+	// integerArgument matches flag placeholders that stand for a number
+	integerArgument = regexp.MustCompile(`(?i)^<?(n|num|number|int|uint|count|lines|seconds|secs|size|depth|level|port|jobs)>?$`)
+	// enumArgument matches a placeholder listing its choices, as argparse
+	// prints them ("{json,yaml}")
+	enumArgument = regexp.MustCompile(`^\{([^{}]+,[^{}]+)\}$`)
+)
+
+// Function is one callable tool: a command and the JSON schema of its
+// parameters
+type Function struct {
+	Name        string
+	Description string
+	Parameters  Schema
+}
+
+// Schema is the JSON schema of a function's parameters
+type Schema struct {
+	Type                 string              `json:"type"`
+	Properties           map[string]Property `json:"properties"`
+	AdditionalProperties bool                `json:"additionalProperties"`
+}
+
+// Property is one parameter of a function: a flag, the subcommand, or the
+// positional arguments
+type Property struct {
+	Type        string      `json:"type"`
+	Description string      `json:"description,omitempty"`
+	Enum        []string    `json:"enum,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Items       *Property   `json:"items,omitempty"`
+}
+
+// OpenAITool is a tool definition for the OpenAI chat completions API
+type OpenAITool struct {
+	Type     string         `json:"type"`
+	Function OpenAIFunction `json:"function"`
+}
+
+// OpenAIFunction is the function an OpenAI tool definition describes
+type OpenAIFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Parameters  Schema `json:"parameters"`
+}
+
+// AnthropicTool is a tool definition for the Anthropic messages API
+type AnthropicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	InputSchema Schema `json:"input_schema"`
+}
+
+// Functions describes each tool as a function, described by its man page
+// summary from descriptions (keyed by path) when it has one. Subcommands
+// whose own help was walked (export --help-depth) become functions of their
+// own, named after the tool and the subcommand ("git_commit"). Names are
+// made unique by numbering repeats.
+func Functions(tools []models.Tool, descriptions map[string]string) []Function {
+	var functions []Function
+	used := make(map[string]bool)
+	for _, tool := range tools {
+		description := descriptions[tool.Path]
+		var info models.ToolInfo
+		if tool.Help != nil {
+			info = *tool.Help
+			if description == "" {
+				description = info.Description
+			}
+		}
+		functions = appendFunction(functions, used, []string{tool.Name}, description, info.Usage, info.CommonFlags, info.Subcommands)
+	}
+	return functions
+}
+
+// appendFunction adds the function running the command words, then one for
+// each of its walked subcommands
+func appendFunction(functions []Function, used map[string]bool, words []string, description, usage string, flags []models.Flag, subcommands []models.Subcommand) []Function {
+	function := Function{
+		Name:        uniqueName(used, functionName(words)),
+		Description: functionDescription(words, description, usage),
+		Parameters:  parameters(flags, subcommands),
+	}
+	functions = append(functions, function)
+
+	for _, sub := range subcommands {
+		if sub.Usage == "" && len(sub.Flags) == 0 && len(sub.Subcommands) == 0 {
+			continue
+		}
+		functions = appendFunction(functions, used, append(words[:len(words):len(words)], sub.Name), sub.Description, sub.Usage, sub.Flags, sub.Subcommands)
+	}
+	return functions
+}
+
+// OpenAI returns the functions as OpenAI tool definitions
+func OpenAI(functions []Function) []OpenAITool {
+	tools := make([]OpenAITool, 0, len(functions))
+	for _, f := range functions {
+		tools = append(tools, OpenAITool{
+			Type:     "function",
+			Function: OpenAIFunction{Name: f.Name, Description: f.Description, Parameters: f.Parameters},
+		})
+	}
+	return tools
+}
+
+// Anthropic returns the functions as Anthropic tool definitions
+func Anthropic(functions []Function) []AnthropicTool {
+	tools := make([]AnthropicTool, 0, len(functions))
+	for _, f := range functions {
+		tools = append(tools, AnthropicTool{Name: f.Name, Description: f.Description, InputSchema: f.Parameters})
+	}
+	return tools
+}
+
+// functionName turns command words into a valid function name
+func functionName(words []string) string {
+	name := strings.Trim(invalidName.ReplaceAllString(strings.Join(words, "_"), "_"), "_")
+	if name == "" {
+		name = "tool"
+	}
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	return name
+}
+
+// uniqueName numbers a name already used ("python_2")
+func uniqueName(used map[string]bool, name string) string {
+	unique := name
+	for n := 2; used[unique]; n++ {
+		suffix := "_" + strconv.Itoa(n)
+		base := name
+		if len(base)+len(suffix) > maxNameLength {
+			base = base[:maxNameLength-len(suffix)]
+		}
+		unique = base + suffix
+	}
+	used[unique] = true
+	return unique
+}
+
+// functionDescription says what the command does and how it is run, so a
+// model can tell the functions apart and the caller can build the command
+// line
+func functionDescription(words []string, description, usage string) string {
+	command := strings.Join(words, " ")
+	text := "Run " + command
+	if description = strings.Join(strings.Fields(description), " "); description != "" {
+		text += ": " + description
+	}
+	if usage = strings.TrimSpace(usage); usage != "" {
+		text += "\nUsage: " + usage
+	}
+	if runes := []rune(text); len(runes) > maxDescriptionLength {
+		text = string(runes[:maxDescriptionLength-3]) + "..."
+	}
+	return text
+}
+
+// parameters builds the parameter schema of a command: one property per
+// flag, named after its long form, the subcommand if it has any, and its
+// positional arguments. --help and --version are left out, since calling
+// them is not running the tool.
+func parameters(flags []models.Flag, subcommands []models.Subcommand) Schema {
+	schema := Schema{Type: "object", Properties: make(map[string]Property)}
+
+	if len(subcommands) > 0 {
+		prop := Property{Type: "string", Description: "Subcommand to run"}
+		for _, sub := range subcommands {
+			prop.Enum = append(prop.Enum, sub.Name)
+		}
+		schema.Properties[SubcommandProperty] = prop
+	}
+
+	for _, flag := range flags {
+		if isMetaFlag(flag) {
+			continue
+		}
+		name := propertyName(flag.Name)
+		if name == "" || name == ArgsProperty || name == SubcommandProperty {
+			continue
+		}
+		if _, ok := schema.Properties[name]; ok {
+			continue
+		}
+		schema.Properties[name] = flagProperty(flag)
+	}
+
+	schema.Properties[ArgsProperty] = Property{
+		Type:        "array",
+		Description: "Positional arguments, in order, after the flags",
+		Items:       &Property{Type: "string"},
+	}
+	return schema
+}
+
+// isMetaFlag reports whether a flag only prints help or the version
+func isMetaFlag(flag models.Flag) bool {
+	switch flag.Name {
+	case "--help", "-h", "--version", "-V":
+		return flag.Argument == ""
+	}
+	return false
+}
+
+// propertyName turns a flag into a parameter name: its name without the
+// leading dashes
+func propertyName(flag string) string {
+	name := invalidProperty.ReplaceAllString(strings.TrimLeft(flag, "-"), "_")
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	return name
+}
+
+// flagProperty describes a flag as a parameter: a boolean for a switch, an
+// integer or one of a set of strings when its placeholder says so, and a
+// string otherwise. The description names the flag as written, since the
+// parameter name alone does not say whether it takes one dash or two.
+func flagProperty(flag models.Flag) Property {
+	form := flag.Name
+	if flag.Short != "" {
+		form = flag.Short + ", " + form
+	}
+	if flag.Argument != "" {
+		form += " " + flag.Argument
+	}
+	description := strings.TrimSpace(flag.Description)
+	if description == "" {
+		description = form
+	} else {
+		description += " (" + form + ")"
+	}
+	prop := Property{Type: "string", Description: description}
+
+	switch {
+	case flag.Argument == "":
+		prop.Type = "boolean"
+	case integerArgument.MatchString(flag.Argument):
+		prop.Type = "integer"
+		if n, err := strconv.Atoi(flag.Default); err == nil {
+			prop.Default = n
+		}
+	default:
+		if match := enumArgument.FindStringSubmatch(flag.Argument); match != nil {
+			prop.Enum = strings.Split(match[1], ",")
+		}
+		if flag.Default != "" {
+			prop.Default = flag.Default
+		}
+	}
+	return prop
+}
@@ -0,0 +1,561 @@
+// Package tui is the full-screen browser of `cli tui`: a search box that
+// fuzzy-searches tools and the packages that provide them as the user
+// types, a list of the results, a pane with the details of the selected
+// one, and actions on it.
+package tui
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/cli-ai-org/cli/internal/display"
+	"github.com/cli-ai-org/cli/internal/models"
+	"github.com/cli-ai-org/cli/internal/packages"
+	"github.com/cli-ai-org/cli/internal/shims"
+)
+
+// splitWidth is the terminal width from which the details are shown beside
+// the results rather than below them
+const splitWidth = 100
+
+// Styles of the parts of the screen, used only when color is enabled
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true)
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	faintStyle    = lipgloss.NewStyle().Faint(true)
+)
+
+// Browser is an interactive session over the tools in PATH, run as a
+// bubbletea program
+type Browser struct {
+	entries []*entry
+	input   textinput.Model
+	// results are the entries matching the search, best first
+	results []*entry
+	// cursor is the selected result, and offset the first one on screen
+	cursor, offset int
+	width, height  int
+	// message is the outcome of the last action, shown above the key help
+	message string
+
+	// Describe fills in the version and help text of a tool when it is
+	// first selected, since probing every tool up front is slow. It runs
+	// outside the user interface, on a copy of the tool.
+	Describe func(tool *models.Tool)
+	// Clipboard is where copying writes the OSC 52 escape sequence that
+	// sets the terminal's clipboard; nil disables it
+	Clipboard io.Writer
+}
+
+// entry holds every installation of one tool name, in PATH order
+type entry struct {
+	name     string
+	installs []models.Tool
+	// describing is set while Describe runs, and described once it has
+	describing, described bool
+}
+
+// match is an entry found by a search, with how well it matched
+type match struct {
+	entry *entry
+	score int
+}
+
+// describedMsg carries the version and help Describe found for an entry
+type describedMsg struct {
+	entry         *entry
+	version, help string
+}
+
+// New creates a browser over tools, which may hold several installations
+// of a name; the one earliest in PATH is the one that runs. The search
+// starts out as query.
+func New(tools []models.Tool, query string) *Browser {
+	byName := make(map[string]*entry)
+	var entries []*entry
+	for _, tool := range tools {
+		e, ok := byName[tool.Name]
+		if !ok {
+			e = &entry{name: tool.Name}
+			byName[tool.Name] = e
+			entries = append(entries, e)
+		}
+		e.installs = append(e.installs, tool)
+	}
+	for _, e := range entries {
+		sort.SliceStable(e.installs, func(i, j int) bool {
+			return e.installs[i].PathIndex < e.installs[j].PathIndex
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+
+	input := textinput.New()
+	input.Prompt = "Search: "
+	input.Placeholder = "tool or package name"
+	input.SetValue(query)
+	input.Focus()
+
+	b := &Browser{entries: entries, input: input}
+	b.search()
+	return b
+}
+
+// Init starts the cursor blinking and describes the first result
+func (b *Browser) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, b.describe())
+}
+
+// Update handles a key press, a resize, or a finished description
+func (b *Browser) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		b.width, b.height = msg.Width, msg.Height
+		b.input.Width = msg.Width - len(b.input.Prompt) - 1
+		b.scroll()
+		return b, nil
+
+	case describedMsg:
+		msg.entry.describing = false
+		msg.entry.described = true
+		msg.entry.installs[0].Version = msg.version
+		msg.entry.installs[0].HelpText = msg.help
+		return b, nil
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC:
+			return b, tea.Quit
+		case tea.KeyEsc:
+			if b.input.Value() == "" {
+				return b, tea.Quit
+			}
+			b.input.SetValue("")
+			b.search()
+			return b, b.describe()
+		case tea.KeyUp, tea.KeyCtrlP:
+			return b, b.move(-1)
+		case tea.KeyDown, tea.KeyCtrlN:
+			return b, b.move(1)
+		case tea.KeyPgUp:
+			return b, b.move(-b.listHeight())
+		case tea.KeyPgDown:
+			return b, b.move(b.listHeight())
+		case tea.KeyCtrlY:
+			return b, b.copyPath()
+		case tea.KeyCtrlU:
+			if e := b.selected(); e != nil {
+				b.message = uninstallText(e)
+			}
+			return b, nil
+		}
+
+		query := b.input.Value()
+		var cmd tea.Cmd
+		b.input, cmd = b.input.Update(msg)
+		if b.input.Value() != query {
+			b.search()
+			return b, tea.Batch(cmd, b.describe())
+		}
+		return b, cmd
+	}
+
+	var cmd tea.Cmd
+	b.input, cmd = b.input.Update(msg)
+	return b, cmd
+}
+
+// selected returns the result under the cursor, or nil when there is none
+func (b *Browser) selected() *entry {
+	if b.cursor < 0 || b.cursor >= len(b.results) {
+		return nil
+	}
+	return b.results[b.cursor]
+}
+
+// move moves the cursor by delta results, keeping it on the list and on
+// screen, and describes the result it lands on
+func (b *Browser) move(delta int) tea.Cmd {
+	b.cursor += delta
+	if b.cursor >= len(b.results) {
+		b.cursor = len(b.results) - 1
+	}
+	if b.cursor < 0 {
+		b.cursor = 0
+	}
+	b.message = ""
+	b.scroll()
+	return b.describe()
+}
+
+// scroll keeps the cursor within the part of the list on screen
+func (b *Browser) scroll() {
+	height := b.listHeight()
+	if b.cursor < b.offset {
+		b.offset = b.cursor
+	}
+	if b.cursor >= b.offset+height {
+		b.offset = b.cursor - height + 1
+	}
+	if b.offset < 0 {
+		b.offset = 0
+	}
+}
+
+// describe returns the command that probes the version and help of the
+// selected result, if it has not been probed yet
+func (b *Browser) describe() tea.Cmd {
+	e := b.selected()
+	if e == nil || e.described || e.describing || b.Describe == nil {
+		return nil
+	}
+	e.describing = true
+	tool := e.installs[0]
+	return func() tea.Msg {
+		b.Describe(&tool)
+		return describedMsg{entry: e, version: tool.Version, help: tool.HelpText}
+	}
+}
+
+// search lists the entries whose name or package fuzzily matches the
+// query, best first, or every entry when the query is empty
+func (b *Browser) search() {
+	query := strings.ToLower(strings.TrimSpace(b.input.Value()))
+	b.cursor, b.offset, b.message = 0, 0, ""
+	if query == "" {
+		b.results = b.entries
+		return
+	}
+
+	var matches []match
+	for _, e := range b.entries {
+		best, found := Score(query, e.name)
+		for _, tool := range e.installs {
+			if tool.PackageName == "" {
+				continue
+			}
+			// A package match ranks below a name match of the same quality
+			if score, ok := Score(query, tool.PackageName); ok && (!found || score/2 > best) {
+				best, found = score/2, true
+			}
+		}
+		if found {
+			matches = append(matches, match{entry: e, score: best})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].entry.name < matches[j].entry.name
+	})
+	b.results = make([]*entry, len(matches))
+	for i, m := range matches {
+		b.results[i] = m.entry
+	}
+}
+
+// Score rates how well query matches text when its characters appear in
+// text in order: an exact match is best, then a prefix, then a substring,
+// then a scattered match, which loses a point per character skipped
+func Score(query, text string) (int, bool) {
+	text = strings.ToLower(text)
+	switch {
+	case query == text:
+		return 1000, true
+	case strings.HasPrefix(text, query):
+		return 800 - len(text), true
+	case strings.Contains(text, query):
+		return 600 - len(text), true
+	}
+
+	score, i := 400, 0
+	for _, r := range text {
+		if i < len(query) && rune(query[i]) == r {
+			i++
+		} else if i > 0 && i < len(query) {
+			score--
+		}
+	}
+	if i < len(query) {
+		return 0, false
+	}
+	return score - len(text), true
+}
+
+// copyPath returns the command that copies the path of the installation
+// that runs to the terminal's clipboard
+func (b *Browser) copyPath() tea.Cmd {
+	e := b.selected()
+	if e == nil {
+		return nil
+	}
+	path := e.installs[0].Path
+	if b.Clipboard == nil {
+		b.message = path
+		return nil
+	}
+	b.message = "Copied " + path + " (terminals without OSC 52 support ignore it)"
+	out := b.Clipboard
+	return func() tea.Msg {
+		fmt.Fprintf(out, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(path)))
+		return nil
+	}
+}
+
+// View draws the search box, the results, the details of the selected one,
+// and the key help
+func (b *Browser) View() string {
+	if b.width == 0 {
+		return ""
+	}
+
+	header := b.input.View()
+	status := fmt.Sprintf("%d of %d tools", len(b.results), len(b.entries))
+	footer := []string{
+		fit(b.message, b.width),
+		fit(style(faintStyle, "↑/↓ select · ctrl+y copy path · ctrl+u uninstall command · esc clear/quit · "+status), b.width),
+	}
+
+	height := b.bodyHeight()
+	var body string
+	if b.width >= splitWidth {
+		listWidth := b.width * 2 / 5
+		list := b.listLines(listWidth, height)
+		details := b.detailLines(b.width-listWidth-3, height)
+		lines := make([]string, height)
+		for i := range lines {
+			lines[i] = pad(list[i], listWidth) + style(faintStyle, " │ ") + details[i]
+		}
+		body = strings.Join(lines, "\n")
+	} else {
+		listHeight := b.listHeight()
+		list := b.listLines(b.width, listHeight)
+		details := b.detailLines(b.width, height-listHeight-1)
+		body = strings.Join(list, "\n") + "\n" + style(faintStyle, strings.Repeat("─", b.width)) + "\n" + strings.Join(details, "\n")
+	}
+	return header + "\n" + body + "\n" + strings.Join(footer, "\n")
+}
+
+// bodyHeight is the number of lines between the search box and the footer
+func (b *Browser) bodyHeight() int {
+	if height := b.height - 3; height > 1 {
+		return height
+	}
+	return 1
+}
+
+// listHeight is the number of results on screen
+func (b *Browser) listHeight() int {
+	height := b.bodyHeight()
+	if b.width >= splitWidth {
+		return height
+	}
+	// Below the split width, the list takes the top half and a rule
+	// separates it from the details
+	if height/2 > 1 {
+		return height / 2
+	}
+	return 1
+}
+
+// listLines renders the results on screen, height lines of width
+// characters
+func (b *Browser) listLines(width, height int) []string {
+	lines := make([]string, height)
+	if len(b.results) == 0 {
+		lines[0] = fit(fmt.Sprintf("No tool or package matches %q", b.input.Value()), width)
+		return lines
+	}
+	nameWidth := 0
+	for _, e := range b.results {
+		if len(e.name) > nameWidth {
+			nameWidth = len(e.name)
+		}
+	}
+	if nameWidth > width/2 {
+		nameWidth = width / 2
+	}
+	for i := range lines {
+		n := b.offset + i
+		if n >= len(b.results) {
+			break
+		}
+		e := b.results[n]
+		if n == b.cursor {
+			line := fmt.Sprintf("› %-*s  %s%s", nameWidth, e.name, owner(e.installs[0]), plainMarkers(e))
+			if display.ColorEnabled() {
+				line = selectedStyle.Render(pad(fit(line, width), width))
+			}
+			lines[i] = fit(line, width)
+			continue
+		}
+		lines[i] = fit(fmt.Sprintf("  %-*s  %s%s", nameWidth, e.name, owner(e.installs[0]), markers(e)), width)
+	}
+	return lines
+}
+
+// markers flags the problems of an entry in the results
+func markers(e *entry) string {
+	var marks []string
+	if len(e.installs) > 1 {
+		marks = append(marks, display.Status("shadowed", fmt.Sprintf("⚠ %d installations", len(e.installs))))
+	}
+	if e.installs[0].Broken {
+		marks = append(marks, display.Status("broken", "✗ broken"))
+	}
+	if len(marks) == 0 {
+		return ""
+	}
+	return "  " + strings.Join(marks, ", ")
+}
+
+// plainMarkers is markers without color, for the selected result, whose
+// line is drawn in reverse video
+func plainMarkers(e *entry) string {
+	return ansi.Strip(markers(e))
+}
+
+// owner describes what provides a tool: its package, the version manager
+// whose shim it is, or nothing known
+func owner(tool models.Tool) string {
+	switch {
+	case tool.Shim != nil:
+		text := tool.Shim.Manager + " shim"
+		if tool.Shim.Plugin != "" {
+			text += " for " + strings.TrimSpace(tool.Shim.Plugin+" "+tool.Shim.Version)
+		}
+		return text
+	case tool.PackageName != "":
+		return strings.TrimSpace(tool.PackageManager + " " + tool.PackageName + " " + tool.PackageVersion)
+	case packages.IsSystemPath(tool.Path):
+		return "system"
+	default:
+		return "unmanaged"
+	}
+}
+
+// detailLines renders everything about the selected result: each
+// installation, which one runs, and as much of the help of that one as
+// fits, in height lines of width characters
+func (b *Browser) detailLines(width, height int) []string {
+	lines := make([]string, 0, height)
+	add := func(line string) {
+		lines = append(lines, fit(line, width))
+	}
+
+	e := b.selected()
+	if e == nil {
+		return padLines(lines, height)
+	}
+	active := e.installs[0]
+	add(style(titleStyle, e.name))
+	add("Path:     " + active.Path)
+	if active.IsSymlink && active.SymlinkTo != "" {
+		add("Links to: " + active.SymlinkTo)
+	}
+	add("Owner:    " + owner(active))
+	version := active.Version
+	if version == "" {
+		version = active.PackageVersion
+	}
+	switch {
+	case version != "":
+		add("Version:  " + version)
+	case e.describing:
+		add("Version:  " + style(faintStyle, "probing..."))
+	}
+	if active.Broken {
+		add("Broken:   " + display.Status("broken", active.BrokenReason))
+	}
+	if len(e.installs) > 1 {
+		add(display.Status("shadowed", fmt.Sprintf("Clash:    %d installations; the first runs, the others are shadowed", len(e.installs))))
+		for _, tool := range e.installs[1:] {
+			add(fmt.Sprintf("          %s (%s)", tool.Path, owner(tool)))
+		}
+	}
+
+	help := strings.TrimSpace(active.HelpText)
+	if help != "" && len(lines)+2 < height {
+		add("")
+		add("Help:")
+		helpLines := strings.Split(help, "\n")
+		room := height - len(lines)
+		for i, line := range helpLines {
+			if i == room-1 && len(helpLines) > room {
+				add(style(faintStyle, fmt.Sprintf("  ... %d more lines (%s --help)", len(helpLines)-i, e.name)))
+				break
+			}
+			add("  " + strings.TrimRight(line, " \t\r"))
+		}
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	return padLines(lines, height)
+}
+
+// uninstallText is the command that removes the installation that runs,
+// which is shown but not run, or why there is none
+func uninstallText(e *entry) string {
+	tool := e.installs[0]
+	switch {
+	case tool.Shim != nil:
+		if tool.Shim.Plugin == "" || tool.Shim.Version == "" {
+			return fmt.Sprintf("%s is a %s shim; remove the runtime with %s", tool.Path, tool.Shim.Manager, tool.Shim.Manager)
+		}
+		return "Uninstall with: " + shims.UninstallCommand(shims.Manager(tool.Shim.Manager), tool.Shim.Plugin, tool.Shim.Version)
+	case tool.PackageName != "":
+		argv := packages.UninstallCommand(packages.PackageManager(tool.PackageManager), tool.PackageName)
+		if argv == nil {
+			return fmt.Sprintf("%s has no uninstall command for a package; cli uninstall %s deletes its binaries", tool.PackageManager, e.name)
+		}
+		quoted := make([]string, len(argv))
+		for i, arg := range argv {
+			quoted[i] = display.ShellQuote(arg)
+		}
+		return fmt.Sprintf("Uninstall with: %s (or cli uninstall %s, which lists the package's other tools first)", strings.Join(quoted, " "), e.name)
+	case packages.IsSystemPath(tool.Path):
+		return fmt.Sprintf("%s belongs to the operating system; remove it with the system's package manager", tool.Path)
+	default:
+		return fmt.Sprintf("%s is not owned by a detected package manager; cli uninstall %s --force deletes the file", tool.Path, e.name)
+	}
+}
+
+// style renders text in s when color is enabled
+func style(s lipgloss.Style, text string) string {
+	if !display.ColorEnabled() || text == "" {
+		return text
+	}
+	return s.Render(text)
+}
+
+// fit shortens text to width characters on screen, ending it with "…"
+func fit(text string, width int) string {
+	if width < 1 {
+		return ""
+	}
+	return ansi.Truncate(text, width, "…")
+}
+
+// pad fills text with spaces to width characters on screen
+func pad(text string, width int) string {
+	if n := width - ansi.StringWidth(text); n > 0 {
+		return text + strings.Repeat(" ", n)
+	}
+	return text
+}
+
+// padLines fills lines with empty lines to height
+func padLines(lines []string, height int) []string {
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return lines
+}
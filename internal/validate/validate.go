@@ -0,0 +1,193 @@
+package validate
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// Required fields. Fields added in later versions (path_index, path_rank, hostname)
+// are optional so catalogs from older versions still validate.
+var (
+	requiredCatalogFields = []string{"tools"}
+	requiredToolFields    = []string{"name", "path"}
+	requiredPackageFields = []string{"name", "manager"}
+)
+
+// Problem is one validation failure, located by a JSON path such as
+// tools[3].path or, for NDJSON, line 7
+type Problem struct {
+	Location string `json:"location"`
+	Message  string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return p.Location + ": " + p.Message
+}
+
+// Result summarizes the validation of one file
+type Result struct {
+	// Format is "catalog" for an export JSON document or "ndjson" for one
+	// tool record per line
+	Format   string    `json:"format"`
+	Tools    int       `json:"tools"`
+	Problems []Problem `json:"problems"`
+}
+
+// Valid reports whether no problems were found
+func (r Result) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+// Data validates an exported catalog: a JSON document as written by
+// `cli export`, or NDJSON tool records as written by `--format ndjson` and
+// `--append`. Every entry is checked for unknown fields, missing required
+// fields, and values of the wrong type, and the whole document is finally
+// decoded into models.ToolCatalog with unknown fields disallowed.
+func Data(data []byte) Result {
+	if models.IsNDJSON(data) {
+		return ndjson(data)
+	}
+	return catalog(data)
+}
+
+func catalog(data []byte) Result {
+	result := Result{Format: "catalog"}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		result.Problems = append(result.Problems, Problem{Location: "catalog", Message: "not a JSON object: " + err.Error()})
+		return result
+	}
+	result.Problems = append(result.Problems, checkObject("catalog", doc, models.ToolCatalog{}, requiredCatalogFields)...)
+
+	var tools []json.RawMessage
+	if raw, ok := doc["tools"]; ok {
+		if err := json.Unmarshal(raw, &tools); err != nil {
+			result.Problems = append(result.Problems, Problem{Location: "tools", Message: "not an array"})
+		}
+	}
+	result.Tools = len(tools)
+	for i, raw := range tools {
+		result.Problems = append(result.Problems, checkEntry(fmt.Sprintf("tools[%d]", i), raw, &models.Tool{}, requiredToolFields)...)
+	}
+
+	var pkgs []json.RawMessage
+	if raw, ok := doc["packages"]; ok {
+		if err := json.Unmarshal(raw, &pkgs); err != nil {
+			result.Problems = append(result.Problems, Problem{Location: "packages", Message: "not an array"})
+		}
+	}
+	for i, raw := range pkgs {
+		result.Problems = append(result.Problems, checkEntry(fmt.Sprintf("packages[%d]", i), raw, &models.PackageInfo{}, requiredPackageFields)...)
+	}
+
+	// The strict decode is the authority; it catches anything the per-entry
+	// checks above do not describe in more detail
+	var parsed models.ToolCatalog
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&parsed); err != nil {
+		if len(result.Problems) == 0 {
+			result.Problems = append(result.Problems, Problem{Location: "catalog", Message: err.Error()})
+		}
+		return result
+	}
+
+	if _, ok := doc["total_tools"]; ok && parsed.TotalTools != len(parsed.Tools) {
+		result.Problems = append(result.Problems, Problem{
+			Location: "total_tools",
+			Message:  fmt.Sprintf("is %d but the catalog has %d tools", parsed.TotalTools, len(parsed.Tools)),
+		})
+	}
+	if _, ok := doc["total_packages"]; ok && parsed.TotalPackages != len(parsed.Packages) {
+		result.Problems = append(result.Problems, Problem{
+			Location: "total_packages",
+			Message:  fmt.Sprintf("is %d but the catalog has %d packages", parsed.TotalPackages, len(parsed.Packages)),
+		})
+	}
+	return result
+}
+
+func ndjson(data []byte) Result {
+	result := Result{Format: "ndjson"}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	// Records carrying help text can be long
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		result.Tools++
+		result.Problems = append(result.Problems, checkEntry(fmt.Sprintf("line %d", line), scanner.Bytes(), &models.Tool{}, requiredToolFields)...)
+	}
+	if err := scanner.Err(); err != nil {
+		result.Problems = append(result.Problems, Problem{Location: fmt.Sprintf("line %d", line+1), Message: err.Error()})
+	}
+	return result
+}
+
+// checkEntry validates one JSON object against the struct target points to
+func checkEntry(location string, raw []byte, target interface{}, required []string) []Problem {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return []Problem{{Location: location, Message: "not a JSON object"}}
+	}
+
+	problems := checkObject(location, obj, reflect.ValueOf(target).Elem().Interface(), required)
+
+	// Check value types field by field so every bad field is reported, not
+	// just the first
+	var names []string
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		single, _ := json.Marshal(map[string]json.RawMessage{name: obj[name]})
+		if err := json.Unmarshal(single, target); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			message := err.Error()
+			if errors.As(err, &typeErr) {
+				message = fmt.Sprintf("expected %s, got JSON %s", typeErr.Type, typeErr.Value)
+			}
+			problems = append(problems, Problem{Location: location + "." + name, Message: message})
+		}
+	}
+	return problems
+}
+
+// checkObject reports unknown and missing required fields of one object
+func checkObject(location string, obj map[string]json.RawMessage, model interface{}, required []string) []Problem {
+	known := make(map[string]bool)
+	for _, field := range models.JSONFields(model) {
+		known[field] = true
+	}
+
+	var problems []Problem
+	var unknown []string
+	for name := range obj {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		problems = append(problems, Problem{Location: location + "." + name, Message: "unknown field"})
+	}
+	for _, name := range required {
+		if _, ok := obj[name]; !ok {
+			problems = append(problems, Problem{Location: location + "." + name, Message: "missing required field"})
+		}
+	}
+	return problems
+}
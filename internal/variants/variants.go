@@ -0,0 +1,113 @@
+package variants
+
+import (
+	"regexp"
+	"sort"
+
+	"github.com/cli-ai-org/cli/internal/models"
+)
+
+// versionSuffix matches a trailing version on a tool name: python3.11,
+// node18, clang-15. The base must end in a letter so names like 2to3 are
+// not split in the middle of a word.
+var versionSuffix = regexp.MustCompile(`^(.*[A-Za-z])-?(\d+(?:\.\d+)*)$`)
+
+// Group is a set of tools that are version-suffixed variants of one base tool
+type Group struct {
+	Base     string   `json:"base"`
+	Variants []string `json:"variants"`
+}
+
+// bitWidths are numeric suffixes that name an encoding or architecture
+// (base32/base64, linux32/linux64) rather than a version
+var bitWidths = map[string]bool{"16": true, "32": true, "64": true}
+
+// BaseName strips a trailing version suffix from a tool name, returning the
+// base name and the suffix. Names without a suffix, whose base would be a
+// single character (x264, b2), or whose suffix is a bit width are returned
+// unchanged with an empty suffix.
+func BaseName(name string) (string, string) {
+	m := versionSuffix.FindStringSubmatch(name)
+	if m == nil || len(m[1]) < 2 || bitWidths[m[2]] {
+		return name, ""
+	}
+	return m[1], m[2]
+}
+
+// Find groups tools by base name. Only groups with at least two distinct
+// names, at least one of them version-suffixed, are returned, sorted by base.
+func Find(tools []models.Tool) []Group {
+	names := make(map[string]map[string]bool)
+	suffixed := make(map[string]bool)
+	for _, tool := range tools {
+		base, suffix := BaseName(tool.Name)
+		if names[base] == nil {
+			names[base] = make(map[string]bool)
+		}
+		names[base][tool.Name] = true
+		if suffix != "" {
+			suffixed[base] = true
+		}
+	}
+
+	var groups []Group
+	for base, set := range names {
+		if len(set) < 2 || !suffixed[base] {
+			continue
+		}
+		group := Group{Base: base}
+		for name := range set {
+			group.Variants = append(group.Variants, name)
+		}
+		sort.Strings(group.Variants)
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Base < groups[j].Base
+	})
+	return groups
+}
+
+// Collapse keeps one tool per variant group, recording the other variants'
+// names in its Aliases. The representative is the tool named exactly after
+// the base when installed, otherwise the first variant in the input order.
+// Tools outside any group are returned unchanged, in their original order.
+func Collapse(tools []models.Tool) []models.Tool {
+	groupOf := make(map[string]*Group)
+	groups := Find(tools)
+	for i := range groups {
+		for _, name := range groups[i].Variants {
+			groupOf[name] = &groups[i]
+		}
+	}
+
+	representative := make(map[string]int)
+	for i, tool := range tools {
+		group, ok := groupOf[tool.Name]
+		if !ok {
+			continue
+		}
+		if _, seen := representative[group.Base]; !seen || tool.Name == group.Base {
+			representative[group.Base] = i
+		}
+	}
+
+	var collapsed []models.Tool
+	for i, tool := range tools {
+		group, ok := groupOf[tool.Name]
+		if !ok {
+			collapsed = append(collapsed, tool)
+			continue
+		}
+		if representative[group.Base] != i {
+			continue
+		}
+		for _, name := range group.Variants {
+			if name != tool.Name {
+				tool.Aliases = append(tool.Aliases, name)
+			}
+		}
+		collapsed = append(collapsed, tool)
+	}
+	return collapsed
+}
@@ -0,0 +1,102 @@
+package vuln
+
+import (
+	"math"
+	"strings"
+)
+
+// cvss3Weights are the CVSS v3 base metric values
+// (https://www.first.org/cvss/v3.1/specification-document#7-4-Metric-Values).
+// Privileges Required is weighted by Scope in cvss3Score.
+var cvss3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvss3Score computes the base score of a CVSS v3.0 or v3.1 vector such as
+// "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", reporting false for
+// anything else
+func cvss3Score(vector string) (float64, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) == 0 || !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return 0, false
+	}
+	metrics := make(map[string]string)
+	for _, part := range parts[1:] {
+		if name, value, ok := strings.Cut(part, ":"); ok {
+			metrics[name] = value
+		}
+	}
+
+	values := make(map[string]float64)
+	for name, weights := range cvss3Weights {
+		weight, ok := weights[metrics[name]]
+		if !ok {
+			return 0, false
+		}
+		values[name] = weight
+	}
+	changed := metrics["S"] == "C"
+	if !changed && metrics["S"] != "U" {
+		return 0, false
+	}
+	switch metrics["PR"] {
+	case "N":
+		values["PR"] = 0.85
+	case "L":
+		values["PR"] = 0.62
+		if changed {
+			values["PR"] = 0.68
+		}
+	case "H":
+		values["PR"] = 0.27
+		if changed {
+			values["PR"] = 0.5
+		}
+	default:
+		return 0, false
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if changed {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+	exploitability := 8.22 * values["AV"] * values["AC"] * values["PR"] * values["UI"]
+	if changed {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// roundUp rounds up to one decimal place as CVSS v3.1 specifies, avoiding
+// floating point error
+func roundUp(x float64) float64 {
+	scaled := int(math.Round(x * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+	return (math.Floor(float64(scaled)/10000) + 1) / 10
+}
+
+// cvss3Rating returns the qualitative rating of a CVSS v3 base score
+func cvss3Rating(score float64) Severity {
+	switch {
+	case score >= 9:
+		return SeverityCritical
+	case score >= 7:
+		return SeverityHigh
+	case score >= 4:
+		return SeverityMedium
+	case score > 0:
+		return SeverityLow
+	}
+	return SeverityUnknown
+}
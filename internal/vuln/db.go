@@ -0,0 +1,172 @@
+package vuln
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// osvDumps serves a zip of every record of each ecosystem
+const osvDumps = "https://osv-vulnerabilities.storage.googleapis.com/"
+
+// MissingDBError is an offline check of ecosystems that have not been
+// downloaded
+type MissingDBError struct {
+	Ecosystems []string
+}
+
+func (e *MissingDBError) Error() string {
+	return fmt.Sprintf("no offline database for %v; download it with --update-db", e.Ecosystems)
+}
+
+// dumpPath returns where an ecosystem's dump is kept
+func (c *Checker) dumpPath(ecosystem string) string {
+	return filepath.Join(c.dbDir, ecosystem+".zip")
+}
+
+// UpdateDB downloads the OSV dumps of the ecosystems, replacing older
+// copies only once each download is complete
+func (c *Checker) UpdateDB(ecosystems []string) error {
+	if c.dbDir == "" {
+		return errors.New("no user cache directory to keep the database in")
+	}
+	if err := os.MkdirAll(c.dbDir, 0755); err != nil {
+		return err
+	}
+	for _, ecosystem := range ecosystems {
+		if err := c.downloadDump(ecosystem); err != nil {
+			return fmt.Errorf("downloading %s database: %w", ecosystem, err)
+		}
+	}
+	return nil
+}
+
+func (c *Checker) downloadDump(ecosystem string) error {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, osvDumps+url.PathEscape(ecosystem)+"/all.zip", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := c.download.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", req.URL, resp.Status)
+	}
+
+	path := c.dumpPath(ecosystem)
+	tmp, err := os.CreateTemp(c.dbDir, ecosystem+"-*.zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// checkOffline matches packages against the downloaded dumps of their
+// ecosystems
+func (c *Checker) checkOffline(pkgs []Package) ([]Finding, error) {
+	// wanted lists the packages to look for in each ecosystem, by the name
+	// OSV knows them by
+	wanted := make(map[string]map[string]bool)
+	for _, pkg := range pkgs {
+		ecosystem, _ := Ecosystem(pkg.Manager)
+		if wanted[ecosystem] == nil {
+			wanted[ecosystem] = make(map[string]bool)
+		}
+		wanted[ecosystem][queryName(ecosystem, pkg.Name)] = true
+	}
+
+	var missing []string
+	for ecosystem := range wanted {
+		if _, err := os.Stat(c.dumpPath(ecosystem)); err != nil {
+			missing = append(missing, ecosystem)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, &MissingDBError{Ecosystems: missing}
+	}
+
+	// records holds, per ecosystem and package name, the records that
+	// mention the package
+	records := make(map[string]map[string][]*Record)
+	for ecosystem, names := range wanted {
+		found, err := c.loadDump(ecosystem, names)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s database: %w", ecosystem, err)
+		}
+		records[ecosystem] = found
+	}
+
+	var findings []Finding
+	for _, pkg := range pkgs {
+		ecosystem, _ := Ecosystem(pkg.Manager)
+		for _, record := range records[ecosystem][queryName(ecosystem, pkg.Name)] {
+			if record.affects(ecosystem, pkg.Name, pkg.Version) {
+				findings = append(findings, record.finding(pkg, ecosystem))
+			}
+		}
+	}
+	return findings, nil
+}
+
+// loadDump reads the records of an ecosystem's dump that mention one of
+// names
+func (c *Checker) loadDump(ecosystem string, names map[string]bool) (map[string][]*Record, error) {
+	archive, err := zip.OpenReader(c.dumpPath(ecosystem))
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	found := make(map[string][]*Record)
+	for _, file := range archive.File {
+		if c.ctx.Err() != nil {
+			return nil, c.ctx.Err()
+		}
+		record, err := readRecord(file)
+		if err != nil {
+			// One bad record should not hide the rest
+			continue
+		}
+		mentioned := make(map[string]bool)
+		for _, affected := range record.Affected {
+			name := queryName(ecosystem, affected.Package.Name)
+			if affected.Package.Ecosystem == ecosystem && names[name] && !mentioned[name] {
+				mentioned[name] = true
+				found[name] = append(found[name], record)
+			}
+		}
+	}
+	return found, nil
+}
+
+func readRecord(file *zip.File) (*Record, error) {
+	r, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	var record Record
+	if err := json.NewDecoder(r).Decode(&record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
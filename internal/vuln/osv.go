@@ -0,0 +1,309 @@
+package vuln
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cli-ai-org/cli/internal/atomicfile"
+)
+
+// OSV API endpoints
+const (
+	osvQueryBatch = "https://api.osv.dev/v1/querybatch"
+	osvVulns      = "https://api.osv.dev/v1/vulns/"
+)
+
+// userAgent identifies requests to OSV
+const userAgent = "cli (https://github.com/cli-ai-org/cli)"
+
+// maxBatch is the most queries OSV accepts in one batch
+const maxBatch = 1000
+
+// fetchWorkers bounds how many vulnerability records are fetched at once
+const fetchWorkers = 8
+
+// Checker finds the vulnerabilities affecting packages, by asking the OSV
+// API or by matching them against a local copy of the OSV database
+type Checker struct {
+	client *http.Client
+	// download fetches database dumps, which take longer than API calls
+	download *http.Client
+	// ctx cancels running requests
+	ctx context.Context
+	// dbDir holds the downloaded database dumps and recordDir the records
+	// fetched from the API
+	dbDir     string
+	recordDir string
+	offline   bool
+}
+
+// NewChecker creates a checker that asks the OSV API, keeping the records
+// it fetches under the user cache directory
+func NewChecker() *Checker {
+	c := &Checker{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		download: &http.Client{Timeout: 10 * time.Minute},
+		ctx:      context.Background(),
+	}
+	if dir, err := os.UserCacheDir(); err == nil {
+		c.dbDir = filepath.Join(dir, "cli", "osv", "db")
+		c.recordDir = filepath.Join(dir, "cli", "osv", "vulns")
+	}
+	return c
+}
+
+// SetContext makes running requests stop when ctx is cancelled
+func (c *Checker) SetContext(ctx context.Context) {
+	c.ctx = ctx
+}
+
+// SetOffline makes Check match packages against the downloaded database
+// instead of asking the API
+func (c *Checker) SetOffline(offline bool) {
+	c.offline = offline
+}
+
+// DBDir returns where the downloaded database is kept
+func (c *Checker) DBDir() string {
+	return c.dbDir
+}
+
+// Check returns the vulnerabilities affecting pkgs, which must all have an
+// OSV ecosystem and a version
+func (c *Checker) Check(pkgs []Package) ([]Finding, error) {
+	if c.offline {
+		return c.checkOffline(pkgs)
+	}
+	return c.checkOnline(pkgs)
+}
+
+// osvQuery asks which vulnerabilities affect a version of a package
+type osvQuery struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Version   string `json:"version"`
+	PageToken string `json:"page_token,omitempty"`
+}
+
+// osvBatchResult answers one query of a batch with the IDs of the
+// vulnerabilities, and a token for the next page if there are more
+type osvBatchResult struct {
+	Vulns []struct {
+		ID       string `json:"id"`
+		Modified string `json:"modified"`
+	} `json:"vulns"`
+	NextPageToken string `json:"next_page_token"`
+}
+
+// checkOnline batch-queries OSV for the IDs of the vulnerabilities
+// affecting each package, then fetches the records it has not kept
+func (c *Checker) checkOnline(pkgs []Package) ([]Finding, error) {
+	queries := make([]osvQuery, len(pkgs))
+	for i, pkg := range pkgs {
+		ecosystem, _ := Ecosystem(pkg.Manager)
+		queries[i].Package.Ecosystem = ecosystem
+		queries[i].Package.Name = queryName(ecosystem, pkg.Name)
+		queries[i].Version = pkg.Version
+	}
+
+	// ids[i] are the vulnerabilities affecting pkgs[i]; modified is when
+	// each was last changed, to tell whether a kept record is current
+	ids := make([][]string, len(pkgs))
+	modified := make(map[string]string)
+	pending := make([]int, len(pkgs))
+	for i := range pending {
+		pending[i] = i
+	}
+	for len(pending) > 0 {
+		var next []int
+		for start := 0; start < len(pending); start += maxBatch {
+			end := start + maxBatch
+			if end > len(pending) {
+				end = len(pending)
+			}
+			batch := make([]osvQuery, 0, end-start)
+			for _, i := range pending[start:end] {
+				batch = append(batch, queries[i])
+			}
+			results, err := c.queryBatch(batch)
+			if err != nil {
+				return nil, err
+			}
+			for n, result := range results {
+				if n >= end-start {
+					break
+				}
+				i := pending[start+n]
+				for _, v := range result.Vulns {
+					ids[i] = append(ids[i], v.ID)
+					modified[v.ID] = v.Modified
+				}
+				if result.NextPageToken != "" {
+					queries[i].PageToken = result.NextPageToken
+					next = append(next, i)
+				}
+			}
+		}
+		pending = next
+	}
+
+	records, err := c.records(modified)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for i, pkg := range pkgs {
+		for _, id := range ids[i] {
+			if record := records[id]; record != nil {
+				findings = append(findings, record.finding(pkg, queries[i].Package.Ecosystem))
+			}
+		}
+	}
+	return findings, nil
+}
+
+// queryBatch posts one batch of queries
+func (c *Checker) queryBatch(queries []osvQuery) ([]osvBatchResult, error) {
+	body, err := json.Marshal(struct {
+		Queries []osvQuery `json:"queries"`
+	}{queries})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, osvQueryBatch, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var response struct {
+		Results []osvBatchResult `json:"results"`
+	}
+	if err := c.do(req, &response); err != nil {
+		return nil, err
+	}
+	return response.Results, nil
+}
+
+// records returns the records of the vulnerabilities modified names, kept
+// from earlier runs when they have not changed since and fetched
+// otherwise. Records that cannot be fetched are left out, unless none can.
+func (c *Checker) records(modified map[string]string) (map[string]*Record, error) {
+	records := make(map[string]*Record)
+	var fetch []string
+	for id, changed := range modified {
+		if record := c.keptRecord(id); record != nil && record.Modified == changed {
+			records[id] = record
+			continue
+		}
+		fetch = append(fetch, id)
+	}
+
+	// Each record goes into its own slot, so workers need no lock
+	fetched := make([]*Record, len(fetch))
+	errs := make([]error, len(fetch))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < fetchWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				if c.ctx.Err() != nil {
+					errs[index] = c.ctx.Err()
+					continue
+				}
+				fetched[index], errs[index] = c.fetchRecord(fetch[index])
+			}
+		}()
+	}
+	for index := range fetch {
+		jobs <- index
+	}
+	close(jobs)
+	wg.Wait()
+
+	var lastErr error
+	for index, id := range fetch {
+		if errs[index] != nil {
+			lastErr = errs[index]
+			continue
+		}
+		records[id] = fetched[index]
+		c.keepRecord(fetched[index])
+	}
+	if len(records) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return records, nil
+}
+
+// fetchRecord gets one vulnerability record from the API
+func (c *Checker) fetchRecord(id string) (*Record, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, osvVulns+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	var record Record
+	if err := c.do(req, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// keptRecord reads a record fetched by an earlier run, or returns nil
+func (c *Checker) keptRecord(id string) *Record {
+	if c.recordDir == "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(c.recordDir, id+".json"))
+	if err != nil {
+		return nil
+	}
+	var record Record
+	if json.Unmarshal(data, &record) != nil {
+		return nil
+	}
+	return &record
+}
+
+// keepRecord saves a fetched record for later runs. Failing to is not an
+// error; the record is fetched again next time.
+func (c *Checker) keepRecord(record *Record) {
+	if c.recordDir == "" || record.ID == "" || filepath.Base(record.ID) != record.ID {
+		return
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	_ = atomicfile.Write(filepath.Join(c.recordDir, record.ID+".json"), data, 0644)
+}
+
+// do sends a request and decodes its JSON response
+func (c *Checker) do(req *http.Request, v interface{}) error {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", req.URL, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
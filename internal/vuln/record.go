@@ -0,0 +1,176 @@
+package vuln
+
+import (
+	"strings"
+
+	"github.com/cli-ai-org/cli/internal/semver"
+)
+
+// osvURL is where a vulnerability is described for people
+const osvURL = "https://osv.dev/vulnerability/"
+
+// Record is the part of an OSV vulnerability record that findings are
+// built from (https://ossf.github.io/osv-schema/)
+type Record struct {
+	ID               string           `json:"id"`
+	Modified         string           `json:"modified"`
+	Withdrawn        string           `json:"withdrawn,omitempty"`
+	Aliases          []string         `json:"aliases,omitempty"`
+	Summary          string           `json:"summary,omitempty"`
+	Details          string           `json:"details,omitempty"`
+	Severity         []RecordSeverity `json:"severity,omitempty"`
+	Affected         []Affected       `json:"affected,omitempty"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity,omitempty"`
+	} `json:"database_specific"`
+}
+
+// RecordSeverity is a severity score, such as a CVSS vector
+type RecordSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Affected lists the versions of one package a vulnerability affects
+type Affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges   []Range  `json:"ranges,omitempty"`
+	Versions []string `json:"versions,omitempty"`
+}
+
+// Range is a span of affected versions, as a list of events
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event starts or ends a span of affected versions
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// finding describes how the record affects pkg
+func (r *Record) finding(pkg Package, ecosystem string) Finding {
+	f := Finding{
+		ID:        r.ID,
+		Aliases:   r.Aliases,
+		Summary:   r.summary(),
+		Package:   pkg.Name,
+		Manager:   pkg.Manager,
+		Ecosystem: ecosystem,
+		Version:   pkg.Version,
+		Severity:  SeverityUnknown,
+		Binaries:  pkg.Binaries,
+		Path:      pkg.Path,
+		URL:       osvURL + r.ID,
+	}
+
+	for _, s := range r.Severity {
+		if s.Type != "CVSS_V3" {
+			continue
+		}
+		if score, ok := cvss3Score(s.Score); ok {
+			f.Score = score
+			f.Severity = cvss3Rating(score)
+			break
+		}
+	}
+	if f.Severity == SeverityUnknown {
+		// GitHub advisories rate themselves even without a vector
+		if severity, ok := ParseSeverity(r.DatabaseSpecific.Severity); ok {
+			f.Severity = severity
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, affected := range r.affecting(ecosystem, pkg.Name) {
+		for _, rng := range affected.Ranges {
+			for _, event := range rng.Events {
+				if event.Fixed != "" && !seen[event.Fixed] {
+					seen[event.Fixed] = true
+					f.Fixed = append(f.Fixed, event.Fixed)
+				}
+			}
+		}
+	}
+	return f
+}
+
+// summary returns the record's summary, or the first line of its details
+func (r *Record) summary() string {
+	if r.Summary != "" {
+		return r.Summary
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(r.Details), "\n")
+	if len(line) > 200 {
+		line = line[:197] + "..."
+	}
+	return line
+}
+
+// affecting returns the record's entries for a package
+func (r *Record) affecting(ecosystem, name string) []Affected {
+	var list []Affected
+	for _, affected := range r.Affected {
+		if affected.Package.Ecosystem == ecosystem && queryName(ecosystem, affected.Package.Name) == queryName(ecosystem, name) {
+			list = append(list, affected)
+		}
+	}
+	return list
+}
+
+// affects reports whether the record affects a version of a package, from
+// its listed versions and its SEMVER and ECOSYSTEM ranges. Versions are
+// compared loosely, as semver.Compare does, which is exact for semantic
+// versions and close for PyPI's and RubyGems' schemes; the OSV API, which
+// knows each scheme, is the more precise check.
+func (r *Record) affects(ecosystem, name, version string) bool {
+	if r.Withdrawn != "" {
+		return false
+	}
+	for _, affected := range r.affecting(ecosystem, name) {
+		for _, v := range affected.Versions {
+			if v == version {
+				return true
+			}
+		}
+		for _, rng := range affected.Ranges {
+			if rng.Type != "SEMVER" && rng.Type != "ECOSYSTEM" {
+				// GIT ranges name commits, not releases
+				continue
+			}
+			if inRange(rng.Events, version) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inRange replays a range's events, which OSV orders by version, and
+// reports whether version ends up affected
+func inRange(events []Event, version string) bool {
+	affected := false
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			if event.Introduced == "0" || semver.Compare(version, event.Introduced) >= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if semver.Compare(version, event.Fixed) >= 0 {
+				affected = false
+			}
+		case event.LastAffected != "":
+			if semver.Compare(version, event.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
@@ -0,0 +1,148 @@
+package vuln
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// sarifSchema is the schema of the SARIF version written
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIFLog is a SARIF 2.1.0 log in its JSON form
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is one run of an analysis tool and what it found
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the analysis tool and the rules it checks
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the analysis tool's main component
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is one vulnerability
+type SARIFRule struct {
+	ID               string            `json:"id"`
+	Name             string            `json:"name,omitempty"`
+	ShortDescription SARIFMessage      `json:"shortDescription"`
+	HelpURI          string            `json:"helpUri"`
+	Properties       map[string]string `json:"properties,omitempty"`
+}
+
+// SARIFMessage is a plain text message
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a vulnerability found in one package
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations,omitempty"`
+}
+
+// SARIFLocation points at the installed tool a package provides
+type SARIFLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+	} `json:"physicalLocation"`
+}
+
+// SARIF builds a SARIF log of findings, with one rule per vulnerability and
+// one result per affected package, located at the first tool the package
+// provides. Critical and high findings are errors, medium ones warnings,
+// and the rest notes; the CVSS score is the rule's security-severity, as
+// GitHub code scanning reads it.
+func SARIF(findings []Finding, toolName, toolVersion string) SARIFLog {
+	run := SARIFRun{
+		Tool: SARIFTool{Driver: SARIFDriver{
+			Name:           toolName,
+			Version:        toolVersion,
+			InformationURI: "https://osv.dev",
+			Rules:          []SARIFRule{},
+		}},
+		Results: []SARIFResult{},
+	}
+
+	ruled := make(map[string]bool)
+	for _, f := range findings {
+		if !ruled[f.ID] {
+			ruled[f.ID] = true
+			rule := SARIFRule{
+				ID:               f.ID,
+				ShortDescription: SARIFMessage{Text: f.ID},
+				HelpURI:          f.URL,
+			}
+			if cve := f.CVE(); cve != f.ID {
+				rule.Name = cve
+			}
+			if f.Summary != "" {
+				rule.ShortDescription.Text = f.Summary
+			}
+			if f.Score > 0 {
+				rule.Properties = map[string]string{"security-severity": strconv.FormatFloat(f.Score, 'f', 1, 64)}
+			}
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+		}
+
+		message := fmt.Sprintf("%s %s (%s) is affected by %s", f.Package, f.Version, f.Manager, f.CVE())
+		if f.Summary != "" {
+			message += ": " + f.Summary
+		}
+		if len(f.Fixed) > 0 {
+			message += ". Fixed in " + strings.Join(f.Fixed, ", ")
+		}
+		result := SARIFResult{
+			RuleID:  f.ID,
+			Level:   sarifLevel(f.Severity),
+			Message: SARIFMessage{Text: message},
+		}
+		if f.Path != "" {
+			var location SARIFLocation
+			location.PhysicalLocation.ArtifactLocation.URI = fileURI(f.Path)
+			result.Locations = []SARIFLocation{location}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	return SARIFLog{Schema: sarifSchema, Version: "2.1.0", Runs: []SARIFRun{run}}
+}
+
+func sarifLevel(severity Severity) string {
+	switch severity {
+	case SeverityCritical, SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	}
+	return "note"
+}
+
+// fileURI returns the file URI of an absolute path, on Windows too
+func fileURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
@@ -0,0 +1,168 @@
+// Package vuln looks up known vulnerabilities affecting installed packages
+// in the OSV database (https://osv.dev), either through its API or from a
+// local copy of its per-ecosystem dumps.
+package vuln
+
+import (
+	"sort"
+	"strings"
+)
+
+// Package is an installed package to check
+type Package struct {
+	Name    string
+	Version string
+	Manager string
+	// Binaries are the tools the package provides, and Path is where the
+	// first of them is installed
+	Binaries []string
+	Path     string
+}
+
+// Finding is a vulnerability affecting an installed package
+type Finding struct {
+	ID        string   `json:"id"`
+	Aliases   []string `json:"aliases,omitempty"`
+	Summary   string   `json:"summary,omitempty"`
+	Package   string   `json:"package"`
+	Manager   string   `json:"manager"`
+	Ecosystem string   `json:"ecosystem"`
+	Version   string   `json:"version"`
+	Severity  Severity `json:"severity"`
+	// Score is the CVSS v3 base score, when the record has a vector
+	Score    float64  `json:"score,omitempty"`
+	Fixed    []string `json:"fixed,omitempty"`
+	Binaries []string `json:"binaries,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	URL      string   `json:"url"`
+}
+
+// CVE returns the finding's CVE identifier, or its OSV ID if it has none
+func (f Finding) CVE() string {
+	if strings.HasPrefix(f.ID, "CVE-") {
+		return f.ID
+	}
+	for _, alias := range f.Aliases {
+		if strings.HasPrefix(alias, "CVE-") {
+			return alias
+		}
+	}
+	return f.ID
+}
+
+// Severity is how severe a vulnerability is, on the CVSS v3 rating scale
+type Severity string
+
+// Severity levels, from least to most severe. SeverityUnknown is a record
+// with neither a CVSS v3 vector nor a severity of its own.
+const (
+	SeverityUnknown  Severity = "unknown"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Severities returns the levels a finding can be filtered by, least severe
+// first
+func Severities() []Severity {
+	return []Severity{SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+}
+
+// ParseSeverity returns the severity level a name stands for. GitHub's
+// "moderate" is taken as medium.
+func ParseSeverity(name string) (Severity, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "low":
+		return SeverityLow, true
+	case "medium", "moderate":
+		return SeverityMedium, true
+	case "high":
+		return SeverityHigh, true
+	case "critical":
+		return SeverityCritical, true
+	}
+	return SeverityUnknown, false
+}
+
+// Rank orders severities: unknown is 0 and critical is 4
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	}
+	return 0
+}
+
+// ecosystems maps package managers to OSV ecosystems. Homebrew, the OS
+// package managers, and the Windows ones have no OSV ecosystem.
+var ecosystems = map[string]string{
+	"pip":    "PyPI",
+	"pipx":   "PyPI",
+	"uv":     "PyPI",
+	"poetry": "PyPI",
+	"npm":    "npm",
+	"cargo":  "crates.io",
+	"go":     "Go",
+	"gem":    "RubyGems",
+}
+
+// Ecosystem returns the OSV ecosystem of a package manager's packages
+func Ecosystem(manager string) (string, bool) {
+	ecosystem, ok := ecosystems[manager]
+	return ecosystem, ok
+}
+
+// Managers returns the package managers whose packages OSV covers
+func Managers() []string {
+	var managers []string
+	for manager := range ecosystems {
+		managers = append(managers, manager)
+	}
+	sort.Strings(managers)
+	return managers
+}
+
+// Filter returns the findings at least as severe as min. Findings of
+// unknown severity are only kept when min is SeverityUnknown.
+func Filter(findings []Finding, min Severity) []Finding {
+	var kept []Finding
+	for _, f := range findings {
+		if f.Severity.Rank() >= min.Rank() {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// Sort orders findings most severe first, then by package and ID
+func Sort(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity.Rank() != b.Severity.Rank() {
+			return a.Severity.Rank() > b.Severity.Rank()
+		}
+		if a.Manager != b.Manager {
+			return a.Manager < b.Manager
+		}
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.ID < b.ID
+	})
+}
+
+// queryName returns the name OSV knows a package by. PyPI names are
+// case-insensitive and treat _ and . as -.
+func queryName(ecosystem, name string) string {
+	if ecosystem == "PyPI" {
+		return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+	}
+	return name
+}